@@ -1,6 +1,7 @@
 package app
 
 import (
+	"fmt"
 	"io"
 	"os"
 
@@ -36,9 +37,13 @@ import (
 	"github.com/xar-network/xar-network/x/issue"
 
 	"github.com/xar-network/xar-network/x/auction"
+	"github.com/xar-network/xar-network/x/authority"
+	authorityclient "github.com/xar-network/xar-network/x/authority/client"
 	"github.com/xar-network/xar-network/x/csdt"
+	csdtclient "github.com/xar-network/xar-network/x/csdt/client"
 	"github.com/xar-network/xar-network/x/liquidator"
 	"github.com/xar-network/xar-network/x/oracle"
+	oracleclient "github.com/xar-network/xar-network/x/oracle/client"
 
 	//Proof of existence
 	"github.com/xar-network/xar-network/x/record"
@@ -51,7 +56,9 @@ import (
 	"github.com/xar-network/xar-network/embedded/price"
 	"github.com/xar-network/xar-network/execution"
 	"github.com/xar-network/xar-network/types"
+	"github.com/xar-network/xar-network/types/store"
 	"github.com/xar-network/xar-network/x/market"
+	marketclient "github.com/xar-network/xar-network/x/market/client"
 	"github.com/xar-network/xar-network/x/order"
 	ordertypes "github.com/xar-network/xar-network/x/order/types"
 )
@@ -74,7 +81,12 @@ var (
 		staking.AppModuleBasic{},
 		mint.AppModuleBasic{},
 		distr.AppModuleBasic{},
-		gov.NewAppModuleBasic(paramsclient.ProposalHandler, distr.ProposalHandler),
+		gov.NewAppModuleBasic(
+			paramsclient.ProposalHandler, distr.ProposalHandler,
+			marketclient.ListMarketProposalHandler, csdtclient.CollateralTypeProposalHandler,
+			oracleclient.AddAssetProposalHandler, authorityclient.UpdateRoleProposalHandler,
+			authorityclient.RotateNomineesProposalHandler,
+		),
 		params.AppModuleBasic{},
 		crisis.AppModuleBasic{},
 		slashing.AppModuleBasic{},
@@ -83,6 +95,7 @@ var (
 
 		issue.AppModuleBasic{},
 		nft.AppModuleBasic{},
+		authority.AppModuleBasic{},
 		auction.AppModuleBasic{},
 		csdt.AppModuleBasic{},
 		liquidator.AppModuleBasic{},
@@ -127,8 +140,12 @@ func MakeCodec() *codec.Codec {
 // xarApp extended ABCI application
 type XarApp struct {
 	*bam.BaseApp
-	cdc *codec.Codec
-	mq  types.Backend
+	cdc    *codec.Codec
+	mq     types.Backend
+	outbox *types.Outbox
+
+	// whether market-data events are attached to end block responses
+	marketDataEvents bool
 
 	invCheckPeriod uint
 
@@ -150,6 +167,7 @@ type XarApp struct {
 	evidenceKeeper *evidence.Keeper
 
 	// app specific keepers
+	authorityKeeper  authority.Keeper
 	auctionKeeper    auction.Keeper
 	csdtKeeper       csdt.Keeper
 	liquidatorKeeper liquidator.Keeper
@@ -172,28 +190,61 @@ type XarApp struct {
 	sm *module.SimulationManager
 }
 
-// NewXarApp returns a reference to an initialized xarApp.
+// MarketDataSubscriptions returns the embedded keepers that index
+// market-data events in mktDataDB, in the order they handle each event.
+func MarketDataSubscriptions(mktDataDB dbm.DB, cdc *codec.Codec) []types.Subscription {
+	return []types.Subscription{
+		{Name: "fill", Handler: fill.NewKeeper(mktDataDB, cdc)},
+		{Name: "price", Handler: price.NewKeeper(mktDataDB, cdc)},
+		{Name: "order", Handler: embeddedorder.NewKeeper(mktDataDB, cdc)},
+		{Name: "batch", Handler: batch.NewKeeper(mktDataDB, cdc)},
+	}
+}
+
+// MarketDataQueriers returns the queriers of the market data in mktDataDB by
+// their query route.
+func MarketDataQueriers(mktDataDB dbm.DB, cdc *codec.Codec) map[string]sdk.Querier {
+	embOrderKeeper := embeddedorder.NewKeeper(mktDataDB, cdc)
+	return map[string]sdk.Querier{
+		"embeddedorder": embeddedorder.NewQuerier(embOrderKeeper),
+		"fill":          fill.NewQuerier(fill.NewKeeper(mktDataDB, cdc)),
+		"price":         price.NewQuerier(price.NewKeeper(mktDataDB, cdc)),
+		"book":          book.NewQuerier(embOrderKeeper),
+		"batch":         batch.NewQuerier(batch.NewKeeper(mktDataDB, cdc)),
+	}
+}
+
+// NewXarApp returns a reference to an initialized xarApp that hands
+// market-data events to the embedded consumers in memory.
 func NewXarApp(
 	logger log.Logger, db dbm.DB, mktDataDB dbm.DB, traceStore io.Writer, loadLatest bool,
 	invCheckPeriod uint, baseAppOptions ...func(*bam.BaseApp),
 ) *XarApp {
+	queue := types.NewMemBackend()
+	queue.Start()
+	return NewXarAppWithBackend(logger, db, mktDataDB, queue, traceStore, loadLatest, invCheckPeriod, baseAppOptions...)
+}
 
-	cdc := MakeCodec()
+// NewXarAppWithBackend returns a reference to an initialized xarApp that
+// publishes market-data events to a started queue. If the queue keeps a
+// log, the embedded consumers first catch up on the entries they missed. A
+// nil queue turns the embedded consumers off.
+func NewXarAppWithBackend(
+	logger log.Logger, db dbm.DB, mktDataDB dbm.DB, queue types.Backend, traceStore io.Writer, loadLatest bool,
+	invCheckPeriod uint, baseAppOptions ...func(*bam.BaseApp),
+) *XarApp {
 
-	fillKeeper := fill.NewKeeper(mktDataDB, cdc)
-	priceKeeper := price.NewKeeper(mktDataDB, cdc)
-	embOrderKeeper := embeddedorder.NewKeeper(mktDataDB, cdc)
-	batchKeeper := batch.NewKeeper(mktDataDB, cdc)
+	cdc := MakeCodec()
 
-	queue := types.NewMemBackend()
-	queue.Start()
-	consumer := types.NewLocalConsumer(queue, []types.EventHandler{
-		fillKeeper,
-		priceKeeper,
-		embOrderKeeper,
-		batchKeeper,
-	})
-	consumer.Start()
+	// without a queue the embedded indexers are left to a separate indexer
+	if queue != nil {
+		batchDB := store.NewBatchDB(mktDataDB)
+		consumer := types.NewLocalConsumer(queue, batchDB, MarketDataSubscriptions(batchDB, cdc))
+		if err := consumer.Replay(); err != nil {
+			cmn.Exit(err.Error())
+		}
+		consumer.Start()
+	}
 
 	bApp := bam.NewBaseApp(appName, logger, db, auth.DefaultTxDecoder(cdc), baseAppOptions...)
 	bApp.SetCommitMultiStoreTracer(traceStore)
@@ -205,10 +256,10 @@ func NewXarApp(
 		gov.StoreKey, params.StoreKey, issue.StoreKey, oracle.StoreKey,
 		auction.StoreKey, csdt.StoreKey, liquidator.StoreKey, nft.StoreKey,
 		denominations.StoreKey, record.StoreKey, evidence.StoreKey,
-		market.StoreKey, ordertypes.StoreKey,
+		market.StoreKey, ordertypes.StoreKey, authority.StoreKey,
 	)
 
-	tKeys := sdk.NewTransientStoreKeys(staking.TStoreKey, params.TStoreKey)
+	tKeys := sdk.NewTransientStoreKeys(staking.TStoreKey, params.TStoreKey, types.OutboxStoreKey)
 
 	app := &XarApp{
 		BaseApp:        bApp,
@@ -216,6 +267,10 @@ func NewXarApp(
 		invCheckPeriod: invCheckPeriod,
 		keys:           keys,
 		tKeys:          tKeys,
+		mq:             queue,
+		// market-data events only reach the queue once their block is
+		// committed
+		outbox: types.NewOutbox(tKeys[types.OutboxStoreKey], queue),
 	}
 
 	// init params keeper and subspaces
@@ -235,11 +290,9 @@ func NewXarApp(
 	liquidatorSubspace := app.paramsKeeper.Subspace(liquidator.DefaultParamspace)
 	recordSubspace := app.paramsKeeper.Subspace(record.DefaultParamspace)
 
-	denominationsSubspace := app.paramsKeeper.Subspace(denominations.DefaultParamspace)
-
 	auctionSubspace := app.paramsKeeper.Subspace(auction.DefaultParamspace)
-	marketSubspace := app.paramsKeeper.Subspace(market.DefaultParamspace)
 	oracleSubspace := app.paramsKeeper.Subspace(oracle.DefaultParamspace)
+	orderSubspace := app.paramsKeeper.Subspace(order.DefaultParamspace)
 
 	// add keepers
 	app.accountKeeper = auth.NewAccountKeeper(app.cdc, keys[auth.StoreKey], authSubspace, auth.ProtoBaseAccount)
@@ -252,19 +305,24 @@ func NewXarApp(
 	app.slashingKeeper = slashing.NewKeeper(app.cdc, keys[slashing.StoreKey], &stakingKeeper, slashingSubspace, slashing.DefaultCodespace)
 	app.crisisKeeper = crisis.NewKeeper(crisisSubspace, invCheckPeriod, app.supplyKeeper, auth.FeeCollectorName)
 
+	app.authorityKeeper = authority.NewKeeper(keys[authority.StoreKey], app.cdc, authority.DefaultCodespace)
 	app.NFTKeeper = nft.NewKeeper(app.cdc, keys[nft.StoreKey])
 	app.issueKeeper = issue.NewKeeper(keys[issue.StoreKey], issueSubspace, app.bankKeeper, app.supplyKeeper, issue.DefaultCodespace, auth.FeeCollectorName)
-	app.oracleKeeper = oracle.NewKeeper(keys[oracle.StoreKey], app.cdc, oracleSubspace, oracle.DefaultCodespace)
+	app.oracleKeeper = oracle.NewKeeper(keys[oracle.StoreKey], app.cdc, oracleSubspace, app.authorityKeeper, oracle.DefaultCodespace)
 	app.recordKeeper = record.NewKeeper(app.cdc, keys[record.StoreKey], recordSubspace, record.DefaultCodespace)
-	app.csdtKeeper = csdt.NewKeeper(app.cdc, keys[csdt.StoreKey], csdtSubspace, app.oracleKeeper, app.bankKeeper, app.supplyKeeper)
+	app.csdtKeeper = csdt.NewKeeper(app.cdc, keys[csdt.StoreKey], csdtSubspace, app.oracleKeeper, app.bankKeeper, app.supplyKeeper, app.authorityKeeper)
 	app.auctionKeeper = auction.NewKeeper(app.cdc, app.supplyKeeper, keys[auction.StoreKey], auctionSubspace)
 	app.liquidatorKeeper = liquidator.NewKeeper(app.cdc, keys[liquidator.StoreKey], liquidatorSubspace, app.csdtKeeper, app.auctionKeeper, app.bankKeeper, app.supplyKeeper)
 
-	app.marketKeeper = market.NewKeeper(keys[markettypes.StoreKey], app.cdc, marketSubspace, market.DefaultCodespace)
-	app.orderKeeper = order.NewKeeper(app.supplyKeeper, app.marketKeeper, keys[ordertypes.StoreKey], queue, app.cdc)
-	app.execKeeper = execution.NewKeeper(queue, app.marketKeeper, app.orderKeeper, app.bankKeeper)
+	marketKeeper := market.NewKeeper(keys[markettypes.StoreKey], app.cdc, app.supplyKeeper, app.authorityKeeper, market.DefaultCodespace)
+	orderKeeper := order.NewKeeper(app.supplyKeeper, app.bankKeeper, marketKeeper, app.oracleKeeper, keys[ordertypes.StoreKey], orderSubspace, app.outbox, app.cdc, order.DefaultCodespace)
+	app.execKeeper = execution.NewKeeper(app.outbox, marketKeeper, orderKeeper, app.supplyKeeper)
+	// register the order hooks so that continuous markets are matched on post
+	app.orderKeeper = *orderKeeper.SetHooks(app.execKeeper.Hooks())
+	// register the market hooks so that delisted markets refund their orders
+	app.marketKeeper = *marketKeeper.SetHooks(app.orderKeeper.MarketHooks())
 
-	app.denominationsKeeper = denominations.NewKeeper(keys[denominations.StoreKey], app.cdc, app.accountKeeper, app.supplyKeeper, denominationsSubspace, denominations.DefaultCodespace)
+	app.denominationsKeeper = denominations.NewKeeper(keys[denominations.StoreKey], app.cdc, app.accountKeeper, app.supplyKeeper, app.authorityKeeper, denominations.DefaultCodespace)
 
 	// create evidence keeper with evidence router
 	app.evidenceKeeper = evidence.NewKeeper(app.cdc, keys[evidence.StoreKey], evidenceSubspace, evidence.DefaultCodespace)
@@ -275,7 +333,11 @@ func NewXarApp(
 	govRouter := gov.NewRouter()
 	govRouter.AddRoute(gov.RouterKey, gov.ProposalHandler).
 		AddRoute(params.RouterKey, params.NewParamChangeProposalHandler(app.paramsKeeper)).
-		AddRoute(distr.RouterKey, distr.NewCommunityPoolSpendProposalHandler(app.distrKeeper))
+		AddRoute(distr.RouterKey, distr.NewCommunityPoolSpendProposalHandler(app.distrKeeper)).
+		AddRoute(market.RouterKey, market.NewProposalHandler(app.marketKeeper)).
+		AddRoute(csdt.RouterKey, csdt.NewProposalHandler(app.csdtKeeper)).
+		AddRoute(oracle.RouterKey, oracle.NewProposalHandler(app.oracleKeeper, app.supplyKeeper)).
+		AddRoute(authority.RouterKey, authority.NewProposalHandler(app.authorityKeeper, app.accountKeeper))
 	app.govKeeper = gov.NewKeeper(app.cdc, keys[gov.StoreKey], govSubspace,
 		app.supplyKeeper, &stakingKeeper, gov.DefaultCodespace, govRouter)
 
@@ -306,6 +368,7 @@ func NewXarApp(
 
 		nft.NewAppModule(app.NFTKeeper),
 		issue.NewAppModule(app.issueKeeper, app.accountKeeper),
+		authority.NewAppModule(app.authorityKeeper),
 		auction.NewAppModule(app.auctionKeeper),
 		csdt.NewAppModule(app.csdtKeeper),
 		liquidator.NewAppModule(app.liquidatorKeeper),
@@ -333,6 +396,7 @@ func NewXarApp(
 		staking.ModuleName,
 		oracle.ModuleName,
 		auction.ModuleName,
+		order.ModuleName,
 	)
 
 	// NOTE: The genutils module must occur after staking so that pools are
@@ -340,17 +404,14 @@ func NewXarApp(
 	app.mm.SetOrderInitGenesis(
 		distr.ModuleName, staking.ModuleName, auth.ModuleName, bank.ModuleName,
 		slashing.ModuleName, gov.ModuleName, mint.ModuleName, supply.ModuleName,
-		crisis.ModuleName, issue.ModuleName,
+		crisis.ModuleName, issue.ModuleName, authority.ModuleName,
 		auction.ModuleName, csdt.ModuleName, liquidator.ModuleName, oracle.ModuleName,
 		denominations.ModuleName, nft.ModuleName, record.ModuleName, genutil.ModuleName,
-		evidence.ModuleName, markettypes.ModuleName,
+		evidence.ModuleName, markettypes.ModuleName, order.ModuleName,
 	)
-	app.QueryRouter().
-		AddRoute("embeddedorder", embeddedorder.NewQuerier(embOrderKeeper)).
-		AddRoute("fill", fill.NewQuerier(fillKeeper)).
-		AddRoute("price", price.NewQuerier(priceKeeper)).
-		AddRoute("book", book.NewQuerier(embOrderKeeper)).
-		AddRoute("batch", batch.NewQuerier(batchKeeper))
+	for route, querier := range MarketDataQueriers(mktDataDB, cdc) {
+		app.QueryRouter().AddRoute(route, querier)
+	}
 
 	app.mm.RegisterInvariants(&app.crisisKeeper)
 	app.mm.RegisterRoutes(app.Router(), app.QueryRouter())
@@ -412,8 +473,37 @@ func (app *XarApp) BeginBlocker(ctx sdk.Context, req abci.RequestBeginBlock) abc
 
 // application updates every end block
 func (app *XarApp) EndBlocker(ctx sdk.Context, req abci.RequestEndBlock) abci.ResponseEndBlock {
-	app.performMatching(ctx)
-	return app.mm.EndBlock(ctx, req)
+	matchCtx := ctx.WithEventManager(sdk.NewEventManager())
+	app.performMatching(matchCtx)
+	res := app.mm.EndBlock(ctx, req)
+	res.Events = append(matchCtx.EventManager().ABCIEvents(), res.Events...)
+	// nothing publishes events after the end blockers
+	events := app.outbox.Collect(ctx)
+	if app.marketDataEvents {
+		res.Events = append(res.Events, events...)
+	}
+	return res
+}
+
+// SetMarketDataEvents sets whether the market-data events of a block are
+// attached to its end block response. They are not by default, since they
+// only grow the responses every node stores. xar-indexer and
+// rebuild-market-data read the events from there, so neither can index
+// the blocks a node committed without them.
+func (app *XarApp) SetMarketDataEvents(attach bool) {
+	app.marketDataEvents = attach
+}
+
+// Commit publishes the market-data events of the block's successful
+// transactions and blockers and then commits the block. Publishing first
+// means no event is lost if the node dies in between. A queue that cannot
+// take the events halts the node before the commit, so that the block is
+// executed and its events are published again on restart.
+func (app *XarApp) Commit() abci.ResponseCommit {
+	if err := app.outbox.Flush(app.LastBlockHeight() + 1); err != nil {
+		panic(fmt.Sprintf("failed to publish market-data events: %s", err))
+	}
+	return app.BaseApp.Commit()
 }
 
 // application update at chain initialization
@@ -469,6 +559,14 @@ func (app *XarApp) ExecKeeper() execution.Keeper {
 	return app.execKeeper
 }
 
+func (app *XarApp) AuthorityKeeper() authority.Keeper {
+	return app.authorityKeeper
+}
+
+func (app *XarApp) OracleKeeper() oracle.Keeper {
+	return app.oracleKeeper
+}
+
 func (app *XarApp) SupplyKeeper() supply.Keeper {
 	return app.supplyKeeper
 }
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/tendermint/tendermint/libs/cli"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/cosmos/cosmos-sdk/client/flags"
+	"github.com/cosmos/cosmos-sdk/client/lcd"
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/xar-network/xar-network/app"
+	embeddedclient "github.com/xar-network/xar-network/embedded/client"
+	"github.com/xar-network/xar-network/embedded/indexer"
+)
+
+// DefaultHome is where the indexer keeps its market-data database.
+var DefaultHome = os.ExpandEnv("$HOME/.xar-indexer")
+
+func main() {
+	cdc := app.MakeCodec()
+
+	config := sdk.GetConfig()
+	config.SetBech32PrefixForAccount("xar", "xarp")
+	config.SetBech32PrefixForValidator("xva", "xvap")
+	config.SetBech32PrefixForConsensusNode("xca", "xcap")
+	config.SetKeyringServiceName("xar")
+	config.Seal()
+
+	cobra.EnableCommandSorting = false
+
+	rootCmd := &cobra.Command{
+		Use:   "xar-indexer",
+		Short: "Market-data indexer of a xard node",
+	}
+	rootCmd.AddCommand(startCmd(cdc))
+
+	executor := cli.PrepareMainCmd(rootCmd, "XI", DefaultHome)
+	err := executor.Execute()
+	if err != nil {
+		panic(err)
+	}
+}
+
+func startCmd(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "start",
+		Short: "Index the market data of a node and serve it over REST",
+		Long: `Follow the node given by --node over RPC, index the market-data events
+of every block it commits into the database under --home, and serve the
+embedded REST routes from that database. Queries of anything else and
+transactions are passed on to the node.
+
+The node must run with --market-data-events, or market-data-events = true
+in its app.toml, so that its blocks carry the events the indexer reads. It
+can run with --event-backend none, so that it does not index market data
+itself.`,
+		Args: cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			db, err := dbm.NewGoLevelDB("mktdata", path.Join(viper.GetString(cli.HomeFlag), "data"))
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+
+			rs := lcd.NewRestServer(cdc)
+			if rs.CliCtx.Client == nil {
+				return errors.New("no node to index, set --node")
+			}
+			idx := indexer.New(rs.CliCtx.Client, db, cdc)
+			if err := idx.Start(); err != nil {
+				return err
+			}
+			defer idx.Stop()
+
+			rs.CliCtx = rs.CliCtx.WithClient(idx.QueryClient())
+			embeddedclient.RegisterRoutes(rs.CliCtx, rs.Mux, cdc, false)
+			return rs.Start(
+				viper.GetString(flags.FlagListenAddr),
+				viper.GetInt(flags.FlagMaxOpenConnections),
+				uint(viper.GetInt(flags.FlagRPCReadTimeout)),
+				uint(viper.GetInt(flags.FlagRPCWriteTimeout)),
+			)
+		},
+	}
+	return flags.RegisterRestServerFlags(cmd)
+}
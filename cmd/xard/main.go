@@ -2,6 +2,7 @@ package main
 
 import (
 	"encoding/json"
+	"fmt"
 	"io"
 	"path"
 
@@ -15,6 +16,7 @@ import (
 	dbm "github.com/tendermint/tm-db"
 
 	"github.com/xar-network/xar-network/app"
+	"github.com/xar-network/xar-network/types"
 
 	"github.com/cosmos/cosmos-sdk/baseapp"
 	"github.com/cosmos/cosmos-sdk/client"
@@ -27,10 +29,20 @@ import (
 	"github.com/cosmos/cosmos-sdk/x/staking"
 )
 
-const flagInvCheckPeriod = "inv-check-period"
+const (
+	flagInvCheckPeriod   = "inv-check-period"
+	flagEventBackend     = "event-backend"
+	flagMarketDataEvents = "market-data-events"
+
+	eventBackendMemory = "memory"
+	eventBackendWAL    = "wal"
+	eventBackendNone   = "none"
+)
 
 var invCheckPeriod uint
 
+var eventBackend string
+
 var mktDataDB dbm.DB
 
 func main() {
@@ -67,6 +79,8 @@ func main() {
 	rootCmd.AddCommand(genutilcli.InitCmd(ctx, cdc, app.ModuleBasics, app.DefaultNodeHome))
 	rootCmd.AddCommand(genutilcli.CollectGenTxsCmd(ctx, cdc, auth.GenesisAccountIterator{}, app.DefaultNodeHome))
 	rootCmd.AddCommand(genutilcli.MigrateGenesisCmd(ctx, cdc))
+	rootCmd.AddCommand(migrateAuthorityCmd(cdc))
+	rootCmd.AddCommand(migrateMarketsCmd(cdc))
 	rootCmd.AddCommand(
 		genutilcli.GenTxCmd(
 			ctx, cdc, app.ModuleBasics, staking.AppModuleBasic{},
@@ -78,6 +92,7 @@ func main() {
 	rootCmd.AddCommand(client.NewCompletionCmd(rootCmd, true))
 	rootCmd.AddCommand(testnetCmd(ctx, cdc, app.ModuleBasics, auth.GenesisAccountIterator{}))
 	rootCmd.AddCommand(replayCmd())
+	rootCmd.AddCommand(rebuildMarketDataCmd())
 	rootCmd.AddCommand(debug.Cmd(cdc))
 
 	server.AddCommands(ctx, cdc, rootCmd, newApp, exportAppStateAndTMValidators)
@@ -86,6 +101,14 @@ func main() {
 	executor := cli.PrepareBaseCmd(rootCmd, "GA", app.DefaultNodeHome)
 	rootCmd.PersistentFlags().UintVar(&invCheckPeriod, flagInvCheckPeriod,
 		0, "Assert registered invariants every N blocks")
+	rootCmd.PersistentFlags().StringVar(&eventBackend, flagEventBackend,
+		eventBackendMemory, "Queue of market-data events: memory, wal to log them to disk and replay them on restart, or none to leave indexing to xar-indexer")
+	rootCmd.PersistentFlags().Bool(flagMarketDataEvents,
+		false, "Attach market-data events to end block responses for xar-indexer and rebuild-market-data to read; also settable in app.toml")
+	// bound so that nodes serving xar-indexer can enable it in app.toml
+	if err := viper.BindPFlag(flagMarketDataEvents, rootCmd.PersistentFlags().Lookup(flagMarketDataEvents)); err != nil {
+		panic(err)
+	}
 	err := executor.Execute()
 	if err != nil {
 		panic(err)
@@ -99,14 +122,21 @@ func newApp(logger log.Logger, db dbm.DB, traceStore io.Writer) abci.Application
 		cache = store.NewCommitKVStoreCacheManager()
 	}
 
-	return app.NewXarApp(
-		logger, db, mktDataDB, traceStore, true, invCheckPeriod,
+	queue, err := newEventBackend()
+	if err != nil {
+		panic(err)
+	}
+
+	xapp := app.NewXarAppWithBackend(
+		logger, db, mktDataDB, queue, traceStore, true, invCheckPeriod,
 		baseapp.SetPruning(store.NewPruningOptionsFromString(viper.GetString("pruning"))),
 		baseapp.SetMinGasPrices(viper.GetString(server.FlagMinGasPrices)),
 		baseapp.SetHaltHeight(viper.GetUint64(server.FlagHaltHeight)),
 		baseapp.SetHaltTime(viper.GetUint64(server.FlagHaltTime)),
 		baseapp.SetInterBlockCache(cache),
 	)
+	xapp.SetMarketDataEvents(viper.GetBool(flagMarketDataEvents))
+	return xapp
 }
 
 func exportAppStateAndTMValidators(
@@ -126,6 +156,28 @@ func exportAppStateAndTMValidators(
 	return gapp.ExportAppStateAndValidators(forZeroHeight, jailWhiteList)
 }
 
+// newEventBackend returns the started queue chosen by --event-backend, nil
+// if the node does not index market data itself.
+func newEventBackend() (types.Backend, error) {
+	switch eventBackend {
+	case eventBackendNone:
+		return nil, nil
+	case eventBackendMemory:
+		queue := types.NewMemBackend()
+		queue.Start()
+		return queue, nil
+	case eventBackendWAL:
+		queue, err := types.NewWALBackend(path.Join(viper.GetString(cli.HomeFlag), "data", "events.wal"))
+		if err != nil {
+			return nil, err
+		}
+		queue.Start()
+		return queue, nil
+	default:
+		return nil, fmt.Errorf("unknown event backend %q", eventBackend)
+	}
+}
+
 func initMktDataDB() (dbm.DB, error) {
 	dir := path.Join(viper.GetString(cli.HomeFlag), "data")
 	return dbm.NewGoLevelDB("mktdata", dir)
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	tmtypes "github.com/tendermint/tendermint/types"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/genutil"
+
+	authoritylegacy "github.com/xar-network/xar-network/x/authority/legacy"
+	marketlegacy "github.com/xar-network/xar-network/x/market/legacy"
+)
+
+// migrateAuthorityCmd converts the nominee lists of a genesis file into
+// authority role grants.
+func migrateAuthorityCmd(cdc *codec.Codec) *cobra.Command {
+	return migrateGenesisCmd(cdc, "migrate-authority",
+		"Migrate module nominee lists to authority roles and print to STDOUT",
+		`Removes the nominee lists of the market, csdt, oracle and denominations
+genesis states and grants their nominees the market-admin, collateral-admin,
+oracle-admin and token-issuer roles of the authority module. The migrated
+genesis is printed to STDOUT.`,
+		"nominees", authoritylegacy.Migrate)
+}
+
+// migrateMarketsCmd converts the markets of a genesis file exported from the
+// market params into markets of the market store.
+func migrateMarketsCmd(cdc *codec.Codec) *cobra.Command {
+	return migrateGenesisCmd(cdc, "migrate-markets",
+		"Migrate markets kept in the market params to the market store and print to STDOUT",
+		`Converts the markets of the market genesis state, as exported from the
+market params, into active batch markets of the market store that keep their
+IDs and assets. The migrated genesis is printed to STDOUT.`,
+		"markets", marketlegacy.Migrate)
+}
+
+// migrateGenesisCmd returns a command that applies migrate to the app state
+// of a genesis file and prints the migrated genesis.
+func migrateGenesisCmd(cdc *codec.Codec, use, short, long, what string, migrate func(genutil.AppMap) (genutil.AppMap, error)) *cobra.Command {
+	return &cobra.Command{
+		Use:   use + " [genesis-file]",
+		Short: short,
+		Long:  long,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			genDoc, err := tmtypes.GenesisDocFromFile(args[0])
+			if err != nil {
+				return errors.Wrapf(err, "failed to read genesis document from file %s", args[0])
+			}
+
+			var appState genutil.AppMap
+			if err := cdc.UnmarshalJSON(genDoc.AppState, &appState); err != nil {
+				return errors.Wrap(err, "failed to JSON unmarshal initial genesis state")
+			}
+			appState, err = migrate(appState)
+			if err != nil {
+				return errors.Wrapf(err, "failed to migrate %s", what)
+			}
+			genDoc.AppState, err = cdc.MarshalJSON(appState)
+			if err != nil {
+				return errors.Wrap(err, "failed to JSON marshal migrated genesis state")
+			}
+
+			bz, err := cdc.MarshalJSONIndent(genDoc, "", "  ")
+			if err != nil {
+				return errors.Wrap(err, "failed to marshal genesis doc")
+			}
+			sortedBz, err := sdk.SortJSON(bz)
+			if err != nil {
+				return errors.Wrap(err, "failed to sort JSON genesis doc")
+			}
+
+			fmt.Println(string(sortedBz))
+			return nil
+		},
+	}
+}
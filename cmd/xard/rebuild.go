@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/tendermint/tendermint/libs/cli"
+	tmsm "github.com/tendermint/tendermint/state"
+
+	"github.com/xar-network/xar-network/app"
+	"github.com/xar-network/xar-network/embedded/batch"
+	"github.com/xar-network/xar-network/embedded/fill"
+	embeddedorder "github.com/xar-network/xar-network/embedded/order"
+	"github.com/xar-network/xar-network/embedded/price"
+	"github.com/xar-network/xar-network/types"
+	"github.com/xar-network/xar-network/types/store"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const flagFromHeight = "from-height"
+
+func rebuildMarketDataCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rebuild-market-data",
+		Short: "Rebuild the embedded market-data index from the tendermint state",
+		Long: `Index the market-data events of the blocks from --from-height on again,
+reading them from the end block responses that tendermint keeps in its
+state database. Starting from the first block clears the index first. A
+later height only adds the events of the blocks the index is missing, so
+the index must hold every block before it and none after it. Only the
+blocks the node committed with --market-data-events on have events to
+index.
+
+The node must be stopped. Its tendermint state is only read.`,
+		Args: cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return rebuildMarketData(viper.GetString(cli.HomeFlag), viper.GetInt64(flagFromHeight))
+		},
+	}
+	cmd.Flags().Int64(flagFromHeight, 1, "Index the events of the blocks from this height on")
+	return cmd
+}
+
+func rebuildMarketData(rootDir string, fromHeight int64) error {
+	if fromHeight < 1 {
+		return fmt.Errorf("--%s must be at least 1", flagFromHeight)
+	}
+	dataDir := filepath.Join(rootDir, "data")
+
+	tmDB, err := sdk.NewLevelDB("state", dataDir)
+	if err != nil {
+		return err
+	}
+	defer tmDB.Close()
+	last := tmsm.LoadState(tmDB).LastBlockHeight
+	if fromHeight > last {
+		return fmt.Errorf("the tendermint state ends at height %d", last)
+	}
+
+	cdc := app.MakeCodec()
+	batchDB := store.NewBatchDB(mktDataDB)
+	subs := app.MarketDataSubscriptions(batchDB, cdc)
+	if fromHeight == 1 {
+		fmt.Fprintln(os.Stderr, "Clearing market data")
+		for _, table := range []string{fill.TableKey, price.EntityName, embeddedorder.TableKey, batch.TableKey} {
+			store.NewTable(mktDataDB, table).Clear()
+		}
+	}
+
+	for height := fromHeight; height <= last; height++ {
+		res, err := tmsm.LoadABCIResponses(tmDB, height)
+		if err != nil {
+			return err
+		}
+		events, err := types.MarketDataEvents(res.EndBlock.Events)
+		if err != nil {
+			return fmt.Errorf("block %d: %s", height, err)
+		}
+		for _, event := range events {
+			for _, sub := range subs {
+				if err := sub.Handler.OnEvent(event); err != nil {
+					fmt.Fprintf(os.Stderr, "%s: block %d: %s\n", sub.Name, height, err)
+				}
+			}
+		}
+		batchDB.Commit()
+		if height%1000 == 0 || height == last {
+			fmt.Fprintf(os.Stderr, "Indexed block %d of %d\n", height, last)
+		}
+	}
+
+	// the event log only holds events of blocks that were just indexed
+	walPath := filepath.Join(dataDir, "events.wal")
+	if _, err := os.Stat(walPath); err == nil {
+		wal, err := types.NewWALBackend(walPath)
+		if err != nil {
+			return err
+		}
+		checkpoints := types.NewCheckpoints(batchDB)
+		for _, sub := range subs {
+			checkpoints.Set(sub.Name, wal.LastSeq())
+		}
+		batchDB.Commit()
+		if err := wal.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
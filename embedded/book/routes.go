@@ -68,12 +68,12 @@ func bookHandler(ctx context.CLIContext, cdc *codec.Codec) http.HandlerFunc {
 
 			entry, ok := m[o.Price.String()]
 			if ok {
-				entry.Quantity = entry.Quantity.Add(o.Quantity.Sub(o.QuantityFilled))
+				entry.Quantity = entry.Quantity.Add(o.Visible())
 				m[o.Price.String()] = entry
 			} else {
 				entry = QueryResultEntry{
 					Price:    o.Price,
-					Quantity: o.Quantity.Sub(o.QuantityFilled),
+					Quantity: o.Visible(),
 				}
 				m[o.Price.String()] = entry
 			}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/xar-network/xar-network/embedded"
 	"github.com/xar-network/xar-network/embedded/auth"
+	"github.com/xar-network/xar-network/pkg/matcheng"
 	"github.com/xar-network/xar-network/types/store"
 	"github.com/xar-network/xar-network/x/order/types"
 
@@ -37,7 +38,41 @@ func postOrderHandler(ctx context.CLIContext, cdc *codec.Codec) http.HandlerFunc
 		owner := kb.GetAddr()
 		ctx = ctx.WithFromAddress(owner)
 
-		msg := types.NewMsgPost(owner, req.MarketID, req.Direction, req.Price, req.Quantity, req.TimeInForce)
+		orderType := matcheng.Limit
+		if req.Type != "" {
+			var err error
+			orderType, err = matcheng.OrderTypeFromString(strings.ToUpper(req.Type))
+			if err != nil {
+				rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+
+		expiryType := types.GoodTillBlocks
+		if req.ExpiryType != "" {
+			var err error
+			expiryType, err = types.ExpiryTypeFromString(strings.ToUpper(req.ExpiryType))
+			if err != nil {
+				rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+
+		stp := matcheng.CancelNewest
+		if req.STPMode != "" {
+			var err error
+			stp, err = matcheng.STPModeFromString(strings.ToUpper(req.STPMode))
+			if err != nil {
+				rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+
+		msg := types.NewMsgPost(owner, req.MarketID, req.Direction, req.Price, req.Quantity, req.TimeInForce, orderType, req.SlippageBps)
+		msg.ExpiryType = expiryType
+		msg.GoodTillTime = req.GoodTillTime
+		msg.STPMode = stp
+		msg.DisplayQuantity = req.DisplayQuantity
 		msgs := []sdk.Msg{msg}
 		err := msg.ValidateBasic()
 		if err != nil {
@@ -66,28 +101,25 @@ func postOrderHandler(ctx context.CLIContext, cdc *codec.Codec) http.HandlerFunc
 			return
 		}
 
-		var orderIDStr string
-		for _, log := range broadcastRes.Logs {
-			if strings.HasPrefix(log.Log, "order_id") {
-				orderIDStr = strings.TrimPrefix(log.Log, "order_id:")
-				break
-			}
-		}
-		orderID := store.NewEntityIDFromString(orderIDStr)
+		orderID := store.NewEntityIDFromString(postedOrderID(broadcastRes.Logs))
 		res := OrderCreationResponse{
 			BlockInclusion: embedded.BlockInclusion{
 				BlockNumber:     broadcastRes.Height,
 				TransactionHash: broadcastRes.TxHash,
 				BlockTimestamp:  broadcastRes.Timestamp,
 			},
-			ID:          orderID,
-			MarketID:    msg.MarketID,
-			Direction:   msg.Direction,
-			Price:       msg.Price,
-			Quantity:    msg.Quantity,
-			Type:        req.Type,
-			TimeInForce: msg.TimeInForce,
-			Status:      "OPEN",
+			ID:              orderID,
+			MarketID:        msg.MarketID,
+			Direction:       msg.Direction,
+			Price:           msg.Price,
+			Quantity:        msg.Quantity,
+			Type:            orderType.String(),
+			TimeInForce:     msg.TimeInForce,
+			ExpiryType:      expiryType.String(),
+			GoodTillTime:    msg.GoodTillTime,
+			STPMode:         stp.String(),
+			Status:          "OPEN",
+			DisplayQuantity: msg.DisplayQuantity,
 		}
 
 		out, sdkErr := cdc.MarshalJSON(res)
@@ -100,3 +132,21 @@ func postOrderHandler(ctx context.CLIContext, cdc *codec.Codec) http.HandlerFunc
 		}
 	}
 }
+
+// postedOrderID returns the ID of the order a transaction posted, read from
+// its post_order event.
+func postedOrderID(logs sdk.ABCIMessageLogs) string {
+	for _, log := range logs {
+		for _, event := range log.Events {
+			if event.Type != types.EventTypePostOrder {
+				continue
+			}
+			for _, attr := range event.Attributes {
+				if attr.Key == types.AttributeKeyOrderID {
+					return attr.Value
+				}
+			}
+		}
+	}
+	return ""
+}
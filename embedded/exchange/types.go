@@ -9,22 +9,32 @@ import (
 )
 
 type OrderCreationRequest struct {
-	MarketID    store.EntityID     `json:"market_id"`
-	Direction   matcheng.Direction `json:"direction"`
-	Price       sdk.Uint           `json:"price"`
-	Quantity    sdk.Uint           `json:"quantity"`
-	Type        string             `json:"type"`
-	TimeInForce uint16             `json:"time_in_force"`
+	MarketID     store.EntityID     `json:"market_id"`
+	Direction    matcheng.Direction `json:"direction"`
+	Price        sdk.Uint           `json:"price"`
+	Quantity     sdk.Uint           `json:"quantity"`
+	Type         string             `json:"type"`
+	TimeInForce  uint16             `json:"time_in_force"`
+	SlippageBps  uint16             `json:"slippage_bps"`
+	ExpiryType   string             `json:"expiry_type"`
+	GoodTillTime int64              `json:"good_till_time"`
+	STPMode      string             `json:"stp_mode"`
+	// DisplayQuantity makes the order an iceberg order if it is set.
+	DisplayQuantity *sdk.Uint `json:"display_quantity,omitempty"`
 }
 
 type OrderCreationResponse struct {
-	BlockInclusion embedded.BlockInclusion `json:"block_inclusion"`
-	ID             store.EntityID          `json:"id"`
-	MarketID       store.EntityID          `json:"market_id"`
-	Direction      matcheng.Direction      `json:"direction"`
-	Price          sdk.Uint                `json:"price"`
-	Quantity       sdk.Uint                `json:"quantity"`
-	Type           string                  `json:"type"`
-	TimeInForce    uint16                  `json:"time_in_force"`
-	Status         string                  `json:"status"`
+	BlockInclusion  embedded.BlockInclusion `json:"block_inclusion"`
+	ID              store.EntityID          `json:"id"`
+	MarketID        store.EntityID          `json:"market_id"`
+	Direction       matcheng.Direction      `json:"direction"`
+	Price           sdk.Uint                `json:"price"`
+	Quantity        sdk.Uint                `json:"quantity"`
+	Type            string                  `json:"type"`
+	TimeInForce     uint16                  `json:"time_in_force"`
+	ExpiryType      string                  `json:"expiry_type"`
+	GoodTillTime    int64                   `json:"good_till_time"`
+	STPMode         string                  `json:"stp_mode"`
+	Status          string                  `json:"status"`
+	DisplayQuantity *sdk.Uint               `json:"display_quantity,omitempty"`
 }
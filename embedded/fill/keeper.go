@@ -37,6 +37,9 @@ func (k Keeper) OnFillEvent(event types.Fill) {
 		QtyUnfilled: event.QtyUnfilled,
 		BlockNumber: event.BlockNumber,
 		Price:       event.Price,
+		Type:        event.Type,
+		Maker:       event.Maker,
+		Fee:         event.Fee,
 	}
 	storedB := k.cdc.MustMarshalBinaryBare(fill)
 	k.as.Set(fillKey(event.BlockNumber, event.OrderID), storedB)
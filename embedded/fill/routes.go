@@ -87,6 +87,9 @@ func userFills(ctx context.CLIContext, cdc *codec.Codec) http.HandlerFunc {
 				Pair:             fill.Pair,
 				Price:            fill.Price,
 				Owner:            fill.Owner,
+				Type:             fill.Type,
+				Maker:            fill.Maker,
+				Fee:              fill.Fee,
 			})
 		}
 
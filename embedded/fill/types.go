@@ -17,6 +17,9 @@ type Fill struct {
 	QtyUnfilled sdk.Uint           `json:"qty_unfilled"`
 	BlockNumber int64              `json:"block_number"`
 	Price       sdk.Uint           `json:"price"`
+	Type        matcheng.OrderType `json:"type"`
+	Maker       bool               `json:"maker"`
+	Fee         sdk.Coins          `json:"fee"`
 }
 
 type QueryRequest struct {
@@ -42,4 +45,7 @@ type RESTFill struct {
 	Pair             string                  `json:"pair"`
 	Price            sdk.Uint                `json:"price"`
 	Owner            sdk.AccAddress          `json:"owner"`
+	Type             matcheng.OrderType      `json:"type"`
+	Maker            bool                    `json:"maker"`
+	Fee              sdk.Coins               `json:"fee"`
 }
@@ -0,0 +1,152 @@
+package indexer
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	tmlog "github.com/tendermint/tendermint/libs/log"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+
+	"github.com/xar-network/xar-network/pkg/log"
+	"github.com/xar-network/xar-network/types"
+)
+
+const subscriber = "xar-indexer"
+
+// pollInterval is how often the feed asks the node for new blocks when no
+// block header arrived over the websocket, e.g. after it reconnected.
+const pollInterval = 5 * time.Second
+
+// seqBits is the number of bits of a sequence number that number the events
+// within a block.
+const seqBits = 24
+
+// Seq returns the sequence number of the index-th market-data event of the
+// block at height. Sequence numbers grow with the chain, so they serve as the
+// consumers' checkpoints.
+func Seq(height int64, index int) uint64 {
+	return uint64(height)<<seqBits | uint64(index+1)
+}
+
+// Height returns the height of the block of the event numbered seq.
+func Height(seq uint64) int64 {
+	return int64(seq >> seqBits)
+}
+
+// Feed is a read-only Backend that follows a node over RPC. It reads the
+// market-data events a block attached to its end block response, once the
+// node committed the block, and hands them to the consumer as LogEntries.
+type Feed struct {
+	node   rpcclient.Client
+	next   int64
+	height int64
+	items  chan interface{}
+	quitCh chan struct{}
+	lgr    tmlog.Logger
+}
+
+// NewFeed returns a feed that starts with the block at height from.
+func NewFeed(node rpcclient.Client, from int64) *Feed {
+	return &Feed{
+		node:   node,
+		next:   from,
+		height: from - 1,
+		items:  make(chan interface{}),
+		quitCh: make(chan struct{}),
+		lgr:    log.WithModule("indexer-feed"),
+	}
+}
+
+func (f *Feed) Start() error {
+	if !f.node.IsRunning() {
+		if err := f.node.Start(); err != nil {
+			return err
+		}
+	}
+	headers, err := f.node.Subscribe(context.Background(), subscriber, tmtypes.EventQueryNewBlockHeader.String())
+	if err != nil {
+		return err
+	}
+	go f.run(headers)
+	return nil
+}
+
+func (f *Feed) Stop() {
+	close(f.quitCh)
+}
+
+// Publish fails; only the chain adds events to the feed.
+func (f *Feed) Publish(interface{}) error {
+	return errors.New("the indexer feed is read-only")
+}
+
+// Consume returns nil once the feed is stopped.
+func (f *Feed) Consume() interface{} {
+	select {
+	case item := <-f.items:
+		return item
+	case <-f.quitCh:
+		return nil
+	}
+}
+
+// Height returns the height of the last block whose events were handed to
+// the consumer.
+func (f *Feed) Height() int64 {
+	return atomic.LoadInt64(&f.height)
+}
+
+func (f *Feed) run(headers <-chan ctypes.ResultEvent) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		if err := f.catchUp(); err != nil {
+			f.lgr.Error("error reading blocks", "height", f.next, "err", err.Error())
+		}
+		select {
+		case <-f.quitCh:
+			return
+		case _, ok := <-headers:
+			if !ok {
+				headers = nil
+			}
+		case <-ticker.C:
+		}
+	}
+}
+
+// catchUp hands the events of every block up to the node's latest one to
+// the consumer.
+func (f *Feed) catchUp() error {
+	status, err := f.node.Status()
+	if err != nil {
+		return err
+	}
+	for ; f.next <= status.SyncInfo.LatestBlockHeight; f.next++ {
+		height := f.next
+		res, err := f.node.BlockResults(&height)
+		if err != nil {
+			return err
+		}
+		var events []types.Event
+		if res.Results != nil && res.Results.EndBlock != nil {
+			events, err = types.MarketDataEvents(res.Results.EndBlock.Events)
+			if err != nil {
+				return err
+			}
+		}
+		for i, event := range events {
+			select {
+			case f.items <- types.LogEntry{Seq: Seq(height, i), Height: height, Event: event}:
+			case <-f.quitCh:
+				return nil
+			}
+		}
+		atomic.StoreInt64(&f.height, height)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package indexer
+
+import (
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+
+	"github.com/xar-network/xar-network/app"
+	"github.com/xar-network/xar-network/types"
+	"github.com/xar-network/xar-network/types/store"
+)
+
+// Indexer runs the embedded market-data consumers outside of the node. It
+// reads the events of every committed block from a node over RPC and
+// applies them to its own database, so that validators can turn indexing
+// off and leave serving market data to separate machines.
+type Indexer struct {
+	db       dbm.DB
+	cdc      *codec.Codec
+	feed     *Feed
+	consumer *types.LocalConsumer
+}
+
+// New returns an indexer of the node's blocks into db. It goes on from the
+// oldest block a consumer has not fully applied; the checkpoints skip the
+// events that were applied before.
+func New(node rpcclient.Client, db dbm.DB, cdc *codec.Codec) *Indexer {
+	batchDB := store.NewBatchDB(db)
+	subs := app.MarketDataSubscriptions(batchDB, cdc)
+	checkpoints := types.NewCheckpoints(batchDB)
+	from := int64(-1)
+	for _, sub := range subs {
+		height := Height(checkpoints.Get(sub.Name))
+		if from == -1 || height < from {
+			from = height
+		}
+	}
+	if from < 1 {
+		from = 1
+	}
+
+	feed := NewFeed(node, from)
+	return &Indexer{
+		db:       db,
+		cdc:      cdc,
+		feed:     feed,
+		consumer: types.NewLocalConsumer(feed, batchDB, subs),
+	}
+}
+
+func (i *Indexer) Start() error {
+	if err := i.feed.Start(); err != nil {
+		return err
+	}
+	i.consumer.Start()
+	return nil
+}
+
+// Stop stops reading blocks and waits for the consumers to finish the event
+// they are applying.
+func (i *Indexer) Stop() {
+	i.consumer.Stop()
+	i.feed.Stop()
+	<-i.consumer.Done()
+}
+
+// Height returns the height of the last block the indexer read.
+func (i *Indexer) Height() int64 {
+	return i.feed.Height()
+}
+
+// QueryClient returns a client of the node that answers the market-data
+// queries from the indexer's database.
+func (i *Indexer) QueryClient() *QueryClient {
+	return NewQueryClient(i.feed.node, app.MarketDataQueriers(i.db, i.cdc), i.Height)
+}
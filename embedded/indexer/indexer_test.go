@@ -0,0 +1,115 @@
+package indexer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	cmn "github.com/tendermint/tendermint/libs/common"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	"github.com/tendermint/tendermint/state"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/xar-network/xar-network/app"
+	embeddedorder "github.com/xar-network/xar-network/embedded/order"
+	"github.com/xar-network/xar-network/testutil/testflags"
+	"github.com/xar-network/xar-network/types"
+	"github.com/xar-network/xar-network/types/store"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// fakeNode serves the end block events of its blocks. The calls an indexer
+// does not make are left to the nil Client.
+type fakeNode struct {
+	rpcclient.Client
+	blocks [][]types.Event
+}
+
+func (n *fakeNode) IsRunning() bool { return true }
+
+func (n *fakeNode) Subscribe(context.Context, string, string, ...int) (<-chan ctypes.ResultEvent, error) {
+	return make(chan ctypes.ResultEvent), nil
+}
+
+func (n *fakeNode) Status() (*ctypes.ResultStatus, error) {
+	return &ctypes.ResultStatus{SyncInfo: ctypes.SyncInfo{LatestBlockHeight: int64(len(n.blocks))}}, nil
+}
+
+func (n *fakeNode) BlockResults(height *int64) (*ctypes.ResultBlockResults, error) {
+	var events []abci.Event
+	for _, event := range n.blocks[*height-1] {
+		events = append(events, types.NewMarketDataEvent(event))
+	}
+	return &ctypes.ResultBlockResults{
+		Height:  *height,
+		Results: &state.ABCIResponses{EndBlock: &abci.ResponseEndBlock{Events: events}},
+	}, nil
+}
+
+func (n *fakeNode) ABCIQueryWithOptions(path string, _ cmn.HexBytes, _ rpcclient.ABCIQueryOptions) (*ctypes.ResultABCIQuery, error) {
+	return &ctypes.ResultABCIQuery{Response: abci.ResponseQuery{Log: "node: " + path}}, nil
+}
+
+func TestIndexer(t *testing.T) {
+	testflags.UnitTest(t)
+	cdc := app.MakeCodec()
+	db := dbm.NewMemDB()
+	created := func(id uint64) types.Event {
+		return types.OrderCreated{ID: store.NewEntityID(id), MarketID: store.NewEntityID(1)}
+	}
+	node := &fakeNode{blocks: [][]types.Event{
+		{created(1)},
+		{},
+		{created(2), types.OrderCancelled{OrderID: store.NewEntityID(1)}},
+	}}
+
+	idx := New(node, db, cdc)
+	require.NoError(t, idx.Start())
+	checkpoints := types.NewCheckpoints(db)
+	require.Eventually(t, func() bool {
+		return idx.Height() == 3 && checkpoints.Get("order") == Seq(3, 1)
+	}, 5*time.Second, 10*time.Millisecond)
+	// returns once the consumer did
+	idx.Stop()
+
+	client := idx.QueryClient()
+	res, err := client.ABCIQuery("/custom/embeddedorder/list", cdc.MustMarshalBinaryBare(embeddedorder.ListQueryRequest{}))
+	require.NoError(t, err)
+	require.True(t, res.Response.IsOK(), res.Response.Log)
+	require.Equal(t, int64(3), res.Response.Height)
+	var orders embeddedorder.ListQueryResult
+	cdc.MustUnmarshalJSON(res.Response.Value, &orders)
+	require.Len(t, orders.Orders, 2)
+	require.Equal(t, "CANCELLED", orders.Orders[1].Status)
+
+	res, err = client.ABCIQuery("/custom/embeddedorder/unknown", nil)
+	require.NoError(t, err)
+	require.False(t, res.Response.IsOK())
+
+	res, err = client.ABCIQuery("/custom/acc/account", nil)
+	require.NoError(t, err)
+	require.Equal(t, "node: /custom/acc/account", res.Response.Log)
+
+	// a restarted indexer goes on from the last block with events
+	require.Equal(t, int64(3), New(node, db, cdc).feed.next)
+}
+
+func TestQueryClient_Context(t *testing.T) {
+	testflags.UnitTest(t)
+	client := NewQueryClient(&fakeNode{}, map[string]sdk.Querier{
+		"cached": func(ctx sdk.Context, _ []string, _ abci.RequestQuery) ([]byte, sdk.Error) {
+			// queriers may branch the context like the node's do
+			cached, _ := ctx.CacheContext()
+			return []byte(cached.ChainID()), nil
+		},
+	}, func() int64 { return 7 })
+
+	res, err := client.ABCIQuery("/custom/cached", nil)
+	require.NoError(t, err)
+	require.True(t, res.Response.IsOK(), res.Response.Log)
+	require.Equal(t, int64(7), res.Response.Height)
+}
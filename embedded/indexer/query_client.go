@@ -0,0 +1,76 @@
+package indexer
+
+import (
+	"fmt"
+	"strings"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	cmn "github.com/tendermint/tendermint/libs/common"
+	"github.com/tendermint/tendermint/libs/log"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// QueryClient is a client of a node that answers the custom queries of the
+// market data itself, the way the node's query router would, and passes
+// everything else on to the node.
+type QueryClient struct {
+	rpcclient.Client
+
+	queriers map[string]sdk.Querier
+	height   func() int64
+	// the market-data queriers read the indexer's database rather than
+	// the context's store, which is empty
+	ms sdk.CommitMultiStore
+}
+
+// NewQueryClient returns a client that serves queries for the routes of
+// queriers. height returns the height the market data was indexed up to.
+func NewQueryClient(node rpcclient.Client, queriers map[string]sdk.Querier, height func() int64) *QueryClient {
+	ms := store.NewCommitMultiStore(dbm.NewMemDB())
+	if err := ms.LoadLatestVersion(); err != nil {
+		panic(err)
+	}
+	return &QueryClient{
+		Client:   node,
+		queriers: queriers,
+		height:   height,
+		ms:       ms,
+	}
+}
+
+func (c *QueryClient) ABCIQuery(path string, data cmn.HexBytes) (*ctypes.ResultABCIQuery, error) {
+	return c.ABCIQueryWithOptions(path, data, rpcclient.DefaultABCIQueryOptions)
+}
+
+func (c *QueryClient) ABCIQueryWithOptions(path string, data cmn.HexBytes, opts rpcclient.ABCIQueryOptions) (*ctypes.ResultABCIQuery, error) {
+	parts := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	if len(parts) < 2 || parts[0] != "custom" {
+		return c.Client.ABCIQueryWithOptions(path, data, opts)
+	}
+	querier, ok := c.queriers[parts[1]]
+	if !ok {
+		return c.Client.ABCIQueryWithOptions(path, data, opts)
+	}
+	if opts.Prove {
+		return nil, fmt.Errorf("the indexer cannot prove %s", path)
+	}
+
+	height := c.height()
+	ctx := sdk.NewContext(c.ms.CacheMultiStore(), abci.Header{Height: height}, true, log.NewNopLogger())
+	req := abci.RequestQuery{Data: data, Path: path, Height: height}
+	res := abci.ResponseQuery{Height: height}
+	bz, err := querier(ctx, parts[2:], req)
+	if err != nil {
+		res.Code = uint32(err.Code())
+		res.Codespace = string(err.Codespace())
+		res.Log = err.ABCILog()
+	} else {
+		res.Value = bz
+	}
+	return &ctypes.ResultABCIQuery{Response: res}, nil
+}
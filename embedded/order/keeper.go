@@ -69,17 +69,21 @@ func (k Keeper) OrdersByOwner(owner sdk.AccAddress, cb IteratorCB) {
 
 func (k Keeper) OnOrderCreatedEvent(event types.OrderCreated) {
 	order := Order{
-		ID:             event.ID,
-		Owner:          event.Owner,
-		MarketID:       event.MarketID,
-		Direction:      event.Direction,
-		Price:          event.Price,
-		Quantity:       event.Quantity,
-		Status:         "OPEN",
-		Type:           "LIMIT",
-		TimeInForce:    event.TimeInForceBlocks,
-		QuantityFilled: sdk.NewUint(0),
-		CreatedBlock:   event.CreatedBlock,
+		ID:              event.ID,
+		Owner:           event.Owner,
+		MarketID:        event.MarketID,
+		Direction:       event.Direction,
+		Price:           event.Price,
+		Quantity:        event.Quantity,
+		Status:          "OPEN",
+		Type:            event.Type.String(),
+		TimeInForce:     event.TimeInForceBlocks,
+		ExpiryType:      event.ExpiryType.String(),
+		GoodTillTime:    event.GoodTillTime,
+		QuantityFilled:  sdk.NewUint(0),
+		CreatedBlock:    event.CreatedBlock,
+		STPMode:         event.STPMode.String(),
+		DisplayQuantity: event.DisplayQuantity,
 	}
 	k.Set(order)
 	k.as.Set(ownerOrderKey(order.Owner, order.ID), order.ID.Bytes())
@@ -106,7 +110,22 @@ func (k Keeper) OnOrderCancelledEvent(event types.OrderCancelled) sdk.Error {
 		return err
 	}
 
-	order.Status = "CANCELLED"
+	order.Status = event.Reason.Status().String()
+	order.CancelReason = event.Reason.String()
+	k.Set(order)
+	return nil
+}
+
+func (k Keeper) OnOrderReducedEvent(event types.OrderReduced) sdk.Error {
+	order, err := k.Get(event.OrderID)
+	if err != nil {
+		return err
+	}
+
+	order.Quantity = order.Quantity.Sub(event.Quantity)
+	if order.Quantity.Equal(order.QuantityFilled) {
+		order.Status = "FILLED"
+	}
 	k.Set(order)
 	return nil
 }
@@ -176,6 +195,8 @@ func (k Keeper) OnEvent(event interface{}) error {
 		k.OnOrderCreatedEvent(ev)
 	case types.OrderCancelled:
 		return k.OnOrderCancelledEvent(ev)
+	case types.OrderReduced:
+		return k.OnOrderReducedEvent(ev)
 	case types.Fill:
 		return k.OnFillEvent(ev)
 	}
@@ -11,6 +11,7 @@ import (
 	"github.com/xar-network/xar-network/testutil/testflags"
 	"github.com/xar-network/xar-network/types"
 	"github.com/xar-network/xar-network/types/store"
+	ordertypes "github.com/xar-network/xar-network/x/order/types"
 
 	"github.com/cosmos/cosmos-sdk/codec"
 	sdk "github.com/cosmos/cosmos-sdk/types"
@@ -88,6 +89,13 @@ func TestKeeper(t *testing.T) {
 			OrderID: store.NewEntityID(4),
 		},
 	}
+	reductionEvs := []types.OrderReduced{
+		{
+			OrderID:  store.NewEntityID(1),
+			Quantity: sdk.NewUint(10),
+			Reason:   ordertypes.CancelledSelfTrade,
+		},
+	}
 	fillEvs := []types.Fill{
 		{
 			OrderID:   store.NewEntityID(5),
@@ -104,6 +112,9 @@ func TestKeeper(t *testing.T) {
 	for _, e := range cancellationEvs {
 		require.NoError(t, k.OnEvent(e))
 	}
+	for _, e := range reductionEvs {
+		require.NoError(t, k.OnEvent(e))
+	}
 	for _, e := range fillEvs {
 		require.NoError(t, k.OnEvent(e))
 	}
@@ -124,8 +135,10 @@ func TestKeeper(t *testing.T) {
 			Status:         "OPEN",
 			Type:           "LIMIT",
 			TimeInForce:    ev4.TimeInForceBlocks,
+			ExpiryType:     "GTB",
 			QuantityFilled: sdk.NewUint(99),
 			CreatedBlock:   ev4.CreatedBlock,
+			STPMode:        "CANCEL_NEWEST",
 		}, res[0])
 		assertEqualOrders(t, cdc, Order{
 			ID:             ev1.ID,
@@ -137,8 +150,10 @@ func TestKeeper(t *testing.T) {
 			Status:         "OPEN",
 			Type:           "LIMIT",
 			TimeInForce:    ev1.TimeInForceBlocks,
+			ExpiryType:     "GTB",
 			QuantityFilled: sdk.NewUint(0),
 			CreatedBlock:   ev1.CreatedBlock,
+			STPMode:        "CANCEL_NEWEST",
 		}, res[1])
 		assertEqualOrders(t, cdc, Order{
 			ID:             ev0.ID,
@@ -146,12 +161,14 @@ func TestKeeper(t *testing.T) {
 			MarketID:       ev0.MarketID,
 			Direction:      ev0.Direction,
 			Price:          ev0.Price,
-			Quantity:       ev0.Quantity,
+			Quantity:       ev0.Quantity.Sub(sdk.NewUint(10)),
 			Status:         "OPEN",
 			Type:           "LIMIT",
 			TimeInForce:    ev0.TimeInForceBlocks,
+			ExpiryType:     "GTB",
 			QuantityFilled: sdk.NewUint(0),
 			CreatedBlock:   ev0.CreatedBlock,
+			STPMode:        "CANCEL_NEWEST",
 		}, res[2])
 	})
 	t.Run("cancelled orders are returned as cancelled", func(t *testing.T) {
@@ -168,8 +185,11 @@ func TestKeeper(t *testing.T) {
 			Status:         "CANCELLED",
 			Type:           "LIMIT",
 			TimeInForce:    ev3.TimeInForceBlocks,
+			ExpiryType:     "GTB",
 			QuantityFilled: sdk.NewUint(0),
 			CreatedBlock:   ev3.CreatedBlock,
+			CancelReason:   "OWNER",
+			STPMode:        "CANCEL_NEWEST",
 		}, res)
 	})
 	t.Run("fully filled orders are returned as filled", func(t *testing.T) {
@@ -186,8 +206,10 @@ func TestKeeper(t *testing.T) {
 			Status:         "FILLED",
 			Type:           "LIMIT",
 			TimeInForce:    ev5.TimeInForceBlocks,
+			ExpiryType:     "GTB",
 			QuantityFilled: sdk.NewUint(100),
 			CreatedBlock:   ev5.CreatedBlock,
+			STPMode:        "CANCEL_NEWEST",
 		}, res)
 	})
 }
@@ -197,3 +219,26 @@ func assertEqualOrders(t *testing.T, cdc *codec.Codec, exp Order, actual Order)
 	actualJson := cdc.MustMarshalJSON(actual)
 	assert.Equal(t, string(expJson), string(actualJson))
 }
+
+func TestKeeper_Iceberg(t *testing.T) {
+	testflags.UnitTest(t)
+	k := NewKeeper(dbm.NewMemDB(), codec.New())
+	display := sdk.NewUint(10)
+	require.NoError(t, k.OnEvent(types.OrderCreated{
+		ID:              store.NewEntityID(1),
+		MarketID:        store.NewEntityID(1),
+		Direction:       matcheng.Ask,
+		Price:           sdk.NewUint(100),
+		Quantity:        sdk.NewUint(100),
+		DisplayQuantity: &display,
+	}))
+	ord, err := k.Get(store.NewEntityID(1))
+	require.NoError(t, err)
+	assert.Equal(t, display.String(), ord.Visible().String())
+
+	// only the reserve left is shown once it is smaller than a slice
+	require.NoError(t, k.OnEvent(types.Fill{OrderID: store.NewEntityID(1), QtyFilled: sdk.NewUint(94)}))
+	ord, err = k.Get(store.NewEntityID(1))
+	require.NoError(t, err)
+	assert.Equal(t, "6", ord.Visible().String())
+}
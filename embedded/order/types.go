@@ -17,8 +17,23 @@ type Order struct {
 	Status         string             `json:"status"`
 	Type           string             `json:"type"`
 	TimeInForce    uint16             `json:"time_in_force"`
+	ExpiryType     string             `json:"expiry_type"`
+	GoodTillTime   int64              `json:"good_till_time"`
 	QuantityFilled sdk.Uint           `json:"quantity_filled"`
 	CreatedBlock   int64              `json:"created_block"`
+	CancelReason   string             `json:"cancel_reason,omitempty"`
+	STPMode        string             `json:"stp_mode"`
+	// DisplayQuantity is set for iceberg orders only.
+	DisplayQuantity *sdk.Uint `json:"display_quantity,omitempty"`
+}
+
+// Visible returns the open quantity of the order that is shown in the book.
+func (o Order) Visible() sdk.Uint {
+	open := o.Quantity.Sub(o.QuantityFilled)
+	if o.DisplayQuantity != nil && o.DisplayQuantity.LT(open) {
+		return *o.DisplayQuantity
+	}
+	return open
 }
 
 type ListQueryRequest struct {
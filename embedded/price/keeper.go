@@ -50,16 +50,27 @@ func (k Keeper) IteratorByMarketAndInterval(mktID store.EntityID, from time.Time
 	})
 }
 
+// OnFillEvent records the fill's price as the market's tick for its block
+// time. Fees of every fill sharing that tick are added up.
 func (k Keeper) OnFillEvent(event types.Fill) {
+	key := tickKey(event.MarketID, event.BlockTime)
+	fees := event.Fee
+	if storedB := k.as.Get(key); storedB != nil {
+		var prev Tick
+		k.cdc.MustUnmarshalBinaryBare(storedB, &prev)
+		fees = fees.Add(prev.Fees)
+	}
+
 	tick := Tick{
 		MarketID:    event.MarketID,
 		Pair:        event.Pair,
 		BlockNumber: event.BlockNumber,
 		BlockTime:   event.BlockTime,
 		Price:       event.Price,
+		Fees:        fees,
 	}
 	storedB := k.cdc.MustMarshalBinaryBare(tick)
-	k.as.Set(tickKey(event.MarketID, tick.BlockTime), storedB)
+	k.as.Set(key, storedB)
 }
 
 func (k Keeper) OnEvent(event interface{}) error {
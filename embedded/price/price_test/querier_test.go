@@ -41,6 +41,9 @@ func TestQuerier_Candles(t *testing.T) {
 			1,
 			100,
 			sdk.NewUint(100),
+			matcheng.Limit,
+			false,
+			sdk.NewCoins(),
 		},
 		{
 			store.NewEntityID(1),
@@ -53,6 +56,9 @@ func TestQuerier_Candles(t *testing.T) {
 			2,
 			130,
 			sdk.NewUint(90),
+			matcheng.Limit,
+			false,
+			sdk.NewCoins(),
 		},
 		{
 			store.NewEntityID(1),
@@ -65,6 +71,9 @@ func TestQuerier_Candles(t *testing.T) {
 			3,
 			160,
 			sdk.NewUint(120),
+			matcheng.Limit,
+			false,
+			sdk.NewCoins(),
 		},
 		{
 			store.NewEntityID(1),
@@ -77,6 +86,9 @@ func TestQuerier_Candles(t *testing.T) {
 			4,
 			190,
 			sdk.NewUint(140),
+			matcheng.Limit,
+			false,
+			sdk.NewCoins(),
 		},
 	}
 
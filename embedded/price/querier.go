@@ -134,6 +134,7 @@ func queryDaily(path []string, keeper Keeper) ([]byte, sdk.Error) {
 		Last:   sdk.ZeroUint(),
 		High:   sdk.ZeroUint(),
 		Low:    sdk.ZeroUint(),
+		Fees:   sdk.NewCoins(),
 	}
 
 	now := time.Now()
@@ -151,6 +152,7 @@ func queryDaily(path []string, keeper Keeper) ([]byte, sdk.Error) {
 		if res.Low.IsZero() || res.Low.GT(tick.Price) {
 			res.Low = tick.Price
 		}
+		res.Fees = res.Fees.Add(tick.Fees)
 		return true
 	})
 	if res.Pair == "" {
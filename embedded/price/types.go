@@ -21,6 +21,7 @@ type Tick struct {
 	BlockNumber int64
 	BlockTime   int64
 	Price       sdk.Uint
+	Fees        sdk.Coins
 }
 
 type TickEntry struct {
@@ -136,10 +137,11 @@ type CandleEntry struct {
 }
 
 type DailyQueryResult struct {
-	Pair   string   `json:"pair"`
-	Volume sdk.Uint `json:"volume"`
-	Change sdk.Dec  `json:"change"`
-	Last   sdk.Uint `json:"last"`
-	High   sdk.Uint `json:"high"`
-	Low    sdk.Uint `json:"low"`
+	Pair   string    `json:"pair"`
+	Volume sdk.Uint  `json:"volume"`
+	Change sdk.Dec   `json:"change"`
+	Last   sdk.Uint  `json:"last"`
+	High   sdk.Uint  `json:"high"`
+	Low    sdk.Uint  `json:"low"`
+	Fees   sdk.Coins `json:"fees"`
 }
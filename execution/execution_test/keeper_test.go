@@ -2,19 +2,26 @@ package execution_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/xar-network/xar-network/execution"
 	"github.com/xar-network/xar-network/pkg/matcheng"
 	"github.com/xar-network/xar-network/testutil"
 	"github.com/xar-network/xar-network/testutil/mockapp"
 	"github.com/xar-network/xar-network/testutil/testflags"
+	"github.com/xar-network/xar-network/types"
 	uexstore "github.com/xar-network/xar-network/types/store"
+	"github.com/xar-network/xar-network/x/authority"
 	"github.com/xar-network/xar-network/x/denominations"
 	types2 "github.com/xar-network/xar-network/x/market/types"
+	"github.com/xar-network/xar-network/x/order"
+	ordertypes "github.com/xar-network/xar-network/x/order/types"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
 	"github.com/cosmos/cosmos-sdk/x/supply"
 )
 
@@ -26,9 +33,7 @@ func TestKeeper_ExecuteAndCancelExpired(t *testing.T) {
 	seller := testutil.RandAddr()
 
 	app.SupplyKeeper.SetSupply(app.Ctx, supply.NewSupply(sdk.Coins{}))
-	marketParams := app.MarketKeeper.GetParams(app.Ctx)
-	marketParams.Nominees = []string{nominee.String()}
-	app.MarketKeeper.SetParams(app.Ctx, marketParams)
+	app.GrantRole(authority.RoleMarketAdmin, nominee)
 
 	err := app.SupplyKeeper.MintCoins(app.Ctx, denominations.ModuleName, sdk.NewCoins(sdk.NewCoin("tst1", sdk.NewInt(1000000000000)), sdk.NewCoin("tst2", sdk.NewInt(1000000000000))))
 	require.NoError(t, err)
@@ -36,11 +41,17 @@ func TestKeeper_ExecuteAndCancelExpired(t *testing.T) {
 	require.NoError(t, app.SupplyKeeper.SendCoinsFromModuleToAccount(app.Ctx, denominations.ModuleName, buyer, sdk.NewCoins(sdk.NewCoin("tst2", sdk.NewInt(10000000000)))))
 	require.NoError(t, app.SupplyKeeper.SendCoinsFromModuleToAccount(app.Ctx, denominations.ModuleName, seller, sdk.NewCoins(sdk.NewCoin("tst1", sdk.NewInt(10000000000)))))
 	require.NoError(t, app.SupplyKeeper.SendCoinsFromModuleToAccount(app.Ctx, denominations.ModuleName, seller, sdk.NewCoins(sdk.NewCoin("tst2", sdk.NewInt(10000000000)))))
-	market := types2.NewMsgCreateMarket(nominee, "tst1", "tst2")
-	mkt, err := app.MarketKeeper.CreateMarket(app.Ctx, market.Nominee.String(), market.BaseAsset, market.QuoteAsset)
+	market := types2.NewMsgCreateMarket(nominee, "tst1", "tst2", types2.MatchingModeBatch)
+	mkt, err := app.MarketKeeper.CreateMarket(app.Ctx, market.Nominee.String(), market.MarketParams())
 	require.NoError(t, err)
 
-	_, err = app.OrderKeeper.Post(app.Ctx, buyer, mkt.ID, matcheng.Bid, testutil.ToBaseUnits(1), testutil.ToBaseUnits(1), 100)
+	_, err = app.OrderKeeper.PostOrder(app.Ctx, buyer, ordertypes.OrderParams{
+		MarketID:  mkt.ID,
+		Direction: matcheng.Bid,
+		Price:     testutil.ToBaseUnits(1),
+		Quantity:  testutil.ToBaseUnits(1),
+		Expiry:    ordertypes.NewBlockExpiry(100),
+	})
 	require.NoError(t, err)
 
 	ctx := app.Ctx.WithBlockHeight(602)
@@ -55,11 +66,23 @@ func TestKeeper_ExecuteAndCancelExpired(t *testing.T) {
 		{4, 10},
 	}
 	for _, bid := range bids {
-		_, err = app.OrderKeeper.Post(ctx, buyer, mkt.ID, matcheng.Bid, testutil.ToBaseUnits(bid[0]), testutil.ToBaseUnits(bid[1]), 100)
+		_, err = app.OrderKeeper.PostOrder(ctx, buyer, ordertypes.OrderParams{
+			MarketID:  mkt.ID,
+			Direction: matcheng.Bid,
+			Price:     testutil.ToBaseUnits(bid[0]),
+			Quantity:  testutil.ToBaseUnits(bid[1]),
+			Expiry:    ordertypes.NewBlockExpiry(100),
+		})
 		require.NoError(t, err)
 	}
 	for _, ask := range asks {
-		_, err = app.OrderKeeper.Post(ctx, seller, mkt.ID, matcheng.Ask, testutil.ToBaseUnits(ask[0]), testutil.ToBaseUnits(ask[1]), 100)
+		_, err = app.OrderKeeper.PostOrder(ctx, seller, ordertypes.OrderParams{
+			MarketID:  mkt.ID,
+			Direction: matcheng.Ask,
+			Price:     testutil.ToBaseUnits(ask[0]),
+			Quantity:  testutil.ToBaseUnits(ask[1]),
+			Expiry:    ordertypes.NewBlockExpiry(100),
+		})
 		require.NoError(t, err)
 	}
 	require.NoError(t, app.ExecutionKeeper.ExecuteAndCancelExpired(ctx))
@@ -105,3 +128,1029 @@ func TestKeeper_ExecuteAndCancelExpired(t *testing.T) {
 		testutil.AssertEqualUints(t, testutil.ToBaseUnits(80), buyerAsset2Bal)
 	})
 }
+
+func TestKeeper_ExpiryTypes(t *testing.T) {
+	testflags.UnitTest(t)
+	app, mkt, buyer, _ := setupMarket(t, types2.MatchingModeBatch)
+	now := app.Ctx.BlockHeader().Time
+
+	gtb, err := app.OrderKeeper.PostOrder(app.Ctx, buyer, ordertypes.OrderParams{
+		MarketID:  mkt.ID,
+		Direction: matcheng.Bid,
+		Price:     testutil.ToBaseUnits(1),
+		Quantity:  testutil.ToBaseUnits(1),
+		Expiry:    ordertypes.NewBlockExpiry(10),
+	})
+	require.NoError(t, err)
+	gtt, err := app.OrderKeeper.PostOrder(app.Ctx, buyer, ordertypes.OrderParams{
+		MarketID:  mkt.ID,
+		Direction: matcheng.Bid,
+		Price:     testutil.ToBaseUnits(1),
+		Quantity:  testutil.ToBaseUnits(1),
+		Expiry:    ordertypes.NewTimeExpiry(now.Add(time.Hour).Unix()),
+	})
+	require.NoError(t, err)
+	gtc, err := app.OrderKeeper.PostOrder(app.Ctx, buyer, ordertypes.OrderParams{
+		MarketID:  mkt.ID,
+		Direction: matcheng.Bid,
+		Price:     testutil.ToBaseUnits(1),
+		Quantity:  testutil.ToBaseUnits(1),
+		Expiry:    ordertypes.NewNoExpiry(),
+	})
+	require.NoError(t, err)
+
+	t.Run("rejects good-till-time orders in the past", func(t *testing.T) {
+		_, err := app.OrderKeeper.PostOrder(app.Ctx, buyer, ordertypes.OrderParams{
+			MarketID:  mkt.ID,
+			Direction: matcheng.Bid,
+			Price:     testutil.ToBaseUnits(1),
+			Quantity:  testutil.ToBaseUnits(1),
+			Expiry:    ordertypes.NewTimeExpiry(now.Unix()),
+		})
+		assert.Error(t, err)
+	})
+	t.Run("keeps good-till-blocks orders for their time in force", func(t *testing.T) {
+		ctx := app.Ctx.WithBlockHeight(11)
+		require.NoError(t, app.ExecutionKeeper.ExecuteAndCancelExpired(ctx))
+		assert.True(t, app.OrderKeeper.Has(ctx, gtb.ID))
+	})
+	t.Run("cancels good-till-blocks orders after their time in force", func(t *testing.T) {
+		ctx := app.Ctx.WithBlockHeight(12)
+		require.NoError(t, app.ExecutionKeeper.ExecuteAndCancelExpired(ctx))
+		assert.False(t, app.OrderKeeper.Has(ctx, gtb.ID))
+		assert.True(t, app.OrderKeeper.Has(ctx, gtt.ID))
+	})
+	t.Run("cancels good-till-time orders once their time is reached", func(t *testing.T) {
+		ctx := app.Ctx.WithBlockHeight(13).WithBlockTime(now.Add(time.Hour - time.Second))
+		require.NoError(t, app.ExecutionKeeper.ExecuteAndCancelExpired(ctx))
+		assert.True(t, app.OrderKeeper.Has(ctx, gtt.ID))
+
+		ctx = app.Ctx.WithBlockHeight(14).WithBlockTime(now.Add(time.Hour))
+		require.NoError(t, app.ExecutionKeeper.ExecuteAndCancelExpired(ctx))
+		assert.False(t, app.OrderKeeper.Has(ctx, gtt.ID))
+	})
+	t.Run("never cancels good-till-cancel orders", func(t *testing.T) {
+		ctx := app.Ctx.WithBlockHeight(100000).WithBlockTime(now.Add(24 * 365 * time.Hour))
+		require.NoError(t, app.ExecutionKeeper.ExecuteAndCancelExpired(ctx))
+		assert.True(t, app.OrderKeeper.Has(ctx, gtc.ID))
+		// every order but the good-till-cancel one has been refunded
+		testutil.AssertEqualUints(t, testutil.ToBaseUnits(99), balanceOf(app, buyer, "tst2"))
+	})
+}
+
+func TestKeeper_ExecuteContinuous(t *testing.T) {
+	testflags.UnitTest(t)
+	app, mkt, buyer, seller := setupMarket(t, types2.MatchingModeContinuous)
+
+	ask1, err := app.OrderKeeper.PostOrder(app.Ctx, seller, ordertypes.OrderParams{
+		MarketID:  mkt.ID,
+		Direction: matcheng.Ask,
+		Price:     testutil.ToBaseUnits(2),
+		Quantity:  testutil.ToBaseUnits(10),
+		Expiry:    ordertypes.NewBlockExpiry(100),
+	})
+	require.NoError(t, err)
+	ask2, err := app.OrderKeeper.PostOrder(app.Ctx, seller, ordertypes.OrderParams{
+		MarketID:  mkt.ID,
+		Direction: matcheng.Ask,
+		Price:     testutil.ToBaseUnits(3),
+		Quantity:  testutil.ToBaseUnits(10),
+		Expiry:    ordertypes.NewBlockExpiry(100),
+	})
+	require.NoError(t, err)
+	bid, err := app.OrderKeeper.PostOrder(app.Ctx, buyer, ordertypes.OrderParams{
+		MarketID:  mkt.ID,
+		Direction: matcheng.Bid,
+		Price:     testutil.ToBaseUnits(3),
+		Quantity:  testutil.ToBaseUnits(15),
+		Expiry:    ordertypes.NewBlockExpiry(100),
+	})
+	require.NoError(t, err)
+
+	t.Run("should fill the incoming order at resting prices", func(t *testing.T) {
+		assert.False(t, app.OrderKeeper.Has(app.Ctx, ask1.ID))
+		assert.False(t, app.OrderKeeper.Has(app.Ctx, bid.ID))
+		ord, err := app.OrderKeeper.Get(app.Ctx, ask2.ID)
+		require.NoError(t, err)
+		testutil.AssertEqualUints(t, testutil.ToBaseUnits(5), ord.Quantity)
+	})
+	t.Run("should settle both sides", func(t *testing.T) {
+		// 10 @ 2 + 5 @ 3 = 35
+		testutil.AssertEqualUints(t, testutil.ToBaseUnits(35), balanceOf(app, seller, "tst2"))
+		testutil.AssertEqualUints(t, testutil.ToBaseUnits(15), balanceOf(app, buyer, "tst1"))
+		// escrowed 15 @ 3 = 45, refunded 10 * (3 - 2) = 10
+		testutil.AssertEqualUints(t, testutil.ToBaseUnits(100-35), balanceOf(app, buyer, "tst2"))
+	})
+	t.Run("should not rematch continuous markets at the end of the block", func(t *testing.T) {
+		require.NoError(t, app.ExecutionKeeper.ExecuteAndCancelExpired(app.Ctx))
+		ord, err := app.OrderKeeper.Get(app.Ctx, ask2.ID)
+		require.NoError(t, err)
+		testutil.AssertEqualUints(t, testutil.ToBaseUnits(5), ord.Quantity)
+	})
+}
+
+func TestKeeper_ImmediateOrders(t *testing.T) {
+	testflags.UnitTest(t)
+	for _, mode := range []types2.MatchingMode{types2.MatchingModeBatch, types2.MatchingModeContinuous} {
+		t.Run(mode.String(), func(t *testing.T) {
+			t.Run("ioc orders cancel their unfilled remainder", func(t *testing.T) {
+				app, mkt, buyer, seller := setupMarket(t, mode)
+				_, err := app.OrderKeeper.PostOrder(app.Ctx, seller, ordertypes.OrderParams{
+					MarketID:  mkt.ID,
+					Direction: matcheng.Ask,
+					Price:     testutil.ToBaseUnits(2),
+					Quantity:  testutil.ToBaseUnits(10),
+					Expiry:    ordertypes.NewBlockExpiry(100),
+				})
+				require.NoError(t, err)
+				ioc, err := app.OrderKeeper.PostOrder(app.Ctx, buyer, ordertypes.OrderParams{
+					MarketID:  mkt.ID,
+					Direction: matcheng.Bid,
+					Type:      matcheng.ImmediateOrCancel,
+					Price:     testutil.ToBaseUnits(2),
+					Quantity:  testutil.ToBaseUnits(15),
+					Expiry:    ordertypes.NewBlockExpiry(0),
+				})
+				require.NoError(t, err)
+				require.NoError(t, app.ExecutionKeeper.ExecuteAndCancelExpired(app.Ctx))
+
+				assert.False(t, app.OrderKeeper.Has(app.Ctx, ioc.ID))
+				testutil.AssertEqualUints(t, testutil.ToBaseUnits(10), balanceOf(app, buyer, "tst1"))
+				testutil.AssertEqualUints(t, testutil.ToBaseUnits(80), balanceOf(app, buyer, "tst2"))
+			})
+			t.Run("immediate orders that do not cross are cancelled", func(t *testing.T) {
+				app, mkt, buyer, seller := setupMarket(t, mode)
+				ask, err := app.OrderKeeper.PostOrder(app.Ctx, seller, ordertypes.OrderParams{
+					MarketID:  mkt.ID,
+					Direction: matcheng.Ask,
+					Price:     testutil.ToBaseUnits(3),
+					Quantity:  testutil.ToBaseUnits(10),
+					Expiry:    ordertypes.NewBlockExpiry(100),
+				})
+				require.NoError(t, err)
+				ioc, err := app.OrderKeeper.PostOrder(app.Ctx, buyer, ordertypes.OrderParams{
+					MarketID:  mkt.ID,
+					Direction: matcheng.Bid,
+					Type:      matcheng.ImmediateOrCancel,
+					Price:     testutil.ToBaseUnits(2),
+					Quantity:  testutil.ToBaseUnits(15),
+					Expiry:    ordertypes.NewBlockExpiry(0),
+				})
+				require.NoError(t, err)
+				require.NoError(t, app.ExecutionKeeper.ExecuteAndCancelExpired(app.Ctx))
+
+				assert.False(t, app.OrderKeeper.Has(app.Ctx, ioc.ID))
+				assert.True(t, app.OrderKeeper.Has(app.Ctx, ask.ID))
+				testutil.AssertEqualUints(t, testutil.ToBaseUnits(100), balanceOf(app, buyer, "tst2"))
+			})
+			t.Run("fok orders are cancelled without fills if they cannot fill completely", func(t *testing.T) {
+				app, mkt, buyer, seller := setupMarket(t, mode)
+				ask, err := app.OrderKeeper.PostOrder(app.Ctx, seller, ordertypes.OrderParams{
+					MarketID:  mkt.ID,
+					Direction: matcheng.Ask,
+					Price:     testutil.ToBaseUnits(2),
+					Quantity:  testutil.ToBaseUnits(10),
+					Expiry:    ordertypes.NewBlockExpiry(100),
+				})
+				require.NoError(t, err)
+				fok, err := app.OrderKeeper.PostOrder(app.Ctx, buyer, ordertypes.OrderParams{
+					MarketID:  mkt.ID,
+					Direction: matcheng.Bid,
+					Type:      matcheng.FillOrKill,
+					Price:     testutil.ToBaseUnits(2),
+					Quantity:  testutil.ToBaseUnits(15),
+					Expiry:    ordertypes.NewBlockExpiry(0),
+				})
+				require.NoError(t, err)
+				require.NoError(t, app.ExecutionKeeper.ExecuteAndCancelExpired(app.Ctx))
+
+				assert.False(t, app.OrderKeeper.Has(app.Ctx, fok.ID))
+				assert.True(t, app.OrderKeeper.Has(app.Ctx, ask.ID))
+				assert.True(t, balanceOf(app, buyer, "tst1").IsZero())
+				testutil.AssertEqualUints(t, testutil.ToBaseUnits(100), balanceOf(app, buyer, "tst2"))
+			})
+			t.Run("fok orders fill when there is enough liquidity", func(t *testing.T) {
+				app, mkt, buyer, seller := setupMarket(t, mode)
+				_, err := app.OrderKeeper.PostOrder(app.Ctx, seller, ordertypes.OrderParams{
+					MarketID:  mkt.ID,
+					Direction: matcheng.Ask,
+					Price:     testutil.ToBaseUnits(2),
+					Quantity:  testutil.ToBaseUnits(20),
+					Expiry:    ordertypes.NewBlockExpiry(100),
+				})
+				require.NoError(t, err)
+				fok, err := app.OrderKeeper.PostOrder(app.Ctx, buyer, ordertypes.OrderParams{
+					MarketID:  mkt.ID,
+					Direction: matcheng.Bid,
+					Type:      matcheng.FillOrKill,
+					Price:     testutil.ToBaseUnits(2),
+					Quantity:  testutil.ToBaseUnits(15),
+					Expiry:    ordertypes.NewBlockExpiry(0),
+				})
+				require.NoError(t, err)
+				require.NoError(t, app.ExecutionKeeper.ExecuteAndCancelExpired(app.Ctx))
+
+				assert.False(t, app.OrderKeeper.Has(app.Ctx, fok.ID))
+				testutil.AssertEqualUints(t, testutil.ToBaseUnits(15), balanceOf(app, buyer, "tst1"))
+			})
+		})
+	}
+}
+
+func TestKeeper_StopOrders(t *testing.T) {
+	testflags.UnitTest(t)
+	for _, mode := range []types2.MatchingMode{types2.MatchingModeBatch, types2.MatchingModeContinuous} {
+		t.Run(mode.String(), func(t *testing.T) {
+			app, mkt, buyer, seller := setupMarket(t, mode)
+			stop, err := app.OrderKeeper.PostStop(app.Ctx, buyer, mkt.ID, matcheng.Bid, matcheng.Limit, testutil.ToBaseUnits(2), testutil.ToBaseUnits(3), testutil.ToBaseUnits(5), 100, ordertypes.TriggerClearing)
+			require.NoError(t, err)
+
+			_, err = app.OrderKeeper.PostOrder(app.Ctx, seller, ordertypes.OrderParams{
+				MarketID:  mkt.ID,
+				Direction: matcheng.Ask,
+				Price:     testutil.ToBaseUnits(2),
+				Quantity:  testutil.ToBaseUnits(10),
+				Expiry:    ordertypes.NewBlockExpiry(100),
+			})
+			require.NoError(t, err)
+			_, err = app.OrderKeeper.PostOrder(app.Ctx, buyer, ordertypes.OrderParams{
+				MarketID:  mkt.ID,
+				Direction: matcheng.Bid,
+				Price:     testutil.ToBaseUnits(2),
+				Quantity:  testutil.ToBaseUnits(5),
+				Expiry:    ordertypes.NewBlockExpiry(100),
+			})
+			require.NoError(t, err)
+			require.NoError(t, app.ExecutionKeeper.ExecuteAndCancelExpired(app.Ctx))
+
+			last, ok := app.MarketKeeper.LastPrice(app.Ctx, mkt.ID)
+			require.True(t, ok)
+			testutil.AssertEqualUints(t, testutil.ToBaseUnits(2), last)
+
+			app.OrderKeeper.ProcessTriggers(app.Ctx)
+			assert.False(t, app.OrderKeeper.HasTrigger(app.Ctx, stop.ID))
+			require.NoError(t, app.ExecutionKeeper.ExecuteAndCancelExpired(app.Ctx))
+			testutil.AssertEqualUints(t, testutil.ToBaseUnits(10), balanceOf(app, buyer, "tst1"))
+		})
+	}
+}
+
+func TestKeeper_Settlement(t *testing.T) {
+	testflags.UnitTest(t)
+	for _, mode := range []types2.MatchingMode{types2.MatchingModeBatch, types2.MatchingModeContinuous} {
+		t.Run(mode.String(), func(t *testing.T) {
+			app, mkt, buyer, seller := setupMarket(t, mode)
+			supplyBefore := app.SupplyKeeper.GetSupply(app.Ctx).GetTotal()
+
+			price := sdk.NewUint(123456789)
+			for _, qty := range []uint64{33333333, 7, 100000001} {
+				_, err := app.OrderKeeper.PostOrder(app.Ctx, seller, ordertypes.OrderParams{
+					MarketID:  mkt.ID,
+					Direction: matcheng.Ask,
+					Price:     price,
+					Quantity:  sdk.NewUint(qty),
+					Expiry:    ordertypes.NewBlockExpiry(100),
+				})
+				require.NoError(t, err)
+			}
+			for _, qty := range []uint64{3, 66666667, 50000000} {
+				_, err := app.OrderKeeper.PostOrder(app.Ctx, buyer, ordertypes.OrderParams{
+					MarketID:  mkt.ID,
+					Direction: matcheng.Bid,
+					Price:     price.Add(sdk.NewUint(qty)),
+					Quantity:  sdk.NewUint(qty),
+					Expiry:    ordertypes.NewBlockExpiry(100),
+				})
+				require.NoError(t, err)
+			}
+			require.NoError(t, app.ExecutionKeeper.ExecuteAndCancelExpired(app.Ctx))
+
+			assertInvariants(t, app)
+			moduleCoins := app.SupplyKeeper.GetModuleAccount(app.Ctx, order.ModuleName).GetCoins()
+			for _, denom := range []string{"tst1", "tst2"} {
+				total := balanceOf(app, buyer, denom).Add(balanceOf(app, seller, denom)).Add(sdk.NewUintFromBigInt(moduleCoins.AmountOf(denom).BigInt()))
+				testutil.AssertEqualUints(t, testutil.ToBaseUnits(100), total)
+			}
+			assert.True(t, supplyBefore.IsEqual(app.SupplyKeeper.GetSupply(app.Ctx).GetTotal()))
+
+			var open []uexstore.EntityID
+			app.OrderKeeper.Iterator(app.Ctx, func(ord ordertypes.Order) bool {
+				open = append(open, ord.ID)
+				return true
+			})
+			require.NotEmpty(t, open)
+			for _, id := range open {
+				require.NoError(t, app.OrderKeeper.Cancel(app.Ctx, id))
+			}
+			assertInvariants(t, app)
+			moduleCoins = app.SupplyKeeper.GetModuleAccount(app.Ctx, order.ModuleName).GetCoins()
+			assert.True(t, moduleCoins.IsEqual(app.OrderKeeper.GetDust(app.Ctx)))
+		})
+	}
+}
+
+func TestKeeper_UnrepresentableFills(t *testing.T) {
+	testflags.UnitTest(t)
+	for _, mode := range []types2.MatchingMode{types2.MatchingModeBatch, types2.MatchingModeContinuous} {
+		t.Run(mode.String(), func(t *testing.T) {
+			app, mkt, buyer, seller := setupMarket(t, mode)
+
+			// one base unit is worth half a unit of the quote asset
+			price := sdk.NewUint(50000000)
+			post := func(owner sdk.AccAddress, direction matcheng.Direction, qty uint64) ordertypes.Order {
+				ord, err := app.OrderKeeper.PostOrder(app.Ctx, owner, ordertypes.OrderParams{
+					MarketID:  mkt.ID,
+					Direction: direction,
+					Price:     price,
+					Quantity:  sdk.NewUint(qty),
+					Expiry:    ordertypes.NewBlockExpiry(100),
+				})
+				require.NoError(t, err)
+				return ord
+			}
+			ask := post(seller, matcheng.Ask, 99999999)
+			tiny := post(seller, matcheng.Ask, 1)
+			bid := post(buyer, matcheng.Bid, 100000000)
+			require.NoError(t, app.ExecutionKeeper.ExecuteAndCancelExpired(app.Ctx))
+
+			assert.False(t, app.OrderKeeper.Has(app.Ctx, ask.ID))
+			got, err := app.OrderKeeper.Get(app.Ctx, tiny.ID)
+			require.NoError(t, err)
+			testutil.AssertEqualUints(t, sdk.NewUint(1), got.Quantity)
+			// the bid's remainder of one unit could never be filled, so it
+			// is cancelled and the escrow it held beyond its fill refunded.
+			assert.False(t, app.OrderKeeper.Has(app.Ctx, bid.ID))
+			testutil.AssertEqualUints(t, sdk.NewUint(99999999), balanceOf(app, buyer, "tst1"))
+			testutil.AssertEqualUints(t, sdk.NewUint(10000000000-49999999), balanceOf(app, buyer, "tst2"))
+			testutil.AssertEqualUints(t, sdk.NewUint(49999999), balanceOf(app, seller, "tst2"))
+			assert.True(t, app.OrderKeeper.GetDust(app.Ctx).IsZero())
+			assertInvariants(t, app)
+		})
+	}
+}
+
+func TestKeeper_Events(t *testing.T) {
+	testflags.UnitTest(t)
+	app, mkt, buyer, seller := setupMarket(t, types2.MatchingModeBatch)
+	ask, err := app.OrderKeeper.PostOrder(app.Ctx, seller, ordertypes.OrderParams{
+		MarketID:  mkt.ID,
+		Direction: matcheng.Ask,
+		Price:     testutil.ToBaseUnits(2),
+		Quantity:  testutil.ToBaseUnits(10),
+		Expiry:    ordertypes.NewBlockExpiry(100),
+	})
+	require.NoError(t, err)
+	bid, err := app.OrderKeeper.PostOrder(app.Ctx, buyer, ordertypes.OrderParams{
+		MarketID:  mkt.ID,
+		Direction: matcheng.Bid,
+		Price:     testutil.ToBaseUnits(2),
+		Quantity:  testutil.ToBaseUnits(4),
+		Expiry:    ordertypes.NewBlockExpiry(100),
+	})
+	require.NoError(t, err)
+
+	ctx := app.Ctx.WithEventManager(sdk.NewEventManager())
+	require.NoError(t, app.ExecutionKeeper.ExecuteAndCancelExpired(ctx))
+	events := make(map[string][]sdk.Attribute)
+	for _, ev := range sdk.StringifyEvents(ctx.EventManager().ABCIEvents()) {
+		events[ev.Type] = ev.Attributes
+	}
+
+	assert.Equal(t, []sdk.Attribute{
+		{Key: ordertypes.AttributeKeyMarketID, Value: mkt.ID.String()},
+		{Key: ordertypes.AttributeKeyClearingPrice, Value: testutil.ToBaseUnits(2).String()},
+	}, events[ordertypes.EventTypeClearBatch])
+	fills := events[ordertypes.EventTypeFillOrder]
+	require.Len(t, fills, 18)
+	filled := make(map[string]string)
+	for i := 0; i < len(fills); i += 9 {
+		assert.Equal(t, ordertypes.AttributeKeyOrderID, fills[i].Key)
+		assert.Equal(t, ordertypes.AttributeKeyQuantityFilled, fills[i+5].Key)
+		assert.Equal(t, ordertypes.AttributeKeyQuantityUnfilled, fills[i+6].Key)
+		filled[fills[i].Value] = fills[i+5].Value + "/" + fills[i+6].Value
+	}
+	assert.Equal(t, map[string]string{
+		ask.ID.String(): testutil.ToBaseUnits(4).String() + "/" + testutil.ToBaseUnits(6).String(),
+		bid.ID.String(): testutil.ToBaseUnits(4).String() + "/0",
+	}, filled)
+}
+
+func assertInvariants(t *testing.T, app *mockapp.MockApp) {
+	msg, broken := order.AllInvariants(app.OrderKeeper)(app.Ctx)
+	assert.False(t, broken, msg)
+	msg, broken = supply.AllInvariants(app.SupplyKeeper)(app.Ctx)
+	assert.False(t, broken, msg)
+}
+
+func TestKeeper_Fees(t *testing.T) {
+	testflags.UnitTest(t)
+	feeBalance := func(app *mockapp.MockApp, denom string) sdk.Uint {
+		coins := app.SupplyKeeper.GetModuleAccount(app.Ctx, auth.FeeCollectorName).GetCoins()
+		return sdk.NewUintFromBigInt(coins.AmountOf(denom).BigInt())
+	}
+
+	t.Run("fees are deducted from the received asset", func(t *testing.T) {
+		app, mkt, buyer, seller := setupMarketWithFees(t, types2.MatchingModeContinuous, types2.NewFeeSchedule(10, 20, "", ""))
+		_, err := app.OrderKeeper.PostOrder(app.Ctx, seller, ordertypes.OrderParams{
+			MarketID:  mkt.ID,
+			Direction: matcheng.Ask,
+			Price:     testutil.ToBaseUnits(2),
+			Quantity:  testutil.ToBaseUnits(10),
+			Expiry:    ordertypes.NewBlockExpiry(100),
+		})
+		require.NoError(t, err)
+		_, err = app.OrderKeeper.PostOrder(app.Ctx, buyer, ordertypes.OrderParams{
+			MarketID:  mkt.ID,
+			Direction: matcheng.Bid,
+			Price:     testutil.ToBaseUnits(2),
+			Quantity:  testutil.ToBaseUnits(10),
+			Expiry:    ordertypes.NewBlockExpiry(100),
+		})
+		require.NoError(t, err)
+
+		testutil.AssertEqualUints(t, sdk.NewUint(998000000), balanceOf(app, buyer, "tst1"))
+		testutil.AssertEqualUints(t, sdk.NewUint(1998000000), balanceOf(app, seller, "tst2"))
+		testutil.AssertEqualUints(t, sdk.NewUint(2000000), feeBalance(app, "tst1"))
+		testutil.AssertEqualUints(t, sdk.NewUint(2000000), feeBalance(app, "tst2"))
+		assertInvariants(t, app)
+	})
+
+	t.Run("bids owed no quote asset pay quote asset fees in the received asset", func(t *testing.T) {
+		app, mkt, buyer, seller := setupMarketWithFees(t, types2.MatchingModeBatch, types2.NewFeeSchedule(10, 20, "tst2", ""))
+		_, err := app.OrderKeeper.PostOrder(app.Ctx, seller, ordertypes.OrderParams{
+			MarketID:  mkt.ID,
+			Direction: matcheng.Ask,
+			Price:     testutil.ToBaseUnits(2),
+			Quantity:  testutil.ToBaseUnits(10),
+			Expiry:    ordertypes.NewBlockExpiry(100),
+		})
+		require.NoError(t, err)
+		ctx := app.Ctx.WithBlockHeight(app.Ctx.BlockHeight() + 1)
+		_, err = app.OrderKeeper.PostOrder(ctx, buyer, ordertypes.OrderParams{
+			MarketID:  mkt.ID,
+			Direction: matcheng.Bid,
+			Price:     testutil.ToBaseUnits(2),
+			Quantity:  testutil.ToBaseUnits(10),
+			Expiry:    ordertypes.NewBlockExpiry(100),
+		})
+		require.NoError(t, err)
+		require.NoError(t, app.ExecutionKeeper.ExecuteAndCancelExpired(ctx))
+
+		// the resting ask is the maker and the bid posted in this block the
+		// taker. The bid is owed no quote asset, so its fee is taken from the
+		// base asset it receives rather than from its balance.
+		testutil.AssertEqualUints(t, sdk.NewUint(998000000), balanceOf(app, buyer, "tst1"))
+		testutil.AssertEqualUints(t, sdk.NewUint(8000000000), balanceOf(app, buyer, "tst2"))
+		testutil.AssertEqualUints(t, sdk.NewUint(1998000000), balanceOf(app, seller, "tst2"))
+		testutil.AssertEqualUints(t, sdk.NewUint(2000000), feeBalance(app, "tst1"))
+		testutil.AssertEqualUints(t, sdk.NewUint(2000000), feeBalance(app, "tst2"))
+		assertInvariants(t, app)
+	})
+}
+
+func TestKeeper_SelfTradePrevention(t *testing.T) {
+	testflags.UnitTest(t)
+	for _, mode := range []types2.MatchingMode{types2.MatchingModeBatch, types2.MatchingModeContinuous} {
+		t.Run(mode.String(), func(t *testing.T) {
+			t.Run("decrement and cancel", func(t *testing.T) {
+				app, mkt, trader, seller := setupMarket(t, mode)
+				require.NoError(t, app.SupplyKeeper.SendCoinsFromModuleToAccount(app.Ctx, denominations.ModuleName, trader, sdk.NewCoins(sdk.NewCoin("tst1", sdk.NewInt(10000000000)))))
+				price := testutil.ToBaseUnits(1)
+
+				_, err := app.OrderKeeper.PostOrder(app.Ctx, seller, ordertypes.OrderParams{
+					MarketID:  mkt.ID,
+					Direction: matcheng.Ask,
+					Price:     price,
+					Quantity:  testutil.ToBaseUnits(4),
+					Expiry:    ordertypes.NewBlockExpiry(100),
+				})
+				require.NoError(t, err)
+				_, err = app.OrderKeeper.PostOrder(app.Ctx, trader, ordertypes.OrderParams{
+					MarketID:  mkt.ID,
+					Direction: matcheng.Ask,
+					Price:     price,
+					Quantity:  testutil.ToBaseUnits(6),
+					Expiry:    ordertypes.NewBlockExpiry(100),
+				})
+				require.NoError(t, err)
+				_, err = app.OrderKeeper.PostOrder(app.Ctx, trader, ordertypes.OrderParams{
+					MarketID:  mkt.ID,
+					Direction: matcheng.Bid,
+					Price:     price,
+					Quantity:  testutil.ToBaseUnits(10),
+					Expiry:    ordertypes.NewBlockExpiry(100),
+					STPMode:   matcheng.DecrementAndCancel,
+				})
+				require.NoError(t, err)
+				require.NoError(t, app.ExecutionKeeper.ExecuteAndCancelExpired(app.Ctx))
+
+				// the bid trades 4 with the seller and the remaining 6 are
+				// cancelled on both sides of the trader's book
+				assertInvariants(t, app)
+				app.OrderKeeper.Iterator(app.Ctx, func(ord ordertypes.Order) bool {
+					t.Errorf("order %s is still open", ord.ID)
+					return true
+				})
+				testutil.AssertEqualUints(t, testutil.ToBaseUnits(104), balanceOf(app, trader, "tst1"))
+				testutil.AssertEqualUints(t, testutil.ToBaseUnits(96), balanceOf(app, trader, "tst2"))
+				testutil.AssertEqualUints(t, testutil.ToBaseUnits(4), balanceOf(app, seller, "tst2"))
+			})
+
+			t.Run("cancel newest", func(t *testing.T) {
+				app, mkt, trader, _ := setupMarket(t, mode)
+				require.NoError(t, app.SupplyKeeper.SendCoinsFromModuleToAccount(app.Ctx, denominations.ModuleName, trader, sdk.NewCoins(sdk.NewCoin("tst1", sdk.NewInt(10000000000)))))
+
+				ask, err := app.OrderKeeper.PostOrder(app.Ctx, trader, ordertypes.OrderParams{
+					MarketID:  mkt.ID,
+					Direction: matcheng.Ask,
+					Price:     testutil.ToBaseUnits(1),
+					Quantity:  testutil.ToBaseUnits(6),
+					Expiry:    ordertypes.NewBlockExpiry(100),
+				})
+				require.NoError(t, err)
+				bid, err := app.OrderKeeper.PostOrder(app.Ctx, trader, ordertypes.OrderParams{
+					MarketID:  mkt.ID,
+					Direction: matcheng.Bid,
+					Price:     testutil.ToBaseUnits(2),
+					Quantity:  testutil.ToBaseUnits(6),
+					Expiry:    ordertypes.NewBlockExpiry(100),
+				})
+				require.NoError(t, err)
+				require.NoError(t, app.ExecutionKeeper.ExecuteAndCancelExpired(app.Ctx))
+
+				assertInvariants(t, app)
+				assert.True(t, app.OrderKeeper.Has(app.Ctx, ask.ID))
+				assert.False(t, app.OrderKeeper.Has(app.Ctx, bid.ID))
+				testutil.AssertEqualUints(t, testutil.ToBaseUnits(100), balanceOf(app, trader, "tst2"))
+			})
+
+			t.Run("cancel oldest iceberg", func(t *testing.T) {
+				app, mkt, trader, _ := setupMarket(t, mode)
+				require.NoError(t, app.SupplyKeeper.SendCoinsFromModuleToAccount(app.Ctx, denominations.ModuleName, trader, sdk.NewCoins(sdk.NewCoin("tst1", sdk.NewInt(10000000000)))))
+
+				ask, err := app.OrderKeeper.PostOrder(app.Ctx, trader, ordertypes.OrderParams{
+					MarketID:        mkt.ID,
+					Direction:       matcheng.Ask,
+					Price:           testutil.ToBaseUnits(1),
+					Quantity:        testutil.ToBaseUnits(6),
+					DisplayQuantity: testutil.ToBaseUnits(2),
+					Expiry:          ordertypes.NewBlockExpiry(100),
+				})
+				require.NoError(t, err)
+				bid, err := app.OrderKeeper.PostOrder(app.Ctx, trader, ordertypes.OrderParams{
+					MarketID:  mkt.ID,
+					Direction: matcheng.Bid,
+					Price:     testutil.ToBaseUnits(1),
+					Quantity:  testutil.ToBaseUnits(6),
+					Expiry:    ordertypes.NewBlockExpiry(100),
+					STPMode:   matcheng.CancelOldest,
+				})
+				require.NoError(t, err)
+				require.NoError(t, app.ExecutionKeeper.ExecuteAndCancelExpired(app.Ctx))
+
+				// the hidden reserve is cancelled along with the visible
+				// slice, so none of the iceberg is left to self-trade later
+				assertInvariants(t, app)
+				assert.False(t, app.OrderKeeper.Has(app.Ctx, ask.ID))
+				assert.True(t, app.OrderKeeper.Has(app.Ctx, bid.ID))
+				testutil.AssertEqualUints(t, testutil.ToBaseUnits(100), balanceOf(app, trader, "tst1"))
+			})
+		})
+	}
+}
+
+func TestKeeper_OrderRecords(t *testing.T) {
+	testflags.UnitTest(t)
+	app, mkt, buyer, seller := setupMarket(t, types2.MatchingModeBatch)
+	price := testutil.ToBaseUnits(1)
+
+	ask, err := app.OrderKeeper.PostOrder(app.Ctx, seller, ordertypes.OrderParams{
+		MarketID:  mkt.ID,
+		Direction: matcheng.Ask,
+		Price:     price,
+		Quantity:  testutil.ToBaseUnits(4),
+		Expiry:    ordertypes.NewBlockExpiry(100),
+	})
+	require.NoError(t, err)
+	bid, err := app.OrderKeeper.PostOrder(app.Ctx, buyer, ordertypes.OrderParams{
+		MarketID:  mkt.ID,
+		Direction: matcheng.Bid,
+		Price:     price,
+		Quantity:  testutil.ToBaseUnits(10),
+		Expiry:    ordertypes.NewBlockExpiry(100),
+	})
+	require.NoError(t, err)
+	fok, err := app.OrderKeeper.PostOrder(app.Ctx, buyer, ordertypes.OrderParams{
+		MarketID:  mkt.ID,
+		Direction: matcheng.Bid,
+		Type:      matcheng.FillOrKill,
+		Price:     price,
+		Quantity:  testutil.ToBaseUnits(1),
+		Expiry:    ordertypes.NewBlockExpiry(100),
+	})
+	require.NoError(t, err)
+	require.NoError(t, app.ExecutionKeeper.ExecuteAndCancelExpired(app.Ctx))
+
+	rec, err := app.OrderKeeper.Record(app.Ctx, ask.ID)
+	require.NoError(t, err)
+	assert.Equal(t, ordertypes.Filled, rec.Status)
+	testutil.AssertEqualUints(t, testutil.ToBaseUnits(4), rec.QuantityFilled)
+	testutil.AssertEqualUints(t, price, rec.AvgPrice)
+	assert.Equal(t, app.Ctx.BlockHeight(), rec.ClosedBlock)
+
+	rec, err = app.OrderKeeper.Record(app.Ctx, bid.ID)
+	require.NoError(t, err)
+	assert.Equal(t, ordertypes.PartiallyFilled, rec.Status)
+	testutil.AssertEqualUints(t, testutil.ToBaseUnits(10), rec.Quantity)
+	testutil.AssertEqualUints(t, testutil.ToBaseUnits(4), rec.QuantityFilled)
+
+	rec, err = app.OrderKeeper.Record(app.Ctx, fok.ID)
+	require.NoError(t, err)
+	assert.Equal(t, ordertypes.Rejected, rec.Status)
+	testutil.AssertEqualUints(t, sdk.ZeroUint(), rec.QuantityFilled)
+
+	require.NoError(t, app.OrderKeeper.Cancel(app.Ctx, bid.ID))
+	rec, err = app.OrderKeeper.Record(app.Ctx, bid.ID)
+	require.NoError(t, err)
+	assert.Equal(t, ordertypes.Cancelled, rec.Status)
+	testutil.AssertEqualUints(t, testutil.ToBaseUnits(4), rec.QuantityFilled)
+	testutil.AssertEqualUints(t, price, rec.AvgPrice)
+	assertInvariants(t, app)
+}
+
+func TestKeeper_PriceBand(t *testing.T) {
+	testflags.UnitTest(t)
+	app, mkt, buyer, seller := setupMarketWithBand(t, types2.MatchingModeBatch, types2.FeeSchedule{}, types2.NewPriceBand(1000, 2, 5))
+	trade := func(ctx sdk.Context, price uint64) (ordertypes.Order, ordertypes.Order) {
+		ask, err := app.OrderKeeper.PostOrder(ctx, seller, ordertypes.OrderParams{
+			MarketID:  mkt.ID,
+			Direction: matcheng.Ask,
+			Price:     sdk.NewUint(price),
+			Quantity:  testutil.ToBaseUnits(1),
+			Expiry:    ordertypes.NewBlockExpiry(100),
+		})
+		require.NoError(t, err)
+		bid, err := app.OrderKeeper.PostOrder(ctx, buyer, ordertypes.OrderParams{
+			MarketID:  mkt.ID,
+			Direction: matcheng.Bid,
+			Price:     sdk.NewUint(price),
+			Quantity:  testutil.ToBaseUnits(1),
+			Expiry:    ordertypes.NewBlockExpiry(100),
+		})
+		require.NoError(t, err)
+		require.NoError(t, app.ExecutionKeeper.ExecuteAndCancelExpired(ctx))
+		return ask, bid
+	}
+
+	// the first batch has no reference price
+	ask, _ := trade(app.Ctx, 100000000)
+	assert.False(t, app.OrderKeeper.Has(app.Ctx, ask.ID))
+
+	// a batch 20% above the last price is skipped and its orders carried
+	// over, until the second breach halts the market
+	ask, bid := trade(app.Ctx, 120000000)
+	assert.True(t, app.OrderKeeper.Has(app.Ctx, ask.ID))
+	assert.True(t, app.OrderKeeper.Has(app.Ctx, bid.ID))
+	assert.Equal(t, uint16(1), app.MarketKeeper.GetCircuitBreaker(app.Ctx, mkt.ID).Breaches)
+	require.NoError(t, app.ExecutionKeeper.ExecuteAndCancelExpired(app.Ctx))
+	breaker := app.MarketKeeper.GetCircuitBreaker(app.Ctx, mkt.ID)
+	assert.Equal(t, app.Ctx.BlockHeight()+6, breaker.HaltedUntil)
+
+	// halted markets accept no orders and are not matched, but their
+	// orders can still be cancelled
+	ctx := app.Ctx.WithBlockHeight(breaker.HaltedUntil - 1)
+	assert.True(t, app.MarketKeeper.IsHalted(ctx, mkt.ID))
+	_, err := app.OrderKeeper.PostOrder(ctx, seller, ordertypes.OrderParams{
+		MarketID:  mkt.ID,
+		Direction: matcheng.Ask,
+		Price:     sdk.NewUint(105000000),
+		Quantity:  testutil.ToBaseUnits(1),
+		Expiry:    ordertypes.NewBlockExpiry(100),
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "halted")
+	require.NoError(t, app.ExecutionKeeper.ExecuteAndCancelExpired(ctx))
+	assert.True(t, app.OrderKeeper.Has(ctx, ask.ID))
+	require.NoError(t, app.OrderKeeper.Cancel(ctx, ask.ID))
+	require.NoError(t, app.OrderKeeper.Cancel(ctx, bid.ID))
+
+	ctx = app.Ctx.WithBlockHeight(breaker.HaltedUntil)
+	assert.False(t, app.MarketKeeper.IsHalted(ctx, mkt.ID))
+	ask, _ = trade(ctx, 105000000)
+	assert.False(t, app.OrderKeeper.Has(ctx, ask.ID))
+	last, _ := app.MarketKeeper.LastPrice(ctx, mkt.ID)
+	testutil.AssertEqualUints(t, sdk.NewUint(105000000), last)
+	assertInvariants(t, app)
+}
+
+func TestKeeper_PriceBandContinuous(t *testing.T) {
+	testflags.UnitTest(t)
+	app, mkt, buyer, seller := setupMarketWithBand(t, types2.MatchingModeContinuous, types2.FeeSchedule{}, types2.NewPriceBand(1000, 1, 5))
+	post := func(ctx sdk.Context, owner sdk.AccAddress, direction matcheng.Direction, typ matcheng.OrderType, price uint64, qty uint64) ordertypes.Order {
+		ord, err := app.OrderKeeper.PostOrder(ctx, owner, ordertypes.OrderParams{
+			MarketID:  mkt.ID,
+			Direction: direction,
+			Type:      typ,
+			Price:     sdk.NewUint(price),
+			Quantity:  testutil.ToBaseUnits(qty),
+			Expiry:    ordertypes.NewBlockExpiry(100),
+		})
+		require.NoError(t, err)
+		return ord
+	}
+	app.MarketKeeper.SetLastPrice(app.Ctx, mkt.ID, sdk.NewUint(100000000))
+	post(app.Ctx, seller, matcheng.Ask, matcheng.Limit, 100000000, 1)
+	post(app.Ctx, seller, matcheng.Ask, matcheng.Limit, 105000000, 1)
+	far := post(app.Ctx, seller, matcheng.Ask, matcheng.Limit, 120000000, 1)
+
+	// the trade 20% above the last price breaches the band, which halts
+	// the market and cancels the rest of the bid instead of resting it
+	// in a crossed book
+	bid := post(app.Ctx, buyer, matcheng.Bid, matcheng.Limit, 120000000, 3)
+	assert.False(t, app.OrderKeeper.Has(app.Ctx, bid.ID))
+	assert.True(t, app.OrderKeeper.Has(app.Ctx, far.ID))
+	testutil.AssertEqualUints(t, testutil.ToBaseUnits(2), balanceOf(app, buyer, "tst1"))
+	last, _ := app.MarketKeeper.LastPrice(app.Ctx, mkt.ID)
+	testutil.AssertEqualUints(t, sdk.NewUint(105000000), last)
+	assert.True(t, app.MarketKeeper.IsHalted(app.Ctx.WithBlockHeight(app.Ctx.BlockHeight()+1), mkt.ID))
+
+	// fill-or-kill orders are killed before any of their trades execute
+	ctx := app.Ctx.WithBlockHeight(app.MarketKeeper.GetCircuitBreaker(app.Ctx, mkt.ID).HaltedUntil)
+	near := post(ctx, seller, matcheng.Ask, matcheng.Limit, 110000000, 1)
+	fok := post(ctx, buyer, matcheng.Bid, matcheng.FillOrKill, 120000000, 2)
+	assert.False(t, app.OrderKeeper.Has(ctx, fok.ID))
+	assert.True(t, app.OrderKeeper.Has(ctx, near.ID))
+	assert.True(t, app.OrderKeeper.Has(ctx, far.ID))
+	testutil.AssertEqualUints(t, testutil.ToBaseUnits(2), balanceOf(app, buyer, "tst1"))
+	assertInvariants(t, app)
+}
+
+func TestKeeper_MaxBatchOrders(t *testing.T) {
+	testflags.UnitTest(t)
+	app, mkt, buyer, seller := setupMarket(t, types2.MatchingModeBatch)
+	app.OrderKeeper.SetParams(app.Ctx, order.NewParams(0, 0, sdk.NewCoins(), 4, 0))
+
+	var bids, asks []uexstore.EntityID
+	for i := 0; i < 5; i++ {
+		bid, err := app.OrderKeeper.PostOrder(app.Ctx, buyer, ordertypes.OrderParams{
+			MarketID:  mkt.ID,
+			Direction: matcheng.Bid,
+			Price:     sdk.NewUint(100000000),
+			Quantity:  testutil.ToBaseUnits(1),
+			Expiry:    ordertypes.NewBlockExpiry(100),
+		})
+		require.NoError(t, err)
+		bids = append(bids, bid.ID)
+		ask, err := app.OrderKeeper.PostOrder(app.Ctx, seller, ordertypes.OrderParams{
+			MarketID:  mkt.ID,
+			Direction: matcheng.Ask,
+			Price:     sdk.NewUint(100000000),
+			Quantity:  testutil.ToBaseUnits(1),
+			Expiry:    ordertypes.NewBlockExpiry(100),
+		})
+		require.NoError(t, err)
+		asks = append(asks, ask.ID)
+	}
+
+	// every batch matches the two oldest bids and asks, the others carry
+	// over in time priority
+	for batch, open := range []int{2, 4, 5} {
+		ctx := app.Ctx.WithBlockHeight(app.Ctx.BlockHeight() + int64(batch))
+		require.NoError(t, app.ExecutionKeeper.ExecuteAndCancelExpired(ctx))
+		for i := 0; i < 5; i++ {
+			assert.Equal(t, i >= open, app.OrderKeeper.Has(ctx, bids[i]), "batch %d bid %d", batch, i)
+			assert.Equal(t, i >= open, app.OrderKeeper.Has(ctx, asks[i]), "batch %d ask %d", batch, i)
+		}
+	}
+	assertInvariants(t, app)
+}
+
+func TestKeeper_IcebergOrders(t *testing.T) {
+	testflags.UnitTest(t)
+	app, mkt, buyer, seller := setupMarket(t, types2.MatchingModeBatch)
+	price := sdk.NewUint(100000000)
+	ask, err := app.OrderKeeper.PostOrder(app.Ctx, seller, ordertypes.OrderParams{
+		MarketID:        mkt.ID,
+		Direction:       matcheng.Ask,
+		Price:           price,
+		Quantity:        testutil.ToBaseUnits(5),
+		DisplayQuantity: testutil.ToBaseUnits(2),
+		Expiry:          ordertypes.NewBlockExpiry(100),
+	})
+	require.NoError(t, err)
+	for i := 0; i < 5; i++ {
+		_, err := app.OrderKeeper.PostOrder(app.Ctx, buyer, ordertypes.OrderParams{
+			MarketID:  mkt.ID,
+			Direction: matcheng.Bid,
+			Price:     price,
+			Quantity:  testutil.ToBaseUnits(1),
+			Expiry:    ordertypes.NewBlockExpiry(100),
+		})
+		require.NoError(t, err)
+	}
+
+	// every batch fills one visible slice, which is then refilled from the
+	// reserve
+	queue := &recordingBackend{}
+	keeper := execution.NewKeeper(queue, app.MarketKeeper, app.OrderKeeper, app.SupplyKeeper)
+	for batch, left := range []uint64{3, 1, 0} {
+		ctx := app.Ctx.WithBlockHeight(app.Ctx.BlockHeight() + int64(batch))
+		require.NoError(t, keeper.ExecuteAndCancelExpired(ctx))
+		ord, err := app.OrderKeeper.Get(ctx, ask.ID)
+		if left == 0 {
+			require.Error(t, err)
+			continue
+		}
+		require.NoError(t, err)
+		testutil.AssertEqualUints(t, testutil.ToBaseUnits(left), ord.Quantity)
+	}
+
+	// batch aggregates only report the visible slices
+	var batches int
+	for _, ev := range queue.events {
+		b, ok := ev.(types.Batch)
+		if !ok {
+			continue
+		}
+		batches++
+		for _, agg := range b.Asks {
+			assert.False(t, agg[1].GT(testutil.ToBaseUnits(2)), "batch %d shows %s", b.BlockNumber, agg[1])
+		}
+	}
+	assert.Equal(t, 3, batches)
+	assertInvariants(t, app)
+}
+
+func TestKeeper_ParallelMatching(t *testing.T) {
+	testflags.UnitTest(t)
+	app, first, buyer, seller := setupMarket(t, types2.MatchingModeBatch)
+	nominee := testutil.RandAddr()
+	app.GrantRole(authority.RoleMarketAdmin, nominee)
+	markets := []types2.Market{first}
+	for i := 0; i < 20; i++ {
+		mkt, err := app.MarketKeeper.CreateMarket(app.Ctx, nominee.String(), types2.MarketParams{
+			BaseAsset:    "tst1",
+			QuoteAsset:   "tst2",
+			MatchingMode: types2.MatchingModeBatch,
+		})
+		require.NoError(t, err)
+		markets = append(markets, mkt)
+	}
+	// orders are posted to the markets in reverse
+	for i := len(markets) - 1; i >= 0; i-- {
+		price := sdk.NewUint(uint64(i+1) * 10000000)
+		_, err := app.OrderKeeper.PostOrder(app.Ctx, seller, ordertypes.OrderParams{
+			MarketID:  markets[i].ID,
+			Direction: matcheng.Ask,
+			Price:     price,
+			Quantity:  testutil.ToBaseUnits(1),
+			Expiry:    ordertypes.NewBlockExpiry(100),
+		})
+		require.NoError(t, err)
+		_, err = app.OrderKeeper.PostOrder(app.Ctx, buyer, ordertypes.OrderParams{
+			MarketID:  markets[i].ID,
+			Direction: matcheng.Bid,
+			Price:     price,
+			Quantity:  testutil.ToBaseUnits(1),
+			Expiry:    ordertypes.NewBlockExpiry(100),
+		})
+		require.NoError(t, err)
+	}
+
+	queue := &recordingBackend{}
+	keeper := execution.NewKeeper(queue, app.MarketKeeper, app.OrderKeeper, app.SupplyKeeper)
+	require.NoError(t, keeper.ExecuteAndCancelExpired(app.Ctx))
+
+	// every market publishes its batch and then its fills, in market ID order
+	var expected, published []string
+	for i, mkt := range markets {
+		expected = append(expected, "batch "+mkt.ID.String(), "fill "+mkt.ID.String(), "fill "+mkt.ID.String())
+		last, ok := app.MarketKeeper.LastPrice(app.Ctx, mkt.ID)
+		require.True(t, ok)
+		testutil.AssertEqualUints(t, sdk.NewUint(uint64(i+1)*10000000), last)
+	}
+	for _, ev := range queue.events {
+		switch e := ev.(type) {
+		case types.Batch:
+			published = append(published, "batch "+e.MarketID.String())
+		case types.Fill:
+			published = append(published, "fill "+e.MarketID.String())
+		}
+	}
+	assert.Equal(t, expected, published)
+	assertInvariants(t, app)
+}
+
+// recordingBackend keeps published events in order instead of holding
+// them back until commit.
+type recordingBackend struct {
+	events []interface{}
+}
+
+func (r *recordingBackend) Publish(ctx sdk.Context, ev types.Event) {
+	r.events = append(r.events, ev)
+}
+
+func TestKeeper_MarketLifecycle(t *testing.T) {
+	testflags.UnitTest(t)
+	app, mkt, buyer, seller := setupMarket(t, types2.MatchingModeBatch)
+	price := testutil.ToBaseUnits(1)
+
+	ask, err := app.OrderKeeper.PostOrder(app.Ctx, seller, ordertypes.OrderParams{
+		MarketID:  mkt.ID,
+		Direction: matcheng.Ask,
+		Price:     price,
+		Quantity:  testutil.ToBaseUnits(2),
+		Expiry:    ordertypes.NewBlockExpiry(100),
+	})
+	require.NoError(t, err)
+	bid, err := app.OrderKeeper.PostOrder(app.Ctx, buyer, ordertypes.OrderParams{
+		MarketID:  mkt.ID,
+		Direction: matcheng.Bid,
+		Price:     price,
+		Quantity:  testutil.ToBaseUnits(1),
+		Expiry:    ordertypes.NewBlockExpiry(100),
+	})
+	require.NoError(t, err)
+	stop, err := app.OrderKeeper.PostStop(app.Ctx, buyer, mkt.ID, matcheng.Bid, matcheng.Limit, testutil.ToBaseUnits(2), testutil.ToBaseUnits(3), testutil.ToBaseUnits(1), 100, ordertypes.TriggerClearing)
+	require.NoError(t, err)
+
+	t.Run("halted markets neither match nor accept orders", func(t *testing.T) {
+		require.NoError(t, app.MarketKeeper.SetStatus(app.Ctx, mkt.ID, types2.MarketHalted))
+		require.NoError(t, app.ExecutionKeeper.ExecuteAndCancelExpired(app.Ctx))
+		assert.True(t, app.OrderKeeper.Has(app.Ctx, ask.ID))
+		assert.True(t, app.OrderKeeper.Has(app.Ctx, bid.ID))
+
+		_, err := app.OrderKeeper.PostOrder(app.Ctx, buyer, ordertypes.OrderParams{
+			MarketID:  mkt.ID,
+			Direction: matcheng.Bid,
+			Price:     price,
+			Quantity:  testutil.ToBaseUnits(1),
+			Expiry:    ordertypes.NewBlockExpiry(100),
+		})
+		assert.Error(t, err)
+		assert.Error(t, app.OrderKeeper.Cancel(app.Ctx, bid.ID))
+	})
+
+	t.Run("cancel-only markets accept cancellations", func(t *testing.T) {
+		require.NoError(t, app.MarketKeeper.SetStatus(app.Ctx, mkt.ID, types2.MarketCancelOnly))
+		_, err := app.OrderKeeper.PostOrder(app.Ctx, buyer, ordertypes.OrderParams{
+			MarketID:  mkt.ID,
+			Direction: matcheng.Bid,
+			Price:     price,
+			Quantity:  testutil.ToBaseUnits(1),
+			Expiry:    ordertypes.NewBlockExpiry(100),
+		})
+		assert.Error(t, err)
+		require.NoError(t, app.OrderKeeper.Cancel(app.Ctx, bid.ID))
+	})
+
+	t.Run("delisting refunds open orders", func(t *testing.T) {
+		require.NoError(t, app.MarketKeeper.SetStatus(app.Ctx, mkt.ID, types2.MarketDelisted))
+		assert.False(t, app.OrderKeeper.Has(app.Ctx, ask.ID))
+		rec, err := app.OrderKeeper.Record(app.Ctx, ask.ID)
+		require.NoError(t, err)
+		assert.Equal(t, ordertypes.Cancelled, rec.Status)
+		testutil.AssertEqualUints(t, testutil.ToBaseUnits(100), balanceOf(app, seller, "tst1"))
+		testutil.AssertEqualUints(t, testutil.ToBaseUnits(100), balanceOf(app, buyer, "tst2"))
+		assert.False(t, app.OrderKeeper.HasTrigger(app.Ctx, stop.ID))
+		assert.Error(t, app.MarketKeeper.SetStatus(app.Ctx, mkt.ID, types2.MarketActive))
+	})
+	assertInvariants(t, app)
+}
+
+func setupMarket(t *testing.T, mode types2.MatchingMode) (*mockapp.MockApp, types2.Market, sdk.AccAddress, sdk.AccAddress) {
+	return setupMarketWithFees(t, mode, types2.FeeSchedule{})
+}
+
+func setupMarketWithFees(t *testing.T, mode types2.MatchingMode, fees types2.FeeSchedule) (*mockapp.MockApp, types2.Market, sdk.AccAddress, sdk.AccAddress) {
+	return setupMarketWithBand(t, mode, fees, types2.PriceBand{})
+}
+
+func setupMarketWithBand(t *testing.T, mode types2.MatchingMode, fees types2.FeeSchedule, band types2.PriceBand) (*mockapp.MockApp, types2.Market, sdk.AccAddress, sdk.AccAddress) {
+	app := mockapp.New(t)
+	nominee := testutil.RandAddr()
+	buyer := testutil.RandAddr()
+	seller := testutil.RandAddr()
+
+	app.SupplyKeeper.SetSupply(app.Ctx, supply.NewSupply(sdk.Coins{}))
+	app.GrantRole(authority.RoleMarketAdmin, nominee)
+
+	err := app.SupplyKeeper.MintCoins(app.Ctx, denominations.ModuleName, sdk.NewCoins(sdk.NewCoin("tst1", sdk.NewInt(1000000000000)), sdk.NewCoin("tst2", sdk.NewInt(1000000000000))))
+	require.NoError(t, err)
+	require.NoError(t, app.SupplyKeeper.SendCoinsFromModuleToAccount(app.Ctx, denominations.ModuleName, buyer, sdk.NewCoins(sdk.NewCoin("tst2", sdk.NewInt(10000000000)))))
+	require.NoError(t, app.SupplyKeeper.SendCoinsFromModuleToAccount(app.Ctx, denominations.ModuleName, seller, sdk.NewCoins(sdk.NewCoin("tst1", sdk.NewInt(10000000000)))))
+	mkt, err := app.MarketKeeper.CreateMarket(app.Ctx, nominee.String(), types2.MarketParams{
+		BaseAsset:    "tst1",
+		QuoteAsset:   "tst2",
+		MatchingMode: mode,
+		Fees:         fees,
+		Band:         band,
+	})
+	require.NoError(t, err)
+	return app, mkt, buyer, seller
+}
+
+func balanceOf(app *mockapp.MockApp, addr sdk.AccAddress, denom string) sdk.Uint {
+	return sdk.NewUintFromBigInt(app.BankKeeper.GetCoins(app.Ctx, addr).AmountOf(denom).BigInt())
+}
@@ -0,0 +1,33 @@
+package execution
+
+import (
+	"github.com/xar-network/xar-network/x/market"
+	types2 "github.com/xar-network/xar-network/x/order/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// Hooks wrapper struct for the execution keeper
+type Hooks struct {
+	k Keeper
+}
+
+var _ types2.OrderHooks = Hooks{}
+
+// Hooks returns the order hooks used to run continuous matching
+func (k Keeper) Hooks() Hooks {
+	return Hooks{k}
+}
+
+// AfterOrderPosted matches orders posted to continuous markets as soon as
+// they arrive. Orders in batch markets wait for the end of the block.
+func (h Hooks) AfterOrderPosted(ctx sdk.Context, ord types2.Order) sdk.Error {
+	mkt, err := h.k.mk.Get(ctx, ord.MarketID)
+	if err != nil {
+		return err
+	}
+	if mkt.MatchingMode != market.MatchingModeContinuous {
+		return nil
+	}
+	return h.k.ExecuteContinuous(ctx, ord)
+}
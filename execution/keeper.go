@@ -1,8 +1,10 @@
 package execution
 
 import (
+	"strconv"
 	"time"
 
+	"github.com/xar-network/xar-network/pkg/conv"
 	"github.com/xar-network/xar-network/pkg/log"
 	"github.com/xar-network/xar-network/pkg/matcheng"
 	"github.com/xar-network/xar-network/types"
@@ -12,31 +14,32 @@ import (
 	types2 "github.com/xar-network/xar-network/x/order/types"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
-	"github.com/cosmos/cosmos-sdk/x/bank"
+	"github.com/cosmos/cosmos-sdk/x/supply"
 )
 
 type Keeper struct {
-	queue     types.Backend
+	queue     types.Publisher
 	mk        market.Keeper
 	ordK      order.Keeper
-	bk        bank.Keeper
+	sk        supply.Keeper
 	metrics   *Metrics
 	saveFills bool
 }
 
-type matcherByMarket struct {
-	matcher *matcheng.Matcher
-	mktID   store.EntityID
+// marketBatch holds the crossing orders of a market's batch auction.
+type marketBatch struct {
+	mkt    market.Market
+	orders []types2.Order
 }
 
 var logger = log.WithModule("execution")
 
-func NewKeeper(queue types.Backend, mk market.Keeper, ordK order.Keeper, bk bank.Keeper) Keeper {
+func NewKeeper(queue types.Publisher, mk market.Keeper, ordK order.Keeper, sk supply.Keeper) Keeper {
 	return Keeper{
 		queue:   queue,
 		mk:      mk,
 		ordK:    ordK,
-		bk:      bk,
+		sk:      sk,
 		metrics: PrometheusMetrics(),
 	}
 }
@@ -46,54 +49,104 @@ func (k Keeper) ExecuteAndCancelExpired(ctx sdk.Context) sdk.Error {
 	height := ctx.BlockHeight()
 
 	var toCancel []store.EntityID
-	k.ordK.Iterator(ctx, func(ord types2.Order) bool {
-		if height-ord.CreatedBlock > int64(ord.TimeInForceBlocks) {
-			toCancel = append(toCancel, ord.ID)
-		}
-
+	k.ordK.ExpiredIterator(ctx, func(ord types2.Order) bool {
+		toCancel = append(toCancel, ord.ID)
 		return true
 	})
 	for _, ordID := range toCancel {
-		if err := k.ordK.Cancel(ctx, ordID); err != nil {
+		if err := k.ordK.CancelWithReason(ctx, ordID, types2.CancelledExpired); err != nil {
 			return err
 		}
 	}
 
 	logger.Info("cancelled expired orders", "count", len(toCancel))
 
-	matchersByMarket := make(map[string]*matcherByMarket)
-
-	k.ordK.ReverseIterator(ctx, func(ord types2.Order) bool {
-		matcher := getMatcherByMarket(matchersByMarket, ord).matcher
-		matcher.EnqueueOrder(ord.Direction, ord.ID, ord.Price, ord.Quantity)
+	// books are read one market at a time, matched in parallel and the
+	// results applied in market ID order, so that state changes and events
+	// do not depend on how the matching was scheduled.
+	var batches []marketBatch
+	maxOrders := k.ordK.GetParams(ctx).MaxBatchOrders
+	k.mk.Iterator(ctx, func(mkt market.Market) bool {
+		// continuous markets are matched as orders arrive, so their
+		// books are never crossed at the end of the block.
+		if mkt.MatchingMode == market.MatchingModeContinuous {
+			return true
+		}
+		// halted markets are not matched until the halt ends. Their
+		// orders still expire above.
+		if !mkt.Status.IsTradable() || k.mk.IsHalted(ctx, mkt.ID) {
+			return true
+		}
+		orders := k.crossingOrders(ctx, mkt.ID, maxOrders)
+		if len(orders) == 0 {
+			return true
+		}
+		batches = append(batches, marketBatch{mkt: mkt, orders: orders})
 		return true
 	})
+	results := matcheng.MatchParallel(len(batches), func(i int, m *matcheng.Matcher) *matcheng.MatchResults {
+		return batches[i].match(m)
+	})
 
-	var toFill []*matcheng.MatchResults
-	for _, m := range matchersByMarket {
-		res := m.matcher.Match()
+	var fillCount int
+	for i, b := range batches {
+		res := results[i]
 		if res == nil {
 			continue
 		}
+		// batches clearing outside the market's price band are skipped
+		// entirely, so their orders carry over to the next batch.
+		if len(res.Fills) > 0 && k.breachesBand(ctx, b.mkt, res.ClearingPrice) {
+			breaker := k.mk.RecordBreach(ctx, b.mkt)
+			logger.Info(
+				"skipped batch outside price band",
+				"market_id", b.mkt.ID.String(),
+				"clearing_price", res.ClearingPrice.String(),
+				"breaches", breaker.Breaches,
+				"halted_until", breaker.HaltedUntil,
+			)
+			continue
+		}
+		// self-trade prevention can leave nothing to clear.
+		if len(res.Fills) > 0 {
+			k.mk.ResetBreaches(ctx, b.mkt.ID)
+			k.queue.Publish(ctx, types.Batch{
+				BlockNumber:   height,
+				BlockTime:     ctx.BlockHeader().Time,
+				MarketID:      b.mkt.ID,
+				ClearingPrice: res.ClearingPrice,
+				Bids:          res.BidAggregates,
+				Asks:          res.AskAggregates,
+			})
+			ctx.EventManager().EmitEvent(
+				sdk.NewEvent(
+					types2.EventTypeClearBatch,
+					sdk.NewAttribute(types2.AttributeKeyMarketID, b.mkt.ID.String()),
+					sdk.NewAttribute(types2.AttributeKeyClearingPrice, res.ClearingPrice.String()),
+				),
+			)
+			k.mk.SetLastPrice(ctx, b.mkt.ID, res.ClearingPrice)
+		}
 
-		_ = k.queue.Publish(types.Batch{
-			BlockNumber:   height,
-			BlockTime:     ctx.BlockHeader().Time,
-			MarketID:      m.mktID,
-			ClearingPrice: res.ClearingPrice,
-			Bids:          res.BidAggregates,
-			Asks:          res.AskAggregates,
-		})
-		toFill = append(toFill, res)
-		matcheng.ReturnMatcher(m.matcher)
-	}
-	var fillCount int
-	for _, res := range toFill {
 		fillCount += len(res.Fills)
-		for _, f := range res.Fills {
-			if err := k.ExecuteFill(ctx, res.ClearingPrice, f); err != nil {
-				return err
-			}
+		if err := k.ExecuteFills(ctx, res.ClearingPrice, res.Fills, restedBefore(height)); err != nil {
+			return err
+		}
+		if err := k.cancelSelfTrades(ctx, res.Cancels); err != nil {
+			return err
+		}
+	}
+
+	// immediate orders never rest in the book, so whatever is left of
+	// them after this batch is cancelled.
+	var immediate []types2.Order
+	k.ordK.ImmediateIterator(ctx, func(ord types2.Order) bool {
+		immediate = append(immediate, ord)
+		return true
+	})
+	for _, ord := range immediate {
+		if err := k.cancelImmediate(ctx, ord); err != nil {
+			return err
 		}
 	}
 
@@ -105,103 +158,628 @@ func (k Keeper) ExecuteAndCancelExpired(ctx sdk.Context) sdk.Error {
 	return nil
 }
 
-func (k Keeper) ExecuteFill(ctx sdk.Context, clearingPrice sdk.Uint, f matcheng.Fill) sdk.Error {
-	ord, err := k.ordK.Get(ctx, f.OrderID)
+// ExecuteContinuous matches a newly posted order against the resting orders
+// of its market in price-time priority. Every trade executes at the resting
+// order's price and is settled through ExecuteFills for both sides. Orders
+// of the incoming order's owner are resolved by self-trade prevention
+// instead of trading. Trades are held to the market's price band like
+// batches are: the first trade outside of it counts as a breach and the
+// remainder of the incoming order is cancelled, so that it never rests in a
+// crossed book.
+func (k Keeper) ExecuteContinuous(ctx sdk.Context, incoming types2.Order) sdk.Error {
+	mkt, err := k.mk.Get(ctx, incoming.MarketID)
 	if err != nil {
 		return err
 	}
-	mkt, err := k.mk.Get(ctx, ord.MarketID)
-	if err != nil {
-		return err
+	matcher := matcheng.NewContinuousMatcher()
+	k.ordK.BookIterator(ctx, incoming.MarketID, incoming.Direction.Opposite(), func(ord types2.Order) bool {
+		if !crosses(incoming.Direction, incoming.Price, ord.Price) {
+			return false
+		}
+		matcher.Enqueue(ord.Direction, matcherOrder(ord))
+		return true
+	})
+
+	// fill-or-kill orders must not trade at all unless there is enough
+	// liquidity to fill them completely.
+	if incoming.Type == matcheng.FillOrKill && matcher.CrossingQuantity(incoming.Direction, incoming.Price).LT(incoming.Quantity) {
+		logger.Info("killed unfillable order", "id", incoming.ID.String())
+		return k.cancelImmediate(ctx, incoming)
 	}
-	pair, err := k.mk.Pair(ctx, mkt.ID)
-	if err != nil {
-		panic(err)
+
+	// the reserve of an incoming iceberg order is only hidden once it
+	// rests, so all of it can trade now.
+	taker := matcherOrder(incoming)
+	taker.Quantity = incoming.Quantity
+	taker.Reserve = sdk.ZeroUint()
+	trades, cancels := matcher.MatchIncomingOrder(incoming.Direction, taker)
+	// self-trade prevention can still keep them from being filled in full.
+	if incoming.Type == matcheng.FillOrKill && filledQuantity(trades).LT(incoming.Quantity) {
+		logger.Info("killed self-trading order", "id", incoming.ID.String())
+		return k.cancelImmediate(ctx, incoming)
 	}
 
-	if ord.Direction == matcheng.Bid {
-		quoteAmount, ok := sdk.NewIntFromString(f.QtyFilled.String())
-		if !ok {
-			panic("invalid QtyFilled value")
+	// trades walk away from the best price, so the first one outside of the
+	// band ends the match. Fill-or-kill orders are killed before any of
+	// their trades execute.
+	inBand := len(trades)
+	for i, t := range trades {
+		if k.breachesBand(ctx, mkt, t.Price) {
+			inBand = i
+			break
 		}
-		_, err = k.bk.AddCoins(ctx, ord.Owner, sdk.NewCoins(sdk.NewCoin(mkt.BaseAssetDenom, quoteAmount)))
-		if err != nil {
+	}
+	breached := inBand < len(trades)
+	if breached && incoming.Type == matcheng.FillOrKill {
+		k.recordContinuousBreach(ctx, mkt, incoming, trades[inBand].Price)
+		return k.cancelImmediate(ctx, incoming)
+	}
+
+	for _, t := range trades[:inBand] {
+		if err := k.ExecuteFills(ctx, t.Price, []matcheng.Fill{t.Maker, t.Taker}, isResting(incoming)); err != nil {
 			return err
 		}
-		if clearingPrice.LT(ord.Price) {
-			diff := ord.Price.Sub(clearingPrice)
-			refund, qErr := matcheng.NormalizeQuoteQuantity(diff, f.QtyFilled)
-			refundInt, ok := sdk.NewIntFromString(refund.String())
-			if !ok {
-				panic("invalid refundInt value")
+		k.mk.ResetBreaches(ctx, mkt.ID)
+		k.mk.SetLastPrice(ctx, incoming.MarketID, t.Price)
+		// a bid left too small to trade again is cancelled as dust.
+		if !k.ordK.Has(ctx, incoming.ID) {
+			break
+		}
+	}
+	// self-trades are only prevented for a match that ran to completion;
+	// the remainder of an order stopped by the band is cancelled instead.
+	if breached {
+		k.recordContinuousBreach(ctx, mkt, incoming, trades[inBand].Price)
+		if !k.ordK.Has(ctx, incoming.ID) {
+			return nil
+		}
+		return k.ordK.CancelWithReason(ctx, incoming.ID, types2.CancelledBand)
+	}
+	if err := k.cancelSelfTrades(ctx, cancels); err != nil {
+		return err
+	}
+
+	logger.Info("matched continuous order", "id", incoming.ID.String(), "count", len(trades))
+	return k.cancelImmediate(ctx, incoming)
+}
+
+// recordContinuousBreach counts a continuous trade at price that breached
+// the market's price band.
+func (k Keeper) recordContinuousBreach(ctx sdk.Context, mkt market.Market, incoming types2.Order, price sdk.Uint) {
+	breaker := k.mk.RecordBreach(ctx, mkt)
+	logger.Info(
+		"stopped match outside price band",
+		"id", incoming.ID.String(),
+		"market_id", mkt.ID.String(),
+		"price", price.String(),
+		"breaches", breaker.Breaches,
+		"halted_until", breaker.HaltedUntil,
+	)
+}
+
+// breachesBand returns true if a batch clearing at price deviates too far
+// from the market's last clearing price or its oracle price.
+func (k Keeper) breachesBand(ctx sdk.Context, mkt market.Market, price sdk.Uint) bool {
+	if !mkt.Band.IsEnabled() {
+		return false
+	}
+	if last, ok := k.mk.LastPrice(ctx, mkt.ID); ok && mkt.Band.Breached(last, price) {
+		return true
+	}
+	return mkt.Band.Breached(k.ordK.OraclePrice(ctx, mkt.ID), price)
+}
+
+// crossingOrders reads the price levels of a market that can trade in a
+// batch auction: bids at or above the best ask and asks at or below the
+// best bid. The first price level beyond them on either side is read as
+// well since the matcher uses it to bound the clearing price. The rest of
+// the book is never loaded.
+//
+// If maxOrders is not zero, at most maxOrders orders are returned. Each
+// side is cut in price-time priority and keeps at least half of the cap
+// unless the other side needs less, so the orders left out are the worst
+// priced and most recent ones. They stay in the book and are matched by the
+// following batches, except for immediate orders, which are cancelled at
+// the end of the block like any other unfilled immediate order.
+func (k Keeper) crossingOrders(ctx sdk.Context, mktID store.EntityID, maxOrders uint64) []types2.Order {
+	bestBid, hasBid := k.bestOrder(ctx, mktID, matcheng.Bid)
+	bestAsk, hasAsk := k.bestOrder(ctx, mktID, matcheng.Ask)
+	if !hasBid || !hasAsk || !crosses(matcheng.Bid, bestBid.Price, bestAsk.Price) {
+		return nil
+	}
+
+	max := int(maxOrders)
+	collect := func(limit types2.Order, orders *[]types2.Order) order.IteratorCB {
+		var boundary *sdk.Uint
+		return func(ord types2.Order) bool {
+			if max > 0 && len(*orders) == max {
+				return false
 			}
-			if qErr == nil {
-				_, err = k.bk.AddCoins(ctx, ord.Owner, sdk.NewCoins(sdk.NewCoin(mkt.QuoteAssetDenom, refundInt)))
-				if err != nil {
-					return err
-				}
-			} else {
-				logger.Info(
-					"refund amount too small",
-					"order_id", ord.ID.String(),
-					"qty_filled", f.QtyFilled.String(),
-					"price_delta", diff.String(),
-				)
+			if boundary == nil && !crosses(limit.Direction, limit.Price, ord.Price) {
+				price := ord.Price
+				boundary = &price
 			}
-		}
-	} else {
-		baseAmount, qErr := matcheng.NormalizeQuoteQuantity(clearingPrice, f.QtyFilled)
-		baseAmountInt, ok := sdk.NewIntFromString(baseAmount.String())
-		if !ok {
-			panic("invalid baseAmountInt")
-		}
-		if qErr == nil {
-			_, err = k.bk.AddCoins(ctx, ord.Owner, sdk.NewCoins(sdk.NewCoin(mkt.QuoteAssetDenom, baseAmountInt)))
-			if err != nil {
-				return err
+			if boundary != nil && !ord.Price.Equal(*boundary) {
+				return false
 			}
+			*orders = append(*orders, ord)
+			return true
+		}
+	}
+	var bids, asks []types2.Order
+	k.ordK.BookIterator(ctx, mktID, matcheng.Bid, collect(bestAsk, &bids))
+	k.ordK.BookIterator(ctx, mktID, matcheng.Ask, collect(bestBid, &asks))
+	if max > 0 && len(bids)+len(asks) > max {
+		nb, na := batchShares(len(bids), len(asks), max)
+		bids, asks = bids[:nb], asks[:na]
+	}
+	return append(bids, asks...)
+}
+
+// batchShares splits a batch of at most max orders between bids and asks.
+// Each side gets half of the batch, and what one side does not need goes to
+// the other.
+func batchShares(bids, asks, max int) (int, int) {
+	nb := minInt(bids, max/2)
+	na := minInt(asks, max-nb)
+	nb = minInt(bids, max-na)
+	return nb, na
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func (k Keeper) bestOrder(ctx sdk.Context, mktID store.EntityID, direction matcheng.Direction) (types2.Order, bool) {
+	var best types2.Order
+	var ok bool
+	k.ordK.BookIterator(ctx, mktID, direction, func(ord types2.Order) bool {
+		best = ord
+		ok = true
+		return false
+	})
+	return best, ok
+}
+
+// crosses returns true if an order in the given direction and price can
+// trade against a resting order at restingPrice.
+func crosses(direction matcheng.Direction, price sdk.Uint, restingPrice sdk.Uint) bool {
+	if direction == matcheng.Bid {
+		return !restingPrice.GT(price)
+	}
+	return restingPrice.GTE(price)
+}
+
+// restedBefore treats the orders that were already resting in the book
+// before the given block as makers of a batch auction.
+func restedBefore(height int64) func(types2.Order) bool {
+	return func(ord types2.Order) bool {
+		return ord.CreatedBlock < height
+	}
+}
+
+// isResting treats every order but the incoming one as a maker of a
+// continuous trade.
+func isResting(incoming types2.Order) func(types2.Order) bool {
+	return func(ord types2.Order) bool {
+		return !ord.ID.Equals(incoming.ID)
+	}
+}
+
+// cancelImmediate cancels the unfilled remainder of market, IOC and FOK
+// orders. Other order types are left untouched. Fill-or-kill orders are
+// only ever cancelled unfilled, so they are rejected.
+func (k Keeper) cancelImmediate(ctx sdk.Context, ord types2.Order) sdk.Error {
+	if !ord.Type.IsImmediate() || !k.ordK.Has(ctx, ord.ID) {
+		return nil
+	}
+	reason := types2.CancelledUnfilled
+	if ord.Type == matcheng.FillOrKill {
+		reason = types2.CancelledKilled
+	}
+	return k.ordK.CancelWithReason(ctx, ord.ID, reason)
+}
+
+// cancelSelfTrades reduces or cancels the orders that self-trade prevention
+// kept from trading against orders of the same owner. Fills are settled
+// relative to the orders' stored quantities, so this may run after them.
+// Orders the fills closed are skipped.
+func (k Keeper) cancelSelfTrades(ctx sdk.Context, cancels []matcheng.Cancel) sdk.Error {
+	for _, c := range cancels {
+		if !k.ordK.Has(ctx, c.OrderID) {
+			continue
+		}
+		logger.Info(
+			"prevented self-trade",
+			"id", c.OrderID.String(),
+			"qty_cancelled", c.QtyCancelled.String(),
+		)
+		if err := k.ordK.Reduce(ctx, c.OrderID, c.QtyCancelled, types2.CancelledSelfTrade); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// matcherOrder converts an order for the matching engine. Only the visible
+// slice of iceberg orders is matched, so a batch fills at most one slice
+// and the rest is shown again in the next one. The rest is passed as the
+// reserve so that self-trade prevention cancels all of it.
+func matcherOrder(ord types2.Order) matcheng.Order {
+	visible := ord.Visible()
+	return matcheng.Order{
+		ID:       ord.ID,
+		Price:    ord.Price,
+		Quantity: visible,
+		Reserve:  ord.Quantity.Sub(visible),
+		Owner:    ord.Owner,
+		STP:      ord.STPMode,
+	}
+}
+
+// filledQuantity returns the quantity the incoming order of a continuous
+// match was filled by.
+func filledQuantity(trades []matcheng.Trade) sdk.Uint {
+	total := sdk.ZeroUint()
+	for _, t := range trades {
+		total = total.Add(t.Taker.QtyFilled)
+	}
+	return total
+}
+
+// ExecuteFills settles the fills of one trade, or of one batch auction, at
+// the given price. Each filled order first releases the escrow it no longer
+// needs into the order module's dust pool, then every owner is paid from the
+// pool: bids receive the base asset plus a refund of the quote asset they
+// escrowed above the price, asks receive the quote asset. Bids are settled
+// first, and the asks are never paid more of the quote asset than the bids
+// paid for the same fills, so settlement never draws on the dust left over
+// from earlier trades. Funds only ever move out of the order module
+// account, so settlement never changes the total supply. isMaker decides
+// which of the filled orders provided liquidity and are charged the
+// market's maker fee rather than its taker fee.
+func (k Keeper) ExecuteFills(ctx sdk.Context, price sdk.Uint, fills []matcheng.Fill, isMaker func(types2.Order) bool) sdk.Error {
+	if len(fills) == 0 {
+		return nil
+	}
+	var bids, asks []orderFill
+	for _, f := range fills {
+		ord, err := k.ordK.Get(ctx, f.OrderID)
+		if err != nil {
+			return err
+		}
+		if ord.Direction == matcheng.Bid {
+			bids = append(bids, orderFill{ord: ord, qty: f.QtyFilled})
 		} else {
-			panic("clearing price too small to represent")
+			asks = append(asks, orderFill{ord: ord, qty: f.QtyFilled})
+		}
+	}
+	mkt, err := k.mk.Get(ctx, fillMarket(bids, asks))
+	if err != nil {
+		return err
+	}
+	bids, asks = representableFills(mkt, price, bids, asks)
+
+	payouts := make([]payout, 0, len(bids)+len(asks))
+	paid := sdk.ZeroInt()
+	var dust []store.EntityID
+	for _, f := range bids {
+		released := k.ordK.ReleaseEscrow(ctx, f.ord, f.ord.Quantity.Sub(f.qty))
+		refund := bidRefund(mkt, price, f)
+		if leftover, ok := dustRemainder(mkt, f, released); ok {
+			refund = refund.Add(leftover)
+			dust = append(dust, f.ord.ID)
+		}
+		paid = paid.Add(released.Amount).Sub(refund.Amount)
+		owed := sdk.NewCoins(sdk.NewCoin(mkt.BaseAssetDenom, uintToInt(f.qty)), refund)
+		// fills are representable, so the notional is only needed as
+		// the basis of fees charged in the quote asset.
+		notional, _ := mkt.QuoteQuantity(price, f.qty)
+		p, err := k.executeFill(ctx, mkt, price, f, isMaker(f.ord), owed, sdk.NewCoin(mkt.BaseAssetDenom, uintToInt(f.qty)), sdk.NewCoin(mkt.QuoteAssetDenom, uintToInt(notional)))
+		if err != nil {
+			return err
+		}
+		payouts = append(payouts, p)
+	}
+
+	askQty := totalQuantity(asks)
+	for _, f := range asks {
+		k.ordK.ReleaseEscrow(ctx, f.ord, f.ord.Quantity.Sub(f.qty))
+		proceeds := askProceeds(mkt, price, f.qty, paid, askQty)
+		received := sdk.NewCoin(mkt.QuoteAssetDenom, proceeds)
+		p, err := k.executeFill(ctx, mkt, price, f, isMaker(f.ord), sdk.NewCoins(received), received, sdk.NewCoin(mkt.BaseAssetDenom, uintToInt(f.qty)))
+		if err != nil {
+			return err
+		}
+		payouts = append(payouts, p)
+	}
+
+	for _, p := range payouts {
+		if err := k.ordK.PayFromDust(ctx, p.owner, p.owed); err != nil {
+			return err
+		}
+		if err := k.ordK.PayFeeFromDust(ctx, p.feeRecipient, p.fee); err != nil {
+			return err
+		}
+	}
+
+	// the escrow of these bids was released and refunded above, so they
+	// are cancelled without a refund.
+	for _, id := range dust {
+		if err := k.ordK.CancelWithReason(ctx, id, types2.CancelledDust); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// orderFill is the quantity of an order filled by a trade.
+type orderFill struct {
+	ord types2.Order
+	qty sdk.Uint
+}
+
+// payout is what settling a fill owes its owner and the market's fee
+// recipient out of the dust pool.
+type payout struct {
+	owner        sdk.AccAddress
+	owed         sdk.Coins
+	feeRecipient string
+	fee          sdk.Coins
+}
+
+// fillMarket returns the market of a trade's fills.
+func fillMarket(bids, asks []orderFill) store.EntityID {
+	if len(bids) > 0 {
+		return bids[0].ord.MarketID
+	}
+	return asks[0].ord.MarketID
+}
+
+// representableFills drops the fills too small to be worth a single unit of
+// the quote asset at price, so that no order gives up its assets for
+// nothing. The quantity they leave unmatched is taken off the fills of the
+// other side, lowest priority first, until both sides trade the same
+// quantity again. The orders of dropped fills keep their quantity.
+func representableFills(mkt market.Market, price sdk.Uint, bids, asks []orderFill) ([]orderFill, []orderFill) {
+	for {
+		var droppedBids, droppedAsks bool
+		bids, droppedBids = dropUnrepresentable(mkt, price, bids)
+		asks, droppedAsks = dropUnrepresentable(mkt, price, asks)
+		if !droppedBids && !droppedAsks {
+			return bids, asks
+		}
+		bidQty, askQty := totalQuantity(bids), totalQuantity(asks)
+		if bidQty.GT(askQty) {
+			bids = trimFills(bids, bidQty.Sub(askQty))
+		} else if askQty.GT(bidQty) {
+			asks = trimFills(asks, askQty.Sub(bidQty))
+		}
+	}
+}
+
+func dropUnrepresentable(mkt market.Market, price sdk.Uint, fills []orderFill) ([]orderFill, bool) {
+	kept := fills[:0]
+	for _, f := range fills {
+		if _, err := mkt.QuoteQuantity(price, f.qty); err != nil {
+			logger.Info(
+				"skipped fill too small to represent",
+				"order_id", f.ord.ID.String(),
+				"qty_filled", f.qty.String(),
+				"price", price.String(),
+			)
+			continue
+		}
+		kept = append(kept, f)
+	}
+	return kept, len(kept) < len(fills)
+}
+
+// trimFills takes quantity off the last fills.
+func trimFills(fills []orderFill, quantity sdk.Uint) []orderFill {
+	for len(fills) > 0 && !quantity.IsZero() {
+		last := &fills[len(fills)-1]
+		if last.qty.GT(quantity) {
+			last.qty = last.qty.Sub(quantity)
+			break
 		}
+		quantity = quantity.Sub(last.qty)
+		fills = fills[:len(fills)-1]
+	}
+	return fills
+}
+
+func totalQuantity(fills []orderFill) sdk.Uint {
+	total := sdk.ZeroUint()
+	for _, f := range fills {
+		total = total.Add(f.qty)
+	}
+	return total
+}
+
+// bidRefund returns the quote asset a bid escrowed above the price for the
+// filled quantity.
+func bidRefund(mkt market.Market, price sdk.Uint, f orderFill) sdk.Coin {
+	refund := sdk.NewCoin(mkt.QuoteAssetDenom, sdk.ZeroInt())
+	if !price.LT(f.ord.Price) {
+		return refund
+	}
+	diff := f.ord.Price.Sub(price)
+	amount, err := mkt.QuoteQuantity(diff, f.qty)
+	if err != nil {
+		logger.Info(
+			"refund amount too small",
+			"order_id", f.ord.ID.String(),
+			"qty_filled", f.qty.String(),
+			"price_delta", diff.String(),
+		)
+		return refund
+	}
+	refund.Amount = uintToInt(amount)
+	return refund
+}
+
+// dustRemainder reports whether a fill leaves a bid open with a remainder
+// too small to be worth a single unit of the quote asset. Such a bid holds
+// no escrow and none of its fills could ever be settled, so it would rest
+// forever. The escrow released for it beyond what the filled quantity needs
+// at the bid's price is returned so that it can be refunded.
+func dustRemainder(mkt market.Market, f orderFill, released sdk.Coin) (sdk.Coin, bool) {
+	remaining := f.ord.Quantity.Sub(f.qty)
+	if remaining.IsZero() {
+		return sdk.Coin{}, false
+	}
+	if _, err := mkt.QuoteQuantity(f.ord.Price, remaining); err == nil {
+		return sdk.Coin{}, false
+	}
+	// fills are representable at the trade price, so they are at the
+	// bid's price too.
+	needed, _ := mkt.QuoteQuantity(f.ord.Price, f.qty)
+	return released.Sub(sdk.NewCoin(mkt.QuoteAssetDenom, uintToInt(needed))), true
+}
+
+// askProceeds returns the quote asset owed for quantity of an ask. The bids
+// of a trade pay for their fills separately, so rounding can leave what they
+// paid a few units short of the asks' notional; the asks then share what
+// was paid in proportion to their quantity.
+func askProceeds(mkt market.Market, price sdk.Uint, quantity sdk.Uint, paid sdk.Int, askQty sdk.Uint) sdk.Int {
+	notional, _ := mkt.QuoteQuantity(price, quantity)
+	share := paid.Mul(uintToInt(quantity)).Quo(uintToInt(askQty))
+	if share.LT(uintToInt(notional)) {
+		return share
 	}
+	return uintToInt(notional)
+}
+
+// executeFill charges the fee on a fill whose escrow has been released,
+// updates or removes the filled order and returns what is owed for the
+// fill.
+func (k Keeper) executeFill(ctx sdk.Context, mkt market.Market, price sdk.Uint, f orderFill, maker bool, owed sdk.Coins, received, paid sdk.Coin) (payout, sdk.Error) {
+	ord := f.ord
+	owed, fee := chargeFee(mkt.Fees, mkt.Fees.Rate(maker), owed, received, paid)
 
-	ord.Quantity = f.QtyUnfilled
+	// the order is updated relative to its stored quantity since
+	// self-trade prevention may reduce it separately.
+	remaining := ord.Quantity.Sub(f.qty)
+	ord.Fill(price, f.qty)
 	if ord.Quantity.Equal(sdk.ZeroUint()) {
 		logger.Info("order completely filled", "id", ord.ID.String())
-		if err := k.ordK.Del(ctx, ord.ID); err != nil {
-			return err
+		if err := k.ordK.Close(ctx, ord, types2.Filled); err != nil {
+			return payout{}, err
 		}
 	} else {
 		logger.Info("order partially filled", "id", ord.ID.String())
 		if err := k.ordK.Set(ctx, ord); err != nil {
-			return err
+			return payout{}, err
 		}
 	}
 
-	_ = k.queue.Publish(types.Fill{
+	k.queue.Publish(ctx, types.Fill{
 		OrderID:     ord.ID,
 		MarketID:    mkt.ID,
 		Owner:       ord.Owner,
-		Pair:        pair,
+		Pair:        mkt.BaseAssetDenom + "/" + mkt.QuoteAssetDenom,
 		Direction:   ord.Direction,
-		QtyFilled:   f.QtyFilled,
-		QtyUnfilled: f.QtyUnfilled,
+		QtyFilled:   f.qty,
+		QtyUnfilled: remaining,
 		BlockNumber: ctx.BlockHeight(),
 		BlockTime:   ctx.BlockHeader().Time.Unix(),
-		Price:       clearingPrice,
+		Price:       price,
+		Type:        ord.Type,
+		Maker:       maker,
+		Fee:         fee,
 	})
-	return nil
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types2.EventTypeFillOrder,
+			sdk.NewAttribute(types2.AttributeKeyOrderID, ord.ID.String()),
+			sdk.NewAttribute(types2.AttributeKeyMarketID, mkt.ID.String()),
+			sdk.NewAttribute(types2.AttributeKeyOwner, ord.Owner.String()),
+			sdk.NewAttribute(types2.AttributeKeyDirection, ord.Direction.String()),
+			sdk.NewAttribute(types2.AttributeKeyPrice, price.String()),
+			sdk.NewAttribute(types2.AttributeKeyQuantityFilled, f.qty.String()),
+			sdk.NewAttribute(types2.AttributeKeyQuantityUnfilled, remaining.String()),
+			sdk.NewAttribute(types2.AttributeKeyMaker, strconv.FormatBool(maker)),
+			sdk.NewAttribute(types2.AttributeKeyFee, fee.String()),
+		),
+	)
+	return payout{
+		owner:        ord.Owner,
+		owed:         owed,
+		feeRecipient: mkt.Fees.RecipientModule(),
+		fee:          fee,
+	}, nil
 }
 
-func getMatcherByMarket(matchers map[string]*matcherByMarket, ord types2.Order) *matcherByMarket {
-	mkt := ord.MarketID.String()
-	matcher := matchers[mkt]
-	if matcher == nil {
-		matcher = &matcherByMarket{
-			matcher: matcheng.GetMatcher(),
-			mktID:   ord.MarketID,
+// chargeFee computes the fee on a fill that received one coin in exchange
+// for another. The fee is only ever taken from what the fill owes its owner,
+// never from their account: it is deducted in the fee denom when they are
+// owed enough of it, and otherwise from the received asset instead, capped
+// at what they receive. It returns what remains owed and the fee charged.
+func chargeFee(fees market.FeeSchedule, rate uint16, owed sdk.Coins, received, paid sdk.Coin) (sdk.Coins, sdk.Coins) {
+	if rate == 0 {
+		return owed, sdk.NewCoins()
+	}
+
+	basis := received
+	if fees.Denom != "" && fees.Denom != received.Denom {
+		basis = paid
+	}
+	fee := sdk.NewCoins(sdk.NewCoin(basis.Denom, market.FeeAmount(basis.Amount, rate)))
+	if rest, negative := owed.SafeSub(fee); !negative {
+		return rest, fee
+	}
+
+	amount := market.FeeAmount(received.Amount, rate)
+	if available := owed.AmountOf(received.Denom); available.LT(amount) {
+		amount = available
+	}
+	fee = sdk.NewCoins(sdk.NewCoin(received.Denom, amount))
+	return owed.Sub(fee), fee
+}
+
+func uintToInt(u sdk.Uint) sdk.Int {
+	return sdk.NewIntFromBigInt(conv.SDKUint2Big(u))
+}
+
+// match runs the batch auction for the market with the given matcher. It
+// only reads the batch, so the batches of different markets can be matched
+// concurrently. Fill-or-kill orders that would not be filled completely are
+// dropped and the auction is rerun until every remaining fill-or-kill order
+// is filled in full.
+func (b marketBatch) match(matcher *matcheng.Matcher) *matcheng.MatchResults {
+	orders := b.orders
+	for {
+		for _, ord := range orders {
+			matcher.Enqueue(ord.Direction, matcherOrder(ord))
+		}
+		res := matcher.Match()
+		matcher.Reset()
+
+		filled := make(map[string]bool)
+		if res != nil {
+			for _, f := range res.Fills {
+				if f.QtyUnfilled.IsZero() {
+					filled[f.OrderID.String()] = true
+				}
+			}
+		}
+
+		var killed bool
+		remaining := make([]types2.Order, 0, len(orders))
+		for _, ord := range orders {
+			if ord.Type == matcheng.FillOrKill && !filled[ord.ID.String()] {
+				killed = true
+				continue
+			}
+			remaining = append(remaining, ord)
+		}
+		if !killed {
+			return res
 		}
-		matchers[mkt] = matcher
+		orders = remaining
 	}
-	return matcher
 }
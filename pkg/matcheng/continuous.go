@@ -0,0 +1,205 @@
+package matcheng
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/xar-network/xar-network/types/store"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// Trade is a single execution between a resting (maker) order and an
+// incoming (taker) order. Both sides are filled at Price, which is always
+// the maker's limit price.
+type Trade struct {
+	Price sdk.Uint
+	Maker Fill
+	Taker Fill
+}
+
+// ContinuousMatcher keeps a resting book in price-time priority and matches
+// each incoming order against it as it arrives, as opposed to Matcher which
+// clears all orders at a single uniform price.
+type ContinuousMatcher struct {
+	bids []Order
+	asks []Order
+
+	mtx sync.Mutex
+}
+
+func NewContinuousMatcher() *ContinuousMatcher {
+	return &ContinuousMatcher{
+		bids: make([]Order, 0),
+		asks: make([]Order, 0),
+	}
+}
+
+// EnqueueOrder adds an order to the resting book without matching it.
+// The caller is responsible for ensuring that the book is not crossed.
+func (m *ContinuousMatcher) EnqueueOrder(oType Direction, id store.EntityID, price sdk.Uint, quantity sdk.Uint) {
+	m.Enqueue(oType, Order{
+		ID:       id,
+		Price:    price,
+		Quantity: quantity,
+	})
+}
+
+// Enqueue adds an order, including its owner and self-trade prevention mode,
+// to the resting book without matching it.
+func (m *ContinuousMatcher) Enqueue(oType Direction, order Order) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.rest(oType, order)
+}
+
+// MatchIncoming matches an incoming order against the opposite side of the
+// book, best price first and oldest order first within a price level. Any
+// unfilled remainder rests in the book. Trades are returned in execution
+// order.
+func (m *ContinuousMatcher) MatchIncoming(oType Direction, id store.EntityID, price sdk.Uint, quantity sdk.Uint) []Trade {
+	trades, _ := m.MatchIncomingOrder(oType, Order{
+		ID:       id,
+		Price:    price,
+		Quantity: quantity,
+	})
+	return trades
+}
+
+// MatchIncomingOrder is like MatchIncoming, but also applies self-trade
+// prevention between the incoming order and resting orders of the same
+// owner. The incoming order is always the newer one. Self-trade
+// cancellations are returned alongside the trades.
+func (m *ContinuousMatcher) MatchIncomingOrder(oType Direction, order Order) ([]Trade, []Cancel) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	var trades []Trade
+	var cancels []Cancel
+	id := order.ID
+	price := order.Price
+
+	for order.Quantity.GT(zero) {
+		var maker *Order
+		if oType == Bid {
+			if len(m.asks) == 0 || m.asks[0].Price.GT(price) {
+				break
+			}
+			maker = &m.asks[0]
+		} else {
+			if len(m.bids) == 0 || m.bids[0].Price.LT(price) {
+				break
+			}
+			maker = &m.bids[0]
+		}
+
+		if selfTrade(order, *maker) {
+			cancels = append(cancels, preventSelfTrade(&order, maker)...)
+		} else {
+			qty := order.Quantity
+			if maker.Quantity.LT(qty) {
+				qty = maker.Quantity
+			}
+			order.Quantity = order.Quantity.Sub(qty)
+			maker.Quantity = maker.Quantity.Sub(qty)
+
+			trades = append(trades, Trade{
+				Price: maker.Price,
+				Maker: Fill{
+					OrderID:     maker.ID,
+					QtyFilled:   qty,
+					QtyUnfilled: maker.Quantity,
+				},
+				Taker: Fill{
+					OrderID:     id,
+					QtyFilled:   qty,
+					QtyUnfilled: order.Quantity,
+				},
+			})
+		}
+
+		if maker.Quantity.IsZero() {
+			if oType == Bid {
+				m.asks = m.asks[1:]
+			} else {
+				m.bids = m.bids[1:]
+			}
+		}
+	}
+
+	if order.Quantity.GT(zero) {
+		m.rest(oType, order)
+	}
+
+	logger.Info(
+		"matched incoming order",
+		"order_id", id.String(),
+		"trade_count", len(trades),
+		"cancel_count", len(cancels),
+	)
+
+	return trades, cancels
+}
+
+// CrossingQuantity returns the resting quantity on the opposite side of the
+// book that an incoming order at the given price could fill against.
+func (m *ContinuousMatcher) CrossingQuantity(oType Direction, price sdk.Uint) sdk.Uint {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	total := zero
+	if oType == Bid {
+		for _, ask := range m.asks {
+			if ask.Price.GT(price) {
+				break
+			}
+			total = total.Add(ask.Quantity)
+		}
+	} else {
+		for _, bid := range m.bids {
+			if bid.Price.LT(price) {
+				break
+			}
+			total = total.Add(bid.Quantity)
+		}
+	}
+	return total
+}
+
+func (m *ContinuousMatcher) Reset() {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.bids = make([]Order, 0)
+	m.asks = make([]Order, 0)
+}
+
+// bids are kept highest price first, asks lowest price first. Within a
+// price level the order with the lowest ID (i.e. the oldest) comes first.
+
+func (m *ContinuousMatcher) rest(oType Direction, order Order) {
+	if oType == Bid {
+		m.bids = insertByPriority(m.bids, order, func(a, b sdk.Uint) bool {
+			return a.LT(b)
+		})
+	} else {
+		m.asks = insertByPriority(m.asks, order, func(a, b sdk.Uint) bool {
+			return a.GT(b)
+		})
+	}
+}
+
+func insertByPriority(book []Order, order Order, worse func(a, b sdk.Uint) bool) []Order {
+	i := sort.Search(len(book), func(i int) bool {
+		tester := book[i]
+		if tester.Price.Equal(order.Price) {
+			return tester.ID.Cmp(order.ID) > 0
+		}
+		return worse(tester.Price, order.Price)
+	})
+
+	book = append(book, Order{})
+	copy(book[i+1:], book[i:])
+	book[i] = order
+	return book
+}
@@ -0,0 +1,95 @@
+package matcheng
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/xar-network/xar-network/testutil"
+	"github.com/xar-network/xar-network/testutil/testflags"
+	"github.com/xar-network/xar-network/types/store"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+func TestContinuousMatcher_NoCross(t *testing.T) {
+	testflags.UnitTest(t)
+	matcher := NewContinuousMatcher()
+	matcher.EnqueueOrder(Ask, store.NewEntityID(1), sdk.NewUint(5), sdk.NewUint(10))
+
+	trades := matcher.MatchIncoming(Bid, store.NewEntityID(2), sdk.NewUint(4), sdk.NewUint(10))
+	assert.Empty(t, trades)
+
+	// the bid should now rest in the book and be matchable
+	trades = matcher.MatchIncoming(Ask, store.NewEntityID(3), sdk.NewUint(4), sdk.NewUint(3))
+	require.Len(t, trades, 1)
+	testutil.AssertEqualUints(t, sdk.NewUint(4), trades[0].Price)
+	assert.True(t, trades[0].Maker.OrderID.Equals(store.NewEntityID(2)))
+	assertFill(t, trades[0].Maker, 3, 7)
+	assertFill(t, trades[0].Taker, 3, 0)
+}
+
+func TestContinuousMatcher_PriceTimePriority(t *testing.T) {
+	testflags.UnitTest(t)
+	matcher := NewContinuousMatcher()
+	matcher.EnqueueOrder(Ask, store.NewEntityID(1), sdk.NewUint(6), sdk.NewUint(10))
+	matcher.EnqueueOrder(Ask, store.NewEntityID(2), sdk.NewUint(5), sdk.NewUint(10))
+	matcher.EnqueueOrder(Ask, store.NewEntityID(3), sdk.NewUint(5), sdk.NewUint(10))
+	matcher.EnqueueOrder(Ask, store.NewEntityID(4), sdk.NewUint(7), sdk.NewUint(10))
+
+	trades := matcher.MatchIncoming(Bid, store.NewEntityID(5), sdk.NewUint(6), sdk.NewUint(25))
+	require.Len(t, trades, 3)
+
+	expected := []struct {
+		maker uint64
+		price uint64
+		fill  uint64
+	}{
+		{2, 5, 10},
+		{3, 5, 10},
+		{1, 6, 5},
+	}
+	for i, exp := range expected {
+		trade := trades[i]
+		assert.True(t, trade.Maker.OrderID.Equals(store.NewEntityID(exp.maker)), "trade %d has maker %s", i, trade.Maker.OrderID)
+		testutil.AssertEqualUints(t, sdk.NewUint(exp.price), trade.Price)
+		testutil.AssertEqualUints(t, sdk.NewUint(exp.fill), trade.Maker.QtyFilled)
+		testutil.AssertEqualUints(t, sdk.NewUint(exp.fill), trade.Taker.QtyFilled)
+	}
+	assertFill(t, trades[2].Maker, 5, 5)
+	assertFill(t, trades[2].Taker, 5, 0)
+}
+
+func TestContinuousMatcher_RestsRemainder(t *testing.T) {
+	testflags.UnitTest(t)
+	matcher := NewContinuousMatcher()
+	matcher.EnqueueOrder(Bid, store.NewEntityID(1), sdk.NewUint(10), sdk.NewUint(4))
+	matcher.EnqueueOrder(Bid, store.NewEntityID(2), sdk.NewUint(8), sdk.NewUint(4))
+
+	trades := matcher.MatchIncoming(Ask, store.NewEntityID(3), sdk.NewUint(9), sdk.NewUint(10))
+	require.Len(t, trades, 1)
+	testutil.AssertEqualUints(t, sdk.NewUint(10), trades[0].Price)
+	assertFill(t, trades[0].Taker, 4, 6)
+
+	// the remaining 6 @ 9 rest on the ask side above the bid @ 8
+	trades = matcher.MatchIncoming(Bid, store.NewEntityID(4), sdk.NewUint(9), sdk.NewUint(10))
+	require.Len(t, trades, 1)
+	testutil.AssertEqualUints(t, sdk.NewUint(9), trades[0].Price)
+	assertFill(t, trades[0].Maker, 6, 0)
+	assertFill(t, trades[0].Taker, 6, 4)
+}
+
+func TestContinuousMatcher_CrossingQuantity(t *testing.T) {
+	testflags.UnitTest(t)
+	matcher := NewContinuousMatcher()
+	matcher.EnqueueOrder(Ask, store.NewEntityID(1), sdk.NewUint(5), sdk.NewUint(10))
+	matcher.EnqueueOrder(Ask, store.NewEntityID(2), sdk.NewUint(6), sdk.NewUint(10))
+	matcher.EnqueueOrder(Bid, store.NewEntityID(3), sdk.NewUint(4), sdk.NewUint(7))
+
+	testutil.AssertEqualUints(t, sdk.NewUint(0), matcher.CrossingQuantity(Bid, sdk.NewUint(4)))
+	testutil.AssertEqualUints(t, sdk.NewUint(10), matcher.CrossingQuantity(Bid, sdk.NewUint(5)))
+	testutil.AssertEqualUints(t, sdk.NewUint(20), matcher.CrossingQuantity(Bid, sdk.NewUint(9)))
+	testutil.AssertEqualUints(t, sdk.NewUint(7), matcher.CrossingQuantity(Ask, sdk.NewUint(1)))
+	testutil.AssertEqualUints(t, sdk.NewUint(0), matcher.CrossingQuantity(Ask, sdk.NewUint(5)))
+}
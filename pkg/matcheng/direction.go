@@ -20,6 +20,16 @@ func (d Direction) String() string {
 	return "ASK"
 }
 
+// Opposite returns the side of the book that orders in this direction
+// match against.
+func (d Direction) Opposite() Direction {
+	if d == Bid {
+		return Ask
+	}
+
+	return Bid
+}
+
 func (d *Direction) UnmarshalJSON(data []byte) error {
 	var str string
 	err := json.Unmarshal(data, &str)
@@ -11,10 +11,26 @@ import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 )
 
+// Order is a limit order as seen by the matchers. Orders with an Owner are
+// subject to self-trade prevention according to STP. Reserve is the hidden
+// quantity of an iceberg order beyond Quantity: it is never matched, but
+// self-trade prevention cancels it along with the order.
 type Order struct {
 	ID       store.EntityID
 	Price    sdk.Uint
 	Quantity sdk.Uint
+	Reserve  sdk.Uint
+	Owner    sdk.AccAddress
+	STP      STPMode
+}
+
+// reserve returns the hidden reserve of an order. Orders built without one
+// have none.
+func (o Order) reserve() sdk.Uint {
+	if o.Reserve == (sdk.Uint{}) {
+		return zero
+	}
+	return o.Reserve
 }
 
 type MatchResults struct {
@@ -23,6 +39,7 @@ type MatchResults struct {
 	MatchTable    []MatchEntry
 	BidAggregates []AggregatePrice
 	AskAggregates []AggregatePrice
+	Cancels       []Cancel
 }
 
 type Matcher struct {
@@ -54,26 +71,143 @@ func NewMatcher() *Matcher {
 // other degen case: no overlap.
 
 func (m *Matcher) EnqueueOrder(oType Direction, id store.EntityID, price sdk.Uint, quantity sdk.Uint) {
-	m.mtx.Lock()
-	defer m.mtx.Unlock()
-
-	order := &Order{
+	m.Enqueue(oType, Order{
 		ID:       id,
 		Price:    price,
 		Quantity: quantity,
-	}
+	})
+}
+
+// Enqueue adds an order, including its owner and self-trade prevention mode,
+// to the batch.
+func (m *Matcher) Enqueue(oType Direction, order Order) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
 
 	if oType == Bid {
-		m.enqueueBid(order)
+		m.enqueueBid(&order)
 	} else {
-		m.enqueueAsk(order)
+		m.enqueueAsk(&order)
 	}
 }
 
+// Match clears the batch at a single uniform price. Orders of the same owner
+// on both sides of the clearing price are resolved by self-trade prevention
+// first, and the batch is cleared again until no owner trades with itself.
+// The resulting cancellations are returned even if nothing is left to match.
 func (m *Matcher) Match() *MatchResults {
 	m.mtx.Lock()
 	defer m.mtx.Unlock()
 
+	var cancels []Cancel
+	for {
+		res := m.match()
+		if res == nil {
+			if len(cancels) == 0 {
+				return nil
+			}
+			return &MatchResults{
+				ClearingPrice: zero,
+				Cancels:       cancels,
+			}
+		}
+
+		stp := m.preventSelfTrades(res.ClearingPrice)
+		if len(stp) == 0 {
+			res.Cancels = cancels
+			return res
+		}
+		cancels = append(cancels, stp...)
+	}
+}
+
+// preventSelfTrades resolves self-trades between the bids at or above and
+// the asks at or below the clearing price. Within each owner, the newest
+// crossing order is resolved against the oldest crossing order on the other
+// side until one of the sides is exhausted. Emptied orders are removed.
+func (m *Matcher) preventSelfTrades(clearingPrice sdk.Uint) []Cancel {
+	var cancels []Cancel
+	for {
+		bid, ask := m.findSelfTrade(clearingPrice)
+		if bid == nil {
+			break
+		}
+
+		if bid.ID.Cmp(ask.ID) > 0 {
+			cancels = append(cancels, preventSelfTrade(bid, ask)...)
+		} else {
+			cancels = append(cancels, preventSelfTrade(ask, bid)...)
+		}
+		m.bids = removeEmpty(m.bids)
+		m.asks = removeEmpty(m.asks)
+	}
+	return cancels
+}
+
+// findSelfTrade returns the first pair of crossing orders with the same
+// owner, scanning bids from the best price down. Of the owner's crossing
+// orders, the newest one and the oldest one on the other side are returned.
+func (m *Matcher) findSelfTrade(clearingPrice sdk.Uint) (*Order, *Order) {
+	for i := len(m.bids) - 1; i >= 0; i-- {
+		if m.bids[i].Price.LT(clearingPrice) {
+			break
+		}
+		if m.bids[i].Owner.Empty() {
+			continue
+		}
+
+		owner := m.bids[i].Owner
+		var newestBid, oldestBid, newestAsk, oldestAsk *Order
+		for j := len(m.bids) - 1; j >= 0 && !m.bids[j].Price.LT(clearingPrice); j-- {
+			if !owner.Equals(m.bids[j].Owner) {
+				continue
+			}
+			newestBid, oldestBid = newer(newestBid, &m.bids[j]), older(oldestBid, &m.bids[j])
+		}
+		for j := 0; j < len(m.asks) && !m.asks[j].Price.GT(clearingPrice); j++ {
+			if !owner.Equals(m.asks[j].Owner) {
+				continue
+			}
+			newestAsk, oldestAsk = newer(newestAsk, &m.asks[j]), older(oldestAsk, &m.asks[j])
+		}
+		if newestAsk == nil {
+			continue
+		}
+
+		if newestBid.ID.Cmp(newestAsk.ID) > 0 {
+			return newestBid, oldestAsk
+		}
+		return oldestBid, newestAsk
+	}
+	return nil, nil
+}
+
+func newer(a *Order, b *Order) *Order {
+	if a == nil || b.ID.Cmp(a.ID) > 0 {
+		return b
+	}
+	return a
+}
+
+func older(a *Order, b *Order) *Order {
+	if a == nil || b.ID.Cmp(a.ID) < 0 {
+		return b
+	}
+	return a
+}
+
+func removeEmpty(orders []Order) []Order {
+	out := orders[:0]
+	for _, order := range orders {
+		if order.Quantity.IsZero() {
+			continue
+		}
+		out = append(out, order)
+	}
+	return out
+}
+
+func (m *Matcher) match() *MatchResults {
 	if len(m.bids) == 0 || len(m.asks) == 0 {
 		logger.Info("no bids or asks in this block")
 		return nil
@@ -3,6 +3,7 @@ package matcheng
 import (
 	"errors"
 	"math"
+	"math/big"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 
@@ -15,10 +16,20 @@ const (
 
 var divisor = sdk.NewDec(int64(math.Pow(float64(10), float64(AssetDecimals))))
 
+// NormalizeQuoteQuantity converts a quantity of a base asset with
+// AssetDecimals decimals into the quote asset at quotePrice.
 func NormalizeQuoteQuantity(quotePrice sdk.Uint, baseQuantity sdk.Uint) (sdk.Uint, error) {
+	return NormalizeQuoteQuantityWithDecimals(quotePrice, baseQuantity, AssetDecimals)
+}
+
+// NormalizeQuoteQuantityWithDecimals converts a quantity of a base asset with
+// baseDecimals decimals into the quote asset. quotePrice is the amount of
+// the quote asset, in its smallest unit, that one whole unit of the base
+// asset costs. The result is rounded down.
+func NormalizeQuoteQuantityWithDecimals(quotePrice sdk.Uint, baseQuantity sdk.Uint, baseDecimals uint8) (sdk.Uint, error) {
 	quotePDec := sdk.NewDecFromBigInt(conv.SDKUint2Big(quotePrice))
 	baseQDec := sdk.NewDecFromBigInt(conv.SDKUint2Big(baseQuantity))
-	baseMult := baseQDec.Quo(divisor)
+	baseMult := baseQDec.Quo(decimalsDivisor(baseDecimals))
 	res := sdk.NewUintFromBigInt(quotePDec.Mul(baseMult).TruncateInt().BigInt())
 	var err error
 	if res.IsZero() {
@@ -26,3 +37,26 @@ func NormalizeQuoteQuantity(quotePrice sdk.Uint, baseQuantity sdk.Uint) (sdk.Uin
 	}
 	return res, err
 }
+
+// NormalizePrice converts a decimal price such as an oracle median into the
+// fixed-point representation with AssetDecimals decimals used by orders.
+// Negative prices are returned as zero.
+func NormalizePrice(price sdk.Dec) sdk.Uint {
+	return NormalizePriceWithDecimals(price, AssetDecimals)
+}
+
+// NormalizePriceWithDecimals converts a decimal price into the smallest unit
+// of a quote asset with quoteDecimals decimals.
+func NormalizePriceWithDecimals(price sdk.Dec, quoteDecimals uint8) sdk.Uint {
+	if !price.IsPositive() {
+		return sdk.ZeroUint()
+	}
+	return sdk.NewUintFromBigInt(price.Mul(decimalsDivisor(quoteDecimals)).TruncateInt().BigInt())
+}
+
+func decimalsDivisor(decimals uint8) sdk.Dec {
+	if decimals == AssetDecimals {
+		return divisor
+	}
+	return sdk.NewDecFromBigInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil))
+}
@@ -33,3 +33,26 @@ func TestNormalizeQuoteQuantity(t *testing.T) {
 	}
 
 }
+
+func TestNormalizePrice(t *testing.T) {
+	testutil.AssertEqualUints(t, testutil.ToBaseUnits(2), NormalizePrice(sdk.NewDec(2)))
+	testutil.AssertEqualUints(t, sdk.NewUint(150000000), NormalizePrice(sdk.NewDecWithPrec(15, 1)))
+	testutil.AssertEqualUints(t, sdk.NewUint(1), NormalizePrice(sdk.NewDecWithPrec(1, 8)))
+	testutil.AssertEqualUints(t, sdk.ZeroUint(), NormalizePrice(sdk.NewDec(-1)))
+}
+
+func TestNormalizeQuoteQuantityWithDecimals(t *testing.T) {
+	// 2.5 of a six decimal base asset at 3 quote units each
+	res, err := NormalizeQuoteQuantityWithDecimals(sdk.NewUint(3), sdk.NewUint(2500000), 6)
+	require.NoError(t, err)
+	testutil.AssertEqualUints(t, sdk.NewUint(7), res)
+
+	res, err = NormalizeQuoteQuantityWithDecimals(testutil.ToBaseUnits(2), sdk.NewUint(5), 0)
+	require.NoError(t, err)
+	testutil.AssertEqualUints(t, testutil.ToBaseUnits(10), res)
+
+	_, err = NormalizeQuoteQuantityWithDecimals(sdk.NewUint(1), sdk.NewUint(999999), 6)
+	assert.Error(t, err)
+
+	testutil.AssertEqualUints(t, sdk.NewUint(1500000), NormalizePriceWithDecimals(sdk.NewDecWithPrec(15, 1), 6))
+}
@@ -0,0 +1,78 @@
+package matcheng
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+const (
+	// Limit orders rest in the book until filled, cancelled or expired.
+	Limit OrderType = iota
+	// Market orders sweep the book up to a slippage limit and cancel any
+	// unfilled remainder.
+	Market
+	// ImmediateOrCancel orders fill what they can in the current match
+	// and cancel any unfilled remainder.
+	ImmediateOrCancel
+	// FillOrKill orders are either filled completely in the current match
+	// or cancelled without any fill.
+	FillOrKill
+	// PostOnly orders are rejected if they would cross the resting book.
+	PostOnly
+)
+
+type OrderType uint8
+
+var orderTypeNames = map[OrderType]string{
+	Limit:             "LIMIT",
+	Market:            "MARKET",
+	ImmediateOrCancel: "IOC",
+	FillOrKill:        "FOK",
+	PostOnly:          "POST_ONLY",
+}
+
+func (t OrderType) String() string {
+	name, ok := orderTypeNames[t]
+	if !ok {
+		return "UNKNOWN"
+	}
+	return name
+}
+
+func (t OrderType) IsValid() bool {
+	_, ok := orderTypeNames[t]
+	return ok
+}
+
+// IsImmediate returns true for order types that never rest in the book.
+func (t OrderType) IsImmediate() bool {
+	return t == Market || t == ImmediateOrCancel || t == FillOrKill
+}
+
+func OrderTypeFromString(str string) (OrderType, error) {
+	for t, name := range orderTypeNames {
+		if name == str {
+			return t, nil
+		}
+	}
+	return Limit, errors.New("invalid order type")
+}
+
+func (t *OrderType) UnmarshalJSON(data []byte) error {
+	var str string
+	err := json.Unmarshal(data, &str)
+	if err != nil {
+		return err
+	}
+
+	out, err := OrderTypeFromString(str)
+	if err != nil {
+		return err
+	}
+	*t = out
+	return nil
+}
+
+func (t OrderType) MarshalJSON() ([]byte, error) {
+	return []byte("\"" + t.String() + "\""), nil
+}
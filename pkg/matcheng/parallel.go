@@ -0,0 +1,62 @@
+package matcheng
+
+import (
+	"runtime"
+	"sync"
+)
+
+// MatchParallel runs n independent batch auctions concurrently and returns
+// their results in the order of the batches, however they were scheduled.
+// match is called once for every batch with a reset matcher from the pool;
+// it must enqueue the batch's orders and match them without touching state
+// shared with other batches. A panic in match is raised again on the
+// calling goroutine once every batch finished. If several batches panic,
+// the panic of the first of them is raised, so that the outcome does not
+// depend on how the batches were scheduled.
+func MatchParallel(n int, match func(i int, m *Matcher) *MatchResults) []*MatchResults {
+	results := make([]*MatchResults, n)
+	workers := runtime.GOMAXPROCS(0)
+	if workers > n {
+		workers = n
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	// each batch only ever writes its own slot
+	recovered := make([]interface{}, n)
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			m := GetMatcher()
+			defer ReturnMatcher(m)
+			for i := range jobs {
+				results[i] = runMatch(i, m, match, func(r interface{}) {
+					recovered[i] = r
+				})
+				m.Reset()
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	for _, r := range recovered {
+		if r != nil {
+			panic(r)
+		}
+	}
+	return results
+}
+
+func runMatch(i int, m *Matcher, match func(int, *Matcher) *MatchResults, onPanic func(interface{})) (res *MatchResults) {
+	defer func() {
+		if r := recover(); r != nil {
+			onPanic(r)
+		}
+	}()
+	return match(i, m)
+}
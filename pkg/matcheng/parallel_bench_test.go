@@ -0,0 +1,42 @@
+package matcheng
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// BenchmarkMatchMarkets compares matching the batches of many markets one
+// after another with matching them in parallel.
+func BenchmarkMatchMarkets(b *testing.B) {
+	for _, markets := range []int{100, 250, 500} {
+		batches := makeBatches(rand.New(rand.NewSource(1)), markets, 200)
+
+		b.Run(fmt.Sprintf("sequential/markets=%d", markets), func(b *testing.B) {
+			m := GetMatcher()
+			defer ReturnMatcher(m)
+			b.ResetTimer()
+			start := time.Now()
+			for i := 0; i < b.N; i++ {
+				for _, batch := range batches {
+					batch.enqueue(m)
+					m.Match()
+					m.Reset()
+				}
+			}
+			b.ReportMetric(float64(b.N*markets)/time.Since(start).Seconds(), "markets/s")
+		})
+
+		b.Run(fmt.Sprintf("parallel/markets=%d", markets), func(b *testing.B) {
+			start := time.Now()
+			for i := 0; i < b.N; i++ {
+				MatchParallel(len(batches), func(j int, m *Matcher) *MatchResults {
+					batches[j].enqueue(m)
+					return m.Match()
+				})
+			}
+			b.ReportMetric(float64(b.N*markets)/time.Since(start).Seconds(), "markets/s")
+		})
+	}
+}
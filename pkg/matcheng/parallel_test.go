@@ -0,0 +1,103 @@
+package matcheng
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/xar-network/xar-network/testutil/testflags"
+	"github.com/xar-network/xar-network/types/store"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+func TestMatchParallel(t *testing.T) {
+	testflags.UnitTest(t)
+	batches := makeBatches(rand.New(rand.NewSource(1)), 150, 50)
+
+	expected := make([]*MatchResults, len(batches))
+	for i, b := range batches {
+		m := NewMatcher()
+		b.enqueue(m)
+		expected[i] = m.Match()
+	}
+
+	for run := 0; run < 3; run++ {
+		results := MatchParallel(len(batches), func(i int, m *Matcher) *MatchResults {
+			batches[i].enqueue(m)
+			return m.Match()
+		})
+		require.Equal(t, expected, results)
+	}
+	assert.Empty(t, MatchParallel(0, func(int, *Matcher) *MatchResults {
+		panic("no batches to match")
+	}))
+}
+
+func TestMatchParallel_Panic(t *testing.T) {
+	testflags.UnitTest(t)
+	assert.PanicsWithValue(t, "boom", func() {
+		MatchParallel(10, func(i int, m *Matcher) *MatchResults {
+			if i == 7 {
+				panic("boom")
+			}
+			return nil
+		})
+	})
+
+	// the first batch to panic wins, even if later ones panic sooner
+	for run := 0; run < 3; run++ {
+		assert.PanicsWithValue(t, 2, func() {
+			MatchParallel(10, func(i int, m *Matcher) *MatchResults {
+				if i == 2 {
+					time.Sleep(10 * time.Millisecond)
+				}
+				if i%3 == 2 {
+					panic(i)
+				}
+				return nil
+			})
+		})
+	}
+}
+
+// testBatch is the order book of one market.
+type testBatch struct {
+	bids []Order
+	asks []Order
+}
+
+func (b testBatch) enqueue(m *Matcher) {
+	for _, o := range b.bids {
+		m.Enqueue(Bid, o)
+	}
+	for _, o := range b.asks {
+		m.Enqueue(Ask, o)
+	}
+}
+
+// makeBatches creates crossing books of size orders on each side for n
+// markets.
+func makeBatches(r *rand.Rand, n int, size int) []testBatch {
+	id := store.NewEntityID(0)
+	order := func(lo, hi int) Order {
+		id = id.Inc()
+		return Order{
+			ID:       id,
+			Price:    sdk.NewUint(uint64(lo + r.Intn(hi-lo))),
+			Quantity: sdk.NewUint(uint64(1 + r.Intn(1000))),
+		}
+	}
+
+	batches := make([]testBatch, n)
+	for i := range batches {
+		for j := 0; j < size; j++ {
+			batches[i].bids = append(batches[i].bids, order(900, 1100))
+			batches[i].asks = append(batches[i].asks, order(1000, 1200))
+		}
+	}
+	return batches
+}
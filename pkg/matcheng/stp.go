@@ -0,0 +1,130 @@
+package matcheng
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/xar-network/xar-network/types/store"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	// CancelNewest cancels the newer of two orders of the same owner that
+	// would trade against each other.
+	CancelNewest STPMode = iota
+	// CancelOldest cancels the older of the two orders.
+	CancelOldest
+	// CancelBoth cancels both orders.
+	CancelBoth
+	// DecrementAndCancel reduces both orders by the quantity they would have
+	// traded, cancelling whichever of them is left with nothing.
+	DecrementAndCancel
+)
+
+// STPMode selects how self-trade prevention resolves two orders of the same
+// owner crossing each other. The mode of the newer order applies.
+type STPMode uint8
+
+var stpModeNames = map[STPMode]string{
+	CancelNewest:       "CANCEL_NEWEST",
+	CancelOldest:       "CANCEL_OLDEST",
+	CancelBoth:         "CANCEL_BOTH",
+	DecrementAndCancel: "DECREMENT_AND_CANCEL",
+}
+
+func (m STPMode) String() string {
+	name, ok := stpModeNames[m]
+	if !ok {
+		return "UNKNOWN"
+	}
+	return name
+}
+
+func (m STPMode) IsValid() bool {
+	_, ok := stpModeNames[m]
+	return ok
+}
+
+func STPModeFromString(str string) (STPMode, error) {
+	for m, name := range stpModeNames {
+		if name == str {
+			return m, nil
+		}
+	}
+	return CancelNewest, errors.New("invalid self-trade prevention mode")
+}
+
+func (m *STPMode) UnmarshalJSON(data []byte) error {
+	var str string
+	err := json.Unmarshal(data, &str)
+	if err != nil {
+		return err
+	}
+
+	out, err := STPModeFromString(str)
+	if err != nil {
+		return err
+	}
+	*m = out
+	return nil
+}
+
+func (m STPMode) MarshalJSON() ([]byte, error) {
+	return []byte("\"" + m.String() + "\""), nil
+}
+
+// Cancel reduces an order by QtyCancelled instead of filling it, because it
+// would otherwise have traded against an order of the same owner. Orders
+// with no QtyRemaining are cancelled completely. Both quantities include
+// the hidden reserve of iceberg orders.
+type Cancel struct {
+	OrderID      store.EntityID
+	QtyCancelled sdk.Uint
+	QtyRemaining sdk.Uint
+}
+
+// selfTrade returns true if both orders belong to the same known owner.
+func selfTrade(a Order, b Order) bool {
+	return !a.Owner.Empty() && a.Owner.Equals(b.Owner)
+}
+
+// preventSelfTrade resolves a self-trade between two orders according to
+// the newer order's mode. The orders' quantities are reduced in place.
+// Cancelled orders lose their hidden reserve as well, while decrementing
+// only takes the quantity that would have traded off the visible part.
+func preventSelfTrade(newer *Order, older *Order) []Cancel {
+	switch newer.STP {
+	case CancelOldest:
+		return []Cancel{cancelAll(older)}
+	case CancelBoth:
+		return []Cancel{cancelAll(newer), cancelAll(older)}
+	case DecrementAndCancel:
+		qty := newer.Quantity
+		if older.Quantity.LT(qty) {
+			qty = older.Quantity
+		}
+		return []Cancel{reduce(newer, qty), reduce(older, qty)}
+	default:
+		return []Cancel{cancelAll(newer)}
+	}
+}
+
+func reduce(order *Order, qty sdk.Uint) Cancel {
+	order.Quantity = order.Quantity.Sub(qty)
+	return Cancel{
+		OrderID:      order.ID,
+		QtyCancelled: qty,
+		QtyRemaining: order.Quantity.Add(order.reserve()),
+	}
+}
+
+// cancelAll cancels the whole remaining quantity of an order, including
+// its hidden reserve.
+func cancelAll(order *Order) Cancel {
+	reserve := order.reserve()
+	order.Reserve = zero
+	c := reduce(order, order.Quantity)
+	c.QtyCancelled = c.QtyCancelled.Add(reserve)
+	return c
+}
@@ -0,0 +1,180 @@
+package matcheng
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/xar-network/xar-network/testutil"
+	"github.com/xar-network/xar-network/testutil/testflags"
+	"github.com/xar-network/xar-network/types/store"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+var (
+	alice = sdk.AccAddress([]byte("alice"))
+	bob   = sdk.AccAddress([]byte("bob"))
+)
+
+func TestSTPMode_JSON(t *testing.T) {
+	testflags.UnitTest(t)
+	for mode := range stpModeNames {
+		data, err := mode.MarshalJSON()
+		require.NoError(t, err)
+		var out STPMode
+		require.NoError(t, out.UnmarshalJSON(data))
+		assert.Equal(t, mode, out)
+	}
+	var out STPMode
+	assert.Error(t, out.UnmarshalJSON([]byte("\"CANCEL_NOTHING\"")))
+}
+
+func TestContinuousMatcher_SelfTradePrevention(t *testing.T) {
+	testflags.UnitTest(t)
+	tests := []struct {
+		mode        STPMode
+		trades      int
+		cancels     []Cancel
+		crossingAsk uint64
+	}{
+		{
+			mode:   CancelNewest,
+			trades: 0,
+			cancels: []Cancel{
+				{store.NewEntityID(3), sdk.NewUint(10), sdk.NewUint(0)},
+			},
+			crossingAsk: 15,
+		},
+		{
+			mode:   CancelOldest,
+			trades: 1,
+			cancels: []Cancel{
+				{store.NewEntityID(1), sdk.NewUint(5), sdk.NewUint(0)},
+			},
+			crossingAsk: 0,
+		},
+		{
+			mode:   CancelBoth,
+			trades: 0,
+			cancels: []Cancel{
+				{store.NewEntityID(3), sdk.NewUint(10), sdk.NewUint(0)},
+				{store.NewEntityID(1), sdk.NewUint(5), sdk.NewUint(0)},
+			},
+			crossingAsk: 10,
+		},
+		{
+			mode:   DecrementAndCancel,
+			trades: 1,
+			cancels: []Cancel{
+				{store.NewEntityID(3), sdk.NewUint(5), sdk.NewUint(5)},
+				{store.NewEntityID(1), sdk.NewUint(5), sdk.NewUint(0)},
+			},
+			crossingAsk: 5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode.String(), func(t *testing.T) {
+			matcher := NewContinuousMatcher()
+			matcher.Enqueue(Ask, Order{ID: store.NewEntityID(1), Price: sdk.NewUint(5), Quantity: sdk.NewUint(5), Owner: alice})
+			matcher.Enqueue(Ask, Order{ID: store.NewEntityID(2), Price: sdk.NewUint(6), Quantity: sdk.NewUint(10), Owner: bob})
+
+			trades, cancels := matcher.MatchIncomingOrder(Bid, Order{
+				ID:       store.NewEntityID(3),
+				Price:    sdk.NewUint(6),
+				Quantity: sdk.NewUint(10),
+				Owner:    alice,
+				STP:      tt.mode,
+			})
+			require.Len(t, trades, tt.trades)
+			for _, trade := range trades {
+				assert.True(t, trade.Maker.OrderID.Equals(store.NewEntityID(2)))
+			}
+			require.Len(t, cancels, len(tt.cancels))
+			for i, exp := range tt.cancels {
+				assert.True(t, exp.OrderID.Equals(cancels[i].OrderID))
+				testutil.AssertEqualUints(t, exp.QtyCancelled, cancels[i].QtyCancelled)
+				testutil.AssertEqualUints(t, exp.QtyRemaining, cancels[i].QtyRemaining)
+			}
+			testutil.AssertEqualUints(t, sdk.NewUint(tt.crossingAsk), matcher.CrossingQuantity(Bid, sdk.NewUint(6)))
+		})
+	}
+}
+
+func TestContinuousMatcher_SelfTradePreventionIceberg(t *testing.T) {
+	testflags.UnitTest(t)
+	tests := []struct {
+		mode    STPMode
+		resting Cancel
+	}{
+		// cancelling the iceberg cancels its hidden reserve as well
+		{CancelOldest, Cancel{store.NewEntityID(1), sdk.NewUint(10), sdk.NewUint(0)}},
+		// decrementing only takes the visible quantity off
+		{DecrementAndCancel, Cancel{store.NewEntityID(1), sdk.NewUint(2), sdk.NewUint(8)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode.String(), func(t *testing.T) {
+			matcher := NewContinuousMatcher()
+			matcher.Enqueue(Ask, Order{ID: store.NewEntityID(1), Price: sdk.NewUint(5), Quantity: sdk.NewUint(2), Reserve: sdk.NewUint(8), Owner: alice})
+
+			_, cancels := matcher.MatchIncomingOrder(Bid, Order{
+				ID:       store.NewEntityID(2),
+				Price:    sdk.NewUint(5),
+				Quantity: sdk.NewUint(4),
+				Owner:    alice,
+				STP:      tt.mode,
+			})
+			var resting *Cancel
+			for i := range cancels {
+				if cancels[i].OrderID.Equals(tt.resting.OrderID) {
+					resting = &cancels[i]
+				}
+			}
+			require.NotNil(t, resting)
+			testutil.AssertEqualUints(t, tt.resting.QtyCancelled, resting.QtyCancelled)
+			testutil.AssertEqualUints(t, tt.resting.QtyRemaining, resting.QtyRemaining)
+		})
+	}
+}
+
+func TestMatcher_SelfTradePrevention(t *testing.T) {
+	testflags.UnitTest(t)
+	matcher := NewMatcher()
+	matcher.Enqueue(Bid, Order{ID: store.NewEntityID(1), Price: sdk.NewUint(10), Quantity: sdk.NewUint(10), Owner: alice})
+	matcher.Enqueue(Ask, Order{ID: store.NewEntityID(2), Price: sdk.NewUint(9), Quantity: sdk.NewUint(10), Owner: bob})
+	matcher.Enqueue(Ask, Order{ID: store.NewEntityID(3), Price: sdk.NewUint(8), Quantity: sdk.NewUint(4), Owner: alice, STP: DecrementAndCancel})
+
+	res := matcher.Match()
+	require.NotNil(t, res)
+	require.Len(t, res.Cancels, 2)
+	assert.True(t, res.Cancels[0].OrderID.Equals(store.NewEntityID(3)))
+	testutil.AssertEqualUints(t, sdk.NewUint(4), res.Cancels[0].QtyCancelled)
+	testutil.AssertEqualUints(t, sdk.ZeroUint(), res.Cancels[0].QtyRemaining)
+	assert.True(t, res.Cancels[1].OrderID.Equals(store.NewEntityID(1)))
+	testutil.AssertEqualUints(t, sdk.NewUint(4), res.Cancels[1].QtyCancelled)
+	testutil.AssertEqualUints(t, sdk.NewUint(6), res.Cancels[1].QtyRemaining)
+
+	fills := make(map[string]Fill)
+	for _, f := range res.Fills {
+		fills[f.OrderID.String()] = f
+	}
+	require.Len(t, fills, 2)
+	assertFill(t, fills["1"], 6, 0)
+	assertFill(t, fills["2"], 6, 4)
+}
+
+func TestMatcher_SelfTradePreventionEmptiesBatch(t *testing.T) {
+	testflags.UnitTest(t)
+	matcher := NewMatcher()
+	matcher.Enqueue(Bid, Order{ID: store.NewEntityID(1), Price: sdk.NewUint(10), Quantity: sdk.NewUint(10), Owner: alice})
+	matcher.Enqueue(Ask, Order{ID: store.NewEntityID(2), Price: sdk.NewUint(9), Quantity: sdk.NewUint(10), Owner: alice})
+
+	res := matcher.Match()
+	require.NotNil(t, res)
+	assert.Empty(t, res.Fills)
+	require.Len(t, res.Cancels, 1)
+	assert.True(t, res.Cancels[0].OrderID.Equals(store.NewEntityID(2)))
+}
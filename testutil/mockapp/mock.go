@@ -11,7 +11,9 @@ import (
 	"github.com/xar-network/xar-network/app"
 	"github.com/xar-network/xar-network/execution"
 	"github.com/xar-network/xar-network/types"
+	"github.com/xar-network/xar-network/x/authority"
 	"github.com/xar-network/xar-network/x/market"
+	"github.com/xar-network/xar-network/x/oracle"
 	"github.com/xar-network/xar-network/x/order"
 
 	"github.com/cosmos/cosmos-sdk/codec"
@@ -35,6 +37,8 @@ type MockApp struct {
 	OrderKeeper     order.Keeper
 	BankKeeper      bank.Keeper
 	ExecutionKeeper execution.Keeper
+	OracleKeeper    oracle.Keeper
+	AuthorityKeeper authority.Keeper
 }
 
 type Option func(t *testing.T, app *MockApp)
@@ -63,6 +67,8 @@ func New(t *testing.T, options ...Option) *MockApp {
 		OrderKeeper:     dex.OrderKeeper(),
 		BankKeeper:      dex.BankKeeper(),
 		ExecutionKeeper: dex.ExecKeeper(),
+		OracleKeeper:    dex.OracleKeeper(),
+		AuthorityKeeper: dex.AuthorityKeeper(),
 	}
 
 	for _, opt := range options {
@@ -71,3 +77,12 @@ func New(t *testing.T, options ...Option) *MockApp {
 
 	return mock
 }
+
+// GrantRole grants a role to addrs without expiry.
+func (app *MockApp) GrantRole(role string, addrs ...sdk.AccAddress) {
+	r := app.AuthorityKeeper.GetRole(app.Ctx, role)
+	for _, addr := range addrs {
+		r.Grants = append(r.Grants, authority.NewGrant(addr, time.Time{}))
+	}
+	app.AuthorityKeeper.SetRole(app.Ctx, r)
+}
@@ -0,0 +1,40 @@
+package types
+
+import (
+	"encoding/binary"
+
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/xar-network/xar-network/types/store"
+)
+
+const CheckpointTableKey = "checkpoint"
+
+// Checkpoints records, per consumer, the sequence number of the last log
+// entry the consumer applied to the market-data database. A checkpoint is
+// written in the same batch as the data of the entry it covers, so every
+// entry is applied exactly once, even across a crash.
+type Checkpoints struct {
+	as store.ArchiveStore
+}
+
+func NewCheckpoints(db dbm.DB) Checkpoints {
+	return Checkpoints{
+		as: store.NewTable(db, CheckpointTableKey),
+	}
+}
+
+// Get returns the consumer's checkpoint, zero if it never applied an entry.
+func (c Checkpoints) Get(name string) uint64 {
+	bz := c.as.Get([]byte(name))
+	if bz == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint64(bz)
+}
+
+func (c Checkpoints) Set(name string, seq uint64) {
+	bz := make([]byte, 8)
+	binary.BigEndian.PutUint64(bz, seq)
+	c.as.Set([]byte(name), bz)
+}
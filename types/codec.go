@@ -0,0 +1,26 @@
+package types
+
+import "github.com/cosmos/cosmos-sdk/codec"
+
+// EventCdc encodes market-data events wherever they are stored before they
+// reach the event handlers.
+var EventCdc = codec.New()
+
+func init() {
+	RegisterEvents(EventCdc)
+}
+
+// Event is any of the market-data events below.
+type Event interface{}
+
+// RegisterEvents registers the market-data events so that they can be
+// encoded as an Event.
+func RegisterEvents(cdc *codec.Codec) {
+	cdc.RegisterInterface((*Event)(nil), nil)
+	cdc.RegisterConcrete(Batch{}, "xar/event/Batch", nil)
+	cdc.RegisterConcrete(Fill{}, "xar/event/Fill", nil)
+	cdc.RegisterConcrete(OrderCreated{}, "xar/event/OrderCreated", nil)
+	cdc.RegisterConcrete(OrderCancelled{}, "xar/event/OrderCancelled", nil)
+	cdc.RegisterConcrete(OrderReduced{}, "xar/event/OrderReduced", nil)
+	cdc.RegisterConcrete(BurnCreated{}, "xar/event/BurnCreated", nil)
+}
@@ -46,3 +46,16 @@ func ErrOrBlankResult(err sdk.Error) sdk.Result {
 
 	return err.Result()
 }
+
+// MessageResult emits the message event of a message of module sent by
+// sender and returns the events of ctx along with log.
+func MessageResult(ctx sdk.Context, module string, sender sdk.AccAddress, log string) sdk.Result {
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			sdk.EventTypeMessage,
+			sdk.NewAttribute(sdk.AttributeKeyModule, module),
+			sdk.NewAttribute(sdk.AttributeKeySender, sender.String()),
+		),
+	)
+	return sdk.Result{Log: log, Events: ctx.EventManager().Events()}
+}
@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/log"
 
 	"github.com/xar-network/xar-network/testutil/testflags"
 
@@ -16,3 +18,16 @@ func TestErrOrBlankResult(t *testing.T) {
 	assert.EqualValues(t, err.Result(), ErrOrBlankResult(err))
 	assert.EqualValues(t, sdk.Result{}, ErrOrBlankResult(nil))
 }
+
+func TestMessageResult(t *testing.T) {
+	testflags.UnitTest(t)
+	ctx := sdk.NewContext(nil, abci.Header{}, false, log.NewNopLogger())
+	sender := sdk.AccAddress([]byte("sender"))
+	res := MessageResult(ctx, "order", sender, "order_id:1")
+	assert.Equal(t, "order_id:1", res.Log)
+	assert.Equal(t, sdk.Events{sdk.NewEvent(
+		sdk.EventTypeMessage,
+		sdk.NewAttribute(sdk.AttributeKeyModule, "order"),
+		sdk.NewAttribute(sdk.AttributeKeySender, sender.String()),
+	)}, res.Events)
+}
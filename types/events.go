@@ -5,6 +5,7 @@ import (
 
 	"github.com/xar-network/xar-network/pkg/matcheng"
 	"github.com/xar-network/xar-network/types/store"
+	ordertypes "github.com/xar-network/xar-network/x/order/types"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 )
@@ -33,6 +34,9 @@ type Fill struct {
 	BlockNumber int64
 	BlockTime   int64
 	Price       sdk.Uint
+	Type        matcheng.OrderType
+	Maker       bool
+	Fee         sdk.Coins
 }
 
 type OrderCreated struct {
@@ -44,10 +48,25 @@ type OrderCreated struct {
 	Quantity          sdk.Uint
 	TimeInForceBlocks uint16
 	CreatedBlock      int64
+	Type              matcheng.OrderType
+	ExpiryType        ordertypes.ExpiryType
+	GoodTillTime      int64
+	STPMode           matcheng.STPMode
+	// DisplayQuantity is only set for iceberg orders.
+	DisplayQuantity *sdk.Uint
 }
 
 type OrderCancelled struct {
 	OrderID store.EntityID
+	Reason  ordertypes.CancelReason
+}
+
+// OrderReduced is published when an order's open quantity is reduced by
+// Quantity without being filled or cancelled completely.
+type OrderReduced struct {
+	OrderID  store.EntityID
+	Quantity sdk.Uint
+	Reason   ordertypes.CancelReason
 }
 
 type BurnCreated struct {
@@ -0,0 +1,153 @@
+package types
+
+import (
+	"encoding/binary"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	cmn "github.com/tendermint/tendermint/libs/common"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// OutboxStoreKey is the name of the transient store the outbox keeps the
+// events of the current block in.
+const OutboxStoreKey = "transient_outbox"
+
+// The events of a block can also be attached to its end block response, one
+// ABCI event each, for indexers that follow the chain over RPC and for
+// rebuilding the index from the stored responses. The event attribute holds
+// the event's binary encoding, the same one the outbox and the event log
+// keep.
+const (
+	EventTypeMarketData = "market_data"
+	AttributeKeyEvent   = "event"
+)
+
+var (
+	outboxSeqKey   = []byte("seq")
+	outboxEventKey = []byte("event/")
+)
+
+// Publisher publishes the market-data events caused by the state changes in
+// ctx.
+type Publisher interface {
+	Publish(ctx sdk.Context, event Event)
+}
+
+// CommittedEvent is an event as it is published to the backend once the
+// block at Height that caused it is about to be committed. Index is the
+// position of the event among the events of its block.
+type CommittedEvent struct {
+	Height int64
+	Index  int
+	Event  Event
+}
+
+// Outbox holds back market-data events until the block that caused them is
+// committed, so that the event handlers never see events of state that
+// consensus did not keep.
+//
+// Events are written to a transient store through the context they were
+// published in. Their writes are dropped along with everything else when a
+// transaction fails, when a cached context is discarded, and in CheckTx and
+// simulation. Collect reads what is left at the end of the block and Flush
+// publishes it to the backend right before the commit. By then consensus
+// has decided the block, so it is committed even if the process dies before
+// the commit: the block is executed again on restart and publishes the same
+// events, which a backend that keeps a log skips by their height and index.
+type Outbox struct {
+	key     sdk.StoreKey
+	backend Backend
+	pending []Event
+}
+
+func NewOutbox(key sdk.StoreKey, backend Backend) *Outbox {
+	return &Outbox{
+		key:     key,
+		backend: backend,
+	}
+}
+
+// Publish adds an event to the block's outbox. The outbox is not charged
+// gas, so publishing events does not change what transactions cost.
+func (o *Outbox) Publish(ctx sdk.Context, event Event) {
+	kv := ctx.MultiStore().GetKVStore(o.key)
+	var seq uint64
+	if bz := kv.Get(outboxSeqKey); bz != nil {
+		seq = binary.BigEndian.Uint64(bz)
+	}
+	seq++
+	kv.Set(outboxSeqKey, sdk.Uint64ToBigEndian(seq))
+	kv.Set(append(outboxEventKey, sdk.Uint64ToBigEndian(seq)...), EventCdc.MustMarshalBinaryBare(&event))
+}
+
+// Collect takes the events published in ctx so far, in the order they were
+// published, and returns them as ABCI events. It must run at the end of the
+// block, after the last event was published and before the transient store
+// is reset on commit.
+func (o *Outbox) Collect(ctx sdk.Context) []abci.Event {
+	kv := ctx.MultiStore().GetKVStore(o.key)
+	iter := sdk.KVStorePrefixIterator(kv, outboxEventKey)
+	defer iter.Close()
+	var events []abci.Event
+	for ; iter.Valid(); iter.Next() {
+		var event Event
+		EventCdc.MustUnmarshalBinaryBare(iter.Value(), &event)
+		o.pending = append(o.pending, event)
+		events = append(events, marketDataEvent(iter.Value()))
+	}
+	return events
+}
+
+// Flush publishes the collected events to the backend, stamped with the
+// height of the block about to be committed. Without a backend the events
+// are only attached to the block. It stops at the first event the backend
+// fails to take and returns the error, keeping that event and the ones
+// after it pending.
+func (o *Outbox) Flush(height int64) error {
+	if o.backend != nil {
+		for i, event := range o.pending {
+			if err := o.backend.Publish(CommittedEvent{Height: height, Index: i, Event: event}); err != nil {
+				o.pending = o.pending[i:]
+				return err
+			}
+		}
+	}
+	o.pending = nil
+	return nil
+}
+
+// NewMarketDataEvent returns the ABCI event an event is attached to an end
+// block response as.
+func NewMarketDataEvent(event Event) abci.Event {
+	return marketDataEvent(EventCdc.MustMarshalBinaryBare(&event))
+}
+
+func marketDataEvent(bz []byte) abci.Event {
+	return abci.Event{
+		Type:       EventTypeMarketData,
+		Attributes: []cmn.KVPair{{Key: []byte(AttributeKeyEvent), Value: bz}},
+	}
+}
+
+// MarketDataEvents decodes the market-data events among a block's end block
+// events, in the order they were published.
+func MarketDataEvents(events []abci.Event) ([]Event, error) {
+	var out []Event
+	for _, ev := range events {
+		if ev.Type != EventTypeMarketData {
+			continue
+		}
+		for _, attr := range ev.Attributes {
+			if string(attr.Key) != AttributeKeyEvent {
+				continue
+			}
+			var event Event
+			if err := EventCdc.UnmarshalBinaryBare(attr.Value, &event); err != nil {
+				return nil, err
+			}
+			out = append(out, event)
+		}
+	}
+	return out, nil
+}
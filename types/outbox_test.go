@@ -0,0 +1,81 @@
+package types
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/log"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/xar-network/xar-network/testutil/testflags"
+	"github.com/xar-network/xar-network/types/store"
+
+	cstore "github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+type recordingBackend struct {
+	items []interface{}
+	err   error
+}
+
+func (r *recordingBackend) Publish(item interface{}) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.items = append(r.items, item)
+	return nil
+}
+
+func (r *recordingBackend) Consume() interface{} {
+	return nil
+}
+
+func TestOutbox(t *testing.T) {
+	testflags.UnitTest(t)
+	key := sdk.NewTransientStoreKey(OutboxStoreKey)
+	ms := cstore.NewCommitMultiStore(dbm.NewMemDB())
+	ms.MountStoreWithDB(key, sdk.StoreTypeTransient, nil)
+	require.NoError(t, ms.LoadLatestVersion())
+	ctx := sdk.NewContext(ms.CacheMultiStore(), abci.Header{Height: 5}, false, log.NewNopLogger())
+
+	backend := &recordingBackend{}
+	outbox := NewOutbox(key, backend)
+	cancelled := func(id uint64) Event {
+		return OrderCancelled{OrderID: store.NewEntityID(id)}
+	}
+
+	outbox.Publish(ctx, cancelled(1))
+	// a failed transaction's cache is never written
+	failed, _ := ctx.CacheContext()
+	outbox.Publish(failed, cancelled(2))
+	succeeded, write := ctx.CacheContext()
+	outbox.Publish(succeeded, cancelled(3))
+	write()
+
+	attached, err := MarketDataEvents(outbox.Collect(ctx))
+	require.NoError(t, err)
+	require.Equal(t, []Event{cancelled(1), cancelled(3)}, attached)
+	require.Empty(t, backend.items)
+	require.NoError(t, outbox.Flush(5))
+	require.Equal(t, []interface{}{
+		CommittedEvent{Height: 5, Index: 0, Event: cancelled(1)},
+		CommittedEvent{Height: 5, Index: 1, Event: cancelled(3)},
+	}, backend.items)
+
+	// flushed events are not published again
+	require.NoError(t, outbox.Flush(6))
+	require.Len(t, backend.items, 2)
+
+	// events the backend fails to take stay pending
+	next := sdk.NewContext(ms.CacheMultiStore(), abci.Header{Height: 6}, false, log.NewNopLogger())
+	outbox.Publish(next, cancelled(4))
+	outbox.Collect(next)
+	backend.err = errors.New("disk full")
+	require.Error(t, outbox.Flush(6))
+	backend.err = nil
+	require.NoError(t, outbox.Flush(6))
+	require.Equal(t, CommittedEvent{Height: 6, Index: 0, Event: cancelled(4)}, backend.items[2])
+}
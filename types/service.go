@@ -4,45 +4,142 @@ import (
 	tmlog "github.com/tendermint/tendermint/libs/log"
 
 	"github.com/xar-network/xar-network/pkg/log"
+	"github.com/xar-network/xar-network/types/store"
 )
 
+// Subscription is an event handler of the local consumer. Name identifies
+// its checkpoint when the backend keeps a log.
+type Subscription struct {
+	Name    string
+	Handler EventHandler
+}
+
 type LocalConsumer struct {
-	queue    Backend
-	quitCh   chan bool
-	handlers []EventHandler
-	lgr      tmlog.Logger
+	queue       Backend
+	quitCh      chan struct{}
+	doneCh      chan struct{}
+	subs        []Subscription
+	db          *store.BatchDB
+	checkpoints Checkpoints
+	lgr         tmlog.Logger
 }
 
-func NewLocalConsumer(queue Backend, hdlrs []EventHandler) *LocalConsumer {
+// NewLocalConsumer returns a consumer handing the items of queue to subs.
+// The subscriptions must write to db, which the consumer commits together
+// with the checkpoints after every item.
+func NewLocalConsumer(queue Backend, db *store.BatchDB, subs []Subscription) *LocalConsumer {
 	return &LocalConsumer{
-		queue:    queue,
-		handlers: hdlrs,
-		lgr:      log.WithModule("local-consumer"),
+		queue:       queue,
+		quitCh:      make(chan struct{}),
+		doneCh:      make(chan struct{}),
+		subs:        subs,
+		db:          db,
+		checkpoints: NewCheckpoints(db),
+		lgr:         log.WithModule("local-consumer"),
+	}
+}
+
+// Replay hands every logged entry a subscription has not applied yet to it
+// again and then prunes the entries every subscription has applied from the
+// log. It must run before Start, so that replayed entries come before the
+// ones published from then on.
+func (s *LocalConsumer) Replay() error {
+	replayer, ok := s.queue.(Replayer)
+	if !ok {
+		return nil
+	}
+	if err := s.replay(replayer); err != nil {
+		return err
+	}
+
+	applied := replayer.LastSeq()
+	for _, sub := range s.subs {
+		if seq := s.checkpoints.Get(sub.Name); seq < applied {
+			applied = seq
+		}
+	}
+	return replayer.Prune(applied)
+}
+
+func (s *LocalConsumer) replay(replayer Replayer) error {
+	last := replayer.LastSeq()
+	from := last
+	for _, sub := range s.subs {
+		seq := s.checkpoints.Get(sub.Name)
+		if seq > last {
+			// the log was removed; the consumer can only go on from its end
+			s.lgr.Error("checkpoint is ahead of the event log", "consumer", sub.Name, "checkpoint", seq, "log", last)
+			s.checkpoints.Set(sub.Name, last)
+			seq = last
+		}
+		if seq < from {
+			from = seq
+		}
+	}
+	s.db.Commit()
+	if from == last {
+		return nil
 	}
+
+	s.lgr.Info("replaying event log", "from", from+1, "to", last)
+	return replayer.Replay(from, func(entry LogEntry) bool {
+		s.handleItem(entry)
+		return true
+	})
 }
 
 func (s *LocalConsumer) Start() {
 	go func() {
+		defer close(s.doneCh)
 		for {
+			item := s.queue.Consume()
 			select {
 			case <-s.quitCh:
 				return
 			default:
-				item := s.queue.Consume()
 				s.handleItem(item)
 			}
 		}
 	}()
 }
 
+// Stop makes the consumer return once the item it waits for is consumed,
+// without handling it. The queue must then hand it an item, e.g. by being
+// stopped itself; Done is closed once the consumer returned.
 func (s *LocalConsumer) Stop() {
-	s.quitCh <- true
+	close(s.quitCh)
+}
+
+func (s *LocalConsumer) Done() <-chan struct{} {
+	return s.doneCh
 }
 
 func (s *LocalConsumer) handleItem(item interface{}) {
-	for _, hdlr := range s.handlers {
-		if err := hdlr.OnEvent(item); err != nil {
-			s.lgr.Error("error consuming queue item", "err", err.Error())
+	switch it := item.(type) {
+	case LogEntry:
+		for _, sub := range s.subs {
+			// entries at or before the checkpoint were applied before a
+			// restart
+			if it.Seq <= s.checkpoints.Get(sub.Name) {
+				continue
+			}
+			s.handle(sub, it.Event)
+			s.checkpoints.Set(sub.Name, it.Seq)
+		}
+	case CommittedEvent:
+		for _, sub := range s.subs {
+			s.handle(sub, it.Event)
+		}
+	default:
+		for _, sub := range s.subs {
+			s.handle(sub, item)
 		}
 	}
+	s.db.Commit()
+}
+
+func (s *LocalConsumer) handle(sub Subscription, event interface{}) {
+	if err := sub.Handler.OnEvent(event); err != nil {
+		s.lgr.Error("error consuming queue item", "consumer", sub.Name, "err", err.Error())
+	}
 }
@@ -0,0 +1,88 @@
+package store
+
+import (
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/cosmos/cosmos-sdk/store/cachekv"
+	"github.com/cosmos/cosmos-sdk/store/dbadapter"
+)
+
+// BatchDB is a database whose writes are held back in memory until Commit
+// writes them to the underlying database in a single batch, so that after
+// a crash either all or none of them are found. Reads through the BatchDB
+// see the writes held back, readers of the underlying database only see
+// committed ones. It is not safe for concurrent use.
+type BatchDB struct {
+	dbm.DB
+	parent *batchingStore
+	cache  *cachekv.Store
+}
+
+func NewBatchDB(db dbm.DB) *BatchDB {
+	parent := &batchingStore{
+		Store: dbadapter.Store{DB: db},
+		batch: db.NewBatch(),
+	}
+	return &BatchDB{
+		DB:     db,
+		parent: parent,
+		cache:  cachekv.NewStore(parent),
+	}
+}
+
+func (b *BatchDB) Get(key []byte) []byte {
+	return b.cache.Get(key)
+}
+
+func (b *BatchDB) Has(key []byte) bool {
+	return b.cache.Has(key)
+}
+
+func (b *BatchDB) Set(key, value []byte) {
+	b.cache.Set(key, value)
+}
+
+// SetSync is the same as Set, the write is only synced by Commit.
+func (b *BatchDB) SetSync(key, value []byte) {
+	b.cache.Set(key, value)
+}
+
+func (b *BatchDB) Delete(key []byte) {
+	b.cache.Delete(key)
+}
+
+// DeleteSync is the same as Delete, the write is only synced by Commit.
+func (b *BatchDB) DeleteSync(key []byte) {
+	b.cache.Delete(key)
+}
+
+func (b *BatchDB) Iterator(start, end []byte) dbm.Iterator {
+	return b.cache.Iterator(start, end)
+}
+
+func (b *BatchDB) ReverseIterator(start, end []byte) dbm.Iterator {
+	return b.cache.ReverseIterator(start, end)
+}
+
+// Commit writes the writes held back to the underlying database in one
+// batch.
+func (b *BatchDB) Commit() {
+	b.cache.Write()
+	b.parent.batch.Write()
+	b.parent.batch.Close()
+	b.parent.batch = b.DB.NewBatch()
+}
+
+// batchingStore reads from a database and adds the writes to a batch.
+type batchingStore struct {
+	dbadapter.Store
+	batch dbm.Batch
+}
+
+func (s *batchingStore) Set(key, value []byte) {
+	s.batch.Set(key, value)
+}
+
+func (s *batchingStore) Delete(key []byte) {
+	s.batch.Delete(key)
+}
@@ -0,0 +1,42 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	dbm "github.com/tendermint/tm-db"
+)
+
+func TestBatchDB(t *testing.T) {
+	db := dbm.NewMemDB()
+	db.Set([]byte("a"), []byte("1"))
+	db.Set([]byte("b"), []byte("2"))
+	bdb := NewBatchDB(db)
+
+	bdb.Set([]byte("c"), []byte("3"))
+	bdb.Delete([]byte("a"))
+	t.Run("reads see the writes held back", func(t *testing.T) {
+		assert.Equal(t, "3", string(bdb.Get([]byte("c"))))
+		assert.False(t, bdb.Has([]byte("a")))
+		var keys []string
+		iter := bdb.Iterator(nil, nil)
+		for ; iter.Valid(); iter.Next() {
+			keys = append(keys, string(iter.Key()))
+		}
+		iter.Close()
+		assert.Equal(t, []string{"b", "c"}, keys)
+	})
+	t.Run("the database only sees committed writes", func(t *testing.T) {
+		assert.True(t, db.Has([]byte("a")))
+		assert.False(t, db.Has([]byte("c")))
+		bdb.Commit()
+		assert.False(t, db.Has([]byte("a")))
+		assert.Equal(t, "3", string(db.Get([]byte("c"))))
+	})
+	t.Run("goes on after a commit", func(t *testing.T) {
+		bdb.Set([]byte("d"), []byte("4"))
+		assert.False(t, db.Has([]byte("d")))
+		bdb.Commit()
+		assert.Equal(t, "4", string(db.Get([]byte("d"))))
+	})
+}
@@ -80,3 +80,17 @@ func (t *Table) Substore(prefix string) ArchiveStore {
 		prefix: fmt.Sprintf("%s/%s", t.prefix, prefix),
 	}
 }
+
+// Clear deletes every key of the table.
+func (t *Table) Clear() {
+	start := []byte(t.prefix + "/")
+	iter := t.db.Iterator(start, sdk.PrefixEndBytes(start))
+	var keys [][]byte
+	for ; iter.Valid(); iter.Next() {
+		keys = append(keys, iter.Key())
+	}
+	iter.Close()
+	for _, key := range keys {
+		t.db.Delete(key)
+	}
+}
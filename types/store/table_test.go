@@ -127,4 +127,16 @@ func TestTable(t *testing.T) {
 		// there are 255 entities, so this goes to -1
 		assert.Equal(t, -1, i)
 	})
+	t.Run("clear deletes the table's keys only", func(t *testing.T) {
+		tb2 := NewTable(db, "bar")
+		tb3 := NewTable(db, "foobar")
+		tb2.Set(k1, []byte("value"))
+		tb3.Set(k1, []byte("value"))
+
+		tb1.Clear()
+		assert.False(t, tb1.Has(k1))
+		assert.False(t, tb1.Has(PrefixKeyString("pref1", []byte{0x01})))
+		assert.True(t, tb2.Has(k1))
+		assert.True(t, tb3.Has(k1))
+	})
 }
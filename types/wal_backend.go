@@ -0,0 +1,373 @@
+package types
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// LogEntry is a committed event as it is stored in the write-ahead log.
+// Seq numbers the entries of a log from one without gaps. Index is the
+// position of the event among the events of its block.
+type LogEntry struct {
+	Seq    uint64
+	Height int64
+	Event  Event
+	Index  int
+}
+
+// Replayer is a Backend that can publish the events it has seen before
+// again, so that consumers can catch up after a restart.
+type Replayer interface {
+	Backend
+	// LastSeq returns the sequence number of the last entry in the log.
+	LastSeq() uint64
+	// Replay calls cb with every entry after seq, oldest first, until cb
+	// returns false.
+	Replay(seq uint64, cb func(LogEntry) bool) error
+	// Prune drops the entries up to seq, once every consumer applied them.
+	Prune(seq uint64) error
+}
+
+// WALBackend is a Backend that appends every committed event to a log file
+// before it is handed to consumers, so that events in flight survive a
+// crash. Each record is the length of the entry, a CRC32 of it and the
+// amino-encoded LogEntry. A record that was only partially written when the
+// process died is cut off when the log is opened again. A corrupt record
+// with more of the log after it fails opening the log instead, as cutting
+// it off would silently drop the entries after it.
+//
+// Events are published before their block is committed, so a block that
+// was executed again after a crash publishes events that are already
+// logged. Those are recognised by their height and index and skipped.
+type WALBackend struct {
+	mtx  sync.Mutex
+	path string
+	file *os.File
+	seq  uint64
+	// last is the last entry in the log
+	last LogEntry
+
+	// queue hands entries to the consumer once they are on disk
+	queue *MemBackend
+}
+
+func NewWALBackend(path string) (*WALBackend, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	w := &WALBackend{
+		path:  path,
+		file:  file,
+		queue: NewMemBackend(),
+	}
+
+	// find the last complete entry and drop a torn record after it
+	var end int64
+	err = readLog(file, func(entry LogEntry, offset int64) bool {
+		w.seq = entry.Seq
+		w.last = entry
+		end = offset
+		return true
+	})
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	if err := file.Truncate(end); err != nil {
+		file.Close()
+		return nil, err
+	}
+	if _, err := file.Seek(end, io.SeekStart); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *WALBackend) Start() {
+	w.queue.Start()
+}
+
+func (w *WALBackend) Stop() {
+	w.queue.Stop()
+	_ = w.Close()
+}
+
+// Close closes the log file without stopping the queue, for logs that were
+// only opened to be read.
+func (w *WALBackend) Close() error {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	return w.file.Close()
+}
+
+// Publish appends a CommittedEvent to the log and syncs it to disk before
+// the consumer sees it. Events at or before the last logged one are
+// skipped.
+func (w *WALBackend) Publish(item interface{}) error {
+	committed, ok := item.(CommittedEvent)
+	if !ok {
+		return fmt.Errorf("cannot log %T, only committed events", item)
+	}
+
+	w.mtx.Lock()
+	if w.seq > 0 && (committed.Height < w.last.Height || committed.Height == w.last.Height && committed.Index <= w.last.Index) {
+		w.mtx.Unlock()
+		return nil
+	}
+	entry := LogEntry{
+		Seq:    w.seq + 1,
+		Height: committed.Height,
+		Event:  committed.Event,
+		Index:  committed.Index,
+	}
+	err := w.append(entry)
+	if err == nil {
+		w.seq = entry.Seq
+		w.last = entry
+	}
+	w.mtx.Unlock()
+	if err != nil {
+		return err
+	}
+	return w.queue.Publish(entry)
+}
+
+func (w *WALBackend) Consume() interface{} {
+	return w.queue.Consume()
+}
+
+func (w *WALBackend) LastSeq() uint64 {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	return w.seq
+}
+
+// Replay reads the log from a separate handle, so it can run while new
+// entries are appended.
+func (w *WALBackend) Replay(seq uint64, cb func(LogEntry) bool) error {
+	file, err := os.Open(w.path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return readLog(file, func(entry LogEntry, _ int64) bool {
+		if entry.Seq <= seq {
+			return true
+		}
+		return cb(entry)
+	})
+}
+
+// Prune drops the entries up to seq so that the log does not grow without
+// bound and is not read in full at every start. The last entry is always
+// kept, so that the sequence and the heights of the log carry on. The kept
+// entries are copied to a new file that replaces the log, so Prune must not
+// run concurrently with Replay.
+func (w *WALBackend) Prune(seq uint64) error {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	if w.seq == 0 {
+		return nil
+	}
+	if seq >= w.seq {
+		seq = w.seq - 1
+	}
+
+	src, err := os.Open(w.path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	var first LogEntry
+	if err := readLog(src, func(entry LogEntry, _ int64) bool {
+		first = entry
+		return false
+	}); err != nil {
+		return err
+	}
+	if first.Seq > seq {
+		return nil
+	}
+	if _, err := src.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	tmpPath := w.path + ".prune"
+	tmp, err := os.OpenFile(tmpPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	var werr error
+	err = readLog(src, func(entry LogEntry, _ int64) bool {
+		if entry.Seq <= seq {
+			return true
+		}
+		werr = writeRecord(tmp, entry)
+		return werr == nil
+	})
+	if err == nil {
+		err = werr
+	}
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, w.path)
+	}
+	if err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	w.file.Close()
+	w.file = tmp
+	return syncDir(filepath.Dir(w.path))
+}
+
+func (w *WALBackend) append(entry LogEntry) error {
+	if err := writeRecord(w.file, entry); err != nil {
+		return err
+	}
+	return w.file.Sync()
+}
+
+func writeRecord(file *os.File, entry LogEntry) error {
+	bz, err := EventCdc.MarshalBinaryBare(entry)
+	if err != nil {
+		return err
+	}
+	rec := make([]byte, binary.MaxVarintLen64+4+len(bz))
+	n := binary.PutUvarint(rec, uint64(len(bz)))
+	binary.BigEndian.PutUint32(rec[n:], crc32.ChecksumIEEE(bz))
+	n += 4
+	n += copy(rec[n:], bz)
+	_, err = file.Write(rec[:n])
+	return err
+}
+
+// syncDir syncs a directory so that a file renamed into it survives a
+// crash.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
+
+// maxRecordSize bounds the entries read back, so that a corrupt length
+// is not taken for a huge record.
+const maxRecordSize = 1 << 26
+
+var (
+	errTornRecord    = errors.New("torn record")
+	errCorruptRecord = errors.New("corrupt record")
+)
+
+// readLog calls cb with every complete entry in r and the offset right
+// after it. A torn record at the end of the log, left by a crash while it
+// was written, ends the log without an error. A corrupt record with more of
+// the log after it is an error.
+func readLog(r io.Reader, cb func(entry LogEntry, offset int64) bool) error {
+	cr := &countingReader{r: bufio.NewReader(r)}
+	for {
+		start := cr.n
+		entry, err := readRecord(cr)
+		if err == io.EOF || err == errTornRecord {
+			return nil
+		}
+		if err == errCorruptRecord {
+			return fmt.Errorf("corrupt event log record at offset %d", start)
+		}
+		if err != nil {
+			return err
+		}
+		if !cb(entry, cr.n) {
+			return nil
+		}
+	}
+}
+
+func readRecord(cr *countingReader) (LogEntry, error) {
+	var entry LogEntry
+	size, err := binary.ReadUvarint(cr)
+	if err == io.EOF {
+		return entry, io.EOF
+	}
+	if err == io.ErrUnexpectedEOF {
+		return entry, errTornRecord
+	}
+	if err != nil || size > maxRecordSize {
+		return entry, errCorruptRecord
+	}
+	if size == 0 {
+		// entries are never empty, but a crash can leave a tail of zeros
+		// when the file grew before its data was written
+		return entry, cr.tailError()
+	}
+	var sum [4]byte
+	if _, err := io.ReadFull(cr, sum[:]); err != nil {
+		return entry, errTornRecord
+	}
+	bz := make([]byte, size)
+	if _, err := io.ReadFull(cr, bz); err != nil {
+		return entry, errTornRecord
+	}
+	if crc32.ChecksumIEEE(bz) != binary.BigEndian.Uint32(sum[:]) {
+		return entry, cr.tailError()
+	}
+	if err := EventCdc.UnmarshalBinaryBare(bz, &entry); err != nil {
+		return entry, err
+	}
+	return entry, nil
+}
+
+type countingReader struct {
+	r *bufio.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// tailError returns errTornRecord if nothing but zeros is left to read,
+// i.e. the bad record is the torn end of the log, and errCorruptRecord if
+// the log goes on after it.
+func (c *countingReader) tailError() error {
+	var buf [4096]byte
+	for {
+		n, err := c.Read(buf[:])
+		for _, b := range buf[:n] {
+			if b != 0 {
+				return errCorruptRecord
+			}
+		}
+		if err == io.EOF {
+			return errTornRecord
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (c *countingReader) ReadByte() (byte, error) {
+	b, err := c.r.ReadByte()
+	if err == nil {
+		c.n++
+	}
+	return b, err
+}
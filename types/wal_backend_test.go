@@ -0,0 +1,180 @@
+package types
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/xar-network/xar-network/testutil/testflags"
+	"github.com/xar-network/xar-network/types/store"
+)
+
+type recordingHandler struct {
+	events []interface{}
+}
+
+func (r *recordingHandler) OnEvent(event interface{}) error {
+	r.events = append(r.events, event)
+	return nil
+}
+
+func TestWALBackend(t *testing.T) {
+	testflags.UnitTest(t)
+	dir, err := ioutil.TempDir("", "wal")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "events.wal")
+	cancelled := func(id uint64) Event {
+		return OrderCancelled{OrderID: store.NewEntityID(id)}
+	}
+
+	wal, err := NewWALBackend(path)
+	require.NoError(t, err)
+	wal.Start()
+	for i := uint64(1); i <= 3; i++ {
+		require.NoError(t, wal.Publish(CommittedEvent{Height: int64(i), Event: cancelled(i)}))
+		require.Equal(t, LogEntry{Seq: i, Height: int64(i), Event: cancelled(i)}, wal.Consume())
+	}
+	require.Error(t, wal.Publish(cancelled(4)))
+	wal.Stop()
+
+	// a record cut short by a crash is dropped
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0600)
+	require.NoError(t, err)
+	_, err = f.Write([]byte{40, 1, 2})
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	wal, err = NewWALBackend(path)
+	require.NoError(t, err)
+	wal.Start()
+	defer wal.Stop()
+	require.Equal(t, uint64(3), wal.LastSeq())
+	require.NoError(t, wal.Publish(CommittedEvent{Height: 4, Event: cancelled(4)}))
+	require.Equal(t, LogEntry{Seq: 4, Height: 4, Event: cancelled(4)}, wal.Consume())
+
+	// a block executed again after a crash publishes logged events again
+	require.NoError(t, wal.Publish(CommittedEvent{Height: 3, Event: cancelled(3)}))
+	require.NoError(t, wal.Publish(CommittedEvent{Height: 4, Event: cancelled(4)}))
+	require.Equal(t, uint64(4), wal.LastSeq())
+
+	var replayed []uint64
+	require.NoError(t, wal.Replay(2, func(entry LogEntry) bool {
+		replayed = append(replayed, entry.Seq)
+		return true
+	}))
+	require.Equal(t, []uint64{3, 4}, replayed)
+
+	t.Run("consumers catch up from their checkpoints", func(t *testing.T) {
+		db := store.NewBatchDB(dbm.NewMemDB())
+		checkpoints := NewCheckpoints(db)
+		checkpoints.Set("fill", 4)
+		checkpoints.Set("price", 2)
+		fill, price, batch := &recordingHandler{}, &recordingHandler{}, &recordingHandler{}
+		consumer := NewLocalConsumer(wal, db, []Subscription{
+			{Name: "fill", Handler: fill},
+			{Name: "price", Handler: price},
+			{Name: "batch", Handler: batch},
+		})
+		require.NoError(t, consumer.Replay())
+
+		require.Empty(t, fill.events)
+		require.Equal(t, []interface{}{cancelled(3), cancelled(4)}, price.events)
+		require.Len(t, batch.events, 4)
+		for _, name := range []string{"fill", "price", "batch"} {
+			require.Equal(t, uint64(4), checkpoints.Get(name))
+		}
+
+		// entries are applied once
+		consumer.handleItem(LogEntry{Seq: 4, Height: 4, Event: cancelled(4)})
+		require.Len(t, batch.events, 4)
+	})
+}
+
+func TestWALBackend_Corruption(t *testing.T) {
+	testflags.UnitTest(t)
+	dir, err := ioutil.TempDir("", "wal")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "events.wal")
+
+	wal, err := NewWALBackend(path)
+	require.NoError(t, err)
+	wal.Start()
+	for i := uint64(1); i <= 3; i++ {
+		require.NoError(t, wal.Publish(CommittedEvent{Height: int64(i), Event: OrderCancelled{OrderID: store.NewEntityID(i)}}))
+		wal.Consume()
+	}
+	wal.Stop()
+	bz, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+
+	t.Run("a zero-filled tail is cut off", func(t *testing.T) {
+		require.NoError(t, ioutil.WriteFile(path, append(append([]byte{}, bz...), make([]byte, 100)...), 0600))
+		wal, err := NewWALBackend(path)
+		require.NoError(t, err)
+		require.Equal(t, uint64(3), wal.LastSeq())
+		require.NoError(t, wal.Close())
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		require.Equal(t, int64(len(bz)), info.Size())
+	})
+
+	t.Run("a corrupt record before the end fails opening the log", func(t *testing.T) {
+		corrupt := append([]byte{}, bz...)
+		// the last byte of the first record
+		corrupt[len(bz)/3-1] ^= 0xff
+		require.NoError(t, ioutil.WriteFile(path, corrupt, 0600))
+		_, err := NewWALBackend(path)
+		require.Error(t, err)
+	})
+}
+
+func TestWALBackend_Prune(t *testing.T) {
+	testflags.UnitTest(t)
+	dir, err := ioutil.TempDir("", "wal")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "events.wal")
+	cancelled := func(id uint64) Event {
+		return OrderCancelled{OrderID: store.NewEntityID(id)}
+	}
+
+	wal, err := NewWALBackend(path)
+	require.NoError(t, err)
+	wal.Start()
+	for i := uint64(1); i <= 4; i++ {
+		require.NoError(t, wal.Publish(CommittedEvent{Height: int64(i), Event: cancelled(i)}))
+		wal.Consume()
+	}
+
+	replayed := func() []uint64 {
+		var seqs []uint64
+		require.NoError(t, wal.Replay(0, func(entry LogEntry) bool {
+			seqs = append(seqs, entry.Seq)
+			return true
+		}))
+		return seqs
+	}
+	require.NoError(t, wal.Prune(2))
+	require.Equal(t, []uint64{3, 4}, replayed())
+
+	// the last entry is kept
+	require.NoError(t, wal.Prune(4))
+	require.Equal(t, []uint64{4}, replayed())
+
+	// appends go to the pruned log
+	require.NoError(t, wal.Publish(CommittedEvent{Height: 5, Event: cancelled(5)}))
+	require.Equal(t, LogEntry{Seq: 5, Height: 5, Event: cancelled(5)}, wal.Consume())
+	wal.Stop()
+
+	wal, err = NewWALBackend(path)
+	require.NoError(t, err)
+	defer wal.Close()
+	require.Equal(t, uint64(5), wal.LastSeq())
+	require.Equal(t, []uint64{4, 5}, replayed())
+}
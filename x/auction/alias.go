@@ -22,6 +22,23 @@ const (
 	QuerierRoute      = types.QuerierRoute
 	DefaultParamspace = types.DefaultParamspace
 	StoreKey          = types.StoreKey
+
+	EventTypeStartAuction        = types.EventTypeStartAuction
+	EventTypePlaceBid            = types.EventTypePlaceBid
+	EventTypeCloseAuction        = types.EventTypeCloseAuction
+	AttributeValueCategory       = types.AttributeValueCategory
+	AttributeValueForward        = types.AttributeValueForward
+	AttributeValueReverse        = types.AttributeValueReverse
+	AttributeValueForwardReverse = types.AttributeValueForwardReverse
+	AttributeKeyAuctionID        = types.AttributeKeyAuctionID
+	AttributeKeyAuctionType      = types.AttributeKeyAuctionType
+	AttributeKeyInitiator        = types.AttributeKeyInitiator
+	AttributeKeyBidder           = types.AttributeKeyBidder
+	AttributeKeyLot              = types.AttributeKeyLot
+	AttributeKeyBid              = types.AttributeKeyBid
+	AttributeKeyEndTime          = types.AttributeKeyEndTime
+	AttributeKeyRecipient        = types.AttributeKeyRecipient
+	AttributeKeyPayout           = types.AttributeKeyPayout
 )
 
 var (
@@ -34,7 +34,14 @@ func handleMsgPlaceBid(ctx sdk.Context, keeper keeper.Keeper, msg types.MsgPlace
 		return err.Result()
 	}
 
-	return sdk.Result{}
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			sdk.EventTypeMessage,
+			sdk.NewAttribute(sdk.AttributeKeyModule, types.AttributeValueCategory),
+			sdk.NewAttribute(sdk.AttributeKeySender, msg.Bidder.String()),
+		),
+	)
+	return sdk.Result{Events: ctx.EventManager().Events()}
 }
 
 // EndBlocker runs at the end of every block.
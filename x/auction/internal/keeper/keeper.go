@@ -40,6 +40,7 @@ func (k Keeper) StartForwardAuction(ctx sdk.Context, seller sdk.AccAddress, lot
 	if err != nil {
 		return 0, err
 	}
+	emitStartAuction(ctx, auctionID, types.AttributeValueForward, seller, lot, initialBid, auction.GetEndTime())
 	return auctionID, nil
 }
 
@@ -53,6 +54,7 @@ func (k Keeper) StartReverseAuction(ctx sdk.Context, buyer sdk.AccAddress, bid s
 	if err != nil {
 		return 0, err
 	}
+	emitStartAuction(ctx, auctionID, types.AttributeValueReverse, buyer, initialLot, bid, auction.GetEndTime())
 	return auctionID, nil
 }
 
@@ -67,9 +69,25 @@ func (k Keeper) StartForwardReverseAuction(ctx sdk.Context, seller sdk.AccAddres
 	if err != nil {
 		return 0, err
 	}
+	emitStartAuction(ctx, auctionID, types.AttributeValueForwardReverse, seller, lot, initialBid, auction.GetEndTime())
 	return auctionID, nil
 }
 
+// emitStartAuction emits the event of an auction that was just started.
+func emitStartAuction(ctx sdk.Context, auctionID types.ID, auctionType string, initiator sdk.AccAddress, lot sdk.Coin, bid sdk.Coin, endTime types.EndTime) {
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeStartAuction,
+			sdk.NewAttribute(types.AttributeKeyAuctionID, fmt.Sprintf("%d", auctionID)),
+			sdk.NewAttribute(types.AttributeKeyAuctionType, auctionType),
+			sdk.NewAttribute(types.AttributeKeyInitiator, initiator.String()),
+			sdk.NewAttribute(types.AttributeKeyLot, lot.String()),
+			sdk.NewAttribute(types.AttributeKeyBid, bid.String()),
+			sdk.NewAttribute(types.AttributeKeyEndTime, fmt.Sprintf("%d", endTime)),
+		),
+	)
+}
+
 func (k Keeper) startAuction(ctx sdk.Context, auction types.Auction, initiatorOutput types.BankOutput) (types.ID, sdk.Error) {
 	// get ID
 	newAuctionID, err := k.getNextAuctionID(ctx)
@@ -124,6 +142,16 @@ func (k Keeper) PlaceBid(ctx sdk.Context, auctionID types.ID, bidder sdk.AccAddr
 	// store updated auction
 	k.SetAuction(ctx, auction)
 
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypePlaceBid,
+			sdk.NewAttribute(types.AttributeKeyAuctionID, fmt.Sprintf("%d", auctionID)),
+			sdk.NewAttribute(types.AttributeKeyBidder, bidder.String()),
+			sdk.NewAttribute(types.AttributeKeyLot, lot.String()),
+			sdk.NewAttribute(types.AttributeKeyBid, bid.String()),
+			sdk.NewAttribute(types.AttributeKeyEndTime, fmt.Sprintf("%d", auction.GetEndTime())),
+		),
+	)
 	return nil
 }
 
@@ -150,6 +178,14 @@ func (k Keeper) CloseAuction(ctx sdk.Context, auctionID types.ID) sdk.Error {
 	// delete auction from store (and queue)
 	k.DeleteAuction(ctx, auctionID)
 
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeCloseAuction,
+			sdk.NewAttribute(types.AttributeKeyAuctionID, fmt.Sprintf("%d", auctionID)),
+			sdk.NewAttribute(types.AttributeKeyRecipient, coinInput.Address.String()),
+			sdk.NewAttribute(types.AttributeKeyPayout, coinInput.Coin.String()),
+		),
+	)
 	return nil
 }
 
@@ -0,0 +1,24 @@
+package types
+
+// Auction module event types
+const (
+	EventTypeStartAuction = "start_auction"
+	EventTypePlaceBid     = "place_bid"
+	EventTypeCloseAuction = "close_auction"
+
+	AttributeValueCategory = ModuleName
+
+	AttributeValueForward        = "forward"
+	AttributeValueReverse        = "reverse"
+	AttributeValueForwardReverse = "forward_reverse"
+
+	AttributeKeyAuctionID   = "auction_id"
+	AttributeKeyAuctionType = "auction_type"
+	AttributeKeyInitiator   = "initiator"
+	AttributeKeyBidder      = "bidder"
+	AttributeKeyLot         = "lot"
+	AttributeKeyBid         = "bid"
+	AttributeKeyEndTime     = "end_time"
+	AttributeKeyRecipient   = "recipient"
+	AttributeKeyPayout      = "payout"
+)
@@ -0,0 +1,45 @@
+package authority
+
+import (
+	"github.com/xar-network/xar-network/x/authority/types"
+)
+
+type (
+	Role  = types.Role
+	Grant = types.Grant
+	Event = types.Event
+
+	UpdateRoleProposal     = types.UpdateRoleProposal
+	RotateNomineesProposal = types.RotateNomineesProposal
+)
+
+const (
+	ModuleName       = types.ModuleName
+	RouterKey        = types.RouterKey
+	StoreKey         = types.StoreKey
+	QuerierRoute     = types.QuerierRoute
+	DefaultCodespace = types.DefaultCodespace
+
+	RoleMarketAdmin     = types.RoleMarketAdmin
+	RoleCollateralAdmin = types.RoleCollateralAdmin
+	RoleOracleAdmin     = types.RoleOracleAdmin
+	RoleTokenIssuer     = types.RoleTokenIssuer
+
+	EventTypeApprovalPending = types.EventTypeApprovalPending
+	AttributeKeyApprovals    = types.AttributeKeyApprovals
+	AttributeKeyDigest       = types.AttributeKeyDigest
+	MaxEvents                = types.MaxEvents
+)
+
+var (
+	ModuleCdc = types.ModuleCdc
+
+	RoleNames    = types.RoleNames
+	NewRole      = types.NewRole
+	NewGrant     = types.NewGrant
+	DefaultRoles = types.DefaultRoles
+	NomineeRoles = types.NomineeRoles
+
+	NewUpdateRoleProposal     = types.NewUpdateRoleProposal
+	NewRotateNomineesProposal = types.NewRotateNomineesProposal
+)
@@ -0,0 +1,21 @@
+package cli
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/codec"
+)
+
+func GetQueryCmd(sk string, cdc *codec.Codec) *cobra.Command {
+	authorityQueryCmd := &cobra.Command{
+		Use:   "authority",
+		Short: "queries roles and the authority audit log",
+	}
+	authorityQueryCmd.AddCommand(client.GetCommands(
+		GetCmdRoles(sk, cdc),
+		GetCmdRole(sk, cdc),
+		GetCmdEvents(sk, cdc),
+	)...)
+	return authorityQueryCmd
+}
@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/cosmos/cosmos-sdk/codec"
+
+	"github.com/xar-network/xar-network/x/authority/types"
+)
+
+const (
+	flagRole  = "role"
+	flagPage  = "page"
+	flagLimit = "limit"
+)
+
+func GetCmdRoles(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "roles",
+		Short: "lists all roles and their grants",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.NewCLIContext().WithCodec(cdc)
+			res, _, err := ctx.QueryWithData(fmt.Sprintf("custom/%s/roles", queryRoute), nil)
+			if err != nil {
+				return err
+			}
+
+			var out types.RolesQueryResult
+			cdc.MustUnmarshalJSON(res, &out)
+			return ctx.PrintOutput(out)
+		},
+	}
+}
+
+func GetCmdRole(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "role [name]",
+		Short: "shows the threshold and grants of a role",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.NewCLIContext().WithCodec(cdc)
+			res, _, err := ctx.QueryWithData(fmt.Sprintf("custom/%s/role/%s", queryRoute, args[0]), nil)
+			if err != nil {
+				return err
+			}
+
+			var out types.Role
+			cdc.MustUnmarshalJSON(res, &out)
+			return ctx.PrintOutput(out)
+		},
+	}
+}
+
+func GetCmdEvents(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	out := &cobra.Command{
+		Use:   "events",
+		Short: "lists the authority audit log",
+		Long: `Lists grants, revocations, threshold changes, approvals and executed actions,
+oldest first. Events can be filtered by --role. Results are paginated with
+--page and --limit.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.NewCLIContext().WithCodec(cdc)
+			params := types.NewEventsQueryParams(viper.GetString(flagRole), viper.GetInt(flagPage), viper.GetInt(flagLimit))
+			bz, err := cdc.MarshalJSON(params)
+			if err != nil {
+				return err
+			}
+			res, _, err := ctx.QueryWithData(fmt.Sprintf("custom/%s/events", queryRoute), bz)
+			if err != nil {
+				return err
+			}
+
+			var out types.EventsQueryResult
+			cdc.MustUnmarshalJSON(res, &out)
+			return ctx.PrintOutput(out)
+		},
+	}
+	out.Flags().String(flagRole, "", "only list events of this role")
+	out.Flags().Int(flagPage, 1, "page of results to show")
+	out.Flags().Int(flagLimit, types.DefaultQueryLimit, "number of events per page")
+	return out
+}
@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth/client/utils"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	govcli "github.com/cosmos/cosmos-sdk/x/gov/client/cli"
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types"
+
+	"github.com/xar-network/xar-network/pkg/cliutil"
+	"github.com/xar-network/xar-network/x/authority/types"
+)
+
+// GetCmdSubmitUpdateRoleProposal is registered as a gov submit-proposal
+// subcommand.
+func GetCmdSubmitUpdateRoleProposal(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "update-role [role] [threshold] [grants]",
+		Short: "submits a proposal to replace the threshold and grants of a role",
+		Long: fmt.Sprintf(`Submits a governance proposal to replace the threshold and grants of the
+%s role. Grants are given as a comma separated list of existing
+accounts, each optionally followed by @ and an RFC3339 expiry time, e.g.
+xar1...@2021-01-01T00:00:00Z. Actions under the role need the approval of
+threshold distinct grantees.`, strings.Join(types.RoleNames, ", ")),
+		Args: cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			inBuf := bufio.NewReader(cmd.InOrStdin())
+			cliCtx := client.NewCLIContext().WithCodec(cdc)
+			bldr := authtypes.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
+
+			threshold, err := strconv.ParseUint(args[1], 10, 16)
+			if err != nil {
+				return err
+			}
+			grants, err := parseGrants(args[2])
+			if err != nil {
+				return err
+			}
+			deposit, err := sdk.ParseCoins(viper.GetString(govcli.FlagDeposit))
+			if err != nil {
+				return err
+			}
+
+			content := types.NewUpdateRoleProposal(
+				viper.GetString(govcli.FlagTitle),
+				viper.GetString(govcli.FlagDescription),
+				args[0], uint16(threshold), grants,
+			)
+			msg := govtypes.NewMsgSubmitProposal(content, deposit, cliCtx.GetFromAddress())
+			return cliutil.ValidateAndBroadcast(cliCtx, bldr, msg)
+		},
+	}
+	cmd.Flags().String(govcli.FlagTitle, "", "title of proposal")
+	cmd.Flags().String(govcli.FlagDescription, "", "description of proposal")
+	cmd.Flags().String(govcli.FlagDeposit, "", "deposit of proposal")
+	return cmd
+}
+
+// GetCmdSubmitRotateNomineesProposal is registered as a gov submit-proposal
+// subcommand.
+func GetCmdSubmitRotateNomineesProposal(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rotate-nominees [module] [nominees]",
+		Short: "submits a proposal to replace the nominees of a module",
+		Long: `Submits a governance proposal to replace the nominees of the market, csdt,
+oracle or denominations module, i.e. the grants of the market-admin,
+collateral-admin, oracle-admin or token-issuer role. Nominees are given as a
+comma separated list of existing accounts and are granted the role without
+expiry. The role keeps its threshold.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			inBuf := bufio.NewReader(cmd.InOrStdin())
+			cliCtx := client.NewCLIContext().WithCodec(cdc)
+			bldr := authtypes.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
+
+			deposit, err := sdk.ParseCoins(viper.GetString(govcli.FlagDeposit))
+			if err != nil {
+				return err
+			}
+
+			content := types.NewRotateNomineesProposal(
+				viper.GetString(govcli.FlagTitle),
+				viper.GetString(govcli.FlagDescription),
+				args[0], strings.Split(args[1], ","),
+			)
+			msg := govtypes.NewMsgSubmitProposal(content, deposit, cliCtx.GetFromAddress())
+			return cliutil.ValidateAndBroadcast(cliCtx, bldr, msg)
+		},
+	}
+	cmd.Flags().String(govcli.FlagTitle, "", "title of proposal")
+	cmd.Flags().String(govcli.FlagDescription, "", "description of proposal")
+	cmd.Flags().String(govcli.FlagDeposit, "", "deposit of proposal")
+	return cmd
+}
+
+func parseGrants(arg string) ([]types.Grant, error) {
+	grants := []types.Grant{}
+	if arg == "" {
+		return grants, nil
+	}
+	for _, s := range strings.Split(arg, ",") {
+		parts := strings.SplitN(s, "@", 2)
+		addr, err := sdk.AccAddressFromBech32(parts[0])
+		if err != nil {
+			return nil, err
+		}
+		var expiry time.Time
+		if len(parts) == 2 {
+			expiry, err = time.Parse(time.RFC3339, parts[1])
+			if err != nil {
+				return nil, err
+			}
+		}
+		grants = append(grants, types.NewGrant(addr, expiry))
+	}
+	return grants, nil
+}
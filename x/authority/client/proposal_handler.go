@@ -0,0 +1,15 @@
+package client
+
+import (
+	govclient "github.com/cosmos/cosmos-sdk/x/gov/client"
+
+	"github.com/xar-network/xar-network/x/authority/client/cli"
+	"github.com/xar-network/xar-network/x/authority/client/rest"
+)
+
+// UpdateRoleProposalHandler lets governance grant and revoke roles.
+var UpdateRoleProposalHandler = govclient.NewProposalHandler(cli.GetCmdSubmitUpdateRoleProposal, rest.ProposalRESTHandler)
+
+// RotateNomineesProposalHandler lets governance replace the nominees of a
+// module.
+var RotateNomineesProposalHandler = govclient.NewProposalHandler(cli.GetCmdSubmitRotateNomineesProposal, rest.RotateNomineesProposalRESTHandler)
@@ -0,0 +1,99 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/rest"
+	"github.com/cosmos/cosmos-sdk/x/auth/client/utils"
+	govrest "github.com/cosmos/cosmos-sdk/x/gov/client/rest"
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types"
+
+	"github.com/xar-network/xar-network/x/authority/types"
+)
+
+type updateRoleProposalReq struct {
+	BaseReq     rest.BaseReq   `json:"base_req"`
+	Title       string         `json:"title"`
+	Description string         `json:"description"`
+	Role        string         `json:"role"`
+	Threshold   uint16         `json:"threshold"`
+	Grants      []types.Grant  `json:"grants"`
+	Proposer    sdk.AccAddress `json:"proposer"`
+	Deposit     sdk.Coins      `json:"deposit"`
+}
+
+type rotateNomineesProposalReq struct {
+	BaseReq     rest.BaseReq   `json:"base_req"`
+	Title       string         `json:"title"`
+	Description string         `json:"description"`
+	Module      string         `json:"module"`
+	Nominees    []string       `json:"nominees"`
+	Proposer    sdk.AccAddress `json:"proposer"`
+	Deposit     sdk.Coins      `json:"deposit"`
+}
+
+// ProposalRESTHandler exposes the update role proposal REST handler.
+func ProposalRESTHandler(cliCtx context.CLIContext) govrest.ProposalRESTHandler {
+	return govrest.ProposalRESTHandler{
+		SubRoute: "update_role",
+		Handler:  updateRoleProposalHandler(cliCtx),
+	}
+}
+
+// RotateNomineesProposalRESTHandler exposes the rotate nominees proposal
+// REST handler.
+func RotateNomineesProposalRESTHandler(cliCtx context.CLIContext) govrest.ProposalRESTHandler {
+	return govrest.ProposalRESTHandler{
+		SubRoute: "rotate_nominees",
+		Handler:  rotateNomineesProposalHandler(cliCtx),
+	}
+}
+
+func updateRoleProposalHandler(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req updateRoleProposalReq
+		if !rest.ReadRESTReq(w, r, cliCtx.Codec, &req) {
+			return
+		}
+
+		req.BaseReq = req.BaseReq.Sanitize()
+		if !req.BaseReq.ValidateBasic(w) {
+			return
+		}
+
+		content := types.NewUpdateRoleProposal(req.Title, req.Description, req.Role, req.Threshold, req.Grants)
+		msg := govtypes.NewMsgSubmitProposal(content, req.Deposit, req.Proposer)
+		if err := msg.ValidateBasic(); err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		utils.WriteGenerateStdTxResponse(w, cliCtx, req.BaseReq, []sdk.Msg{msg})
+	}
+}
+
+func rotateNomineesProposalHandler(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req rotateNomineesProposalReq
+		if !rest.ReadRESTReq(w, r, cliCtx.Codec, &req) {
+			return
+		}
+
+		req.BaseReq = req.BaseReq.Sanitize()
+		if !req.BaseReq.ValidateBasic(w) {
+			return
+		}
+
+		content := types.NewRotateNomineesProposal(req.Title, req.Description, req.Module, req.Nominees)
+		msg := govtypes.NewMsgSubmitProposal(content, req.Deposit, req.Proposer)
+		if err := msg.ValidateBasic(); err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		utils.WriteGenerateStdTxResponse(w, cliCtx, req.BaseReq, []sdk.Msg{msg})
+	}
+}
@@ -0,0 +1,72 @@
+package authority
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/xar-network/xar-network/types/store"
+	"github.com/xar-network/xar-network/x/authority/types"
+)
+
+type GenesisState struct {
+	Roles  []types.Role  `json:"roles" yaml:"roles"`
+	Events []types.Event `json:"events" yaml:"events"`
+}
+
+func NewGenesisState(roles []types.Role, events []types.Event) GenesisState {
+	return GenesisState{Roles: roles, Events: events}
+}
+
+func ValidateGenesis(data GenesisState) error {
+	seen := make(map[string]bool)
+	for _, role := range data.Roles {
+		if err := role.Validate(); err != nil {
+			return err
+		}
+		if seen[role.Name] {
+			return fmt.Errorf("duplicate role: '%s'", role.Name)
+		}
+		seen[role.Name] = true
+	}
+	if len(data.Events) > types.MaxEvents {
+		return fmt.Errorf("too many events: %d, at most %d are kept", len(data.Events), types.MaxEvents)
+	}
+	currentId := store.ZeroEntityID
+	for _, ev := range data.Events {
+		if currentId.Cmp(ev.ID) >= 0 {
+			return fmt.Errorf("invalid event %s: IDs must increase", ev.ID)
+		}
+		currentId = ev.ID
+	}
+	return nil
+}
+
+func DefaultGenesisState() GenesisState {
+	return GenesisState{
+		Roles:  types.DefaultRoles(),
+		Events: []types.Event{},
+	}
+}
+
+func InitGenesis(ctx sdk.Context, k Keeper, data GenesisState) {
+	for _, role := range data.Roles {
+		k.SetRole(ctx, role)
+	}
+	for _, ev := range data.Events {
+		k.SetEvent(ctx, ev)
+	}
+}
+
+func ExportGenesis(ctx sdk.Context, k Keeper) GenesisState {
+	roles := make([]types.Role, 0, len(types.RoleNames))
+	for _, name := range types.RoleNames {
+		roles = append(roles, k.GetRole(ctx, name))
+	}
+	events := []types.Event{}
+	k.EventIterator(ctx, func(ev types.Event) bool {
+		events = append(events, ev)
+		return true
+	})
+	return GenesisState{Roles: roles, Events: events}
+}
@@ -0,0 +1,223 @@
+package authority
+
+import (
+	"strconv"
+
+	"github.com/tendermint/tendermint/crypto/tmhash"
+	cmn "github.com/tendermint/tendermint/libs/common"
+
+	"github.com/xar-network/xar-network/types/store"
+	"github.com/xar-network/xar-network/x/authority/types"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	roleKey     = "role"
+	approvalKey = "approval"
+	seqKey      = "seq"
+	eventKey    = "event"
+)
+
+type Keeper struct {
+	storeKey  sdk.StoreKey
+	cdc       *codec.Codec
+	codespace sdk.CodespaceType
+}
+
+func NewKeeper(sk sdk.StoreKey, cdc *codec.Codec, codespace sdk.CodespaceType) Keeper {
+	return Keeper{
+		storeKey:  sk,
+		cdc:       cdc,
+		codespace: codespace,
+	}
+}
+
+// GetRole returns a role. Known roles that were never set have no grants
+// and a threshold of one.
+func (k Keeper) GetRole(ctx sdk.Context, name string) types.Role {
+	role := types.NewRole(name, 1, []types.Grant{})
+	_ = store.Get(ctx, k.storeKey, k.cdc, roleKeyFor(name), &role)
+	// amino decodes empty lists as nil
+	if role.Grants == nil {
+		role.Grants = []types.Grant{}
+	}
+	return role
+}
+
+// SetRole stores a role as is. Use UpdateRole to keep the audit log.
+func (k Keeper) SetRole(ctx sdk.Context, role types.Role) {
+	store.Set(ctx, k.storeKey, k.cdc, roleKeyFor(role.Name), role)
+}
+
+// UpdateRole replaces a role's threshold and grants, records the changes in
+// the audit log and drops the role's pending approvals, which were given
+// under the previous grants.
+func (k Keeper) UpdateRole(ctx sdk.Context, role types.Role) sdk.Error {
+	if err := role.Validate(); err != nil {
+		return sdk.ErrUnknownRequest(err.Error())
+	}
+	prev := k.GetRole(ctx, role.Name)
+	for _, g := range prev.Grants {
+		if _, ok := role.Grant(g.Grantee); !ok {
+			k.appendEvent(ctx, types.Event{Action: types.EventRevoke, Role: role.Name, Address: g.Grantee})
+		}
+	}
+	for _, g := range role.Grants {
+		if old, ok := prev.Grant(g.Grantee); !ok || !old.Expiry.Equal(g.Expiry) {
+			k.appendEvent(ctx, types.Event{Action: types.EventGrant, Role: role.Name, Address: g.Grantee})
+		}
+	}
+	if prev.Threshold != role.Threshold {
+		k.appendEvent(ctx, types.Event{Action: types.EventThreshold, Role: role.Name, Threshold: role.Threshold})
+	}
+	k.SetRole(ctx, role)
+	k.clearApprovals(ctx, role.Name)
+	return nil
+}
+
+// HasRole returns true if addr holds an active grant of the role.
+func (k Keeper) HasRole(ctx sdk.Context, name string, addr sdk.AccAddress) bool {
+	return k.GetRole(ctx, name).IsGranted(addr, ctx.BlockTime())
+}
+
+// Approve records signer's approval of action under a role and returns true
+// once the action may go ahead. Actions of roles with a threshold of one go
+// ahead right away. Otherwise approvals are collected until Threshold
+// distinct active grantees approved the same action, so action must not
+// depend on who signed it. Until then every approval emits an
+// approval_pending event with the action's digest and its approval count.
+func (k Keeper) Approve(ctx sdk.Context, name string, signer sdk.AccAddress, action sdk.Msg) (bool, sdk.Error) {
+	role := k.GetRole(ctx, name)
+	now := ctx.BlockTime()
+	if !role.IsGranted(signer, now) {
+		return false, types.ErrNotGranted(k.codespace, name, signer)
+	}
+
+	digest := tmhash.Sum(action.GetSignBytes())
+	var approvers []sdk.AccAddress
+	_ = store.Get(ctx, k.storeKey, k.cdc, approvalKeyFor(name, digest), &approvers)
+	// approvals of grantees that lost the role since no longer count
+	current := make([]sdk.AccAddress, 0, len(approvers)+1)
+	for _, a := range approvers {
+		if role.IsGranted(a, now) && !a.Equals(signer) {
+			current = append(current, a)
+		}
+	}
+	current = append(current, signer)
+
+	if len(current) < int(role.Threshold) {
+		store.Set(ctx, k.storeKey, k.cdc, approvalKeyFor(name, digest), current)
+		k.appendEvent(ctx, types.Event{Action: types.EventApprove, Role: name, Address: signer, Digest: digest})
+		ctx.EventManager().EmitEvent(
+			sdk.NewEvent(
+				types.EventTypeApprovalPending,
+				sdk.NewAttribute(types.AttributeKeyRole, name),
+				sdk.NewAttribute(types.AttributeKeyDigest, cmn.HexBytes(digest).String()),
+				sdk.NewAttribute(types.AttributeKeyApprovals, strconv.Itoa(len(current))),
+				sdk.NewAttribute(types.AttributeKeyThreshold, strconv.Itoa(int(role.Threshold))),
+			),
+		)
+		return false, nil
+	}
+	ctx.KVStore(k.storeKey).Delete(approvalKeyFor(name, digest))
+	k.appendEvent(ctx, types.Event{Action: types.EventExecute, Role: name, Address: signer, Digest: digest})
+	return true, nil
+}
+
+// Approvals returns the grantees that approved an action which is still
+// waiting for more approvals.
+func (k Keeper) Approvals(ctx sdk.Context, name string, action sdk.Msg) []sdk.AccAddress {
+	var approvers []sdk.AccAddress
+	_ = store.Get(ctx, k.storeKey, k.cdc, approvalKeyFor(name, tmhash.Sum(action.GetSignBytes())), &approvers)
+	return approvers
+}
+
+// EventIterator iterates over the audit log, oldest first. Only the latest
+// MaxEvents entries are kept.
+func (k Keeper) EventIterator(ctx sdk.Context, cb func(types.Event) bool) {
+	kv := ctx.KVStore(k.storeKey)
+	iter := sdk.KVStorePrefixIterator(kv, []byte(eventKey))
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		var ev types.Event
+		k.cdc.MustUnmarshalBinaryBare(iter.Value(), &ev)
+		if !cb(ev) {
+			break
+		}
+	}
+}
+
+// SetEvent stores an audit log entry as is. It is used to import genesis.
+func (k Keeper) SetEvent(ctx sdk.Context, ev types.Event) {
+	store.Set(ctx, k.storeKey, k.cdc, eventKeyFor(ev.ID), ev)
+	if store.GetSeq(ctx, k.storeKey, []byte(seqKey)).Cmp(ev.ID) < 0 {
+		ctx.KVStore(k.storeKey).Set([]byte(seqKey), []byte(ev.ID.String()))
+	}
+}
+
+func (k Keeper) appendEvent(ctx sdk.Context, ev types.Event) {
+	ev.ID = store.IncrementSeq(ctx, k.storeKey, []byte(seqKey))
+	ev.Height = ctx.BlockHeight()
+	ev.Time = ctx.BlockTime()
+	store.Set(ctx, k.storeKey, k.cdc, eventKeyFor(ev.ID), ev)
+	k.pruneEvents(ctx, ev.ID)
+
+	attrs := []sdk.Attribute{
+		sdk.NewAttribute(types.AttributeKeyAction, ev.Action),
+		sdk.NewAttribute(types.AttributeKeyRole, ev.Role),
+	}
+	if !ev.Address.Empty() {
+		attrs = append(attrs, sdk.NewAttribute(types.AttributeKeyAddress, ev.Address.String()))
+	}
+	if len(ev.Digest) > 0 {
+		attrs = append(attrs, sdk.NewAttribute(types.AttributeKeyDigest, ev.Digest.String()))
+	}
+	ctx.EventManager().EmitEvent(sdk.NewEvent(types.ModuleName, attrs...))
+}
+
+// pruneEvents deletes the audit log entries older than the MaxEvents
+// entries up to latest. IDs are sequential, so this is usually the single
+// oldest entry.
+func (k Keeper) pruneEvents(ctx sdk.Context, latest store.EntityID) {
+	if latest.Uint64() <= types.MaxEvents {
+		return
+	}
+	oldest := store.NewEntityID(latest.Uint64() - types.MaxEvents + 1)
+	kv := ctx.KVStore(k.storeKey)
+	iter := kv.Iterator(append([]byte(eventKey), '/'), eventKeyFor(oldest))
+	var keys [][]byte
+	for ; iter.Valid(); iter.Next() {
+		keys = append(keys, iter.Key())
+	}
+	iter.Close()
+	for _, key := range keys {
+		kv.Delete(key)
+	}
+}
+
+func (k Keeper) clearApprovals(ctx sdk.Context, name string) {
+	kv := ctx.KVStore(k.storeKey)
+	iter := sdk.KVStorePrefixIterator(kv, append(store.PrefixKeyString(approvalKey, []byte(name)), '/'))
+	var keys [][]byte
+	for ; iter.Valid(); iter.Next() {
+		keys = append(keys, iter.Key())
+	}
+	iter.Close()
+	for _, key := range keys {
+		kv.Delete(key)
+	}
+}
+
+func roleKeyFor(name string) []byte {
+	return store.PrefixKeyString(roleKey, []byte(name))
+}
+
+func approvalKeyFor(name string, digest []byte) []byte {
+	return store.PrefixKeyString(approvalKey, []byte(name), digest)
+}
+
+func eventKeyFor(id store.EntityID) []byte {
+	return store.PrefixKeyString(eventKey, id.Bytes())
+}
@@ -0,0 +1,259 @@
+package authority_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	cstore "github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	authexported "github.com/cosmos/cosmos-sdk/x/auth/exported"
+	"github.com/cosmos/cosmos-sdk/x/bank"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/libs/log"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/xar-network/xar-network/types/store"
+	"github.com/xar-network/xar-network/x/authority"
+	"github.com/xar-network/xar-network/x/authority/types"
+)
+
+var (
+	first  = sdk.AccAddress(crypto.AddressHash([]byte("first")))
+	second = sdk.AccAddress(crypto.AddressHash([]byte("second")))
+	third  = sdk.AccAddress(crypto.AddressHash([]byte("third")))
+)
+
+func TestKeeper_Expiry(t *testing.T) {
+	ctx, k := setupKeeper(t)
+	expiry := ctx.BlockTime().Add(time.Hour)
+	require.Nil(t, k.UpdateRole(ctx, authority.NewRole(authority.RoleOracleAdmin, 1, []authority.Grant{
+		authority.NewGrant(first, time.Time{}),
+		authority.NewGrant(second, expiry),
+	})))
+
+	require.True(t, k.HasRole(ctx, authority.RoleOracleAdmin, first))
+	require.True(t, k.HasRole(ctx, authority.RoleOracleAdmin, second))
+	require.False(t, k.HasRole(ctx, authority.RoleMarketAdmin, first))
+
+	later := ctx.WithBlockTime(expiry)
+	require.True(t, k.HasRole(later, authority.RoleOracleAdmin, first))
+	require.False(t, k.HasRole(later, authority.RoleOracleAdmin, second))
+
+	_, err := k.Approve(later, authority.RoleOracleAdmin, second, testMsg())
+	require.Error(t, err)
+	require.Equal(t, types.CodeNotGranted, err.Code())
+}
+
+func TestKeeper_Approve(t *testing.T) {
+	ctx, k := setupKeeper(t)
+	require.Nil(t, k.UpdateRole(ctx, authority.NewRole(authority.RoleMarketAdmin, 2, []authority.Grant{
+		authority.NewGrant(first, time.Time{}),
+		authority.NewGrant(second, time.Time{}),
+		authority.NewGrant(third, time.Time{}),
+	})))
+	action := testMsg()
+
+	ok, err := k.Approve(ctx, authority.RoleMarketAdmin, first, action)
+	require.Nil(t, err)
+	require.False(t, ok)
+	// approving again does not count twice
+	ok, err = k.Approve(ctx, authority.RoleMarketAdmin, first, action)
+	require.Nil(t, err)
+	require.False(t, ok)
+	require.Equal(t, []sdk.AccAddress{first}, k.Approvals(ctx, authority.RoleMarketAdmin, action))
+
+	// approvals are per action
+	other := bank.NewMsgSend(first, second, sdk.NewCoins(sdk.NewInt64Coin("uftm", 2)))
+	ok, err = k.Approve(ctx, authority.RoleMarketAdmin, second, other)
+	require.Nil(t, err)
+	require.False(t, ok)
+
+	ok, err = k.Approve(ctx, authority.RoleMarketAdmin, second, action)
+	require.Nil(t, err)
+	require.True(t, ok)
+	require.Empty(t, k.Approvals(ctx, authority.RoleMarketAdmin, action))
+
+	_, err = k.Approve(ctx, authority.RoleCollateralAdmin, first, action)
+	require.Error(t, err)
+
+	t.Run("revoked approvals no longer count", func(t *testing.T) {
+		ok, err := k.Approve(ctx, authority.RoleMarketAdmin, first, action)
+		require.Nil(t, err)
+		require.False(t, ok)
+
+		// changing the role drops pending approvals
+		require.Nil(t, k.UpdateRole(ctx, authority.NewRole(authority.RoleMarketAdmin, 2, []authority.Grant{
+			authority.NewGrant(first, time.Time{}),
+			authority.NewGrant(third, time.Time{}),
+		})))
+		require.Empty(t, k.Approvals(ctx, authority.RoleMarketAdmin, action))
+		require.Empty(t, k.Approvals(ctx, authority.RoleMarketAdmin, other))
+
+		ok, err = k.Approve(ctx, authority.RoleMarketAdmin, third, action)
+		require.Nil(t, err)
+		require.False(t, ok)
+		ok, err = k.Approve(ctx, authority.RoleMarketAdmin, first, action)
+		require.Nil(t, err)
+		require.True(t, ok)
+	})
+}
+
+func TestKeeper_Events(t *testing.T) {
+	ctx, k := setupKeeper(t)
+	require.Nil(t, k.UpdateRole(ctx, authority.NewRole(authority.RoleTokenIssuer, 1, []authority.Grant{
+		authority.NewGrant(first, time.Time{}),
+	})))
+	require.Nil(t, k.UpdateRole(ctx, authority.NewRole(authority.RoleTokenIssuer, 2, []authority.Grant{
+		authority.NewGrant(second, time.Time{}),
+		authority.NewGrant(third, time.Time{}),
+	})))
+	ok, err := k.Approve(ctx, authority.RoleTokenIssuer, second, testMsg())
+	require.Nil(t, err)
+	require.False(t, ok)
+	ok, err = k.Approve(ctx, authority.RoleTokenIssuer, third, testMsg())
+	require.Nil(t, err)
+	require.True(t, ok)
+
+	// invalid roles are rejected without being logged
+	require.Error(t, k.UpdateRole(ctx, authority.NewRole(authority.RoleTokenIssuer, 3, []authority.Grant{
+		authority.NewGrant(second, time.Time{}),
+	})))
+
+	var events []authority.Event
+	k.EventIterator(ctx, func(ev authority.Event) bool {
+		events = append(events, ev)
+		return true
+	})
+	actions := make([]string, 0, len(events))
+	for i, ev := range events {
+		require.Equal(t, authority.RoleTokenIssuer, ev.Role)
+		require.Equal(t, uint64(i+1), ev.ID.Uint64())
+		require.Equal(t, ctx.BlockHeight(), ev.Height)
+		actions = append(actions, ev.Action)
+	}
+	require.Equal(t, []string{
+		types.EventGrant,
+		types.EventRevoke, types.EventGrant, types.EventGrant, types.EventThreshold,
+		types.EventApprove, types.EventExecute,
+	}, actions)
+	require.Equal(t, first, events[1].Address)
+	require.Equal(t, uint16(2), events[4].Threshold)
+	require.Equal(t, events[5].Digest, events[6].Digest)
+	// the pending approval emits an approval_pending event as well
+	abciEvents := ctx.EventManager().Events()
+	require.Len(t, abciEvents, len(events)+1)
+	require.Equal(t, sdk.StringEvent{
+		Type: types.EventTypeApprovalPending,
+		Attributes: []sdk.Attribute{
+			{Key: types.AttributeKeyRole, Value: authority.RoleTokenIssuer},
+			{Key: types.AttributeKeyDigest, Value: events[5].Digest.String()},
+			{Key: types.AttributeKeyApprovals, Value: "1"},
+			{Key: types.AttributeKeyThreshold, Value: "2"},
+		},
+	}, sdk.StringifyEvent(abciEvents[6:7].ToABCIEvents()[0]))
+
+	t.Run("round trips through genesis", func(t *testing.T) {
+		exported := authority.ExportGenesis(ctx, k)
+		require.NoError(t, authority.ValidateGenesis(exported))
+		require.Len(t, exported.Roles, len(authority.RoleNames))
+		require.Equal(t, events, exported.Events)
+
+		imported, ik := setupKeeper(t)
+		authority.InitGenesis(imported, ik, exported)
+		require.Equal(t, exported, authority.ExportGenesis(imported, ik))
+
+		// the log carries on after the imported events
+		require.Nil(t, ik.UpdateRole(imported, authority.NewRole(authority.RoleTokenIssuer, 1, []authority.Grant{})))
+		var last authority.Event
+		ik.EventIterator(imported, func(ev authority.Event) bool {
+			last = ev
+			return true
+		})
+		require.Equal(t, uint64(len(events)+3), last.ID.Uint64())
+	})
+}
+
+func TestKeeper_PruneEvents(t *testing.T) {
+	ctx, k := setupKeeper(t)
+	k.SetEvent(ctx, authority.Event{ID: store.NewEntityID(1), Action: types.EventGrant, Role: authority.RoleTokenIssuer})
+	k.SetEvent(ctx, authority.Event{ID: store.NewEntityID(2), Action: types.EventGrant, Role: authority.RoleTokenIssuer})
+	k.SetEvent(ctx, authority.Event{ID: store.NewEntityID(authority.MaxEvents), Action: types.EventGrant, Role: authority.RoleTokenIssuer})
+
+	// the log is full, so appending prunes the oldest entry
+	require.Nil(t, k.UpdateRole(ctx, authority.NewRole(authority.RoleTokenIssuer, 1, []authority.Grant{
+		authority.NewGrant(first, time.Time{}),
+	})))
+	var ids []uint64
+	k.EventIterator(ctx, func(ev authority.Event) bool {
+		ids = append(ids, ev.ID.Uint64())
+		return true
+	})
+	require.Equal(t, []uint64{2, authority.MaxEvents, authority.MaxEvents + 1}, ids)
+}
+
+type accounts map[string]bool
+
+func (a accounts) GetAccount(_ sdk.Context, addr sdk.AccAddress) authexported.Account {
+	if !a[addr.String()] {
+		return nil
+	}
+	acc := auth.NewBaseAccountWithAddress(addr)
+	return &acc
+}
+
+func TestProposalHandler_RotateNominees(t *testing.T) {
+	ctx, k := setupKeeper(t)
+	handler := authority.NewProposalHandler(k, accounts{first.String(): true, second.String(): true})
+	require.Nil(t, k.UpdateRole(ctx, authority.NewRole(authority.RoleMarketAdmin, 1, []authority.Grant{
+		authority.NewGrant(third, time.Time{}),
+	})))
+
+	proposal := authority.NewRotateNomineesProposal("title", "description", "market", []string{first.String(), second.String()})
+	require.NoError(t, proposal.ValidateBasic())
+	require.NoError(t, handler(ctx, proposal))
+	require.True(t, k.HasRole(ctx, authority.RoleMarketAdmin, first))
+	require.True(t, k.HasRole(ctx, authority.RoleMarketAdmin, second))
+	require.False(t, k.HasRole(ctx, authority.RoleMarketAdmin, third))
+
+	// nominees must be existing accounts
+	proposal = authority.NewRotateNomineesProposal("title", "description", "market", []string{first.String(), third.String()})
+	require.NoError(t, proposal.ValidateBasic())
+	require.Error(t, handler(ctx, proposal))
+	require.True(t, k.HasRole(ctx, authority.RoleMarketAdmin, second))
+
+	// the role keeps its threshold
+	require.Nil(t, k.UpdateRole(ctx, authority.NewRole(authority.RoleOracleAdmin, 2, []authority.Grant{
+		authority.NewGrant(first, time.Time{}),
+		authority.NewGrant(second, time.Time{}),
+	})))
+	require.Error(t, handler(ctx, authority.NewRotateNomineesProposal("title", "description", "oracle", []string{first.String()})))
+	require.Equal(t, uint16(2), k.GetRole(ctx, authority.RoleOracleAdmin).Threshold)
+
+	require.Error(t, authority.NewRotateNomineesProposal("title", "description", "staking", []string{first.String()}).ValidateBasic())
+	require.Error(t, authority.NewRotateNomineesProposal("title", "description", "market", nil).ValidateBasic())
+	require.Error(t, authority.NewRotateNomineesProposal("title", "description", "market", []string{first.String(), first.String()}).ValidateBasic())
+	require.Error(t, authority.NewRotateNomineesProposal("title", "description", "market", []string{"invalid"}).ValidateBasic())
+}
+
+func testMsg() sdk.Msg {
+	return bank.NewMsgSend(first, second, sdk.NewCoins(sdk.NewInt64Coin("uftm", 1)))
+}
+
+func setupKeeper(t *testing.T) (sdk.Context, authority.Keeper) {
+	cdc := codec.New()
+	sdk.RegisterCodec(cdc)
+	codec.RegisterCrypto(cdc)
+
+	key := sdk.NewKVStoreKey(authority.StoreKey)
+	db := dbm.NewMemDB()
+	ms := cstore.NewCommitMultiStore(db)
+	ms.MountStoreWithDB(key, sdk.StoreTypeIAVL, db)
+	require.Nil(t, ms.LoadLatestVersion())
+
+	ctx := sdk.NewContext(ms, abci.Header{ChainID: "xar-chain", Height: 10, Time: time.Unix(1558332092, 0)}, false, log.NewNopLogger())
+	return ctx, authority.NewKeeper(key, cdc, authority.DefaultCodespace)
+}
@@ -0,0 +1,117 @@
+// Package legacy migrates genesis states from the per-module nominee lists
+// to the authority module's role grants.
+package legacy
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/genutil"
+
+	"github.com/xar-network/xar-network/x/authority"
+)
+
+// nomineeLists locates the nominee list of each module that used to keep
+// one by the path of JSON keys within the module's genesis state. Its
+// nominees are granted the module's role in authority.NomineeRoles.
+var nomineeLists = []struct {
+	module string
+	path   []string
+}{
+	{"market", []string{"nominees"}},
+	{"csdt", []string{"params", "nominees"}},
+	{"oracle", []string{"asset_params", "nominees"}},
+	{"denominations", []string{"nominees"}},
+}
+
+// Migrate removes the nominee lists of the market, csdt, oracle and
+// denominations genesis states and grants their nominees the matching role
+// without expiry. Grants already present in the authority genesis state are
+// kept. Invalid nominee addresses fail the migration rather than silently
+// losing an admin.
+func Migrate(appState genutil.AppMap) (genutil.AppMap, error) {
+	gs := authority.DefaultGenesisState()
+	if raw, ok := appState[authority.ModuleName]; ok {
+		if err := authority.ModuleCdc.UnmarshalJSON(raw, &gs); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, list := range nomineeLists {
+		raw, ok := appState[list.module]
+		if !ok {
+			continue
+		}
+		migrated, nominees, err := removeNominees(raw, list.path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", list.module, err)
+		}
+		appState[list.module] = migrated
+
+		for _, n := range nominees {
+			addr, err := sdk.AccAddressFromBech32(n)
+			if err != nil {
+				return nil, fmt.Errorf("%s: invalid nominee '%s': %v", list.module, n, err)
+			}
+			gs.Roles = grant(gs.Roles, authority.NomineeRoles[list.module], addr)
+		}
+	}
+
+	if err := authority.ValidateGenesis(gs); err != nil {
+		return nil, err
+	}
+	raw, err := authority.ModuleCdc.MarshalJSON(gs)
+	if err != nil {
+		return nil, err
+	}
+	appState[authority.ModuleName] = raw
+	return appState, nil
+}
+
+// removeNominees deletes the list at path from a JSON object and returns
+// the object along with the list's entries.
+func removeNominees(raw json.RawMessage, path []string) (json.RawMessage, []string, error) {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return nil, nil, err
+	}
+	child, ok := obj[path[0]]
+	if !ok {
+		return raw, nil, nil
+	}
+
+	var nominees []string
+	if len(path) == 1 {
+		if err := json.Unmarshal(child, &nominees); err != nil {
+			return nil, nil, err
+		}
+		delete(obj, path[0])
+	} else {
+		migrated, found, err := removeNominees(child, path[1:])
+		if err != nil {
+			return nil, nil, err
+		}
+		obj[path[0]] = migrated
+		nominees = found
+	}
+
+	out, err := json.Marshal(obj)
+	return out, nominees, err
+}
+
+// grant adds a grant without expiry of role to addr unless addr already
+// holds it, adding the role if it is missing.
+func grant(roles []authority.Role, role string, addr sdk.AccAddress) []authority.Role {
+	for i, r := range roles {
+		if r.Name != role {
+			continue
+		}
+		if _, ok := r.Grant(addr); !ok {
+			roles[i].Grants = append(r.Grants, authority.NewGrant(addr, time.Time{}))
+		}
+		return roles
+	}
+	return append(roles, authority.NewRole(role, 1, []authority.Grant{authority.NewGrant(addr, time.Time{})}))
+}
@@ -0,0 +1,67 @@
+package legacy_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/genutil"
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/crypto"
+
+	"github.com/xar-network/xar-network/x/authority"
+	"github.com/xar-network/xar-network/x/authority/legacy"
+)
+
+func TestMigrate(t *testing.T) {
+	admin := sdk.AccAddress(crypto.AddressHash([]byte("admin")))
+	issuer := sdk.AccAddress(crypto.AddressHash([]byte("issuer")))
+
+	appState := genutil.AppMap{
+		"market":        json.RawMessage(`{"markets":[],"nominees":["` + admin.String() + `"]}`),
+		"csdt":          json.RawMessage(`{"params":{"global_debt_limit":[],"nominees":["` + admin.String() + `"]},"global_debt":"0"}`),
+		"oracle":        json.RawMessage(`{"asset_params":{"assets":[],"nominees":["` + admin.String() + `","` + issuer.String() + `"]}}`),
+		"denominations": json.RawMessage(`{"nominees":["` + issuer.String() + `"],"token_records":[]}`),
+		"bank":          json.RawMessage(`{"send_enabled":true}`),
+	}
+
+	migrated, err := legacy.Migrate(appState)
+	require.NoError(t, err)
+
+	require.JSONEq(t, `{"markets":[]}`, string(migrated["market"]))
+	require.JSONEq(t, `{"params":{"global_debt_limit":[]},"global_debt":"0"}`, string(migrated["csdt"]))
+	require.JSONEq(t, `{"asset_params":{"assets":[]}}`, string(migrated["oracle"]))
+	require.JSONEq(t, `{"token_records":[]}`, string(migrated["denominations"]))
+	require.JSONEq(t, `{"send_enabled":true}`, string(migrated["bank"]))
+
+	var gs authority.GenesisState
+	require.NoError(t, authority.ModuleCdc.UnmarshalJSON(migrated[authority.ModuleName], &gs))
+	require.NoError(t, authority.ValidateGenesis(gs))
+	grantees := make(map[string][]sdk.AccAddress)
+	for _, role := range gs.Roles {
+		require.Equal(t, uint16(1), role.Threshold)
+		for _, g := range role.Grants {
+			require.True(t, g.Expiry.IsZero())
+			grantees[role.Name] = append(grantees[role.Name], g.Grantee)
+		}
+	}
+	require.Equal(t, map[string][]sdk.AccAddress{
+		authority.RoleMarketAdmin:     {admin},
+		authority.RoleCollateralAdmin: {admin},
+		authority.RoleOracleAdmin:     {admin, issuer},
+		authority.RoleTokenIssuer:     {issuer},
+	}, grantees)
+
+	// migrating again keeps the grants
+	again, err := legacy.Migrate(migrated)
+	require.NoError(t, err)
+	require.JSONEq(t, string(migrated[authority.ModuleName]), string(again[authority.ModuleName]))
+}
+
+func TestMigrate_InvalidNominee(t *testing.T) {
+	appState := genutil.AppMap{
+		"market": json.RawMessage(`{"markets":[],"nominees":["cosmos1wdhk6e2wv9kk2j88d92"]}`),
+	}
+	_, err := legacy.Migrate(appState)
+	require.Error(t, err)
+}
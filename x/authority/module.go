@@ -0,0 +1,108 @@
+package authority
+
+import (
+	"encoding/json"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/module"
+
+	"github.com/gorilla/mux"
+	"github.com/spf13/cobra"
+
+	"github.com/xar-network/xar-network/x/authority/client/cli"
+	"github.com/xar-network/xar-network/x/authority/types"
+)
+
+var (
+	_ module.AppModule      = AppModule{}
+	_ module.AppModuleBasic = AppModuleBasic{}
+)
+
+// AppModuleBasic defines the basic application module used by the authority module.
+type AppModuleBasic struct{}
+
+func (a AppModuleBasic) Name() string { return types.ModuleName }
+
+func (a AppModuleBasic) RegisterCodec(cdc *codec.Codec) {
+	types.RegisterCodec(cdc)
+}
+
+func (a AppModuleBasic) DefaultGenesis() json.RawMessage {
+	return types.ModuleCdc.MustMarshalJSON(DefaultGenesisState())
+}
+
+func (a AppModuleBasic) ValidateGenesis(b json.RawMessage) error {
+	var data GenesisState
+	err := types.ModuleCdc.UnmarshalJSON(b, &data)
+	if err != nil {
+		return err
+	}
+	return ValidateGenesis(data)
+}
+
+func (a AppModuleBasic) RegisterRESTRoutes(context.CLIContext, *mux.Router) {
+}
+
+// GetTxCmd returns nothing, roles are only changed through governance.
+func (a AppModuleBasic) GetTxCmd(cdc *codec.Codec) *cobra.Command {
+	return nil
+}
+
+func (a AppModuleBasic) GetQueryCmd(cdc *codec.Codec) *cobra.Command {
+	return cli.GetQueryCmd(types.QuerierRoute, cdc)
+}
+
+// AppModule implements an application module for the authority module.
+type AppModule struct {
+	AppModuleBasic
+	keeper Keeper
+}
+
+// NewAppModule creates a new AppModule object
+func NewAppModule(keeper Keeper) AppModule {
+	return AppModule{
+		AppModuleBasic: AppModuleBasic{},
+		keeper:         keeper,
+	}
+}
+
+func (a AppModule) InitGenesis(ctx sdk.Context, data json.RawMessage) []abci.ValidatorUpdate {
+	var genesisState GenesisState
+	types.ModuleCdc.MustUnmarshalJSON(data, &genesisState)
+	InitGenesis(ctx, a.keeper, genesisState)
+	return []abci.ValidatorUpdate{}
+}
+
+func (a AppModule) ExportGenesis(ctx sdk.Context) json.RawMessage {
+	gs := ExportGenesis(ctx, a.keeper)
+	return types.ModuleCdc.MustMarshalJSON(gs)
+}
+
+func (a AppModule) RegisterInvariants(sdk.InvariantRegistry) {
+}
+
+// Route returns no route, the module has no messages of its own.
+func (a AppModule) Route() string { return "" }
+
+func (a AppModule) NewHandler() sdk.Handler {
+	return nil
+}
+
+func (a AppModule) QuerierRoute() string {
+	return types.QuerierRoute
+}
+
+func (a AppModule) NewQuerierHandler() sdk.Querier {
+	return NewQuerier(a.keeper)
+}
+
+func (a AppModule) BeginBlock(sdk.Context, abci.RequestBeginBlock) {
+}
+
+func (a AppModule) EndBlock(sdk.Context, abci.RequestEndBlock) []abci.ValidatorUpdate {
+	return []abci.ValidatorUpdate{}
+}
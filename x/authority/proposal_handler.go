@@ -0,0 +1,68 @@
+package authority
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types"
+
+	"github.com/xar-network/xar-network/x/authority/types"
+)
+
+// NewProposalHandler handles the authority module's governance proposals.
+func NewProposalHandler(k Keeper, ak types.AccountKeeper) govtypes.Handler {
+	return func(ctx sdk.Context, content govtypes.Content) sdk.Error {
+		switch c := content.(type) {
+		case types.UpdateRoleProposal:
+			return handleUpdateRoleProposal(ctx, k, ak, c)
+		case types.RotateNomineesProposal:
+			return handleRotateNomineesProposal(ctx, k, ak, c)
+		default:
+			return sdk.ErrUnknownRequest(fmt.Sprintf("unrecognized authority proposal content type: %T", c))
+		}
+	}
+}
+
+// handleUpdateRoleProposal replaces a role's threshold and grants. Grantees
+// have to sign transactions, so only existing accounts are accepted, and
+// grants must not have expired by the time the proposal passes.
+func handleUpdateRoleProposal(ctx sdk.Context, k Keeper, ak types.AccountKeeper, p types.UpdateRoleProposal) sdk.Error {
+	if err := checkGrantees(ctx, ak, p.Grants); err != nil {
+		return err
+	}
+	return k.UpdateRole(ctx, p.ToRole())
+}
+
+// handleRotateNomineesProposal replaces the grants of the role held by a
+// module's nominees. The role keeps its threshold, so leaving fewer
+// nominees than the threshold fails the proposal.
+func handleRotateNomineesProposal(ctx sdk.Context, k Keeper, ak types.AccountKeeper, p types.RotateNomineesProposal) sdk.Error {
+	name, ok := types.NomineeRoles[p.Module]
+	if !ok {
+		return sdk.ErrUnknownRequest(fmt.Sprintf("module has no nominees: '%s'", p.Module))
+	}
+	grants, err := p.Grants()
+	if err != nil {
+		return sdk.ErrInvalidAddress(err.Error())
+	}
+	if err := checkGrantees(ctx, ak, grants); err != nil {
+		return err
+	}
+	role := k.GetRole(ctx, name)
+	role.Grants = grants
+	return k.UpdateRole(ctx, role)
+}
+
+// checkGrantees returns an error unless every grantee is an existing account
+// and no grant has expired yet.
+func checkGrantees(ctx sdk.Context, ak types.AccountKeeper, grants []types.Grant) sdk.Error {
+	for _, g := range grants {
+		if ak.GetAccount(ctx, g.Grantee) == nil {
+			return sdk.ErrUnknownAddress(fmt.Sprintf("account %s does not exist", g.Grantee))
+		}
+		if !g.IsActive(ctx.BlockTime()) {
+			return sdk.ErrUnknownRequest(fmt.Sprintf("grant of %s has already expired", g.Grantee))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package authority
+
+import (
+	"fmt"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/xar-network/xar-network/x/authority/types"
+)
+
+const (
+	QueryRoles  = "roles"
+	QueryRole   = "role"
+	QueryEvents = "events"
+)
+
+func NewQuerier(keeper Keeper) sdk.Querier {
+	return func(ctx sdk.Context, path []string, req abci.RequestQuery) (res []byte, err sdk.Error) {
+		switch path[0] {
+		case QueryRoles:
+			return queryRoles(ctx, keeper)
+		case QueryRole:
+			return queryRole(ctx, path[1:], keeper)
+		case QueryEvents:
+			return queryEvents(ctx, req, keeper)
+		default:
+			return nil, sdk.ErrUnknownRequest("unknown authority query endpoint")
+		}
+	}
+}
+
+func queryRoles(ctx sdk.Context, keeper Keeper) ([]byte, sdk.Error) {
+	res := types.RolesQueryResult{
+		Roles: make([]types.Role, 0, len(types.RoleNames)),
+	}
+	for _, name := range types.RoleNames {
+		res.Roles = append(res.Roles, keeper.GetRole(ctx, name))
+	}
+
+	b, err := codec.MarshalJSONIndent(keeper.cdc, res)
+	if err != nil {
+		panic(err)
+	}
+	return b, nil
+}
+
+func queryRole(ctx sdk.Context, path []string, keeper Keeper) ([]byte, sdk.Error) {
+	if len(path) != 1 {
+		return nil, sdk.ErrUnknownRequest("role query requires a role name")
+	}
+	if !types.IsRoleName(path[0]) {
+		return nil, sdk.ErrUnknownRequest(fmt.Sprintf("unknown role: '%s'", path[0]))
+	}
+
+	b, err := codec.MarshalJSONIndent(keeper.cdc, keeper.GetRole(ctx, path[0]))
+	if err != nil {
+		panic(err)
+	}
+	return b, nil
+}
+
+func queryEvents(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) ([]byte, sdk.Error) {
+	var params types.EventsQueryParams
+	if len(req.Data) > 0 {
+		if err := keeper.cdc.UnmarshalJSON(req.Data, &params); err != nil {
+			return nil, sdk.ErrUnknownRequest(fmt.Sprintf("failed to parse params: %s", err))
+		}
+	}
+	skip, limit := params.Bounds()
+	res := types.EventsQueryResult{
+		Events: make([]types.Event, 0),
+		Page:   skip/limit + 1,
+		Limit:  limit,
+	}
+
+	keeper.EventIterator(ctx, func(ev types.Event) bool {
+		if params.Role != "" && ev.Role != params.Role {
+			return true
+		}
+		if skip > 0 {
+			skip--
+			return true
+		}
+		if len(res.Events) == limit {
+			res.More = true
+			return false
+		}
+		res.Events = append(res.Events, ev)
+		return true
+	})
+
+	b, err := codec.MarshalJSONIndent(keeper.cdc, res)
+	if err != nil {
+		panic(err)
+	}
+	return b, nil
+}
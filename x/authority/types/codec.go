@@ -0,0 +1,17 @@
+package types
+
+import "github.com/cosmos/cosmos-sdk/codec"
+
+var ModuleCdc *codec.Codec
+
+func RegisterCodec(cdc *codec.Codec) {
+	cdc.RegisterConcrete(UpdateRoleProposal{}, "authority/UpdateRoleProposal", nil)
+	cdc.RegisterConcrete(RotateNomineesProposal{}, "authority/RotateNomineesProposal", nil)
+}
+
+func init() {
+	ModuleCdc = codec.New()
+	RegisterCodec(ModuleCdc)
+	codec.RegisterCrypto(ModuleCdc)
+	ModuleCdc.Seal()
+}
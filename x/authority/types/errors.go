@@ -0,0 +1,20 @@
+package types
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	// DefaultCodespace codespace for the module
+	DefaultCodespace sdk.CodespaceType = ModuleName
+
+	// CodeNotGranted error code for accounts acting without a role
+	CodeNotGranted sdk.CodeType = 1
+)
+
+// ErrNotGranted Error constructor
+func ErrNotGranted(codespace sdk.CodespaceType, role string, addr sdk.AccAddress) sdk.Error {
+	return sdk.NewError(codespace, CodeNotGranted, fmt.Sprintf("%s does not hold role %s", addr, role))
+}
@@ -0,0 +1,69 @@
+package types
+
+import (
+	"fmt"
+	"time"
+
+	cmn "github.com/tendermint/tendermint/libs/common"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/xar-network/xar-network/types/store"
+)
+
+const (
+	// EventGrant records an account being granted a role, or its grant's
+	// expiry changing.
+	EventGrant = "grant"
+	// EventRevoke records an account losing a role.
+	EventRevoke = "revoke"
+	// EventThreshold records a change of a role's threshold.
+	EventThreshold = "threshold"
+	// EventApprove records a grantee approving an action that still needs
+	// more approvals.
+	EventApprove = "approve"
+	// EventExecute records an action going ahead, along with the grantee
+	// whose approval completed it.
+	EventExecute = "execute"
+
+	// EventTypeApprovalPending is the ABCI event of an approval that did not
+	// complete its action yet, so that the result of the approving message
+	// tells it apart from one that executed the action.
+	EventTypeApprovalPending = "approval_pending"
+
+	// ABCI event attributes
+	AttributeKeyAction    = "action"
+	AttributeKeyRole      = "role"
+	AttributeKeyAddress   = "address"
+	AttributeKeyDigest    = "digest"
+	AttributeKeyApprovals = "approvals"
+	AttributeKeyThreshold = "threshold"
+
+	// MaxEvents is how many entries the audit log keeps. Appending to a full
+	// log prunes its oldest entries.
+	MaxEvents = 10000
+)
+
+// Event is an entry of the authority module's audit log. Address is the
+// grantee concerned, if any, and Digest identifies the approved action.
+type Event struct {
+	ID        store.EntityID `json:"id" yaml:"id"`
+	Height    int64          `json:"height" yaml:"height"`
+	Time      time.Time      `json:"time" yaml:"time"`
+	Action    string         `json:"action" yaml:"action"`
+	Role      string         `json:"role" yaml:"role"`
+	Address   sdk.AccAddress `json:"address,omitempty" yaml:"address"`
+	Threshold uint16         `json:"threshold,omitempty" yaml:"threshold"`
+	Digest    cmn.HexBytes   `json:"digest,omitempty" yaml:"digest"`
+}
+
+func (e Event) String() string {
+	return fmt.Sprintf(`Event %s:
+  Height:    %d
+  Time:      %s
+  Action:    %s
+  Role:      %s
+  Address:   %s
+  Threshold: %d
+  Digest:    %s`, e.ID, e.Height, e.Time, e.Action, e.Role, e.Address, e.Threshold, e.Digest)
+}
@@ -0,0 +1,15 @@
+package types
+
+const (
+	// ModuleName The name that will be used throughout the module
+	ModuleName = "authority"
+
+	// StoreKey Top level store key where all module items will be stored
+	StoreKey = ModuleName
+
+	// RouterKey Top level router key
+	RouterKey = ModuleName
+
+	// QuerierRoute Top level query string
+	QuerierRoute = ModuleName
+)
@@ -0,0 +1,158 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types"
+)
+
+const (
+	// ProposalTypeUpdateRole defines the type for an UpdateRoleProposal
+	ProposalTypeUpdateRole = "UpdateRole"
+	// ProposalTypeRotateNominees defines the type for a RotateNomineesProposal
+	ProposalTypeRotateNominees = "RotateNominees"
+)
+
+var (
+	_ govtypes.Content = UpdateRoleProposal{}
+	_ govtypes.Content = RotateNomineesProposal{}
+)
+
+func init() {
+	govtypes.RegisterProposalType(ProposalTypeUpdateRole)
+	govtypes.RegisterProposalTypeCodec(UpdateRoleProposal{}, "authority/UpdateRoleProposal")
+	govtypes.RegisterProposalType(ProposalTypeRotateNominees)
+	govtypes.RegisterProposalTypeCodec(RotateNomineesProposal{}, "authority/RotateNomineesProposal")
+}
+
+// UpdateRoleProposal replaces the threshold and grants of a role.
+type UpdateRoleProposal struct {
+	Title       string  `json:"title" yaml:"title"`
+	Description string  `json:"description" yaml:"description"`
+	Role        string  `json:"role" yaml:"role"`
+	Threshold   uint16  `json:"threshold" yaml:"threshold"`
+	Grants      []Grant `json:"grants" yaml:"grants"`
+}
+
+func NewUpdateRoleProposal(title, description, role string, threshold uint16, grants []Grant) UpdateRoleProposal {
+	return UpdateRoleProposal{
+		Title:       title,
+		Description: description,
+		Role:        role,
+		Threshold:   threshold,
+		Grants:      grants,
+	}
+}
+
+func (p UpdateRoleProposal) GetTitle() string { return p.Title }
+
+func (p UpdateRoleProposal) GetDescription() string { return p.Description }
+
+func (p UpdateRoleProposal) ProposalRoute() string { return RouterKey }
+
+func (p UpdateRoleProposal) ProposalType() string { return ProposalTypeUpdateRole }
+
+// ToRole returns the role as it will be after the proposal passed.
+func (p UpdateRoleProposal) ToRole() Role {
+	return NewRole(p.Role, p.Threshold, p.Grants)
+}
+
+func (p UpdateRoleProposal) ValidateBasic() sdk.Error {
+	if err := govtypes.ValidateAbstract(DefaultCodespace, p); err != nil {
+		return err
+	}
+	if err := p.ToRole().Validate(); err != nil {
+		return sdk.ErrUnknownRequest(err.Error())
+	}
+	return nil
+}
+
+func (p UpdateRoleProposal) String() string {
+	grants := make([]string, 0, len(p.Grants))
+	for _, g := range p.Grants {
+		grants = append(grants, g.String())
+	}
+	return fmt.Sprintf(`Update Role Proposal:
+  Title:       %s
+  Description: %s
+  Role:        %s
+  Threshold:   %d
+  Grants:      %s
+`, p.Title, p.Description, p.Role, p.Threshold, strings.Join(grants, ", "))
+}
+
+// RotateNomineesProposal replaces the nominees of a module, i.e. the grants
+// of the role listed for the module in NomineeRoles. Nominees are granted
+// the role without expiry and the role keeps its threshold.
+type RotateNomineesProposal struct {
+	Title       string   `json:"title" yaml:"title"`
+	Description string   `json:"description" yaml:"description"`
+	Module      string   `json:"module" yaml:"module"`
+	Nominees    []string `json:"nominees" yaml:"nominees"`
+}
+
+func NewRotateNomineesProposal(title, description, module string, nominees []string) RotateNomineesProposal {
+	return RotateNomineesProposal{
+		Title:       title,
+		Description: description,
+		Module:      module,
+		Nominees:    nominees,
+	}
+}
+
+func (p RotateNomineesProposal) GetTitle() string { return p.Title }
+
+func (p RotateNomineesProposal) GetDescription() string { return p.Description }
+
+func (p RotateNomineesProposal) ProposalRoute() string { return RouterKey }
+
+func (p RotateNomineesProposal) ProposalType() string { return ProposalTypeRotateNominees }
+
+// Grants returns the grants without expiry of the nominees.
+func (p RotateNomineesProposal) Grants() ([]Grant, error) {
+	grants := make([]Grant, 0, len(p.Nominees))
+	for _, n := range p.Nominees {
+		addr, err := sdk.AccAddressFromBech32(n)
+		if err != nil {
+			return nil, fmt.Errorf("invalid nominee: '%s'", n)
+		}
+		grants = append(grants, NewGrant(addr, time.Time{}))
+	}
+	return grants, nil
+}
+
+func (p RotateNomineesProposal) ValidateBasic() sdk.Error {
+	if err := govtypes.ValidateAbstract(DefaultCodespace, p); err != nil {
+		return err
+	}
+	if _, ok := NomineeRoles[p.Module]; !ok {
+		return sdk.ErrUnknownRequest(fmt.Sprintf("module has no nominees: '%s'", p.Module))
+	}
+	if len(p.Nominees) == 0 {
+		return sdk.ErrUnknownRequest("a module needs at least one nominee")
+	}
+	grants, err := p.Grants()
+	if err != nil {
+		return sdk.ErrInvalidAddress(err.Error())
+	}
+	seen := make(map[string]bool)
+	for _, g := range grants {
+		if seen[g.Grantee.String()] {
+			return sdk.ErrUnknownRequest(fmt.Sprintf("duplicate nominee: '%s'", g.Grantee))
+		}
+		seen[g.Grantee.String()] = true
+	}
+	return nil
+}
+
+func (p RotateNomineesProposal) String() string {
+	return fmt.Sprintf(`Rotate Nominees Proposal:
+  Title:       %s
+  Description: %s
+  Module:      %s
+  Nominees:    %s
+`, p.Title, p.Description, p.Module, strings.Join(p.Nominees, ", "))
+}
@@ -0,0 +1,114 @@
+package types
+
+import (
+	"bytes"
+	"strconv"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+const (
+	DefaultQueryLimit = 100
+	MaxQueryLimit     = 1000
+)
+
+type RolesQueryResult struct {
+	Roles []Role `json:"roles"`
+}
+
+func (r RolesQueryResult) String() string {
+	var buf bytes.Buffer
+	t := tablewriter.NewWriter(&buf)
+	t.SetHeader([]string{
+		"Role",
+		"Threshold",
+		"Grantee",
+		"Expiry",
+	})
+
+	for _, r := range r.Roles {
+		if len(r.Grants) == 0 {
+			t.Append([]string{r.Name, strconv.FormatUint(uint64(r.Threshold), 10), "", ""})
+		}
+		for _, g := range r.Grants {
+			expiry := ""
+			if !g.Expiry.IsZero() {
+				expiry = g.Expiry.String()
+			}
+			t.Append([]string{r.Name, strconv.FormatUint(uint64(r.Threshold), 10), g.Grantee.String(), expiry})
+		}
+	}
+
+	t.Render()
+	return string(buf.Bytes())
+}
+
+// EventsQueryParams selects a page of the audit log, oldest first. An empty
+// role matches the events of every role.
+type EventsQueryParams struct {
+	Role  string `json:"role,omitempty"`
+	Page  int    `json:"page"`
+	Limit int    `json:"limit"`
+}
+
+func NewEventsQueryParams(role string, page, limit int) EventsQueryParams {
+	return EventsQueryParams{
+		Role:  role,
+		Page:  page,
+		Limit: limit,
+	}
+}
+
+// Bounds returns how many matching events to skip and how many to return,
+// applying the default limit and capping it at MaxQueryLimit.
+func (p EventsQueryParams) Bounds() (skip int, limit int) {
+	limit = p.Limit
+	if limit <= 0 {
+		limit = DefaultQueryLimit
+	}
+	if limit > MaxQueryLimit {
+		limit = MaxQueryLimit
+	}
+	page := p.Page
+	if page < 1 {
+		page = 1
+	}
+	return (page - 1) * limit, limit
+}
+
+type EventsQueryResult struct {
+	Events []Event `json:"events"`
+	Page   int     `json:"page"`
+	Limit  int     `json:"limit"`
+	// More is true if there are matching events beyond this page.
+	More bool `json:"more"`
+}
+
+func (l EventsQueryResult) String() string {
+	var buf bytes.Buffer
+	t := tablewriter.NewWriter(&buf)
+	t.SetHeader([]string{
+		"ID",
+		"Height",
+		"Action",
+		"Role",
+		"Address",
+		"Threshold",
+		"Digest",
+	})
+
+	for _, e := range l.Events {
+		t.Append([]string{
+			e.ID.String(),
+			strconv.FormatInt(e.Height, 10),
+			e.Action,
+			e.Role,
+			e.Address.String(),
+			strconv.FormatUint(uint64(e.Threshold), 10),
+			e.Digest.String(),
+		})
+	}
+
+	t.Render()
+	return string(buf.Bytes())
+}
@@ -0,0 +1,153 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	// RoleMarketAdmin lists, halts, delists and reconfigures markets.
+	RoleMarketAdmin = "market-admin"
+	// RoleCollateralAdmin adds and updates CSDT collateral types.
+	RoleCollateralAdmin = "collateral-admin"
+	// RoleOracleAdmin adds oracle assets and sets their oracles.
+	RoleOracleAdmin = "oracle-admin"
+	// RoleTokenIssuer issues new tokens.
+	RoleTokenIssuer = "token-issuer"
+)
+
+// RoleNames are the roles known to the authority module, in the order they
+// are listed in genesis.
+var RoleNames = []string{
+	RoleMarketAdmin,
+	RoleCollateralAdmin,
+	RoleOracleAdmin,
+	RoleTokenIssuer,
+}
+
+// NomineeRoles maps the modules that used to keep a list of nominees to
+// the role their nominees hold.
+var NomineeRoles = map[string]string{
+	"market":        RoleMarketAdmin,
+	"csdt":          RoleCollateralAdmin,
+	"oracle":        RoleOracleAdmin,
+	"denominations": RoleTokenIssuer,
+}
+
+// IsRoleName returns true if name is a known role.
+func IsRoleName(name string) bool {
+	for _, n := range RoleNames {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Grant gives an account a role, optionally until an expiry time. A zero
+// expiry never expires.
+type Grant struct {
+	Grantee sdk.AccAddress `json:"grantee" yaml:"grantee"`
+	Expiry  time.Time      `json:"expiry" yaml:"expiry"`
+}
+
+func NewGrant(grantee sdk.AccAddress, expiry time.Time) Grant {
+	return Grant{
+		Grantee: grantee,
+		Expiry:  expiry,
+	}
+}
+
+// IsActive returns true if the grant has not expired at time now.
+func (g Grant) IsActive(now time.Time) bool {
+	return g.Expiry.IsZero() || now.Before(g.Expiry)
+}
+
+func (g Grant) String() string {
+	if g.Expiry.IsZero() {
+		return g.Grantee.String()
+	}
+	return fmt.Sprintf("%s (until %s)", g.Grantee, g.Expiry.UTC().Format(time.RFC3339))
+}
+
+// Role is a named set of grants. Actions under a role need the approval of
+// Threshold distinct active grantees before they go ahead.
+type Role struct {
+	Name      string  `json:"name" yaml:"name"`
+	Threshold uint16  `json:"threshold" yaml:"threshold"`
+	Grants    []Grant `json:"grants" yaml:"grants"`
+}
+
+func NewRole(name string, threshold uint16, grants []Grant) Role {
+	return Role{
+		Name:      name,
+		Threshold: threshold,
+		Grants:    grants,
+	}
+}
+
+// DefaultRoles returns every known role without grants and with a threshold
+// of one.
+func DefaultRoles() []Role {
+	roles := make([]Role, 0, len(RoleNames))
+	for _, name := range RoleNames {
+		roles = append(roles, NewRole(name, 1, []Grant{}))
+	}
+	return roles
+}
+
+// Grant returns the grant of addr, if any.
+func (r Role) Grant(addr sdk.AccAddress) (Grant, bool) {
+	for _, g := range r.Grants {
+		if g.Grantee.Equals(addr) {
+			return g, true
+		}
+	}
+	return Grant{}, false
+}
+
+// IsGranted returns true if addr holds an active grant of the role at time
+// now.
+func (r Role) IsGranted(addr sdk.AccAddress, now time.Time) bool {
+	g, ok := r.Grant(addr)
+	return ok && g.IsActive(now)
+}
+
+// Validate checks that the role is known, that grantees are unique and that
+// the threshold can be met by the role's grantees.
+func (r Role) Validate() error {
+	if !IsRoleName(r.Name) {
+		return fmt.Errorf("unknown role: '%s'", r.Name)
+	}
+	if r.Threshold == 0 {
+		return errors.New("threshold must be positive")
+	}
+	if len(r.Grants) > 0 && int(r.Threshold) > len(r.Grants) {
+		return fmt.Errorf("threshold %d exceeds the %d grantees", r.Threshold, len(r.Grants))
+	}
+	seen := make(map[string]bool)
+	for _, g := range r.Grants {
+		if g.Grantee.Empty() {
+			return errors.New("missing grantee")
+		}
+		if seen[g.Grantee.String()] {
+			return fmt.Errorf("duplicate grantee: '%s'", g.Grantee)
+		}
+		seen[g.Grantee.String()] = true
+	}
+	return nil
+}
+
+func (r Role) String() string {
+	grants := make([]string, 0, len(r.Grants))
+	for _, g := range r.Grants {
+		grants = append(grants, g.String())
+	}
+	return fmt.Sprintf(`Role %s:
+  Threshold: %d
+  Grants:    %s`, r.Name, r.Threshold, strings.Join(grants, ", "))
+}
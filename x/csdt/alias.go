@@ -17,6 +17,9 @@ type (
 	CSDTs            = types.CSDTs
 	Params           = types.Params
 	CollateralParams = types.CollateralParams
+	CollateralParam  = types.CollateralParam
+
+	CollateralTypeProposal = types.CollateralTypeProposal
 )
 
 const (
@@ -26,10 +29,22 @@ const (
 	DefaultParamspace = types.DefaultParamspace
 	StoreKey          = types.StoreKey
 	StableDenom       = types.StableDenom
+
+	EventTypeModifyCSDT          = types.EventTypeModifyCSDT
+	EventTypeSeizeCSDT           = types.EventTypeSeizeCSDT
+	AttributeValueCategory       = types.AttributeValueCategory
+	AttributeKeyOwner            = types.AttributeKeyOwner
+	AttributeKeyCollateralDenom  = types.AttributeKeyCollateralDenom
+	AttributeKeyCollateralChange = types.AttributeKeyCollateralChange
+	AttributeKeyDebtChange       = types.AttributeKeyDebtChange
+	AttributeKeyCollateral       = types.AttributeKeyCollateral
+	AttributeKeyDebt             = types.AttributeKeyDebt
 )
 
 var (
 	ModuleCdc     = types.ModuleCdc
 	NewKeeper     = keeper.NewKeeper
 	RegisterCodec = types.RegisterCodec
+
+	NewCollateralTypeProposal = types.NewCollateralTypeProposal
 )
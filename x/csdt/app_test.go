@@ -9,6 +9,7 @@ import (
 	"github.com/cosmos/cosmos-sdk/x/mock"
 	"github.com/cosmos/cosmos-sdk/x/supply"
 	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/xar-network/xar-network/x/authority"
 	"github.com/xar-network/xar-network/x/csdt"
 	"github.com/xar-network/xar-network/x/csdt/internal/types"
 	"github.com/xar-network/xar-network/x/oracle"
@@ -115,7 +116,6 @@ func TestApp_CreateModifyDeleteCSDT(t *testing.T) {
 			},
 		},
 	}
-	oracleParams.Nominees = []string{addrs[0].String()}
 
 	keeper.GetOracle().SetParams(ctx, oracleParams)
 	_, _ = keeper.GetOracle().SetPrice(
@@ -180,20 +180,22 @@ func setUpMockAppWithoutGenesis() (*mock.App, csdt.Keeper) {
 	keyCSDT := sdk.NewKVStoreKey(types.StoreKey)
 	keyOracle := sdk.NewKVStoreKey(oracle.StoreKey)
 	keySupply := sdk.NewKVStoreKey(supply.StoreKey)
+	keyAuthority := sdk.NewKVStoreKey(authority.StoreKey)
 
 	maccPerms := map[string][]string{
 		types.ModuleName: {supply.Minter, supply.Burner},
 	}
 
-	oracleKeeper := oracle.NewKeeper(keyOracle, mapp.Cdc, mapp.ParamsKeeper.Subspace(oracle.DefaultParamspace), oracle.DefaultCodespace)
+	authorityKeeper := authority.NewKeeper(keyAuthority, mapp.Cdc, authority.DefaultCodespace)
+	oracleKeeper := oracle.NewKeeper(keyOracle, mapp.Cdc, mapp.ParamsKeeper.Subspace(oracle.DefaultParamspace), authorityKeeper, oracle.DefaultCodespace)
 	bankKeeper := bank.NewBaseKeeper(mapp.AccountKeeper, mapp.ParamsKeeper.Subspace(bank.DefaultParamspace), bank.DefaultCodespace, map[string]bool{})
 	supplyKeeper := supply.NewKeeper(mapp.Cdc, keySupply, mapp.AccountKeeper, bankKeeper, maccPerms)
-	csdtKeeper := csdt.NewKeeper(mapp.Cdc, keyCSDT, mapp.ParamsKeeper.Subspace(types.DefaultParamspace), oracleKeeper, bankKeeper, supplyKeeper)
+	csdtKeeper := csdt.NewKeeper(mapp.Cdc, keyCSDT, mapp.ParamsKeeper.Subspace(types.DefaultParamspace), oracleKeeper, bankKeeper, supplyKeeper, authorityKeeper)
 
 	// Register routes
 	mapp.Router().AddRoute("csdt", csdt.NewHandler(csdtKeeper))
 	// Mount and load the stores
-	err := mapp.CompleteSetup(keyOracle, keyCSDT, keySupply)
+	err := mapp.CompleteSetup(keyOracle, keyCSDT, keySupply, keyAuthority)
 	if err != nil {
 		panic("mock app setup failed")
 	}
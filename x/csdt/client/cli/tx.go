@@ -10,7 +10,10 @@ import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/x/auth"
 	"github.com/cosmos/cosmos-sdk/x/auth/client/utils"
+	govcli "github.com/cosmos/cosmos-sdk/x/gov/client/cli"
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 	"github.com/xar-network/xar-network/x/csdt/internal/types"
 )
 
@@ -311,3 +314,49 @@ func GetCmdAddCollateralParam(cdc *codec.Codec) *cobra.Command {
 
 	return cmd
 }
+
+// GetCmdSubmitCollateralTypeProposal is registered as a gov submit-proposal subcommand.
+func GetCmdSubmitCollateralTypeProposal(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "collateral-type [collateral_denom] [liquidation_ratio] [debt_limit]",
+		Short: "submit a proposal to add or update a collateral type",
+		Long: `Submit a proposal to add a collateral type, or to update it if it already exists.
+The collateral denom must have an active oracle asset.`,
+		Args: cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			inBuf := bufio.NewReader(cmd.InOrStdin())
+			txBldr := auth.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
+			cliCtx := context.NewCLIContextWithInput(inBuf).WithCodec(cdc)
+
+			liquidationRatio, er := sdk.NewDecFromStr(args[1])
+			if er != nil {
+				return er
+			}
+			debtLimit, ok := sdk.NewIntFromString(args[2])
+			if !ok {
+				return fmt.Errorf("invalid debt limit - %s", args[2])
+			}
+			deposit, err := sdk.ParseCoins(viper.GetString(govcli.FlagDeposit))
+			if err != nil {
+				return err
+			}
+
+			param := types.CollateralParam{
+				Denom:            args[0],
+				LiquidationRatio: liquidationRatio,
+				DebtLimit:        sdk.NewCoins(sdk.NewCoin(types.StableDenom, debtLimit)),
+			}
+			content := types.NewCollateralTypeProposal(viper.GetString(govcli.FlagTitle), viper.GetString(govcli.FlagDescription), param)
+			msg := govtypes.NewMsgSubmitProposal(content, deposit, cliCtx.GetFromAddress())
+			if err := msg.ValidateBasic(); err != nil {
+				return err
+			}
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+	cmd.Flags().String(govcli.FlagTitle, "", "title of proposal")
+	cmd.Flags().String(govcli.FlagDescription, "", "description of proposal")
+	cmd.Flags().String(govcli.FlagDeposit, "", "deposit of proposal")
+
+	return cmd
+}
@@ -0,0 +1,10 @@
+package client
+
+import (
+	govclient "github.com/cosmos/cosmos-sdk/x/gov/client"
+	"github.com/xar-network/xar-network/x/csdt/client/cli"
+	"github.com/xar-network/xar-network/x/csdt/client/rest"
+)
+
+// CollateralTypeProposalHandler lets governance add and update collateral types
+var CollateralTypeProposalHandler = govclient.NewProposalHandler(cli.GetCmdSubmitCollateralTypeProposal, rest.ProposalRESTHandler)
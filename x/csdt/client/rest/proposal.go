@@ -0,0 +1,56 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/cosmos/cosmos-sdk/types/rest"
+
+	"github.com/xar-network/xar-network/x/csdt/internal/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth/client/utils"
+	govrest "github.com/cosmos/cosmos-sdk/x/gov/client/rest"
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types"
+)
+
+// CollateralTypeProposalReq defines a collateral type proposal request body
+type CollateralTypeProposalReq struct {
+	BaseReq         rest.BaseReq          `json:"base_req"`
+	Title           string                `json:"title"`
+	Description     string                `json:"description"`
+	CollateralParam types.CollateralParam `json:"collateral_param"`
+	Proposer        sdk.AccAddress        `json:"proposer"`
+	Deposit         sdk.Coins             `json:"deposit"`
+}
+
+// ProposalRESTHandler returns the collateral type proposal REST handler
+func ProposalRESTHandler(cliCtx context.CLIContext) govrest.ProposalRESTHandler {
+	return govrest.ProposalRESTHandler{
+		SubRoute: "collateral_type",
+		Handler:  postCollateralTypeProposalHandlerFn(cliCtx),
+	}
+}
+
+func postCollateralTypeProposalHandlerFn(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req CollateralTypeProposalReq
+		if !rest.ReadRESTReq(w, r, cliCtx.Codec, &req) {
+			return
+		}
+
+		req.BaseReq = req.BaseReq.Sanitize()
+		if !req.BaseReq.ValidateBasic(w) {
+			return
+		}
+
+		content := types.NewCollateralTypeProposal(req.Title, req.Description, req.CollateralParam)
+		msg := govtypes.NewMsgSubmitProposal(content, req.Deposit, req.Proposer)
+		if err := msg.ValidateBasic(); err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		utils.WriteGenerateStdTxResponse(w, cliCtx, req.BaseReq, []sdk.Msg{msg})
+	}
+}
@@ -10,6 +10,8 @@ import (
 	"fmt"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types"
+	"github.com/xar-network/xar-network/types/errs"
 	"github.com/xar-network/xar-network/x/csdt/internal/keeper"
 	"github.com/xar-network/xar-network/x/csdt/internal/types"
 )
@@ -51,7 +53,7 @@ func handleMsgCreateOrModifyCSDT(ctx sdk.Context, keeper keeper.Keeper, msg type
 		return err.Result()
 	}
 
-	return sdk.Result{Events: ctx.EventManager().Events()}
+	return errs.MessageResult(ctx, types.AttributeValueCategory, msg.Sender, "")
 }
 
 func handleMsgDepositCollateral(ctx sdk.Context, keeper keeper.Keeper, msg types.MsgDepositCollateral) sdk.Result {
@@ -66,7 +68,7 @@ func handleMsgDepositCollateral(ctx sdk.Context, keeper keeper.Keeper, msg types
 		return err.Result()
 	}
 
-	return sdk.Result{Events: ctx.EventManager().Events()}
+	return errs.MessageResult(ctx, types.AttributeValueCategory, msg.Sender, "")
 }
 
 func handleMsgWithdrawCollateral(ctx sdk.Context, keeper keeper.Keeper, msg types.MsgWithdrawCollateral) sdk.Result {
@@ -81,7 +83,7 @@ func handleMsgWithdrawCollateral(ctx sdk.Context, keeper keeper.Keeper, msg type
 		return err.Result()
 	}
 
-	return sdk.Result{Events: ctx.EventManager().Events()}
+	return errs.MessageResult(ctx, types.AttributeValueCategory, msg.Sender, "")
 }
 
 func handleMsgSettleDebt(ctx sdk.Context, keeper keeper.Keeper, msg types.MsgSettleDebt) sdk.Result {
@@ -96,7 +98,7 @@ func handleMsgSettleDebt(ctx sdk.Context, keeper keeper.Keeper, msg types.MsgSet
 		return err.Result()
 	}
 
-	return sdk.Result{Events: ctx.EventManager().Events()}
+	return errs.MessageResult(ctx, types.AttributeValueCategory, msg.Sender, "")
 }
 
 func handleMsgWithdrawDebt(ctx sdk.Context, keeper keeper.Keeper, msg types.MsgWithdrawDebt) sdk.Result {
@@ -111,7 +113,7 @@ func handleMsgWithdrawDebt(ctx sdk.Context, keeper keeper.Keeper, msg types.MsgW
 		return err.Result()
 	}
 
-	return sdk.Result{Events: ctx.EventManager().Events()}
+	return errs.MessageResult(ctx, types.AttributeValueCategory, msg.Sender, "")
 }
 
 func handleMsgSetCollateralParam(ctx sdk.Context, keeper keeper.Keeper, msg types.MsgSetCollateralParam) sdk.Result {
@@ -121,6 +123,12 @@ func handleMsgSetCollateralParam(ctx sdk.Context, keeper keeper.Keeper, msg type
 		return err.Result()
 	}
 
+	action := msg
+	action.Nominee = nil
+	if ok, err := keeper.Approve(ctx, msg.Nominee, action); !ok {
+		return pendingResult(ctx, err)
+	}
+
 	params := types.CollateralParam{
 		Denom:            msg.CollateralDenom,
 		LiquidationRatio: msg.LiquidationRatio,
@@ -142,6 +150,12 @@ func handleMsgAddCollateralParam(ctx sdk.Context, keeper keeper.Keeper, msg type
 		return err.Result()
 	}
 
+	action := msg
+	action.Nominee = nil
+	if ok, err := keeper.Approve(ctx, msg.Nominee, action); !ok {
+		return pendingResult(ctx, err)
+	}
+
 	params := types.CollateralParam{
 		Denom:            msg.CollateralDenom,
 		LiquidationRatio: msg.LiquidationRatio,
@@ -155,3 +169,26 @@ func handleMsgAddCollateralParam(ctx sdk.Context, keeper keeper.Keeper, msg type
 
 	return sdk.Result{Events: ctx.EventManager().Events()}
 }
+
+// pendingResult is the result of a message that was not executed, either
+// because its nominee is not a collateral admin or because it still needs
+// the approval of other collateral admins.
+func pendingResult(ctx sdk.Context, err sdk.Error) sdk.Result {
+	if err != nil {
+		return err.Result()
+	}
+	return sdk.Result{Events: ctx.EventManager().Events()}
+}
+
+// NewProposalHandler handles csdt governance proposals.
+func NewProposalHandler(k keeper.Keeper) govtypes.Handler {
+	return func(ctx sdk.Context, content govtypes.Content) sdk.Error {
+		switch c := content.(type) {
+		case types.CollateralTypeProposal:
+			return keeper.HandleCollateralTypeProposal(ctx, k, c)
+		default:
+			errMsg := fmt.Sprintf("Unrecognized csdt proposal content type: %T", c)
+			return sdk.ErrUnknownRequest(errMsg)
+		}
+	}
+}
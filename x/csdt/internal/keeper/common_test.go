@@ -6,6 +6,7 @@ import (
 	"github.com/cosmos/cosmos-sdk/x/mock"
 	"github.com/cosmos/cosmos-sdk/x/supply"
 	"github.com/tendermint/tendermint/crypto"
+	"github.com/xar-network/xar-network/x/authority"
 	"github.com/xar-network/xar-network/x/csdt/internal/keeper"
 	"github.com/xar-network/xar-network/x/csdt/internal/types"
 	"github.com/xar-network/xar-network/x/oracle"
@@ -21,6 +22,13 @@ import (
 //  - EndBlock signals the end of a block
 //  - Commit ?
 func setUpMockAppWithoutGenesis() (*mock.App, keeper.Keeper, []sdk.AccAddress, []crypto.PrivKey) {
+	mapp, csdtKeeper, _, addrs, privKeys := setUpMockAppWithAuthority()
+	return mapp, csdtKeeper, addrs, privKeys
+}
+
+// setUpMockAppWithAuthority is setUpMockAppWithoutGenesis that also returns
+// the authority keeper, so that tests can grant roles.
+func setUpMockAppWithAuthority() (*mock.App, keeper.Keeper, authority.Keeper, []sdk.AccAddress, []crypto.PrivKey) {
 	// Create uninitialized mock app
 	mapp := mock.NewApp()
 
@@ -32,18 +40,20 @@ func setUpMockAppWithoutGenesis() (*mock.App, keeper.Keeper, []sdk.AccAddress, [
 	keyCSDT := sdk.NewKVStoreKey(types.StoreKey)
 	keyOracle := sdk.NewKVStoreKey(oracle.StoreKey)
 	keySupply := sdk.NewKVStoreKey(supply.StoreKey)
+	keyAuthority := sdk.NewKVStoreKey(authority.StoreKey)
 
 	maccPerms := map[string][]string{
 		types.ModuleName: {supply.Minter, supply.Burner},
 	}
 
-	oracleKeeper := oracle.NewKeeper(keyOracle, mapp.Cdc, mapp.ParamsKeeper.Subspace(oracle.DefaultParamspace), oracle.DefaultCodespace)
+	authorityKeeper := authority.NewKeeper(keyAuthority, mapp.Cdc, authority.DefaultCodespace)
+	oracleKeeper := oracle.NewKeeper(keyOracle, mapp.Cdc, mapp.ParamsKeeper.Subspace(oracle.DefaultParamspace), authorityKeeper, oracle.DefaultCodespace)
 	bankKeeper := bank.NewBaseKeeper(mapp.AccountKeeper, mapp.ParamsKeeper.Subspace(bank.DefaultParamspace), bank.DefaultCodespace, map[string]bool{})
 	supplyKeeper := supply.NewKeeper(mapp.Cdc, keySupply, mapp.AccountKeeper, bankKeeper, maccPerms)
-	csdtKeeper := keeper.NewKeeper(mapp.Cdc, keyCSDT, mapp.ParamsKeeper.Subspace(types.DefaultParamspace), oracleKeeper, bankKeeper, supplyKeeper)
+	csdtKeeper := keeper.NewKeeper(mapp.Cdc, keyCSDT, mapp.ParamsKeeper.Subspace(types.DefaultParamspace), oracleKeeper, bankKeeper, supplyKeeper, authorityKeeper)
 
 	// Mount and load the stores
-	err := mapp.CompleteSetup(keyOracle, keyCSDT, keySupply)
+	err := mapp.CompleteSetup(keyOracle, keyCSDT, keySupply, keyAuthority)
 	if err != nil {
 		panic("mock app setup failed")
 	}
@@ -52,7 +62,7 @@ func setUpMockAppWithoutGenesis() (*mock.App, keeper.Keeper, []sdk.AccAddress, [
 	genAccs, addrs, _, privKeys := mock.CreateGenAccounts(10, sdk.NewCoins(sdk.NewInt64Coin("token1", 100), sdk.NewInt64Coin("token2", 100)))
 	mock.SetGenesis(mapp, genAccs)
 
-	return mapp, csdtKeeper, addrs, privKeys
+	return mapp, csdtKeeper, authorityKeeper, addrs, privKeys
 }
 
 // Avoid cluttering test cases with long function name
@@ -7,6 +7,7 @@ import (
 	"github.com/cosmos/cosmos-sdk/codec"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/x/params"
+	"github.com/xar-network/xar-network/x/authority"
 	"github.com/xar-network/xar-network/x/csdt/internal/types"
 )
 
@@ -18,6 +19,7 @@ type Keeper struct {
 	oracle         types.OracleKeeper
 	bank           types.BankKeeper
 	sk             types.SupplyKeeper
+	authority      types.AuthorityKeeper
 }
 
 // NewKeeper creates a new keeper
@@ -28,6 +30,7 @@ func NewKeeper(
 	oracle types.OracleKeeper,
 	bank types.BankKeeper,
 	supply types.SupplyKeeper,
+	authority types.AuthorityKeeper,
 ) Keeper {
 	return Keeper{
 		storeKey:       storeKey,
@@ -36,6 +39,7 @@ func NewKeeper(
 		paramsSubspace: subspace.WithKeyTable(types.ParamKeyTable()),
 		cdc:            cdc,
 		sk:             supply,
+		authority:      authority,
 	}
 }
 
@@ -192,6 +196,17 @@ func (k Keeper) ModifyCSDT(ctx sdk.Context, owner sdk.AccAddress, collateralDeno
 	k.SetGlobalDebt(ctx, gDebt)
 	k.SetCollateralState(ctx, collateralState)
 
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeModifyCSDT,
+			sdk.NewAttribute(types.AttributeKeyOwner, owner.String()),
+			sdk.NewAttribute(types.AttributeKeyCollateralDenom, collateralDenom),
+			sdk.NewAttribute(types.AttributeKeyCollateralChange, changeInCollateral.String()),
+			sdk.NewAttribute(types.AttributeKeyDebtChange, changeInDebt.String()),
+			sdk.NewAttribute(types.AttributeKeyCollateral, csdt.CollateralAmount.String()),
+			sdk.NewAttribute(types.AttributeKeyDebt, csdt.Debt.String()),
+		),
+	)
 	return nil
 }
 
@@ -260,6 +275,18 @@ func (k Keeper) PartialSeizeCSDT(ctx sdk.Context, owner sdk.AccAddress, collater
 		k.SetCSDT(ctx, csdt)
 	}
 	k.SetCollateralState(ctx, collateralState)
+
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeSeizeCSDT,
+			sdk.NewAttribute(types.AttributeKeyOwner, owner.String()),
+			sdk.NewAttribute(types.AttributeKeyCollateralDenom, collateralDenom),
+			sdk.NewAttribute(types.AttributeKeyCollateralChange, collateralToSeize.Neg().String()),
+			sdk.NewAttribute(types.AttributeKeyDebtChange, debtToSeize.Neg().String()),
+			sdk.NewAttribute(types.AttributeKeyCollateral, csdt.CollateralAmount.String()),
+			sdk.NewAttribute(types.AttributeKeyDebt, csdt.Debt.String()),
+		),
+	)
 	return nil
 }
 
@@ -434,13 +461,17 @@ func (k Keeper) GetSupply() types.SupplyKeeper {
 	return k.sk
 }
 
+// IsNominee returns true if nominee is an active collateral admin.
 func (k Keeper) IsNominee(ctx sdk.Context, nominee string) bool {
-	params := k.GetParams(ctx)
-	nominees := params.Nominees
-	for _, v := range nominees {
-		if v == nominee {
-			return true
-		}
+	addr, err := sdk.AccAddressFromBech32(nominee)
+	if err != nil {
+		return false
 	}
-	return false
+	return k.authority.HasRole(ctx, authority.RoleCollateralAdmin, addr)
+}
+
+// Approve records the nominee's approval of action and returns true once
+// enough collateral admins approved it for it to go ahead.
+func (k Keeper) Approve(ctx sdk.Context, nominee sdk.AccAddress, action sdk.Msg) (bool, sdk.Error) {
+	return k.authority.Approve(ctx, authority.RoleCollateralAdmin, nominee, action)
 }
@@ -12,6 +12,7 @@ import (
 	"github.com/cosmos/cosmos-sdk/x/supply"
 	"github.com/stretchr/testify/require"
 	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/xar-network/xar-network/x/authority"
 	"github.com/xar-network/xar-network/x/csdt/internal/types"
 	"github.com/xar-network/xar-network/x/oracle"
 )
@@ -163,7 +164,6 @@ func TestKeeper_ModifyCSDT(t *testing.T) {
 					},
 				},
 			}
-			oracleParams.Nominees = []string{addrs[1].String()}
 
 			keeper.GetOracle().SetParams(ctx, oracleParams)
 			_, _ = keeper.GetOracle().SetPrice(
@@ -243,7 +243,6 @@ func TestKeeper_PartialSeizeCSDT(t *testing.T) {
 			},
 		},
 	}
-	oracleParams.Nominees = []string{addrs[1].String()}
 
 	keeper.GetOracle().SetParams(ctx, oracleParams)
 	_, _ = keeper.GetOracle().SetPrice(
@@ -281,7 +280,7 @@ func TestKeeper_PartialSeizeCSDT(t *testing.T) {
 func TestKeeper_CollateralParams(t *testing.T) {
 	// Setup
 	const collateral = "uftm"
-	mapp, keeper, _, _ := setUpMockAppWithoutGenesis()
+	mapp, keeper, authorityKeeper, _, _ := setUpMockAppWithAuthority()
 	genAccs, addrs, _, _ := mock.CreateGenAccounts(2, cs(c(collateral, 100)))
 
 	//testAddr := addrs[0]
@@ -291,11 +290,12 @@ func TestKeeper_CollateralParams(t *testing.T) {
 	mapp.BeginBlock(abci.RequestBeginBlock{Header: header})
 	ctx := mapp.BaseApp.NewContext(false, header)
 
-	params := types.DefaultParams()
-	params.Nominees = []string{addrs[1].String()}
+	authorityKeeper.SetRole(ctx, authority.NewRole(authority.RoleCollateralAdmin, 1, []authority.Grant{
+		authority.NewGrant(addrs[1], time.Time{}),
+	}))
 
 	// Create CSDT
-	keeper.SetParams(ctx, params)
+	keeper.SetParams(ctx, types.DefaultParams())
 	keeper.SetGlobalDebt(ctx, sdk.NewInt(0))
 	keeper.GetSupply().SetSupply(ctx, supply.NewSupply(sdk.NewCoins(sdk.NewCoin(collateral, sdk.NewInt(200)))))
 
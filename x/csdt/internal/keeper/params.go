@@ -22,7 +22,7 @@ func (k Keeper) SetParams(ctx sdk.Context, params types.Params) {
 
 func (k Keeper) AddCollateralParam(ctx sdk.Context, nominee string, collateralParam types.CollateralParam) sdk.Error {
 	if !k.IsNominee(ctx, nominee) {
-		return sdk.ErrInternal(fmt.Sprintf("not a nominee: '%s'", nominee))
+		return sdk.ErrUnauthorized(fmt.Sprintf("not a collateral admin: '%s'", nominee))
 	}
 	params := k.GetParams(ctx)
 	if params.IsCollateralPresent(collateralParam.Denom) {
@@ -35,7 +35,7 @@ func (k Keeper) AddCollateralParam(ctx sdk.Context, nominee string, collateralPa
 
 func (k Keeper) SetCollateralParam(ctx sdk.Context, nominee string, collateralParam types.CollateralParam) sdk.Error {
 	if !k.IsNominee(ctx, nominee) {
-		return sdk.ErrInternal(fmt.Sprintf("not a nominee: '%s'", nominee))
+		return sdk.ErrUnauthorized(fmt.Sprintf("not a collateral admin: '%s'", nominee))
 	}
 	params := k.GetParams(ctx)
 	if !params.IsCollateralPresent(collateralParam.Denom) {
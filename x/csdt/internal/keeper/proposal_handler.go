@@ -0,0 +1,38 @@
+package keeper
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/xar-network/xar-network/x/csdt/internal/types"
+)
+
+// HandleCollateralTypeProposal adds or updates a collateral type. CSDTs are
+// priced with the oracle asset named after the collateral denom, so that
+// asset has to exist and be active.
+func HandleCollateralTypeProposal(ctx sdk.Context, k Keeper, p types.CollateralTypeProposal) sdk.Error {
+	cp := p.CollateralParam
+	asset, found := k.oracle.GetAsset(ctx, cp.Denom)
+	if !found {
+		return sdk.ErrUnknownRequest(fmt.Sprintf("no oracle asset for collateral: '%s'", cp.Denom))
+	}
+	if !asset.Active {
+		return sdk.ErrUnknownRequest(fmt.Sprintf("oracle asset is not active: '%s'", cp.Denom))
+	}
+
+	params := k.GetParams(ctx)
+	if params.IsCollateralPresent(cp.Denom) {
+		for i := range params.CollateralParams {
+			if params.CollateralParams[i].Denom == cp.Denom {
+				params.CollateralParams[i] = cp
+			}
+		}
+	} else {
+		params.CollateralParams = append(params.CollateralParams, cp)
+	}
+	if err := params.Validate(); err != nil {
+		return sdk.ErrUnknownRequest(err.Error())
+	}
+	k.SetParams(ctx, params)
+	return nil
+}
@@ -0,0 +1,59 @@
+package keeper_test
+
+import (
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/x/mock"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	"github.com/xar-network/xar-network/x/csdt/internal/keeper"
+	"github.com/xar-network/xar-network/x/csdt/internal/types"
+	"github.com/xar-network/xar-network/x/oracle"
+)
+
+func TestKeeper_CollateralTypeProposal(t *testing.T) {
+	const collateral = "ubtc"
+	mapp, k, _, _ := setUpMockAppWithoutGenesis()
+	genAccs, addrs, _, _ := mock.CreateGenAccounts(1, cs(c(collateral, 100)))
+	mock.SetGenesis(mapp, genAccs)
+	header := abci.Header{Height: mapp.LastBlockHeight() + 1}
+	mapp.BeginBlock(abci.RequestBeginBlock{Header: header})
+	ctx := mapp.BaseApp.NewContext(false, header)
+	k.SetParams(ctx, types.NewParams(cs(c(types.StableDenom, 10000)), types.CollateralParams{}, types.DebtParams{}, false))
+	k.GetOracle().SetParams(ctx, oracle.DefaultParams())
+
+	param := types.CollateralParam{
+		Denom:            collateral,
+		LiquidationRatio: d("2.0"),
+		DebtLimit:        cs(c(types.StableDenom, 1000)),
+	}
+	proposal := types.NewCollateralTypeProposal("title", "description", param)
+	require.NoError(t, proposal.ValidateBasic())
+
+	// the collateral needs an active oracle asset
+	require.Error(t, keeper.HandleCollateralTypeProposal(ctx, k, proposal))
+	asset := oracle.Asset{
+		AssetCode:  collateral,
+		BaseAsset:  collateral,
+		QuoteAsset: types.StableDenom,
+		Oracles:    oracle.Oracles{oracle.Oracle{Address: addrs[0]}},
+	}
+	k.GetOracle().SetParams(ctx, oracle.NewParams(oracle.Assets{asset}))
+	require.Error(t, keeper.HandleCollateralTypeProposal(ctx, k, proposal))
+	asset.Active = true
+	k.GetOracle().SetParams(ctx, oracle.NewParams(oracle.Assets{asset}))
+
+	require.NoError(t, keeper.HandleCollateralTypeProposal(ctx, k, proposal))
+	require.Equal(t, param, k.GetParams(ctx).GetCollateralParam(collateral))
+
+	// existing collateral types are updated
+	param.LiquidationRatio = d("3.0")
+	require.NoError(t, keeper.HandleCollateralTypeProposal(ctx, k, types.NewCollateralTypeProposal("title", "description", param)))
+	require.Equal(t, param, k.GetParams(ctx).GetCollateralParam(collateral))
+	require.Len(t, k.GetParams(ctx).CollateralParams, 1)
+
+	// limits still have to fit into the global debt limit
+	param.DebtLimit = cs(c(types.StableDenom, 20000))
+	require.Error(t, keeper.HandleCollateralTypeProposal(ctx, k, types.NewCollateralTypeProposal("title", "description", param)))
+}
@@ -22,4 +22,5 @@ func RegisterCodec(cdc *codec.Codec) {
 	cdc.RegisterConcrete(MsgTransferCSDT{}, "csdt/MsgTransferCSDT", nil)
 	cdc.RegisterConcrete(MsgAddCollateralParam{}, "csdt/MsgAddCollateralParam", nil)
 	cdc.RegisterConcrete(MsgSetCollateralParam{}, "csdt/MsgSetCollateralParam", nil)
+	cdc.RegisterConcrete(CollateralTypeProposal{}, "csdt/CollateralTypeProposal", nil)
 }
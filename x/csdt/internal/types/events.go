@@ -0,0 +1,16 @@
+package types
+
+// CSDT module event types
+const (
+	EventTypeModifyCSDT = "modify_csdt"
+	EventTypeSeizeCSDT  = "seize_csdt"
+
+	AttributeValueCategory = ModuleName
+
+	AttributeKeyOwner            = "owner"
+	AttributeKeyCollateralDenom  = "collateral_denom"
+	AttributeKeyCollateralChange = "collateral_change"
+	AttributeKeyDebtChange       = "debt_change"
+	AttributeKeyCollateral       = "collateral"
+	AttributeKeyDebt             = "debt"
+)
@@ -17,6 +17,7 @@ type BankKeeper interface {
 
 type OracleKeeper interface {
 	GetCurrentPrice(sdk.Context, string) oracle.CurrentPrice
+	GetAsset(sdk.Context, string) (oracle.Asset, bool)
 	// These are used for testing TODO replace mockApp with keeper in tests to remove these
 	AddAsset(sdk.Context, string, string, oracle.Asset) error
 	SetPrice(sdk.Context, sdk.AccAddress, string, sdk.Dec, time.Time) (oracle.PostedPrice, sdk.Error)
@@ -32,3 +33,8 @@ type SupplyKeeper interface {
 	BurnCoins(ctx sdk.Context, moduleName string, amt sdk.Coins) sdk.Error
 	MintCoins(ctx sdk.Context, moduleName string, amt sdk.Coins) sdk.Error
 }
+
+type AuthorityKeeper interface {
+	HasRole(ctx sdk.Context, role string, addr sdk.AccAddress) bool
+	Approve(ctx sdk.Context, role string, signer sdk.AccAddress, action sdk.Msg) (bool, sdk.Error)
+}
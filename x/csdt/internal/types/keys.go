@@ -1,5 +1,7 @@
 package types
 
+import sdk "github.com/cosmos/cosmos-sdk/types"
+
 const (
 	// ModuleKey is the name of the module
 	ModuleName = "csdt"
@@ -11,6 +13,8 @@ const (
 	QuerierRoute = ModuleName
 	// Parameter store default namestore
 	DefaultParamspace = ModuleName
+	// DefaultCodespace codespace for the module
+	DefaultCodespace sdk.CodespaceType = ModuleName
 
 	// StableDenom asset code of the dollar-denominated debt coin
 	StableDenom = "ucsdt" // TODO allow to be changed
@@ -23,7 +23,6 @@ var (
 	KeyCollateralParams     = []byte("CollateralParams")
 	KeyDebtParams           = []byte("DebtParams")
 	KeyCircuitBreaker       = []byte("CircuitBreaker")
-	KeyNominees             = []byte("Nominees")
 	DefaultGlobalDebt       = sdk.NewCoins(sdk.NewCoin(StableDenom, sdk.NewInt(500000000000)))
 	DefaultCircuitBreaker   = false
 	DefaultCollateralParams = CollateralParams{CollateralParam{
@@ -40,7 +39,6 @@ type Params struct {
 	DebtParams       DebtParams       `json:"debt_params" yaml:"debt_params"`
 	GlobalDebtLimit  sdk.Coins        `json:"global_debt_limit" yaml:"global_debt_limit"`
 	CircuitBreaker   bool             `json:"circuit_breaker" yaml:"circuit_breaker"`
-	Nominees         []string         `json:"nominees" yaml:"nominees"`
 }
 
 func (cps Params) IsCollateralPresent(collateralDenom string) bool {
@@ -70,9 +68,8 @@ func (p Params) String() string {
 	Global Debt Limit: %s
 	Collateral Params: %s
 	Debt Params: %s
-	Nominees: %s
 	Circuit Breaker: %t`,
-		p.GlobalDebtLimit, p.CollateralParams, p.DebtParams, p.Nominees, p.CircuitBreaker,
+		p.GlobalDebtLimit, p.CollateralParams, p.DebtParams, p.CircuitBreaker,
 	)
 }
 
@@ -82,14 +79,12 @@ func NewParams(
 	collateralParams CollateralParams,
 	debtParams DebtParams,
 	breaker bool,
-	nominees []string,
 ) Params {
 	return Params{
 		GlobalDebtLimit:  debtLimit,
 		CollateralParams: collateralParams,
 		DebtParams:       debtParams,
 		CircuitBreaker:   breaker,
-		Nominees:         nominees,
 	}
 }
 
@@ -100,7 +95,6 @@ func DefaultParams() Params {
 		DefaultCollateralParams,
 		DefaultDebtParams,
 		DefaultCircuitBreaker,
-		[]string{},
 	)
 }
 
@@ -171,7 +165,6 @@ func (p *Params) ParamSetPairs() params.ParamSetPairs {
 		{Key: KeyCollateralParams, Value: &p.CollateralParams},
 		{Key: KeyDebtParams, Value: &p.DebtParams},
 		{Key: KeyCircuitBreaker, Value: &p.CircuitBreaker},
-		{Key: KeyNominees, Value: &p.Nominees},
 	}
 }
 
@@ -0,0 +1,72 @@
+package types
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types"
+)
+
+const (
+	// ProposalTypeCollateralType defines the type for a CollateralTypeProposal
+	ProposalTypeCollateralType = "CollateralType"
+)
+
+var _ govtypes.Content = CollateralTypeProposal{}
+
+func init() {
+	govtypes.RegisterProposalType(ProposalTypeCollateralType)
+	govtypes.RegisterProposalTypeCodec(CollateralTypeProposal{}, "csdt/CollateralTypeProposal")
+}
+
+// CollateralTypeProposal adds a collateral type, or replaces the parameters
+// of an existing one with the same denom.
+type CollateralTypeProposal struct {
+	Title           string          `json:"title" yaml:"title"`
+	Description     string          `json:"description" yaml:"description"`
+	CollateralParam CollateralParam `json:"collateral_param" yaml:"collateral_param"`
+}
+
+// NewCollateralTypeProposal creates a new collateral type proposal.
+func NewCollateralTypeProposal(title, description string, param CollateralParam) CollateralTypeProposal {
+	return CollateralTypeProposal{title, description, param}
+}
+
+// GetTitle returns the title of a collateral type proposal.
+func (p CollateralTypeProposal) GetTitle() string { return p.Title }
+
+// GetDescription returns the description of a collateral type proposal.
+func (p CollateralTypeProposal) GetDescription() string { return p.Description }
+
+// ProposalRoute returns the routing key of a collateral type proposal.
+func (p CollateralTypeProposal) ProposalRoute() string { return RouterKey }
+
+// ProposalType returns the type of a collateral type proposal.
+func (p CollateralTypeProposal) ProposalType() string { return ProposalTypeCollateralType }
+
+// ValidateBasic runs basic stateless validity checks
+func (p CollateralTypeProposal) ValidateBasic() sdk.Error {
+	if err := govtypes.ValidateAbstract(DefaultCodespace, p); err != nil {
+		return err
+	}
+	cp := p.CollateralParam
+	if len(cp.Denom) == 0 {
+		return sdk.ErrInternal("invalid (empty) collateral denom")
+	}
+	if cp.LiquidationRatio.IsNil() || !cp.LiquidationRatio.IsPositive() {
+		return sdk.ErrInternal("invalid (empty) liquidation ratio")
+	}
+	if !cp.DebtLimit.IsValid() || cp.DebtLimit.IsAnyNegative() {
+		return sdk.ErrInternal("invalid (empty) debt limit")
+	}
+	return nil
+}
+
+// String implements the Stringer interface.
+func (p CollateralTypeProposal) String() string {
+	return fmt.Sprintf(`Collateral Type Proposal:
+  Title:       %s
+  Description: %s
+  %s
+`, p.Title, p.Description, p.CollateralParam)
+}
@@ -6,10 +6,9 @@ import (
 )
 
 const (
-	ModuleName        = types.ModuleName
-	StoreKey          = types.StoreKey
-	DefaultParamspace = types.DefaultParamspace
-	DefaultCodespace  = types.DefaultCodespace
+	ModuleName       = types.ModuleName
+	StoreKey         = types.StoreKey
+	DefaultCodespace = types.DefaultCodespace
 )
 
 var (
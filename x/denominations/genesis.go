@@ -9,7 +9,6 @@ import (
 
 type GenesisState struct {
 	TokenRecords []types.Token `json:"token_records"`
-	Nominees     []string      `json:"nominees" yaml:"nominees"`
 }
 
 func ValidateGenesis(data GenesisState) error {
@@ -30,20 +29,17 @@ func ValidateGenesis(data GenesisState) error {
 	return nil
 }
 
-func NewGenesisState(nominees []string) GenesisState {
-	return GenesisState{TokenRecords: nil, Nominees: nominees}
+func NewGenesisState(records []types.Token) GenesisState {
+	return GenesisState{TokenRecords: records}
 }
 
 func DefaultGenesisState() GenesisState {
 	return GenesisState{
 		TokenRecords: []types.Token{},
-		Nominees:     []string{},
 	}
 }
 
 func InitGenesis(ctx sdk.Context, k Keeper, data GenesisState) {
-	k.SetParams(ctx, types.NewParams(data.Nominees))
-
 	for _, record := range data.TokenRecords {
 		record := record
 		err := k.SetToken(ctx, record.Owner, record.Symbol, &record)
@@ -65,6 +61,5 @@ func ExportGenesis(ctx sdk.Context, k Keeper) GenesisState {
 		}
 		records = append(records, *token)
 	}
-	params := k.GetParams(ctx)
-	return GenesisState{TokenRecords: records, Nominees: params.Nominees}
+	return GenesisState{TokenRecords: records}
 }
@@ -43,6 +43,15 @@ func handleMsgIssueToken(ctx sdk.Context, k Keeper, msg types.MsgIssueToken) sdk
 		msg.Mintable,
 	)
 
+	action := msg
+	action.SourceAddress = nil
+	if ok, err := k.Approve(ctx, msg.SourceAddress, action); !ok {
+		if err != nil {
+			return err.Result()
+		}
+		return sdk.Result{Events: ctx.EventManager().Events()}
+	}
+
 	return k.IssueToken(ctx, msg.SourceAddress, msg.Owner, *token)
 }
 
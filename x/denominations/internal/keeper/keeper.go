@@ -6,23 +6,23 @@ import (
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/x/auth"
-	"github.com/cosmos/cosmos-sdk/x/params/subspace"
 	"github.com/cosmos/cosmos-sdk/x/supply"
 
 	"github.com/cosmos/cosmos-sdk/codec"
 	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	"github.com/xar-network/xar-network/x/authority"
 	"github.com/xar-network/xar-network/x/denominations/internal/types"
 )
 
 // Keeper maintains the link to data storage and exposes getter/setter
 // methods for the various parts of the state machine
 type Keeper struct {
-	storeKey      sdk.StoreKey
-	cdc           *codec.Codec
-	ak            auth.AccountKeeper
-	sk            supply.Keeper
-	paramSubspace subspace.Subspace
-	codespace     sdk.CodespaceType
+	storeKey  sdk.StoreKey
+	cdc       *codec.Codec
+	ak        auth.AccountKeeper
+	sk        supply.Keeper
+	authority types.AuthorityKeeper
+	codespace sdk.CodespaceType
 }
 
 // NewKeeper creates new instances of the assetmanagement Keeper
@@ -31,16 +31,16 @@ func NewKeeper(
 	cdc *codec.Codec,
 	ak auth.AccountKeeper,
 	sk supply.Keeper,
-	paramstore subspace.Subspace,
+	authority types.AuthorityKeeper,
 	codespace sdk.CodespaceType,
 ) Keeper {
 	return Keeper{
-		storeKey:      storeKey,
-		cdc:           cdc,
-		ak:            ak,
-		sk:            sk,
-		paramSubspace: paramstore.WithKeyTable(types.ParamKeyTable()),
-		codespace:     codespace,
+		storeKey:  storeKey,
+		cdc:       cdc,
+		ak:        ak,
+		sk:        sk,
+		authority: authority,
+		codespace: codespace,
 	}
 }
 
@@ -140,7 +140,7 @@ func (k Keeper) IsSymbolPresent(ctx sdk.Context, symbol string) bool {
 
 func (k Keeper) IssueToken(ctx sdk.Context, nominee, owner sdk.AccAddress, token types.Token) sdk.Result {
 	if !k.IsNominee(ctx, nominee.String()) {
-		return sdk.ErrUnknownAddress(fmt.Sprintf("account is not a nominee %s", nominee.String())).Result()
+		return sdk.ErrUnauthorized(fmt.Sprintf("account is not a token issuer %s", nominee.String())).Result()
 	}
 
 	if k.IsSymbolPresent(ctx, token.Symbol) {
@@ -413,13 +413,17 @@ func (k Keeper) UnfreezeCoins(ctx sdk.Context, from sdk.AccAddress, address sdk.
 	return sdk.Result{Events: ctx.EventManager().Events()}
 }
 
+// IsNominee returns true if nominee is an active token issuer.
 func (k Keeper) IsNominee(ctx sdk.Context, nominee string) bool {
-	params := k.GetParams(ctx)
-	nominees := params.Nominees
-	for _, v := range nominees {
-		if v == nominee {
-			return true
-		}
+	addr, err := sdk.AccAddressFromBech32(nominee)
+	if err != nil {
+		return false
 	}
-	return false
+	return k.authority.HasRole(ctx, authority.RoleTokenIssuer, addr)
+}
+
+// Approve records the nominee's approval of action and returns true once
+// enough token issuers approved it for it to go ahead.
+func (k Keeper) Approve(ctx sdk.Context, nominee sdk.AccAddress, action sdk.Msg) (bool, sdk.Error) {
+	return k.authority.Approve(ctx, authority.RoleTokenIssuer, nominee, action)
 }
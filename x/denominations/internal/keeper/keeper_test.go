@@ -2,6 +2,7 @@ package keeper_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/cosmos/cosmos-sdk/codec"
 	sdk "github.com/cosmos/cosmos-sdk/types"
@@ -9,12 +10,14 @@ import (
 	"github.com/cosmos/cosmos-sdk/x/bank"
 	"github.com/cosmos/cosmos-sdk/x/params"
 	"github.com/cosmos/cosmos-sdk/x/supply"
+	"github.com/xar-network/xar-network/x/authority"
 	"github.com/xar-network/xar-network/x/denominations/internal/keeper"
 	"github.com/xar-network/xar-network/x/denominations/internal/types"
 
 	cstore "github.com/cosmos/cosmos-sdk/store"
 	"github.com/stretchr/testify/require"
 	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto"
 	"github.com/tendermint/tendermint/libs/log"
 	dbm "github.com/tendermint/tm-db"
 )
@@ -30,6 +33,7 @@ func TestKeeperCoverage(t *testing.T) {
 		keyAcc     = sdk.NewKVStoreKey(auth.StoreKey)
 		keySupply  = sdk.NewKVStoreKey(supply.StoreKey)
 		keyDenom   = sdk.NewKVStoreKey(types.StoreKey)
+		keyAuth    = sdk.NewKVStoreKey(authority.StoreKey)
 		tkeyParams = sdk.NewTransientStoreKey(params.TStoreKey)
 	)
 
@@ -39,6 +43,7 @@ func TestKeeperCoverage(t *testing.T) {
 	ms.MountStoreWithDB(keyAcc, sdk.StoreTypeIAVL, db)
 	ms.MountStoreWithDB(keySupply, sdk.StoreTypeIAVL, db)
 	ms.MountStoreWithDB(keyDenom, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(keyAuth, sdk.StoreTypeIAVL, db)
 	ms.MountStoreWithDB(tkeyParams, sdk.StoreTypeTransient, db)
 
 	err := ms.LoadLatestVersion()
@@ -58,7 +63,7 @@ func TestKeeperCoverage(t *testing.T) {
 	acc := ak.NewAccountWithAddress(ctx, addr)
 	ak.SetAccount(ctx, acc)
 
-	naddr := sdk.AccAddress([]byte("nominee"))
+	naddr := sdk.AccAddress(crypto.AddressHash([]byte("nominee")))
 	nacc := ak.NewAccountWithAddress(ctx, naddr)
 	ak.SetAccount(ctx, nacc)
 
@@ -66,9 +71,11 @@ func TestKeeperCoverage(t *testing.T) {
 	acc = ak.NewAccountWithAddress(ctx, addrerr)
 	ak.SetAccount(ctx, acc)
 
-	dk := keeper.NewKeeper(keyDenom, cdc, ak, sk, pk.Subspace(types.DefaultParamspace), types.DefaultCodespace)
-	params := types.NewParams([]string{"cosmos1dehk66twv4js5dq8xr"})
-	dk.SetParams(ctx, params)
+	auk := authority.NewKeeper(keyAuth, cdc, authority.DefaultCodespace)
+	auk.SetRole(ctx, authority.NewRole(authority.RoleTokenIssuer, 1, []authority.Grant{
+		authority.NewGrant(naddr, time.Time{}),
+	}))
+	dk := keeper.NewKeeper(keyDenom, cdc, ak, sk, auk, types.DefaultCodespace)
 
 	sk.SetSupply(ctx, supply.NewSupply(sdk.Coins{}))
 
@@ -0,0 +1,11 @@
+package types
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// AuthorityKeeper defines the expected authority keeper
+type AuthorityKeeper interface {
+	HasRole(ctx sdk.Context, role string, addr sdk.AccAddress) bool
+	Approve(ctx sdk.Context, role string, signer sdk.AccAddress, action sdk.Msg) (bool, sdk.Error)
+}
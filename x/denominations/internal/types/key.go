@@ -5,13 +5,9 @@ const (
 
 	QuerierRoute = ModuleName
 
-	ModuleNominees = "nominees"
 	// StoreKey Top level store key where all module items will be stored
 	StoreKey = ModuleName
 
 	// RouterKey Top level router key
 	RouterKey = ModuleName
-
-	// DefaultParamspace default name for parameter store
-	DefaultParamspace = ModuleName
 )
@@ -21,6 +21,16 @@ const (
 	QuerierRoute      = types.QuerierRoute
 	DefaultParamspace = types.DefaultParamspace
 	StoreKey          = types.StoreKey
+
+	EventTypeSeizeCollateral    = types.EventTypeSeizeCollateral
+	EventTypeStartDebtAuction   = types.EventTypeStartDebtAuction
+	EventTypeSettleDebt         = types.EventTypeSettleDebt
+	AttributeValueCategory      = types.AttributeValueCategory
+	AttributeKeyOwner           = types.AttributeKeyOwner
+	AttributeKeyCollateralDenom = types.AttributeKeyCollateralDenom
+	AttributeKeyCollateral      = types.AttributeKeyCollateral
+	AttributeKeyDebt            = types.AttributeKeyDebt
+	AttributeKeyAuctionID       = types.AttributeKeyAuctionID
 )
 
 var (
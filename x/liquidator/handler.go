@@ -10,6 +10,7 @@ import (
 	"fmt"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/xar-network/xar-network/types/errs"
 	"github.com/xar-network/xar-network/x/liquidator/internal/keeper"
 	"github.com/xar-network/xar-network/x/liquidator/internal/types"
 )
@@ -21,7 +22,7 @@ func NewHandler(keeper keeper.Keeper) sdk.Handler {
 		case types.MsgSeizeAndStartCollateralAuction:
 			return handleMsgSeizeAndStartCollateralAuction(ctx, keeper, msg)
 		case types.MsgStartDebtAuction:
-			return handleMsgStartDebtAuction(ctx, keeper)
+			return handleMsgStartDebtAuction(ctx, keeper, msg)
 		// case MsgStartSurplusAuction:
 		// 	return handleMsgStartSurplusAuction(ctx, keeper)
 		default:
@@ -36,10 +37,10 @@ func handleMsgSeizeAndStartCollateralAuction(ctx sdk.Context, keeper keeper.Keep
 	if err != nil {
 		return err.Result()
 	}
-	return sdk.Result{} // TODO tags, return auction ID
+	return errs.MessageResult(ctx, types.AttributeValueCategory, msg.Sender, "")
 }
 
-func handleMsgStartDebtAuction(ctx sdk.Context, keeper keeper.Keeper) sdk.Result {
+func handleMsgStartDebtAuction(ctx sdk.Context, keeper keeper.Keeper, msg types.MsgStartDebtAuction) sdk.Result {
 	// cancel out any debt and stable coins before trying to start auction
 	keeper.SettleDebt(ctx)
 	// start an auction
@@ -47,7 +48,7 @@ func handleMsgStartDebtAuction(ctx sdk.Context, keeper keeper.Keeper) sdk.Result
 	if err != nil {
 		return err.Result()
 	}
-	return sdk.Result{} // TODO tags, return auction ID
+	return errs.MessageResult(ctx, types.AttributeValueCategory, msg.Sender, "")
 }
 
 // With no stability and liquidation fees, surplus auctions can never be run.
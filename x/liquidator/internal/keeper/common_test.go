@@ -16,6 +16,7 @@ import (
 	"github.com/xar-network/xar-network/x/csdt"
 
 	"github.com/xar-network/xar-network/x/auction"
+	"github.com/xar-network/xar-network/x/authority"
 	"github.com/xar-network/xar-network/x/liquidator/internal/keeper"
 	"github.com/xar-network/xar-network/x/liquidator/internal/types"
 	"github.com/xar-network/xar-network/x/oracle"
@@ -44,6 +45,7 @@ func setupTestKeepers() (sdk.Context, keepers) {
 	tkeyParams := sdk.NewTransientStoreKey(params.TStoreKey)
 	keyAcc := sdk.NewKVStoreKey(auth.StoreKey)
 	keyPriceFeed := sdk.NewKVStoreKey(oracle.StoreKey)
+	keyAuthority := sdk.NewKVStoreKey(authority.StoreKey)
 	keyCSDT := sdk.NewKVStoreKey(csdt.StoreKey)
 	keyAuction := sdk.NewKVStoreKey(auction.StoreKey)
 	keyLiquidator := sdk.NewKVStoreKey(types.StoreKey)
@@ -55,6 +57,7 @@ func setupTestKeepers() (sdk.Context, keepers) {
 	ms.MountStoreWithDB(tkeyParams, sdk.StoreTypeTransient, db)
 	ms.MountStoreWithDB(keyAcc, sdk.StoreTypeIAVL, db)
 	ms.MountStoreWithDB(keyPriceFeed, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(keyAuthority, sdk.StoreTypeIAVL, db)
 	ms.MountStoreWithDB(keyCSDT, sdk.StoreTypeIAVL, db)
 	ms.MountStoreWithDB(keyAuction, sdk.StoreTypeIAVL, db)
 	ms.MountStoreWithDB(keyLiquidator, sdk.StoreTypeIAVL, db)
@@ -90,7 +93,8 @@ func setupTestKeepers() (sdk.Context, keepers) {
 	}
 
 	supplyKeeper := supply.NewKeeper(cdc, keySupply, accountKeeper, bankKeeper, maccPerms)
-	oracleKeeper := oracle.NewKeeper(keyPriceFeed, cdc, paramsKeeper.Subspace(oracle.DefaultParamspace), oracle.DefaultCodespace)
+	authorityKeeper := authority.NewKeeper(keyAuthority, cdc, authority.DefaultCodespace)
+	oracleKeeper := oracle.NewKeeper(keyPriceFeed, cdc, paramsKeeper.Subspace(oracle.DefaultParamspace), authorityKeeper, oracle.DefaultCodespace)
 	auctionKeeper := auction.NewKeeper(cdc, supplyKeeper, keyAuction, paramsKeeper.Subspace(auction.DefaultParamspace)) // Note: csdt keeper stands in for bank keeper
 	csdtKeeper := csdt.NewKeeper(
 		cdc,
@@ -99,6 +103,7 @@ func setupTestKeepers() (sdk.Context, keepers) {
 		oracleKeeper,
 		bankKeeper,
 		supplyKeeper,
+		authorityKeeper,
 	)
 	liquidatorKeeper := keeper.NewKeeper(
 		cdc,
@@ -180,7 +185,6 @@ func oracleGenesis(address sdk.AccAddress) oracle.GenesisState {
 		Assets: []oracle.Asset{
 			oracle.Asset{AssetCode: "btc", BaseAsset: "btc", QuoteAsset: "usd"},
 		},
-		Nominees: []string{address.String()},
 	}
 	return oracle.GenesisState{
 		Params: ap,
@@ -1,6 +1,8 @@
 package keeper
 
 import (
+	"fmt"
+
 	"github.com/cosmos/cosmos-sdk/codec"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/x/params"
@@ -83,6 +85,16 @@ func (k Keeper) SeizeAndStartCollateralAuction(ctx sdk.Context, owner sdk.AccAdd
 	if err != nil {
 		panic(err) // TODO how can errors here be handled to be safe with the state update in PartialSeizeCSDT?
 	}
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeSeizeCollateral,
+			sdk.NewAttribute(types.AttributeKeyOwner, owner.String()),
+			sdk.NewAttribute(types.AttributeKeyCollateralDenom, collateralDenom),
+			sdk.NewAttribute(types.AttributeKeyCollateral, collateralToSell.String()),
+			sdk.NewAttribute(types.AttributeKeyDebt, stableToRaise.String()),
+			sdk.NewAttribute(types.AttributeKeyAuctionID, fmt.Sprintf("%d", auctionID)),
+		),
+	)
 	return auctionID, nil
 }
 
@@ -119,6 +131,13 @@ func (k Keeper) StartDebtAuction(ctx sdk.Context) (auction.ID, sdk.Error) {
 	// Record amount of debt sent for auction. Debt can only be reduced in lock step with reducing stable coin
 	seizedDebt.SentToAuction = seizedDebt.SentToAuction.Add(params.DebtAuctionSize)
 	k.SetSeizedDebt(ctx, seizedDebt)
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeStartDebtAuction,
+			sdk.NewAttribute(types.AttributeKeyDebt, params.DebtAuctionSize.String()),
+			sdk.NewAttribute(types.AttributeKeyAuctionID, fmt.Sprintf("%d", auctionID)),
+		),
+	)
 	return auctionID, nil
 }
 
@@ -199,6 +218,12 @@ func (k Keeper) SettleDebt(ctx sdk.Context) sdk.Error {
 	if err != nil {
 		return err // this should not error in this context
 	}
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeSettleDebt,
+			sdk.NewAttribute(types.AttributeKeyDebt, settleAmount.String()),
+		),
+	)
 	return nil
 }
 
@@ -0,0 +1,16 @@
+package types
+
+// Liquidator module event types
+const (
+	EventTypeSeizeCollateral  = "seize_collateral"
+	EventTypeStartDebtAuction = "start_debt_auction"
+	EventTypeSettleDebt       = "settle_debt"
+
+	AttributeValueCategory = ModuleName
+
+	AttributeKeyOwner           = "owner"
+	AttributeKeyCollateralDenom = "collateral_denom"
+	AttributeKeyCollateral      = "collateral"
+	AttributeKeyDebt            = "debt"
+	AttributeKeyAuctionID       = "auction_id"
+)
@@ -4,13 +4,45 @@ import (
 	"github.com/xar-network/xar-network/x/market/types"
 )
 
+type (
+	Market         = types.Market
+	MarketParams   = types.MarketParams
+	FeeSchedule    = types.FeeSchedule
+	TradingRules   = types.TradingRules
+	PriceBand      = types.PriceBand
+	CircuitBreaker = types.CircuitBreaker
+	MarketStatus   = types.MarketStatus
+	MarketHooks    = types.MarketHooks
+
+	MsgCreateMarket       = types.MsgCreateMarket
+	MsgSetMarketStatus    = types.MsgSetMarketStatus
+	MsgUpdateMarketParams = types.MsgUpdateMarketParams
+	ListMarketProposal    = types.ListMarketProposal
+)
+
 const (
-	ModuleName        = types.ModuleName
-	StoreKey          = types.StoreKey
-	DefaultParamspace = types.DefaultParamspace
-	DefaultCodespace  = types.DefaultCodespace
+	ModuleName       = types.ModuleName
+	RouterKey        = types.RouterKey
+	StoreKey         = types.StoreKey
+	DefaultCodespace = types.DefaultCodespace
+
+	MatchingModeBatch      = types.MatchingModeBatch
+	MatchingModeContinuous = types.MatchingModeContinuous
+
+	MarketActive     = types.MarketActive
+	MarketHalted     = types.MarketHalted
+	MarketCancelOnly = types.MarketCancelOnly
+	MarketDelisted   = types.MarketDelisted
 )
 
 var (
 	ModuleCdc = types.ModuleCdc
+
+	NewFeeSchedule  = types.NewFeeSchedule
+	NewTradingRules = types.NewTradingRules
+	NewPriceBand    = types.NewPriceBand
+	FeeAmount       = types.FeeAmount
+
+	MarketStatusFromString = types.MarketStatusFromString
+	NewListMarketProposal  = types.NewListMarketProposal
 )
@@ -14,14 +14,20 @@ func GetQueryCmd(sk string, cdc *codec.Codec) *cobra.Command {
 	}
 	marketQueryCmd.AddCommand(client.GetCommands(
 		GetCmdListMarkets(sk, cdc),
+		GetCmdHalt(sk, cdc),
 	)...)
 	return marketQueryCmd
 }
 
-func GetTxCmd() *cobra.Command {
+func GetTxCmd(cdc *codec.Codec) *cobra.Command {
 	marketTxCmd := &cobra.Command{
 		Use:   "market",
 		Short: "manages available markets",
 	}
+	marketTxCmd.AddCommand(client.PostCommands(
+		GetCmdCreateMarket(cdc),
+		GetCmdSetMarketStatus(cdc),
+		GetCmdUpdateMarketParams(cdc),
+	)...)
 	return marketTxCmd
 }
@@ -28,3 +28,22 @@ func GetCmdListMarkets(queryRoute string, cdc *codec.Codec) *cobra.Command {
 		},
 	}
 }
+
+func GetCmdHalt(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "halt [market-id]",
+		Short: "shows whether the price band circuit breaker halted a market",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.NewCLIContext().WithCodec(cdc)
+			res, _, err := ctx.QueryWithData(fmt.Sprintf("custom/%s/halt/%s", queryRoute, args[0]), nil)
+			if err != nil {
+				return err
+			}
+
+			var out types.HaltQueryResult
+			cdc.MustUnmarshalJSON(res, &out)
+			return ctx.PrintOutput(out)
+		},
+	}
+}
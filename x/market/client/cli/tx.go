@@ -0,0 +1,227 @@
+package cli
+
+import (
+	"bufio"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth/client/utils"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	govcli "github.com/cosmos/cosmos-sdk/x/gov/client/cli"
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types"
+
+	"github.com/xar-network/xar-network/pkg/cliutil"
+	"github.com/xar-network/xar-network/types/store"
+	"github.com/xar-network/xar-network/x/market/types"
+)
+
+const (
+	flagMakerFeeBps     = "maker-fee-bps"
+	flagTakerFeeBps     = "taker-fee-bps"
+	flagFeeDenom        = "fee-denom"
+	flagFeeRecipient    = "fee-recipient"
+	flagTickSize        = "tick-size"
+	flagLotSize         = "lot-size"
+	flagMinNotional     = "min-notional"
+	flagBaseDecimals    = "base-decimals"
+	flagQuoteDecimals   = "quote-decimals"
+	flagMaxDeviationBps = "max-deviation-bps"
+	flagMaxBreaches     = "max-breaches"
+	flagHaltBlocks      = "halt-blocks"
+)
+
+var (
+	feeFlags   = []string{flagMakerFeeBps, flagTakerFeeBps, flagFeeDenom, flagFeeRecipient}
+	rulesFlags = []string{flagTickSize, flagLotSize, flagMinNotional, flagBaseDecimals, flagQuoteDecimals}
+	bandFlags  = []string{flagMaxDeviationBps, flagMaxBreaches, flagHaltBlocks}
+)
+
+func GetCmdCreateMarket(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create [base-asset] [quote-asset] [matching-mode]",
+		Short: "creates a market between two assets in supply",
+		Long: `Creates a market trading the base asset against the quote asset. The matching
+mode is BATCH or CONTINUOUS. Fees, trading rules and the price band default to
+none. Only market admins can create markets.`,
+		Args: cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			inBuf := bufio.NewReader(cmd.InOrStdin())
+			cliCtx := client.NewCLIContext().WithCodec(cdc)
+			bldr := authtypes.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
+
+			mode, err := types.MatchingModeFromString(strings.ToUpper(args[2]))
+			if err != nil {
+				return err
+			}
+
+			msg := types.NewMsgCreateMarket(cliCtx.GetFromAddress(), args[0], args[1], mode)
+			msg.Fees = feesFromFlags()
+			msg.Rules = rulesFromFlags()
+			msg.Band = bandFromFlags()
+			return cliutil.ValidateAndBroadcast(cliCtx, bldr, msg)
+		},
+	}
+	addParamFlags(cmd.Flags())
+	return cmd
+}
+
+func GetCmdSetMarketStatus(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "set-status [market-id] [status]",
+		Short: "halts, resumes or delists a market",
+		Long: `Sets the status of a market to ACTIVE, HALTED, CANCEL_ONLY or DELISTED.
+Halted markets are frozen, cancel-only markets only accept cancellations and
+delisting a market refunds all of its orders. Delisting is final. Only
+market admins can change a market's status.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			inBuf := bufio.NewReader(cmd.InOrStdin())
+			cliCtx := client.NewCLIContext().WithCodec(cdc)
+			bldr := authtypes.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
+
+			status, err := types.MarketStatusFromString(strings.ToUpper(args[1]))
+			if err != nil {
+				return err
+			}
+
+			msg := types.NewMsgSetMarketStatus(cliCtx.GetFromAddress(), store.NewEntityIDFromString(args[0]), status)
+			return cliutil.ValidateAndBroadcast(cliCtx, bldr, msg)
+		},
+	}
+}
+
+func GetCmdUpdateMarketParams(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "update-params [market-id]",
+		Short: "updates the fees, trading rules or price band of a market",
+		Long: `Updates the parameters of a market. Fees, trading rules and the price band are
+each replaced as a whole if any of their flags is set, and left unchanged
+otherwise. The assets' decimals cannot change. Only market admins can
+update a market's parameters.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			inBuf := bufio.NewReader(cmd.InOrStdin())
+			cliCtx := client.NewCLIContext().WithCodec(cdc)
+			bldr := authtypes.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
+
+			msg := types.NewMsgUpdateMarketParams(cliCtx.GetFromAddress(), store.NewEntityIDFromString(args[0]))
+			if anyChanged(cmd.Flags(), feeFlags) {
+				fees := feesFromFlags()
+				msg.Fees = &fees
+			}
+			if anyChanged(cmd.Flags(), rulesFlags) {
+				rules := rulesFromFlags()
+				msg.Rules = &rules
+			}
+			if anyChanged(cmd.Flags(), bandFlags) {
+				band := bandFromFlags()
+				msg.Band = &band
+			}
+			return cliutil.ValidateAndBroadcast(cliCtx, bldr, msg)
+		},
+	}
+	addParamFlags(cmd.Flags())
+	return cmd
+}
+
+// GetCmdSubmitListMarketProposal is registered as a gov submit-proposal
+// subcommand.
+func GetCmdSubmitListMarketProposal(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list-market [base-asset] [quote-asset] [matching-mode]",
+		Short: "submits a proposal to list a market",
+		Long: `Submits a governance proposal to create a market trading the base asset
+against the quote asset, along with an initial deposit. Fees, trading rules
+and the price band are set with the same flags as for market create.`,
+		Args: cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			inBuf := bufio.NewReader(cmd.InOrStdin())
+			cliCtx := client.NewCLIContext().WithCodec(cdc)
+			bldr := authtypes.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
+
+			mode, err := types.MatchingModeFromString(strings.ToUpper(args[2]))
+			if err != nil {
+				return err
+			}
+			deposit, err := sdk.ParseCoins(viper.GetString(govcli.FlagDeposit))
+			if err != nil {
+				return err
+			}
+
+			content := types.NewListMarketProposal(
+				viper.GetString(govcli.FlagTitle),
+				viper.GetString(govcli.FlagDescription),
+				args[0], args[1], mode,
+				feesFromFlags(), rulesFromFlags(), bandFromFlags(),
+			)
+			msg := govtypes.NewMsgSubmitProposal(content, deposit, cliCtx.GetFromAddress())
+			return cliutil.ValidateAndBroadcast(cliCtx, bldr, msg)
+		},
+	}
+	cmd.Flags().String(govcli.FlagTitle, "", "title of proposal")
+	cmd.Flags().String(govcli.FlagDescription, "", "description of proposal")
+	cmd.Flags().String(govcli.FlagDeposit, "", "deposit of proposal")
+	addParamFlags(cmd.Flags())
+	return cmd
+}
+
+func addParamFlags(flags *pflag.FlagSet) {
+	flags.Uint16(flagMakerFeeBps, 0, "maker fee in basis points")
+	flags.Uint16(flagTakerFeeBps, 0, "taker fee in basis points")
+	flags.String(flagFeeDenom, "", "asset fees are collected in, defaults to the received asset")
+	flags.String(flagFeeRecipient, "", "module account collecting fees, defaults to the fee collector")
+	flags.Uint64(flagTickSize, 0, "price increment orders must be a multiple of")
+	flags.Uint64(flagLotSize, 0, "quantity increment orders must be a multiple of")
+	flags.Uint64(flagMinNotional, 0, "minimum value of an order in the quote asset")
+	flags.Uint8(flagBaseDecimals, 0, "decimals of the base asset")
+	flags.Uint8(flagQuoteDecimals, 0, "decimals of the quote asset")
+	flags.Uint32(flagMaxDeviationBps, 0, "maximum deviation of a batch's clearing price in basis points")
+	flags.Uint16(flagMaxBreaches, 0, "consecutive price band breaches that halt the market")
+	flags.Int64(flagHaltBlocks, 0, "blocks the market is halted for after repeated breaches")
+}
+
+func anyChanged(flags *pflag.FlagSet, names []string) bool {
+	for _, name := range names {
+		if flags.Changed(name) {
+			return true
+		}
+	}
+	return false
+}
+
+func feesFromFlags() types.FeeSchedule {
+	return types.NewFeeSchedule(
+		uint16(viper.GetUint(flagMakerFeeBps)),
+		uint16(viper.GetUint(flagTakerFeeBps)),
+		viper.GetString(flagFeeDenom),
+		viper.GetString(flagFeeRecipient),
+	)
+}
+
+func rulesFromFlags() types.TradingRules {
+	return types.NewTradingRules(
+		viper.GetUint64(flagTickSize),
+		viper.GetUint64(flagLotSize),
+		viper.GetUint64(flagMinNotional),
+		uint8(viper.GetUint(flagBaseDecimals)),
+		uint8(viper.GetUint(flagQuoteDecimals)),
+	)
+}
+
+func bandFromFlags() types.PriceBand {
+	return types.NewPriceBand(
+		viper.GetUint32(flagMaxDeviationBps),
+		uint16(viper.GetUint(flagMaxBreaches)),
+		viper.GetInt64(flagHaltBlocks),
+	)
+}
@@ -0,0 +1,11 @@
+package client
+
+import (
+	govclient "github.com/cosmos/cosmos-sdk/x/gov/client"
+
+	"github.com/xar-network/xar-network/x/market/client/cli"
+	"github.com/xar-network/xar-network/x/market/client/rest"
+)
+
+// ListMarketProposalHandler lets governance list markets.
+var ListMarketProposalHandler = govclient.NewProposalHandler(cli.GetCmdSubmitListMarketProposal, rest.ProposalRESTHandler)
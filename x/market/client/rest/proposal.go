@@ -0,0 +1,60 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/rest"
+	"github.com/cosmos/cosmos-sdk/x/auth/client/utils"
+	govrest "github.com/cosmos/cosmos-sdk/x/gov/client/rest"
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types"
+
+	"github.com/xar-network/xar-network/x/market/types"
+)
+
+type listMarketProposalReq struct {
+	BaseReq      rest.BaseReq       `json:"base_req"`
+	Title        string             `json:"title"`
+	Description  string             `json:"description"`
+	BaseAsset    string             `json:"base_asset"`
+	QuoteAsset   string             `json:"quote_asset"`
+	MatchingMode types.MatchingMode `json:"matching_mode"`
+	Fees         types.FeeSchedule  `json:"fees"`
+	Rules        types.TradingRules `json:"rules"`
+	Band         types.PriceBand    `json:"price_band"`
+	Proposer     sdk.AccAddress     `json:"proposer"`
+	Deposit      sdk.Coins          `json:"deposit"`
+}
+
+// ProposalRESTHandler exposes the list market proposal REST handler.
+func ProposalRESTHandler(cliCtx context.CLIContext) govrest.ProposalRESTHandler {
+	return govrest.ProposalRESTHandler{
+		SubRoute: "list_market",
+		Handler:  listMarketProposalHandler(cliCtx),
+	}
+}
+
+func listMarketProposalHandler(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req listMarketProposalReq
+		if !rest.ReadRESTReq(w, r, cliCtx.Codec, &req) {
+			return
+		}
+
+		req.BaseReq = req.BaseReq.Sanitize()
+		if !req.BaseReq.ValidateBasic(w) {
+			return
+		}
+
+		content := types.NewListMarketProposal(req.Title, req.Description, req.BaseAsset, req.QuoteAsset, req.MatchingMode, req.Fees, req.Rules, req.Band)
+		msg := govtypes.NewMsgSubmitProposal(content, req.Deposit, req.Proposer)
+		if err := msg.ValidateBasic(); err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		utils.WriteGenerateStdTxResponse(w, cliCtx, req.BaseReq, []sdk.Msg{msg})
+	}
+}
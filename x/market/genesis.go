@@ -10,12 +10,11 @@ import (
 )
 
 type GenesisState struct {
-	Markets  types.Markets `json:"markets" yaml:"markets"`
-	Nominees []string      `json:"nominees" yaml:"nominees"`
+	Markets types.Markets `json:"markets" yaml:"markets"`
 }
 
-func NewGenesisState(markets types.Markets, nominees []string) GenesisState {
-	return GenesisState{Markets: markets, Nominees: nominees}
+func NewGenesisState(markets types.Markets) GenesisState {
+	return GenesisState{Markets: markets}
 }
 
 func ValidateGenesis(data GenesisState) error {
@@ -31,6 +30,9 @@ func ValidateGenesis(data GenesisState) error {
 		if market.QuoteAssetDenom == "" {
 			return errors.New("Invalid Market: Must specify a non-zero quote asset denom.")
 		}
+		if !market.Status.IsValid() {
+			return errors.New("Invalid Market: Must specify a valid status.")
+		}
 	}
 
 	return nil
@@ -60,15 +62,20 @@ func DefaultGenesisState() GenesisState {
 				QuoteAssetDenom: "uzar",
 			},
 		},
-		Nominees: []string{},
 	}
 }
 
 func InitGenesis(ctx sdk.Context, k Keeper, data GenesisState) {
-	k.SetParams(ctx, types.NewParams(data.Markets, []string{}))
+	for _, mkt := range data.Markets {
+		k.Set(ctx, mkt)
+	}
 }
 
 func ExportGenesis(ctx sdk.Context, k Keeper) GenesisState {
-	params := k.GetParams(ctx)
-	return GenesisState{Markets: params.Markets, Nominees: params.Nominees}
+	markets := types.Markets{}
+	k.Iterator(ctx, func(mkt types.Market) bool {
+		markets = append(markets, mkt)
+		return true
+	})
+	return GenesisState{Markets: markets}
 }
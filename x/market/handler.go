@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/xar-network/xar-network/types/errs"
 	"github.com/xar-network/xar-network/x/market/types"
 )
 
@@ -13,6 +14,10 @@ func NewHandler(k Keeper) sdk.Handler {
 		switch msg := msg.(type) {
 		case types.MsgCreateMarket:
 			return handleCreateMarket(ctx, k, msg)
+		case types.MsgSetMarketStatus:
+			return handleSetMarketStatus(ctx, k, msg)
+		case types.MsgUpdateMarketParams:
+			return handleUpdateMarketParams(ctx, k, msg)
 		default:
 			return sdk.ErrUnknownRequest(fmt.Sprintf("unrecognized market message type: %T", msg)).Result()
 		}
@@ -20,9 +25,48 @@ func NewHandler(k Keeper) sdk.Handler {
 }
 
 func handleCreateMarket(ctx sdk.Context, keeper Keeper, msg types.MsgCreateMarket) sdk.Result {
-	_, err := keeper.CreateMarket(ctx, msg.Nominee.String(), msg.BaseAsset, msg.QuoteAsset)
+	action := msg
+	action.Nominee = nil
+	if ok, err := keeper.Approve(ctx, msg.Nominee, action); !ok {
+		return pendingResult(ctx, msg.Nominee, err)
+	}
+	_, err := keeper.CreateMarket(ctx, msg.Nominee.String(), msg.MarketParams())
+	if err != nil {
+		return err.Result()
+	}
+	return errs.MessageResult(ctx, types.AttributeValueCategory, msg.Nominee, "")
+}
+
+func handleSetMarketStatus(ctx sdk.Context, keeper Keeper, msg types.MsgSetMarketStatus) sdk.Result {
+	action := msg
+	action.Nominee = nil
+	if ok, err := keeper.Approve(ctx, msg.Nominee, action); !ok {
+		return pendingResult(ctx, msg.Nominee, err)
+	}
+	if err := keeper.SetStatus(ctx, msg.MarketID, msg.Status); err != nil {
+		return err.Result()
+	}
+	return errs.MessageResult(ctx, types.AttributeValueCategory, msg.Nominee, "")
+}
+
+func handleUpdateMarketParams(ctx sdk.Context, keeper Keeper, msg types.MsgUpdateMarketParams) sdk.Result {
+	action := msg
+	action.Nominee = nil
+	if ok, err := keeper.Approve(ctx, msg.Nominee, action); !ok {
+		return pendingResult(ctx, msg.Nominee, err)
+	}
+	if _, err := keeper.UpdateParams(ctx, msg.MarketID, msg.Fees, msg.Rules, msg.Band); err != nil {
+		return err.Result()
+	}
+	return errs.MessageResult(ctx, types.AttributeValueCategory, msg.Nominee, "")
+}
+
+// pendingResult is the result of a message that was not executed, either
+// because its nominee is not a market admin or because it still needs the
+// approval of other market admins.
+func pendingResult(ctx sdk.Context, nominee sdk.AccAddress, err sdk.Error) sdk.Result {
 	if err != nil {
 		return err.Result()
 	}
-	return sdk.Result{Events: ctx.EventManager().Events()}
+	return errs.MessageResult(ctx, types.AttributeValueCategory, nominee, "")
 }
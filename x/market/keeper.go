@@ -5,50 +5,64 @@ import (
 
 	"github.com/xar-network/xar-network/types/errs"
 	"github.com/xar-network/xar-network/types/store"
+	"github.com/xar-network/xar-network/x/authority"
 	"github.com/xar-network/xar-network/x/market/types"
 
 	"github.com/cosmos/cosmos-sdk/codec"
 	sdk "github.com/cosmos/cosmos-sdk/types"
-	"github.com/cosmos/cosmos-sdk/x/params/subspace"
+	"github.com/cosmos/cosmos-sdk/x/supply"
 )
 
 const (
-	seqKey = "seq"
-	valKey = "val"
+	seqKey       = "seq"
+	valKey       = "val"
+	lastPriceKey = "last_price"
+	breakerKey   = "breaker"
 )
 
 type IteratorCB func(mkt types.Market) bool
 
 type Keeper struct {
-	storeKey      sdk.StoreKey
-	cdc           *codec.Codec
-	paramSubspace subspace.Subspace
-	codespace     sdk.CodespaceType
+	storeKey        sdk.StoreKey
+	cdc             *codec.Codec
+	supplyKeeper    supply.Keeper
+	authorityKeeper types.AuthorityKeeper
+	codespace       sdk.CodespaceType
+	hooks           types.MarketHooks
 }
 
-func NewKeeper(sk sdk.StoreKey, cdc *codec.Codec, paramstore subspace.Subspace, codespace sdk.CodespaceType) Keeper {
+func NewKeeper(sk sdk.StoreKey, cdc *codec.Codec, supplyKeeper supply.Keeper, authorityKeeper types.AuthorityKeeper, codespace sdk.CodespaceType) Keeper {
 	return Keeper{
-		storeKey:      sk,
-		cdc:           cdc,
-		paramSubspace: paramstore.WithKeyTable(types.ParamKeyTable()),
-		codespace:     codespace,
+		storeKey:        sk,
+		cdc:             cdc,
+		supplyKeeper:    supplyKeeper,
+		authorityKeeper: authorityKeeper,
+		codespace:       codespace,
 	}
 }
 
-func (k Keeper) Get(ctx sdk.Context, id store.EntityID) (types.Market, sdk.Error) {
-	params := k.GetParams(ctx)
-	markets := params.Markets
-	if uint64(len(markets)) < id.Uint64() {
-		return types.Market{}, errs.ErrNotFound("not found")
-	}
-	market := params.Markets[id.Dec().Uint64()]
-	if (market == types.Market{}) {
-		return types.Market{}, errs.ErrNotFound("not found")
+// SetHooks sets the market hooks. It panics if hooks have already been set.
+func (k *Keeper) SetHooks(mh types.MarketHooks) *Keeper {
+	if k.hooks != nil {
+		panic("cannot set market hooks twice")
 	}
-	if !market.ID.Equals(id) {
-		return types.Market{}, errs.ErrNotFound("incorrect index")
+	k.hooks = mh
+	return k
+}
+
+func (k Keeper) Get(ctx sdk.Context, id store.EntityID) (types.Market, sdk.Error) {
+	var out types.Market
+	err := store.Get(ctx, k.storeKey, k.cdc, marketKey(id), &out)
+	return out, err
+}
+
+// Set stores a market, creating it if it does not exist yet. IDs are
+// assigned sequentially, so the sequence is advanced past id.
+func (k Keeper) Set(ctx sdk.Context, mkt types.Market) {
+	store.Set(ctx, k.storeKey, k.cdc, marketKey(mkt.ID), mkt)
+	if store.GetSeq(ctx, k.storeKey, []byte(seqKey)).Cmp(mkt.ID) < 0 {
+		ctx.KVStore(k.storeKey).Set([]byte(seqKey), []byte(mkt.ID.String()))
 	}
-	return market, nil
 }
 
 func (k Keeper) Pair(ctx sdk.Context, id store.EntityID) (string, sdk.Error) {
@@ -65,46 +79,203 @@ func (k Keeper) Has(ctx sdk.Context, id store.EntityID) bool {
 	return err == nil
 }
 
-func (k Keeper) CreateMarket(ctx sdk.Context, nominee, baseAsset, quoteAsset string) (types.Market, sdk.Error) {
+// SetLastPrice records the price the market last executed at.
+func (k Keeper) SetLastPrice(ctx sdk.Context, id store.EntityID, price sdk.Uint) {
+	store.Set(ctx, k.storeKey, k.cdc, lastPriceKeyFor(id), price)
+}
+
+// LastPrice returns the price the market last executed at, or false if it
+// has not executed yet.
+func (k Keeper) LastPrice(ctx sdk.Context, id store.EntityID) (sdk.Uint, bool) {
+	var price sdk.Uint
+	if err := store.Get(ctx, k.storeKey, k.cdc, lastPriceKeyFor(id), &price); err != nil {
+		return sdk.ZeroUint(), false
+	}
+	return price, true
+}
+
+// GetCircuitBreaker returns the price band state of a market.
+func (k Keeper) GetCircuitBreaker(ctx sdk.Context, id store.EntityID) types.CircuitBreaker {
+	out := types.CircuitBreaker{MarketID: id}
+	_ = store.Get(ctx, k.storeKey, k.cdc, breakerKeyFor(id), &out)
+	return out
+}
+
+// IsHalted returns true if the market's circuit breaker has halted it.
+func (k Keeper) IsHalted(ctx sdk.Context, id store.EntityID) bool {
+	return k.GetCircuitBreaker(ctx, id).IsHalted(ctx.BlockHeight())
+}
+
+// RecordBreach counts a batch that breached the market's price band. Once
+// the band's MaxBreaches consecutive breaches are reached the market is
+// halted for the band's HaltBlocks, starting with the next block. It
+// returns the updated circuit breaker.
+func (k Keeper) RecordBreach(ctx sdk.Context, mkt types.Market) types.CircuitBreaker {
+	breaker := k.GetCircuitBreaker(ctx, mkt.ID)
+	breaker.Breaches++
+	if mkt.Band.MaxBreaches > 0 && breaker.Breaches >= mkt.Band.MaxBreaches {
+		breaker.Breaches = 0
+		breaker.HaltedUntil = ctx.BlockHeight() + mkt.Band.HaltBlocks + 1
+	}
+	store.Set(ctx, k.storeKey, k.cdc, breakerKeyFor(mkt.ID), breaker)
+	return breaker
+}
+
+// ResetBreaches clears the consecutive breaches of a market after a batch
+// executed within its price band.
+func (k Keeper) ResetBreaches(ctx sdk.Context, id store.EntityID) {
+	breaker := k.GetCircuitBreaker(ctx, id)
+	if breaker.Breaches == 0 {
+		return
+	}
+	breaker.Breaches = 0
+	store.Set(ctx, k.storeKey, k.cdc, breakerKeyFor(id), breaker)
+}
+
+// CreateMarket creates a market on behalf of a market admin.
+func (k Keeper) CreateMarket(ctx sdk.Context, nominee string, p types.MarketParams) (types.Market, sdk.Error) {
 	if !k.IsNominee(ctx, nominee) {
-		return types.Market{}, sdk.ErrInternal(fmt.Sprintf("not a nominee: '%s'", nominee))
+		return types.Market{}, sdk.ErrUnauthorized(fmt.Sprintf("not a market admin: '%s'", nominee))
+	}
+	return k.createMarket(ctx, p)
+}
+
+// createMarket validates and stores a new market. Callers are responsible
+// for checking that the market may be created at all.
+func (k Keeper) createMarket(ctx sdk.Context, p types.MarketParams) (types.Market, sdk.Error) {
+	if !p.MatchingMode.IsValid() {
+		return types.Market{}, sdk.ErrUnknownRequest("invalid matching mode")
+	}
+	total := k.supplyKeeper.GetSupply(ctx).GetTotal()
+	for _, denom := range []string{p.BaseAsset, p.QuoteAsset} {
+		if !total.AmountOf(denom).IsPositive() {
+			return types.Market{}, errs.ErrInvalidArgument(fmt.Sprintf("asset not in supply: '%s'", denom))
+		}
+	}
+	if err := p.Fees.Validate(p.BaseAsset, p.QuoteAsset); err != nil {
+		return types.Market{}, errs.ErrInvalidArgument(err.Error())
 	}
-	params := k.GetParams(ctx)
-	id := uint64(len(params.Markets))
-	market := types.NewMarket(store.NewEntityID(id).Inc(), baseAsset, quoteAsset)
-	params.Markets = append(params.Markets, market)
-	k.SetParams(ctx, params)
+	if k.supplyKeeper.GetModuleAddress(p.Fees.RecipientModule()) == nil {
+		return types.Market{}, errs.ErrInvalidArgument(fmt.Sprintf("unknown fee recipient: '%s'", p.Fees.RecipientModule()))
+	}
+	if err := p.Rules.Validate(); err != nil {
+		return types.Market{}, errs.ErrInvalidArgument(err.Error())
+	}
+	if err := p.Band.Validate(); err != nil {
+		return types.Market{}, errs.ErrInvalidArgument(err.Error())
+	}
+	id := store.IncrementSeq(ctx, k.storeKey, []byte(seqKey))
+	market := types.NewMarket(id, p.BaseAsset, p.QuoteAsset, p.MatchingMode)
+	market.Fees = p.Fees
+	market.Rules = p.Rules
+	market.Band = p.Band
+	store.Set(ctx, k.storeKey, k.cdc, marketKey(id), market)
 
 	return market, nil
 }
 
+// SetStatus moves a market to another stage of its lifecycle. Delisted
+// markets cannot be changed anymore; delisting a market runs the market
+// hooks so that its orders are refunded.
+func (k Keeper) SetStatus(ctx sdk.Context, id store.EntityID, status types.MarketStatus) sdk.Error {
+	mkt, err := k.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !status.IsValid() {
+		return sdk.ErrUnknownRequest("invalid market status")
+	}
+	if mkt.Status == types.MarketDelisted {
+		return errs.ErrInvalidArgument("market is delisted")
+	}
+	if mkt.Status == status {
+		return errs.ErrInvalidArgument(fmt.Sprintf("market is already %s", status))
+	}
+	mkt.Status = status
+	k.Set(ctx, mkt)
+
+	if status == types.MarketDelisted && k.hooks != nil {
+		return k.hooks.AfterMarketDelisted(ctx, id)
+	}
+	return nil
+}
+
+// UpdateParams replaces the fees, trading rules or price band of a market.
+// Nil parameters are left unchanged. The assets' decimals cannot change
+// since the escrow of open orders depends on them.
+func (k Keeper) UpdateParams(ctx sdk.Context, id store.EntityID, fees *types.FeeSchedule, rules *types.TradingRules, band *types.PriceBand) (types.Market, sdk.Error) {
+	mkt, err := k.Get(ctx, id)
+	if err != nil {
+		return mkt, err
+	}
+	if mkt.Status == types.MarketDelisted {
+		return mkt, errs.ErrInvalidArgument("market is delisted")
+	}
+	if fees != nil {
+		if err := fees.Validate(mkt.BaseAssetDenom, mkt.QuoteAssetDenom); err != nil {
+			return mkt, errs.ErrInvalidArgument(err.Error())
+		}
+		if k.supplyKeeper.GetModuleAddress(fees.RecipientModule()) == nil {
+			return mkt, errs.ErrInvalidArgument(fmt.Sprintf("unknown fee recipient: '%s'", fees.RecipientModule()))
+		}
+		mkt.Fees = *fees
+	}
+	if rules != nil {
+		if err := rules.Validate(); err != nil {
+			return mkt, errs.ErrInvalidArgument(err.Error())
+		}
+		if rules.BaseAssetDecimals() != mkt.Rules.BaseAssetDecimals() || rules.QuoteAssetDecimals() != mkt.Rules.QuoteAssetDecimals() {
+			return mkt, errs.ErrInvalidArgument("asset decimals cannot change")
+		}
+		mkt.Rules = *rules
+	}
+	if band != nil {
+		if err := band.Validate(); err != nil {
+			return mkt, errs.ErrInvalidArgument(err.Error())
+		}
+		mkt.Band = *band
+	}
+	k.Set(ctx, mkt)
+	return mkt, nil
+}
+
+// Iterator iterates over all markets in order of their IDs.
 func (k Keeper) Iterator(ctx sdk.Context, cb IteratorCB) {
-	params := k.GetParams(ctx)
-	for _, mkt := range params.Markets {
+	kv := ctx.KVStore(k.storeKey)
+	iter := sdk.KVStorePrefixIterator(kv, []byte(valKey))
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		var mkt types.Market
+		k.cdc.MustUnmarshalBinaryBare(iter.Value(), &mkt)
 		if !cb(mkt) {
 			break
 		}
 	}
 }
 
-// SetParams sets the auth module's parameters.
-func (k Keeper) SetParams(ctx sdk.Context, params types.Params) {
-	k.paramSubspace.SetParamSet(ctx, &params)
+// IsNominee returns true if nominee is an active market admin.
+func (k Keeper) IsNominee(ctx sdk.Context, nominee string) bool {
+	addr, err := sdk.AccAddressFromBech32(nominee)
+	if err != nil {
+		return false
+	}
+	return k.authorityKeeper.HasRole(ctx, authority.RoleMarketAdmin, addr)
+}
+
+// Approve records the nominee's approval of action and returns true once
+// enough market admins approved it for it to go ahead.
+func (k Keeper) Approve(ctx sdk.Context, nominee sdk.AccAddress, action sdk.Msg) (bool, sdk.Error) {
+	return k.authorityKeeper.Approve(ctx, authority.RoleMarketAdmin, nominee, action)
 }
 
-// GetParams gets the auth module's parameters.
-func (k Keeper) GetParams(ctx sdk.Context) (params types.Params) {
-	k.paramSubspace.GetParamSet(ctx, &params)
-	return
+func marketKey(id store.EntityID) []byte {
+	return store.PrefixKeyString(valKey, id.Bytes())
 }
 
-func (k Keeper) IsNominee(ctx sdk.Context, nominee string) bool {
-	params := k.GetParams(ctx)
-	nominees := params.Nominees
-	for _, v := range nominees {
-		if v == nominee {
-			return true
-		}
-	}
-	return false
+func lastPriceKeyFor(id store.EntityID) []byte {
+	return store.PrefixKeyString(lastPriceKey, id.Bytes())
+}
+
+func breakerKeyFor(id store.EntityID) []byte {
+	return store.PrefixKeyString(breakerKey, id.Bytes())
 }
@@ -2,10 +2,15 @@ package market_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/cosmos/cosmos-sdk/codec"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/bank"
 	"github.com/cosmos/cosmos-sdk/x/params"
+	"github.com/cosmos/cosmos-sdk/x/supply"
+	"github.com/xar-network/xar-network/x/authority"
 	"github.com/xar-network/xar-network/x/market"
 	"github.com/xar-network/xar-network/x/market/types"
 
@@ -13,11 +18,18 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto"
 	"github.com/tendermint/tendermint/libs/log"
 	dbm "github.com/tendermint/tm-db"
 	"github.com/xar-network/xar-network/types/store"
 )
 
+// market admins need well-formed addresses
+var (
+	admin      = sdk.AccAddress(crypto.AddressHash([]byte("someName")))
+	otherAdmin = sdk.AccAddress(crypto.AddressHash([]byte("otherName")))
+)
+
 func TestKeeperCoverage(t *testing.T) {
 
 	cdc := makeTestCodec()
@@ -28,12 +40,19 @@ func TestKeeperCoverage(t *testing.T) {
 	var (
 		keyParams  = sdk.NewKVStoreKey(params.StoreKey)
 		keyMarket  = sdk.NewKVStoreKey(market.StoreKey)
+		keyAcc     = sdk.NewKVStoreKey(auth.StoreKey)
+		keySupply  = sdk.NewKVStoreKey(supply.StoreKey)
+		keyAuth    = sdk.NewKVStoreKey(authority.StoreKey)
 		tkeyParams = sdk.NewTransientStoreKey(params.TStoreKey)
 	)
 
 	db := dbm.NewMemDB()
 	ms := cstore.NewCommitMultiStore(db)
 	ms.MountStoreWithDB(keyParams, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(keyMarket, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(keyAcc, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(keySupply, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(keyAuth, sdk.StoreTypeIAVL, db)
 	ms.MountStoreWithDB(tkeyParams, sdk.StoreTypeTransient, db)
 
 	err := ms.LoadLatestVersion()
@@ -42,10 +61,18 @@ func TestKeeperCoverage(t *testing.T) {
 	ctx := sdk.NewContext(ms, abci.Header{ChainID: "xar-chain"}, true, logger)
 
 	var (
-		pk = params.NewKeeper(cdc, keyParams, tkeyParams, params.DefaultCodespace)
-		mk = market.NewKeeper(keyMarket, cdc, pk.Subspace(market.DefaultParamspace), market.DefaultCodespace)
+		pk  = params.NewKeeper(cdc, keyParams, tkeyParams, params.DefaultCodespace)
+		ak  = auth.NewAccountKeeper(cdc, keyAcc, pk.Subspace(auth.DefaultParamspace), auth.ProtoBaseAccount)
+		bk  = bank.NewBaseKeeper(ak, pk.Subspace(bank.DefaultParamspace), bank.DefaultCodespace, nil)
+		sk  = supply.NewKeeper(cdc, keySupply, ak, bk, map[string][]string{auth.FeeCollectorName: nil})
+		auk = authority.NewKeeper(keyAuth, cdc, authority.DefaultCodespace)
+		mk  = market.NewKeeper(keyMarket, cdc, sk, auk, market.DefaultCodespace)
 	)
-	mk.SetParams(ctx, types.NewParams(market.DefaultGenesisState().Markets, []string{"cosmos1wdhk6e2wv9kk2j88d92"}))
+	market.InitGenesis(ctx, mk, market.DefaultGenesisState())
+	auk.SetRole(ctx, authority.NewRole(authority.RoleMarketAdmin, 1, []authority.Grant{
+		authority.NewGrant(admin, time.Time{}),
+	}))
+	sk.SetSupply(ctx, supply.NewSupply(sdk.NewCoins(sdk.NewInt64Coin("new1", 1000), sdk.NewInt64Coin("new2", 1000))))
 
 	// Get market with ID 1
 	market, err := mk.Get(ctx, store.NewEntityID(1))
@@ -60,18 +87,309 @@ func TestKeeperCoverage(t *testing.T) {
 	require.Equal(t, "uftm/uzar", pair)
 
 	// Create market as a nominee
-	addr := sdk.AccAddress([]byte("someName"))
-	msg := types.NewMsgCreateMarket(addr, "new1", "new2")
-	mkt, err := mk.CreateMarket(ctx, msg.Nominee.String(), msg.BaseAsset, msg.QuoteAsset)
+	addr := admin
+	msg := types.NewMsgCreateMarket(addr, "new1", "new2", types.MatchingModeBatch)
+	mkt, err := mk.CreateMarket(ctx, msg.Nominee.String(), msg.MarketParams())
 	require.Nil(t, err)
 	require.Equal(t, mkt.BaseAssetDenom, msg.BaseAsset)
 
+	require.Equal(t, types.MarketActive, mkt.Status)
+	got, err := mk.Get(ctx, mkt.ID)
+	require.Nil(t, err)
+	require.Equal(t, mkt, got)
+
+	// Both assets must exist in supply
+	_, err = mk.CreateMarket(ctx, msg.Nominee.String(), types.MarketParams{
+		BaseAsset:    "new1",
+		QuoteAsset:   "new3",
+		MatchingMode: types.MatchingModeBatch,
+	})
+	assert.Error(t, err)
+
 	// Create market as a nominee
 	addr = sdk.AccAddress([]byte("someInvalidName"))
-	msg = types.NewMsgCreateMarket(addr, "new1", "new2")
-	mkt, err = mk.CreateMarket(ctx, msg.Nominee.String(), msg.BaseAsset, msg.QuoteAsset)
+	msg = types.NewMsgCreateMarket(addr, "new1", "new2", types.MatchingModeBatch)
+	mkt, err = mk.CreateMarket(ctx, msg.Nominee.String(), msg.MarketParams())
 	assert.Error(t, err)
 	require.Equal(t, "", mkt.BaseAssetDenom)
+
+	// Create market with fees
+	addr = admin
+	fees := types.NewFeeSchedule(10, 20, "new2", "")
+	mkt, err = mk.CreateMarket(ctx, addr.String(), types.MarketParams{
+		BaseAsset:    "new1",
+		QuoteAsset:   "new2",
+		MatchingMode: types.MatchingModeBatch,
+		Fees:         fees,
+	})
+	require.Nil(t, err)
+	require.Equal(t, fees, mkt.Fees)
+	require.Equal(t, auth.FeeCollectorName, mkt.Fees.RecipientModule())
+
+	// Fees must be collected in one of the market's assets by a known module
+	_, err = mk.CreateMarket(ctx, addr.String(), types.MarketParams{
+		BaseAsset:    "new1",
+		QuoteAsset:   "new2",
+		MatchingMode: types.MatchingModeBatch,
+		Fees:         types.NewFeeSchedule(10, 20, "new3", ""),
+	})
+	assert.Error(t, err)
+	_, err = mk.CreateMarket(ctx, addr.String(), types.MarketParams{
+		BaseAsset:    "new1",
+		QuoteAsset:   "new2",
+		MatchingMode: types.MatchingModeBatch,
+		Fees:         types.NewFeeSchedule(10, 20, "", "treasury"),
+	})
+	assert.Error(t, err)
+	// fees sent to the order module's escrow account would break its
+	// invariant
+	_, err = mk.CreateMarket(ctx, addr.String(), types.MarketParams{
+		BaseAsset:    "new1",
+		QuoteAsset:   "new2",
+		MatchingMode: types.MatchingModeBatch,
+		Fees:         types.NewFeeSchedule(10, 20, "", "order"),
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "fee recipient")
+	_, err = mk.CreateMarket(ctx, addr.String(), types.MarketParams{
+		BaseAsset:    "new1",
+		QuoteAsset:   "new2",
+		MatchingMode: types.MatchingModeBatch,
+		Fees:         types.NewFeeSchedule(10, 20, "", auth.FeeCollectorName),
+	})
+	require.Nil(t, err)
+	_, err = mk.CreateMarket(ctx, addr.String(), types.MarketParams{
+		BaseAsset:    "new1",
+		QuoteAsset:   "new2",
+		MatchingMode: types.MatchingModeBatch,
+		Fees:         types.NewFeeSchedule(types.MaxFeeBps+1, 20, "", ""),
+	})
+	assert.Error(t, err)
+
+	// Create market with a price band
+	band := types.NewPriceBand(500, 3, 10)
+	mkt, err = mk.CreateMarket(ctx, addr.String(), types.MarketParams{
+		BaseAsset:    "new1",
+		QuoteAsset:   "new2",
+		MatchingMode: types.MatchingModeBatch,
+		Band:         band,
+	})
+	require.Nil(t, err)
+	require.Equal(t, band, mkt.Band)
+
+	// Halting markets requires a deviation limit and a halt duration
+	_, err = mk.CreateMarket(ctx, addr.String(), types.MarketParams{
+		BaseAsset:    "new1",
+		QuoteAsset:   "new2",
+		MatchingMode: types.MatchingModeBatch,
+		Band:         types.NewPriceBand(500, 3, 0),
+	})
+	assert.Error(t, err)
+	_, err = mk.CreateMarket(ctx, addr.String(), types.MarketParams{
+		BaseAsset:    "new1",
+		QuoteAsset:   "new2",
+		MatchingMode: types.MatchingModeBatch,
+		Band:         types.NewPriceBand(0, 3, 10),
+	})
+	assert.Error(t, err)
+}
+
+type recordingHooks struct {
+	delisted []store.EntityID
+}
+
+func (h *recordingHooks) AfterMarketDelisted(_ sdk.Context, mktID store.EntityID) sdk.Error {
+	h.delisted = append(h.delisted, mktID)
+	return nil
+}
+
+func TestKeeper_Lifecycle(t *testing.T) {
+	ctx, mk, _ := setupKeeper(t)
+	hooks := &recordingHooks{}
+	mk.SetHooks(hooks)
+	nominee := admin
+	handler := market.NewHandler(mk)
+
+	mkt, err := mk.CreateMarket(ctx, nominee.String(), types.MarketParams{
+		BaseAsset:    "new1",
+		QuoteAsset:   "new2",
+		MatchingMode: types.MatchingModeBatch,
+		Rules:        types.NewTradingRules(10, 100, 0, 8, 8),
+	})
+	require.Nil(t, err)
+
+	t.Run("status changes require a market admin", func(t *testing.T) {
+		msg := types.NewMsgSetMarketStatus(sdk.AccAddress([]byte("someInvalidName")), mkt.ID, types.MarketHalted)
+		res := handler(ctx, msg)
+		require.False(t, res.IsOK())
+	})
+
+	t.Run("halts and resumes", func(t *testing.T) {
+		res := handler(ctx, types.NewMsgSetMarketStatus(nominee, mkt.ID, types.MarketHalted))
+		require.True(t, res.IsOK(), res.Log)
+		require.Contains(t, res.Events, sdk.NewEvent(
+			sdk.EventTypeMessage,
+			sdk.NewAttribute(sdk.AttributeKeyModule, types.AttributeValueCategory),
+			sdk.NewAttribute(sdk.AttributeKeySender, nominee.String()),
+		))
+		got, err := mk.Get(ctx, mkt.ID)
+		require.Nil(t, err)
+		require.Equal(t, types.MarketHalted, got.Status)
+
+		require.Error(t, mk.SetStatus(ctx, mkt.ID, types.MarketHalted))
+		require.Nil(t, mk.SetStatus(ctx, mkt.ID, types.MarketCancelOnly))
+		require.Nil(t, mk.SetStatus(ctx, mkt.ID, types.MarketActive))
+		require.Empty(t, hooks.delisted)
+	})
+
+	t.Run("updates parameters", func(t *testing.T) {
+		fees := types.NewFeeSchedule(10, 20, "", "")
+		msg := types.NewMsgUpdateMarketParams(nominee, mkt.ID)
+		msg.Fees = &fees
+		res := handler(ctx, msg)
+		require.True(t, res.IsOK(), res.Log)
+		got, err := mk.Get(ctx, mkt.ID)
+		require.Nil(t, err)
+		require.Equal(t, fees, got.Fees)
+		require.Equal(t, mkt.Rules, got.Rules)
+
+		rules := types.NewTradingRules(20, 100, 0, 8, 8)
+		got, err = mk.UpdateParams(ctx, mkt.ID, nil, &rules, nil)
+		require.Nil(t, err)
+		require.Equal(t, rules, got.Rules)
+		require.Equal(t, fees, got.Fees)
+
+		rules = types.NewTradingRules(20, 100, 0, 6, 8)
+		_, err = mk.UpdateParams(ctx, mkt.ID, nil, &rules, nil)
+		require.Error(t, err)
+	})
+
+	t.Run("delists", func(t *testing.T) {
+		require.Nil(t, mk.SetStatus(ctx, mkt.ID, types.MarketDelisted))
+		require.Equal(t, []store.EntityID{mkt.ID}, hooks.delisted)
+
+		require.Error(t, mk.SetStatus(ctx, mkt.ID, types.MarketActive))
+		band := types.NewPriceBand(500, 3, 10)
+		_, err := mk.UpdateParams(ctx, mkt.ID, nil, nil, &band)
+		require.Error(t, err)
+	})
+}
+
+func TestHandler_Threshold(t *testing.T) {
+	ctx, mk, auk := setupKeeper(t)
+	handler := market.NewHandler(mk)
+	first, second := admin, otherAdmin
+	require.Nil(t, auk.UpdateRole(ctx, authority.NewRole(authority.RoleMarketAdmin, 2, []authority.Grant{
+		authority.NewGrant(first, time.Time{}),
+		authority.NewGrant(second, time.Time{}),
+	})))
+
+	countMarkets := func() int {
+		var n int
+		mk.Iterator(ctx, func(types.Market) bool {
+			n++
+			return true
+		})
+		return n
+	}
+
+	// pending approvals are told apart from executed actions by their
+	// approval_pending event
+	approvals := func(res sdk.Result) string {
+		for _, ev := range sdk.StringifyEvents(res.Events.ToABCIEvents()) {
+			if ev.Type != authority.EventTypeApprovalPending {
+				continue
+			}
+			for _, attr := range ev.Attributes {
+				if attr.Key == authority.AttributeKeyApprovals {
+					return attr.Value
+				}
+			}
+		}
+		return ""
+	}
+	create := func(signer sdk.AccAddress) sdk.Result {
+		return handler(ctx.WithEventManager(sdk.NewEventManager()), types.NewMsgCreateMarket(signer, "new1", "new2", types.MatchingModeBatch))
+	}
+
+	res := create(first)
+	require.True(t, res.IsOK(), res.Log)
+	require.Equal(t, "1", approvals(res))
+	require.Equal(t, 0, countMarkets())
+
+	// the same signer approving twice does not reach the threshold
+	res = create(first)
+	require.True(t, res.IsOK(), res.Log)
+	require.Equal(t, "1", approvals(res))
+	require.Equal(t, 0, countMarkets())
+
+	res = create(second)
+	require.True(t, res.IsOK(), res.Log)
+	require.Empty(t, approvals(res))
+	require.Equal(t, 1, countMarkets())
+}
+
+func TestProposalHandler_ListMarket(t *testing.T) {
+	ctx, mk, _ := setupKeeper(t)
+	handler := market.NewProposalHandler(mk)
+
+	rules := types.NewTradingRules(10, 100, 0, 8, 8)
+	proposal := types.NewListMarketProposal("title", "description", "new1", "new2", types.MatchingModeContinuous, types.FeeSchedule{}, rules, types.PriceBand{})
+	require.NoError(t, proposal.ValidateBasic())
+	require.NoError(t, handler(ctx, proposal))
+
+	var listed []types.Market
+	mk.Iterator(ctx, func(mkt types.Market) bool {
+		listed = append(listed, mkt)
+		return true
+	})
+	require.Len(t, listed, 1)
+	require.Equal(t, "new1", listed[0].BaseAssetDenom)
+	require.Equal(t, types.MatchingModeContinuous, listed[0].MatchingMode)
+	require.Equal(t, rules, listed[0].Rules)
+
+	// both assets must exist in supply
+	proposal = types.NewListMarketProposal("title", "description", "new1", "new3", types.MatchingModeBatch, types.FeeSchedule{}, types.TradingRules{}, types.PriceBand{})
+	require.NoError(t, proposal.ValidateBasic())
+	require.Error(t, handler(ctx, proposal))
+
+	proposal = types.NewListMarketProposal("title", "description", "new1", "new1", types.MatchingModeBatch, types.FeeSchedule{}, types.TradingRules{}, types.PriceBand{})
+	require.Error(t, proposal.ValidateBasic())
+}
+
+func setupKeeper(t *testing.T) (sdk.Context, market.Keeper, authority.Keeper) {
+	cdc := makeTestCodec()
+	var (
+		keyParams  = sdk.NewKVStoreKey(params.StoreKey)
+		keyMarket  = sdk.NewKVStoreKey(market.StoreKey)
+		keyAcc     = sdk.NewKVStoreKey(auth.StoreKey)
+		keySupply  = sdk.NewKVStoreKey(supply.StoreKey)
+		keyAuth    = sdk.NewKVStoreKey(authority.StoreKey)
+		tkeyParams = sdk.NewTransientStoreKey(params.TStoreKey)
+	)
+
+	db := dbm.NewMemDB()
+	ms := cstore.NewCommitMultiStore(db)
+	ms.MountStoreWithDB(keyParams, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(keyMarket, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(keyAcc, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(keySupply, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(keyAuth, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(tkeyParams, sdk.StoreTypeTransient, db)
+	require.Nil(t, ms.LoadLatestVersion())
+
+	ctx := sdk.NewContext(ms, abci.Header{ChainID: "xar-chain"}, true, log.NewNopLogger())
+	pk := params.NewKeeper(cdc, keyParams, tkeyParams, params.DefaultCodespace)
+	ak := auth.NewAccountKeeper(cdc, keyAcc, pk.Subspace(auth.DefaultParamspace), auth.ProtoBaseAccount)
+	bk := bank.NewBaseKeeper(ak, pk.Subspace(bank.DefaultParamspace), bank.DefaultCodespace, nil)
+	sk := supply.NewKeeper(cdc, keySupply, ak, bk, map[string][]string{auth.FeeCollectorName: nil, "order": nil})
+	auk := authority.NewKeeper(keyAuth, cdc, authority.DefaultCodespace)
+	mk := market.NewKeeper(keyMarket, cdc, sk, auk, market.DefaultCodespace)
+	auk.SetRole(ctx, authority.NewRole(authority.RoleMarketAdmin, 1, []authority.Grant{
+		authority.NewGrant(admin, time.Time{}),
+	}))
+	sk.SetSupply(ctx, supply.NewSupply(sdk.NewCoins(sdk.NewInt64Coin("new1", 1000), sdk.NewInt64Coin("new2", 1000))))
+	return ctx, mk, auk
 }
 
 func makeTestCodec() (cdc *codec.Codec) {
@@ -79,6 +397,8 @@ func makeTestCodec() (cdc *codec.Codec) {
 
 	sdk.RegisterCodec(cdc)
 	codec.RegisterCrypto(cdc)
+	auth.RegisterCodec(cdc)
+	supply.RegisterCodec(cdc)
 
 	return
 }
@@ -0,0 +1,76 @@
+// Package legacy migrates market genesis states from the markets kept in the
+// market module's params to the markets kept in its store.
+package legacy
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/x/genutil"
+
+	"github.com/xar-network/xar-network/types/store"
+	"github.com/xar-network/xar-network/x/market"
+	"github.com/xar-network/xar-network/x/market/types"
+)
+
+// paramsMarket is a market as it was kept in the params, before markets had
+// a matching mode, fees, trading rules, a price band or a status.
+type paramsMarket struct {
+	ID              store.EntityID  `json:"id"`
+	BaseAssetDenom  string          `json:"base_asset_denom"`
+	QuoteAssetDenom string          `json:"quote_asset_denom"`
+	Status          json.RawMessage `json:"status"`
+}
+
+// Migrate converts the markets of the market genesis state, exported from
+// the old Params.Markets, into markets of the store. They keep their IDs and
+// assets, are batch matched as before, charge no fees, accept orders of any
+// granularity, are unguarded by a price band and are active. A genesis state
+// whose markets already have a status is left as it is. Other fields of
+// the genesis state, such as the nominees migrated by the authority module,
+// are left as they are. Placeholder markets without an ID or assets fail the
+// migration rather than silently shifting the IDs of the following markets.
+func Migrate(appState genutil.AppMap) (genutil.AppMap, error) {
+	raw, ok := appState[types.ModuleName]
+	if !ok {
+		return appState, nil
+	}
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return nil, err
+	}
+	var old []paramsMarket
+	if child, ok := obj["markets"]; ok {
+		if err := json.Unmarshal(child, &old); err != nil {
+			return nil, fmt.Errorf("market: %v", err)
+		}
+	}
+
+	markets := make(types.Markets, 0, len(old))
+	for _, m := range old {
+		if m.Status != nil {
+			return appState, nil
+		}
+		if !m.ID.IsDefined() || m.BaseAssetDenom == "" || m.QuoteAssetDenom == "" {
+			return nil, fmt.Errorf("market: invalid market '%s' %s/%s", m.ID, m.BaseAssetDenom, m.QuoteAssetDenom)
+		}
+		mkt := types.NewMarket(m.ID, m.BaseAssetDenom, m.QuoteAssetDenom, types.MatchingModeBatch)
+		mkt.Status = types.MarketActive
+		markets = append(markets, mkt)
+	}
+	if err := market.ValidateGenesis(market.NewGenesisState(markets)); err != nil {
+		return nil, fmt.Errorf("market: %v", err)
+	}
+
+	migrated, err := types.ModuleCdc.MarshalJSON(markets)
+	if err != nil {
+		return nil, err
+	}
+	obj["markets"] = migrated
+	out, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	appState[types.ModuleName] = out
+	return appState, nil
+}
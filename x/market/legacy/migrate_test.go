@@ -0,0 +1,55 @@
+package legacy_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/x/genutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/xar-network/xar-network/types/store"
+	"github.com/xar-network/xar-network/x/market"
+	"github.com/xar-network/xar-network/x/market/legacy"
+	"github.com/xar-network/xar-network/x/market/types"
+)
+
+func TestMigrate(t *testing.T) {
+	appState := genutil.AppMap{
+		"market": json.RawMessage(`{"markets":[{"id":"1","base_asset_denom":"uftm","quote_asset_denom":"uzar"},{"id":"2","base_asset_denom":"ubtc","quote_asset_denom":"uzar"}],"nominees":["xar1admin"]}`),
+		"bank":   json.RawMessage(`{"send_enabled":true}`),
+	}
+
+	migrated, err := legacy.Migrate(appState)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"send_enabled":true}`, string(migrated["bank"]))
+
+	var obj map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(migrated["market"], &obj))
+	require.JSONEq(t, `["xar1admin"]`, string(obj["nominees"]))
+
+	var gs market.GenesisState
+	require.NoError(t, types.ModuleCdc.UnmarshalJSON(migrated["market"], &gs))
+	require.NoError(t, market.ValidateGenesis(gs))
+	require.Equal(t, types.Markets{
+		{ID: store.NewEntityID(1), BaseAssetDenom: "uftm", QuoteAssetDenom: "uzar", Status: types.MarketActive},
+		{ID: store.NewEntityID(2), BaseAssetDenom: "ubtc", QuoteAssetDenom: "uzar", Status: types.MarketActive},
+	}, gs.Markets)
+
+	// migrating again keeps the markets
+	again, err := legacy.Migrate(migrated)
+	require.NoError(t, err)
+	require.JSONEq(t, string(migrated["market"]), string(again["market"]))
+
+	// markets of the store keep their state
+	delisted := genutil.AppMap{
+		"market": json.RawMessage(`{"markets":[{"id":"1","base_asset_denom":"uftm","quote_asset_denom":"uzar","matching_mode":"CONTINUOUS","status":"DELISTED"}]}`),
+	}
+	kept, err := legacy.Migrate(delisted)
+	require.NoError(t, err)
+	require.JSONEq(t, string(delisted["market"]), string(kept["market"]))
+
+	_, err = legacy.Migrate(genutil.AppMap{
+		"market": json.RawMessage(`{"markets":[{"id":"0","base_asset_denom":"","quote_asset_denom":""}]}`),
+	})
+	require.Error(t, err)
+}
@@ -0,0 +1,30 @@
+package market
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types"
+
+	"github.com/xar-network/xar-network/x/market/types"
+)
+
+// NewProposalHandler handles market governance proposals.
+func NewProposalHandler(k Keeper) govtypes.Handler {
+	return func(ctx sdk.Context, content govtypes.Content) sdk.Error {
+		switch c := content.(type) {
+		case types.ListMarketProposal:
+			return handleListMarketProposal(ctx, k, c)
+		default:
+			return sdk.ErrUnknownRequest(fmt.Sprintf("unrecognized market proposal content type: %T", c))
+		}
+	}
+}
+
+// handleListMarketProposal creates the proposed market. The keeper checks
+// that both assets exist in supply and that the fee recipient is a known
+// module account.
+func handleListMarketProposal(ctx sdk.Context, k Keeper, p types.ListMarketProposal) sdk.Error {
+	_, err := k.createMarket(ctx, p.MarketParams())
+	return err
+}
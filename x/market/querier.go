@@ -6,11 +6,13 @@ import (
 	"github.com/cosmos/cosmos-sdk/codec"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 
+	"github.com/xar-network/xar-network/types/store"
 	"github.com/xar-network/xar-network/x/market/types"
 )
 
 const (
 	QueryList = "list"
+	QueryHalt = "halt"
 )
 
 func NewQuerier(keeper Keeper) sdk.Querier {
@@ -18,6 +20,8 @@ func NewQuerier(keeper Keeper) sdk.Querier {
 		switch path[0] {
 		case QueryList:
 			return queryList(ctx, keeper)
+		case QueryHalt:
+			return queryHalt(ctx, path[1:], keeper)
 		default:
 			return nil, sdk.ErrUnknownRequest("unknown market query endpoint")
 		}
@@ -42,6 +46,8 @@ func queryList(ctx sdk.Context, keeper Keeper) ([]byte, sdk.Error) {
 			BaseAssetDenom:  mkt.BaseAssetDenom,
 			QuoteAssetDenom: mkt.QuoteAssetDenom,
 			Name:            name,
+			MatchingMode:    mkt.MatchingMode,
+			Status:          mkt.Status,
 		})
 		return true
 	})
@@ -56,3 +62,26 @@ func queryList(ctx sdk.Context, keeper Keeper) ([]byte, sdk.Error) {
 	}
 	return b, nil
 }
+
+func queryHalt(ctx sdk.Context, path []string, keeper Keeper) ([]byte, sdk.Error) {
+	if len(path) != 1 {
+		return nil, sdk.ErrUnknownRequest("halt query requires a market ID")
+	}
+	mkt, err := keeper.Get(ctx, store.NewEntityIDFromString(path[0]))
+	if err != nil {
+		return nil, err
+	}
+	breaker := keeper.GetCircuitBreaker(ctx, mkt.ID)
+	res := types.HaltQueryResult{
+		MarketID:    mkt.ID,
+		Breaches:    breaker.Breaches,
+		HaltedUntil: breaker.HaltedUntil,
+		Halted:      breaker.IsHalted(ctx.BlockHeight()),
+	}
+
+	b, mErr := codec.MarshalJSONIndent(keeper.cdc, res)
+	if mErr != nil {
+		panic(mErr)
+	}
+	return b, nil
+}
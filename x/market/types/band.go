@@ -0,0 +1,88 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/xar-network/xar-network/types/store"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// PriceBand is a market's circuit breaker. A batch whose clearing price
+// deviates from the market's previous clearing price or from the oracle
+// median for its pair by more than MaxDeviationBps basis points is not
+// executed. In continuous markets every trade is held to the band the same
+// way. After MaxBreaches consecutive breaches the market is halted for
+// HaltBlocks blocks: it accepts no new orders and is not matched, but its
+// orders can still be cancelled and expire as usual. A zero MaxDeviationBps
+// disables the band and a zero MaxBreaches never halts the market.
+type PriceBand struct {
+	MaxDeviationBps uint32 `json:"max_deviation_bps" yaml:"max_deviation_bps"`
+	MaxBreaches     uint16 `json:"max_breaches" yaml:"max_breaches"`
+	HaltBlocks      int64  `json:"halt_blocks" yaml:"halt_blocks"`
+}
+
+func NewPriceBand(maxDeviationBps uint32, maxBreaches uint16, haltBlocks int64) PriceBand {
+	return PriceBand{
+		MaxDeviationBps: maxDeviationBps,
+		MaxBreaches:     maxBreaches,
+		HaltBlocks:      haltBlocks,
+	}
+}
+
+// IsEnabled returns true if the band restricts clearing prices.
+func (b PriceBand) IsEnabled() bool {
+	return b.MaxDeviationBps > 0
+}
+
+// Breached returns true if price deviates from a positive reference price by
+// more than the band allows. Zero reference prices are never breached.
+func (b PriceBand) Breached(reference sdk.Uint, price sdk.Uint) bool {
+	if !b.IsEnabled() || reference.IsZero() {
+		return false
+	}
+	deviation := price.Sub(reference)
+	if price.LT(reference) {
+		deviation = reference.Sub(price)
+	}
+	return deviation.MulUint64(bpsDivisor).GT(reference.MulUint64(uint64(b.MaxDeviationBps)))
+}
+
+func (b PriceBand) Validate() error {
+	if b.HaltBlocks < 0 {
+		return errors.New("halt blocks must not be negative")
+	}
+	if b.MaxBreaches > 0 && (!b.IsEnabled() || b.HaltBlocks == 0) {
+		return errors.New("halting markets requires a deviation limit and halt blocks")
+	}
+	return nil
+}
+
+func (b PriceBand) String() string {
+	if !b.IsEnabled() {
+		return "disabled"
+	}
+	return fmt.Sprintf("%d bps, halt %d blocks after %d breaches", b.MaxDeviationBps, b.HaltBlocks, b.MaxBreaches)
+}
+
+// CircuitBreaker is the price band state of a market: how many consecutive
+// batches breached its band, and the first height at which it is no longer
+// halted.
+type CircuitBreaker struct {
+	MarketID    store.EntityID `json:"market_id" yaml:"market_id"`
+	Breaches    uint16         `json:"breaches" yaml:"breaches"`
+	HaltedUntil int64          `json:"halted_until" yaml:"halted_until"`
+}
+
+// IsHalted returns true if the market is halted at height.
+func (c CircuitBreaker) IsHalted(height int64) bool {
+	return height < c.HaltedUntil
+}
+
+type HaltQueryResult struct {
+	MarketID    store.EntityID `json:"market_id" yaml:"market_id"`
+	Breaches    uint16         `json:"breaches" yaml:"breaches"`
+	HaltedUntil int64          `json:"halted_until" yaml:"halted_until"`
+	Halted      bool           `json:"halted" yaml:"halted"`
+}
@@ -6,6 +6,9 @@ var ModuleCdc *codec.Codec
 
 func RegisterCodec(cdc *codec.Codec) {
 	cdc.RegisterConcrete(MsgCreateMarket{}, "market/MsgCreateMarket", nil)
+	cdc.RegisterConcrete(MsgSetMarketStatus{}, "market/MsgSetMarketStatus", nil)
+	cdc.RegisterConcrete(MsgUpdateMarketParams{}, "market/MsgUpdateMarketParams", nil)
+	cdc.RegisterConcrete(ListMarketProposal{}, "market/ListMarketProposal", nil)
 }
 
 func init() {
@@ -0,0 +1,6 @@
+package types
+
+// Market module event attribute values
+const (
+	AttributeValueCategory = ModuleName
+)
@@ -0,0 +1,91 @@
+package types
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+)
+
+const (
+	// MaxFeeBps caps maker and taker fees at 10% of a fill's notional.
+	MaxFeeBps  = 1000
+	bpsDivisor = 10000
+)
+
+// FeeRecipients are the module accounts a market's fees may be sent to.
+// Fees are plain income to them. Module accounts whose balance is checked
+// by an invariant, such as the order module's escrow account or the staking
+// pools, would be broken by fees arriving outside of their bookkeeping, so
+// they must never be added.
+var FeeRecipients = []string{auth.FeeCollectorName}
+
+// FeeSchedule describes the fees a market charges on every fill, in basis
+// points of the fill's notional. Fees are collected in Denom, which must be
+// the market's base or quote asset; when it is empty each side pays in the
+// asset it receives. Fees are only ever taken from what a fill pays out, so
+// a side that is owed too little of Denom pays in the asset it receives
+// instead. Collected fees are sent to the Recipient module account, which
+// defaults to the fee collector and must be one of the FeeRecipients.
+type FeeSchedule struct {
+	MakerFeeBps uint16 `json:"maker_fee_bps" yaml:"maker_fee_bps"`
+	TakerFeeBps uint16 `json:"taker_fee_bps" yaml:"taker_fee_bps"`
+	Denom       string `json:"denom,omitempty" yaml:"denom"`
+	Recipient   string `json:"recipient,omitempty" yaml:"recipient"`
+}
+
+func NewFeeSchedule(makerFeeBps, takerFeeBps uint16, denom, recipient string) FeeSchedule {
+	return FeeSchedule{
+		MakerFeeBps: makerFeeBps,
+		TakerFeeBps: takerFeeBps,
+		Denom:       denom,
+		Recipient:   recipient,
+	}
+}
+
+// Rate returns the fee rate in basis points for the maker or taker side of
+// a fill.
+func (f FeeSchedule) Rate(maker bool) uint16 {
+	if maker {
+		return f.MakerFeeBps
+	}
+	return f.TakerFeeBps
+}
+
+// RecipientModule returns the name of the module account collecting fees.
+func (f FeeSchedule) RecipientModule() string {
+	if f.Recipient == "" {
+		return auth.FeeCollectorName
+	}
+	return f.Recipient
+}
+
+// Validate checks the fee schedule of a market trading baseAsset against
+// quoteAsset.
+func (f FeeSchedule) Validate(baseAsset, quoteAsset string) error {
+	if f.MakerFeeBps > MaxFeeBps || f.TakerFeeBps > MaxFeeBps {
+		return fmt.Errorf("fees must not exceed %d bps", MaxFeeBps)
+	}
+	if f.Denom != "" && f.Denom != baseAsset && f.Denom != quoteAsset {
+		return fmt.Errorf("fee denom must be %s or %s", baseAsset, quoteAsset)
+	}
+	for _, recipient := range FeeRecipients {
+		if f.RecipientModule() == recipient {
+			return nil
+		}
+	}
+	return fmt.Errorf("fee recipient must be one of %v, not '%s'", FeeRecipients, f.RecipientModule())
+}
+
+func (f FeeSchedule) String() string {
+	denom := f.Denom
+	if denom == "" {
+		denom = "received asset"
+	}
+	return fmt.Sprintf("maker %d bps, taker %d bps in %s to %s", f.MakerFeeBps, f.TakerFeeBps, denom, f.RecipientModule())
+}
+
+// FeeAmount returns the fee at rate basis points of amount, rounded down.
+func FeeAmount(amount sdk.Int, rate uint16) sdk.Int {
+	return amount.MulRaw(int64(rate)).QuoRaw(bpsDivisor)
+}
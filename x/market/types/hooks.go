@@ -0,0 +1,15 @@
+package types
+
+import (
+	"github.com/xar-network/xar-network/types/store"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// MarketHooks event hooks for market objects
+type MarketHooks interface {
+	// AfterMarketDelisted is called once a market has been delisted, so
+	// that its orders can be refunded. Returning an error aborts the
+	// delisting.
+	AfterMarketDelisted(ctx sdk.Context, mktID store.EntityID) sdk.Error
+}
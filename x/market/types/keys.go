@@ -9,9 +9,4 @@ const (
 
 	// RouterKey Top level router key
 	RouterKey = ModuleName
-
-	// DefaultParamspace default name for parameter store
-	DefaultParamspace = ModuleName
-
-	ModuleNominees = "marketnominees"
 )
@@ -0,0 +1,59 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+const (
+	// MatchingModeBatch clears the market once per block with a uniform-price
+	// frequent batch auction.
+	MatchingModeBatch MatchingMode = iota
+	// MatchingModeContinuous matches every order as it is posted against a
+	// price-time priority book, filling at the resting order's price.
+	MatchingModeContinuous
+)
+
+type MatchingMode uint8
+
+func (m MatchingMode) String() string {
+	if m == MatchingModeContinuous {
+		return "CONTINUOUS"
+	}
+
+	return "BATCH"
+}
+
+func (m MatchingMode) IsValid() bool {
+	return m == MatchingModeBatch || m == MatchingModeContinuous
+}
+
+func MatchingModeFromString(str string) (MatchingMode, error) {
+	switch str {
+	case "BATCH", "batch":
+		return MatchingModeBatch, nil
+	case "CONTINUOUS", "continuous":
+		return MatchingModeContinuous, nil
+	default:
+		return MatchingModeBatch, errors.New("invalid matching mode")
+	}
+}
+
+func (m *MatchingMode) UnmarshalJSON(data []byte) error {
+	var str string
+	err := json.Unmarshal(data, &str)
+	if err != nil {
+		return err
+	}
+
+	mode, err := MatchingModeFromString(str)
+	if err != nil {
+		return err
+	}
+	*m = mode
+	return nil
+}
+
+func (m MatchingMode) MarshalJSON() ([]byte, error) {
+	return []byte("\"" + m.String() + "\""), nil
+}
@@ -1,40 +1,79 @@
 package types
 
-import sdk "github.com/cosmos/cosmos-sdk/types"
+import (
+	"github.com/xar-network/xar-network/types/store"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
 
 var (
 	_ sdk.Msg = MsgCreateMarket{}
 )
 
 type MsgCreateMarket struct {
-	Nominee    sdk.AccAddress `json:"nominee" yaml:"nominee"`
-	BaseAsset  string         `json:"base_asset" yaml:"base_asset"`
-	QuoteAsset string         `json:"quote_asset" yaml:"quote_asset"`
+	Nominee      sdk.AccAddress `json:"nominee" yaml:"nominee"`
+	BaseAsset    string         `json:"base_asset" yaml:"base_asset"`
+	QuoteAsset   string         `json:"quote_asset" yaml:"quote_asset"`
+	MatchingMode MatchingMode   `json:"matching_mode" yaml:"matching_mode"`
+	Fees         FeeSchedule    `json:"fees" yaml:"fees"`
+	Rules        TradingRules   `json:"rules" yaml:"rules"`
+	Band         PriceBand      `json:"price_band" yaml:"price_band"`
 }
 
 func NewMsgCreateMarket(
 	nominee sdk.AccAddress,
 	baseAsset string,
 	quoteAsset string,
+	mode MatchingMode,
 ) MsgCreateMarket {
 	return MsgCreateMarket{
-		Nominee:    nominee,
-		BaseAsset:  baseAsset,
-		QuoteAsset: quoteAsset,
+		Nominee:      nominee,
+		BaseAsset:    baseAsset,
+		QuoteAsset:   quoteAsset,
+		MatchingMode: mode,
+	}
+}
+
+// MarketParams returns the market the message creates.
+func (msg MsgCreateMarket) MarketParams() MarketParams {
+	return MarketParams{
+		BaseAsset:    msg.BaseAsset,
+		QuoteAsset:   msg.QuoteAsset,
+		MatchingMode: msg.MatchingMode,
+		Fees:         msg.Fees,
+		Rules:        msg.Rules,
+		Band:         msg.Band,
 	}
 }
+
 func (msg MsgCreateMarket) Route() string { return ModuleName }
 
 func (msg MsgCreateMarket) Type() string { return "createMarket" }
 
 func (msg MsgCreateMarket) ValidateBasic() sdk.Error {
+	// whether the assets exist in supply is checked by the keeper
 	if msg.BaseAsset == "" {
 		return sdk.ErrInvalidAddress("missing base asset")
 	}
 
-	//TODO check if asset exists in supply
 	if msg.QuoteAsset == "" {
-		return sdk.ErrInvalidAddress("missing base asset")
+		return sdk.ErrInvalidAddress("missing quote asset")
+	}
+
+	if !msg.MatchingMode.IsValid() {
+		return sdk.ErrUnknownRequest("invalid matching mode")
+	}
+
+	if err := msg.Fees.Validate(msg.BaseAsset, msg.QuoteAsset); err != nil {
+		return sdk.ErrUnknownRequest(err.Error())
+	}
+
+	if err := msg.Rules.Validate(); err != nil {
+		return sdk.ErrUnknownRequest(err.Error())
+	}
+
+	if err := msg.Band.Validate(); err != nil {
+		return sdk.ErrUnknownRequest(err.Error())
 	}
 
 	if msg.Nominee.Empty() {
@@ -51,3 +90,101 @@ func (msg MsgCreateMarket) GetSigners() []sdk.AccAddress {
 func (msg MsgCreateMarket) GetSignBytes() []byte {
 	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(msg))
 }
+
+var (
+	_ sdk.Msg = MsgSetMarketStatus{}
+	_ sdk.Msg = MsgUpdateMarketParams{}
+)
+
+// MsgSetMarketStatus halts, resumes, restricts to cancels only or delists a
+// market. Delisting refunds all of the market's orders and is final.
+type MsgSetMarketStatus struct {
+	Nominee  sdk.AccAddress `json:"nominee" yaml:"nominee"`
+	MarketID store.EntityID `json:"market_id" yaml:"market_id"`
+	Status   MarketStatus   `json:"status" yaml:"status"`
+}
+
+func NewMsgSetMarketStatus(nominee sdk.AccAddress, mktID store.EntityID, status MarketStatus) MsgSetMarketStatus {
+	return MsgSetMarketStatus{
+		Nominee:  nominee,
+		MarketID: mktID,
+		Status:   status,
+	}
+}
+
+func (msg MsgSetMarketStatus) Route() string { return ModuleName }
+
+func (msg MsgSetMarketStatus) Type() string { return "setMarketStatus" }
+
+func (msg MsgSetMarketStatus) ValidateBasic() sdk.Error {
+	if msg.Nominee.Empty() {
+		return sdk.ErrInvalidAddress("missing nominee address")
+	}
+	if !msg.MarketID.IsDefined() {
+		return sdk.ErrUnknownRequest("invalid market ID")
+	}
+	if !msg.Status.IsValid() {
+		return sdk.ErrUnknownRequest("invalid market status")
+	}
+	return nil
+}
+
+func (msg MsgSetMarketStatus) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Nominee}
+}
+
+func (msg MsgSetMarketStatus) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(msg))
+}
+
+// MsgUpdateMarketParams replaces the fees, trading rules or price band of a
+// market. Parameters that are nil are left unchanged.
+type MsgUpdateMarketParams struct {
+	Nominee  sdk.AccAddress `json:"nominee" yaml:"nominee"`
+	MarketID store.EntityID `json:"market_id" yaml:"market_id"`
+	Fees     *FeeSchedule   `json:"fees,omitempty" yaml:"fees"`
+	Rules    *TradingRules  `json:"rules,omitempty" yaml:"rules"`
+	Band     *PriceBand     `json:"price_band,omitempty" yaml:"price_band"`
+}
+
+func NewMsgUpdateMarketParams(nominee sdk.AccAddress, mktID store.EntityID) MsgUpdateMarketParams {
+	return MsgUpdateMarketParams{
+		Nominee:  nominee,
+		MarketID: mktID,
+	}
+}
+
+func (msg MsgUpdateMarketParams) Route() string { return ModuleName }
+
+func (msg MsgUpdateMarketParams) Type() string { return "updateMarketParams" }
+
+func (msg MsgUpdateMarketParams) ValidateBasic() sdk.Error {
+	if msg.Nominee.Empty() {
+		return sdk.ErrInvalidAddress("missing nominee address")
+	}
+	if !msg.MarketID.IsDefined() {
+		return sdk.ErrUnknownRequest("invalid market ID")
+	}
+	if msg.Fees == nil && msg.Rules == nil && msg.Band == nil {
+		return sdk.ErrUnknownRequest("no parameters to update")
+	}
+	if msg.Rules != nil {
+		if err := msg.Rules.Validate(); err != nil {
+			return sdk.ErrUnknownRequest(err.Error())
+		}
+	}
+	if msg.Band != nil {
+		if err := msg.Band.Validate(); err != nil {
+			return sdk.ErrUnknownRequest(err.Error())
+		}
+	}
+	return nil
+}
+
+func (msg MsgUpdateMarketParams) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Nominee}
+}
+
+func (msg MsgUpdateMarketParams) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(msg))
+}
@@ -0,0 +1,107 @@
+package types
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types"
+)
+
+const (
+	// ProposalTypeListMarket defines the type for a ListMarketProposal
+	ProposalTypeListMarket = "ListMarket"
+)
+
+var _ govtypes.Content = ListMarketProposal{}
+
+func init() {
+	govtypes.RegisterProposalType(ProposalTypeListMarket)
+	govtypes.RegisterProposalTypeCodec(ListMarketProposal{}, "market/ListMarketProposal")
+}
+
+// ListMarketProposal creates a market through governance instead of a
+// market admin.
+type ListMarketProposal struct {
+	Title        string       `json:"title" yaml:"title"`
+	Description  string       `json:"description" yaml:"description"`
+	BaseAsset    string       `json:"base_asset" yaml:"base_asset"`
+	QuoteAsset   string       `json:"quote_asset" yaml:"quote_asset"`
+	MatchingMode MatchingMode `json:"matching_mode" yaml:"matching_mode"`
+	Fees         FeeSchedule  `json:"fees" yaml:"fees"`
+	Rules        TradingRules `json:"rules" yaml:"rules"`
+	Band         PriceBand    `json:"price_band" yaml:"price_band"`
+}
+
+func NewListMarketProposal(title, description, baseAsset, quoteAsset string, mode MatchingMode, fees FeeSchedule, rules TradingRules, band PriceBand) ListMarketProposal {
+	return ListMarketProposal{
+		Title:        title,
+		Description:  description,
+		BaseAsset:    baseAsset,
+		QuoteAsset:   quoteAsset,
+		MatchingMode: mode,
+		Fees:         fees,
+		Rules:        rules,
+		Band:         band,
+	}
+}
+
+// MarketParams returns the market the proposal lists.
+func (p ListMarketProposal) MarketParams() MarketParams {
+	return MarketParams{
+		BaseAsset:    p.BaseAsset,
+		QuoteAsset:   p.QuoteAsset,
+		MatchingMode: p.MatchingMode,
+		Fees:         p.Fees,
+		Rules:        p.Rules,
+		Band:         p.Band,
+	}
+}
+
+func (p ListMarketProposal) GetTitle() string { return p.Title }
+
+func (p ListMarketProposal) GetDescription() string { return p.Description }
+
+func (p ListMarketProposal) ProposalRoute() string { return RouterKey }
+
+func (p ListMarketProposal) ProposalType() string { return ProposalTypeListMarket }
+
+func (p ListMarketProposal) ValidateBasic() sdk.Error {
+	if err := govtypes.ValidateAbstract(DefaultCodespace, p); err != nil {
+		return err
+	}
+	if p.BaseAsset == "" {
+		return sdk.ErrUnknownRequest("missing base asset")
+	}
+	if p.QuoteAsset == "" {
+		return sdk.ErrUnknownRequest("missing quote asset")
+	}
+	if p.BaseAsset == p.QuoteAsset {
+		return sdk.ErrUnknownRequest("base and quote assets must differ")
+	}
+	if !p.MatchingMode.IsValid() {
+		return sdk.ErrUnknownRequest("invalid matching mode")
+	}
+	if err := p.Fees.Validate(p.BaseAsset, p.QuoteAsset); err != nil {
+		return sdk.ErrUnknownRequest(err.Error())
+	}
+	if err := p.Rules.Validate(); err != nil {
+		return sdk.ErrUnknownRequest(err.Error())
+	}
+	if err := p.Band.Validate(); err != nil {
+		return sdk.ErrUnknownRequest(err.Error())
+	}
+	return nil
+}
+
+func (p ListMarketProposal) String() string {
+	return fmt.Sprintf(`List Market Proposal:
+  Title:         %s
+  Description:   %s
+  Base Asset:    %s
+  Quote Asset:   %s
+  Matching Mode: %s
+  Fees:          %s
+  Rules:         %s
+  Price Band:    %s
+`, p.Title, p.Description, p.BaseAsset, p.QuoteAsset, p.MatchingMode, p.Fees, p.Rules, p.Band)
+}
@@ -11,6 +11,8 @@ type NamedMarket struct {
 	BaseAssetDenom  string
 	QuoteAssetDenom string
 	Name            string
+	MatchingMode    MatchingMode
+	Status          MarketStatus
 }
 
 type ListQueryResult struct {
@@ -25,6 +27,8 @@ func (l ListQueryResult) String() string {
 		"Name",
 		"Base Asset ID",
 		"Quote Asset ID",
+		"Matching Mode",
+		"Status",
 	})
 
 	for _, m := range l.Markets {
@@ -33,6 +37,8 @@ func (l ListQueryResult) String() string {
 			m.Name,
 			m.BaseAssetDenom,
 			m.QuoteAssetDenom,
+			m.MatchingMode.String(),
+			m.Status.String(),
 		})
 	}
 
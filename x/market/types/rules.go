@@ -0,0 +1,103 @@
+package types
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/xar-network/xar-network/pkg/conv"
+	"github.com/xar-network/xar-network/pkg/matcheng"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// MaxDecimals is the highest asset precision a market can be configured
+// with.
+const MaxDecimals = 18
+
+// TradingRules bound the granularity of the orders a market accepts. Prices
+// must be a multiple of TickSize and quantities a multiple of LotSize, and
+// every order must be worth at least MinNotional of the quote asset. Zero
+// sizes impose no restriction. Prices are expressed in the smallest unit of
+// the quote asset per whole unit of the base asset, so the assets' decimals
+// determine how prices and quantities convert into the quote asset. Zero
+// decimals default to matcheng.AssetDecimals.
+type TradingRules struct {
+	TickSize      uint64 `json:"tick_size" yaml:"tick_size"`
+	LotSize       uint64 `json:"lot_size" yaml:"lot_size"`
+	MinNotional   uint64 `json:"min_notional" yaml:"min_notional"`
+	BaseDecimals  uint8  `json:"base_decimals" yaml:"base_decimals"`
+	QuoteDecimals uint8  `json:"quote_decimals" yaml:"quote_decimals"`
+}
+
+func NewTradingRules(tickSize, lotSize, minNotional uint64, baseDecimals, quoteDecimals uint8) TradingRules {
+	return TradingRules{
+		TickSize:      tickSize,
+		LotSize:       lotSize,
+		MinNotional:   minNotional,
+		BaseDecimals:  baseDecimals,
+		QuoteDecimals: quoteDecimals,
+	}
+}
+
+// BaseAssetDecimals returns the precision of the market's base asset.
+func (r TradingRules) BaseAssetDecimals() uint8 {
+	if r.BaseDecimals == 0 {
+		return matcheng.AssetDecimals
+	}
+	return r.BaseDecimals
+}
+
+// QuoteAssetDecimals returns the precision of the market's quote asset.
+func (r TradingRules) QuoteAssetDecimals() uint8 {
+	if r.QuoteDecimals == 0 {
+		return matcheng.AssetDecimals
+	}
+	return r.QuoteDecimals
+}
+
+func (r TradingRules) Validate() error {
+	if r.BaseDecimals > MaxDecimals || r.QuoteDecimals > MaxDecimals {
+		return fmt.Errorf("decimals must not exceed %d", MaxDecimals)
+	}
+	return nil
+}
+
+// CheckPrice returns an error if price is not a multiple of the tick size.
+func (r TradingRules) CheckPrice(price sdk.Uint) error {
+	if !isMultiple(price, r.TickSize) {
+		return fmt.Errorf("price must be a multiple of the tick size %d", r.TickSize)
+	}
+	return nil
+}
+
+// CheckQuantity returns an error if quantity is not a multiple of the lot
+// size.
+func (r TradingRules) CheckQuantity(quantity sdk.Uint) error {
+	if !isMultiple(quantity, r.LotSize) {
+		return fmt.Errorf("quantity must be a multiple of the lot size %d", r.LotSize)
+	}
+	return nil
+}
+
+// CheckNotional returns an error if an order worth notional of the quote
+// asset is below the minimum notional.
+func (r TradingRules) CheckNotional(notional sdk.Uint) error {
+	if notional.LT(sdk.NewUint(r.MinNotional)) {
+		return fmt.Errorf("order must be worth at least %d of the quote asset", r.MinNotional)
+	}
+	return nil
+}
+
+func (r TradingRules) String() string {
+	return fmt.Sprintf("tick %d, lot %d, min notional %d, decimals %d/%d",
+		r.TickSize, r.LotSize, r.MinNotional, r.BaseAssetDecimals(), r.QuoteAssetDecimals())
+}
+
+// isMultiple returns true if u is a multiple of size, or size is zero.
+func isMultiple(u sdk.Uint, size uint64) bool {
+	if size == 0 {
+		return true
+	}
+	mod := new(big.Int).Mod(conv.SDKUint2Big(u), new(big.Int).SetUint64(size))
+	return mod.Sign() == 0
+}
@@ -0,0 +1,85 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+const (
+	// MarketActive markets accept, match and cancel orders.
+	MarketActive MarketStatus = iota
+	// MarketHalted markets are frozen: orders are neither accepted, matched
+	// nor cancelled until the market is resumed.
+	MarketHalted
+	// MarketCancelOnly markets only let owners cancel their open orders.
+	MarketCancelOnly
+	// MarketDelisted markets had all of their orders refunded and can no
+	// longer be traded or changed.
+	MarketDelisted
+)
+
+// MarketStatus is the stage of a market's lifecycle.
+type MarketStatus uint8
+
+var marketStatusNames = map[MarketStatus]string{
+	MarketActive:     "ACTIVE",
+	MarketHalted:     "HALTED",
+	MarketCancelOnly: "CANCEL_ONLY",
+	MarketDelisted:   "DELISTED",
+}
+
+func (s MarketStatus) String() string {
+	name, ok := marketStatusNames[s]
+	if !ok {
+		return "UNKNOWN"
+	}
+	return name
+}
+
+func (s MarketStatus) IsValid() bool {
+	_, ok := marketStatusNames[s]
+	return ok
+}
+
+// AcceptsOrders returns true if new orders may be posted.
+func (s MarketStatus) AcceptsOrders() bool {
+	return s == MarketActive
+}
+
+// AcceptsCancels returns true if owners may cancel their orders.
+func (s MarketStatus) AcceptsCancels() bool {
+	return s == MarketActive || s == MarketCancelOnly
+}
+
+// IsTradable returns true if orders are matched.
+func (s MarketStatus) IsTradable() bool {
+	return s == MarketActive
+}
+
+func MarketStatusFromString(str string) (MarketStatus, error) {
+	for s, name := range marketStatusNames {
+		if name == str {
+			return s, nil
+		}
+	}
+	return MarketActive, errors.New("invalid market status")
+}
+
+func (s *MarketStatus) UnmarshalJSON(data []byte) error {
+	var str string
+	err := json.Unmarshal(data, &str)
+	if err != nil {
+		return err
+	}
+
+	out, err := MarketStatusFromString(str)
+	if err != nil {
+		return err
+	}
+	*s = out
+	return nil
+}
+
+func (s MarketStatus) MarshalJSON() ([]byte, error) {
+	return []byte("\"" + s.String() + "\""), nil
+}
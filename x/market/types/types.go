@@ -3,7 +3,10 @@ package types
 import (
 	"fmt"
 
+	"github.com/xar-network/xar-network/pkg/matcheng"
 	"github.com/xar-network/xar-network/types/store"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
 )
 
 type Markets []Market
@@ -12,25 +15,55 @@ type Market struct {
 	ID              store.EntityID `json:"id" yaml:"id"`
 	BaseAssetDenom  string         `json:"base_asset_denom" yaml:"base_asset_denom"`
 	QuoteAssetDenom string         `json:"quote_asset_denom" yaml:"quote_asset_denom"`
+	MatchingMode    MatchingMode   `json:"matching_mode" yaml:"matching_mode"`
+	Fees            FeeSchedule    `json:"fees" yaml:"fees"`
+	Rules           TradingRules   `json:"rules" yaml:"rules"`
+	Band            PriceBand      `json:"price_band" yaml:"price_band"`
+	Status          MarketStatus   `json:"status" yaml:"status"`
 }
 
 func NewMarket(
 	id store.EntityID,
 	baseAsset string,
 	quoteAsset string,
+	mode MatchingMode,
 ) Market {
 	return Market{
 		ID:              id,
 		BaseAssetDenom:  baseAsset,
 		QuoteAssetDenom: quoteAsset,
+		MatchingMode:    mode,
 	}
 }
 
+// MarketParams describe a market to create. The zero values of the fees,
+// rules and band charge no fees, accept orders of any granularity and leave
+// the clearing prices unguarded.
+type MarketParams struct {
+	BaseAsset    string
+	QuoteAsset   string
+	MatchingMode MatchingMode
+	Fees         FeeSchedule
+	Rules        TradingRules
+	Band         PriceBand
+}
+
 // implement fmt.Stringer
 func (m Market) String() string {
 	return fmt.Sprintf(`Market:
 	ID: %s
 	Base Asset: %s
-	Quote Asset: %s`,
-		m.ID.String(), m.BaseAssetDenom, m.QuoteAssetDenom)
+	Quote Asset: %s
+	Matching Mode: %s
+	Status: %s
+	Fees: %s
+	Rules: %s
+	Price Band: %s`,
+		m.ID.String(), m.BaseAssetDenom, m.QuoteAssetDenom, m.MatchingMode, m.Status, m.Fees, m.Rules, m.Band)
+}
+
+// QuoteQuantity converts a quantity of the market's base asset into its
+// quote asset at price, rounding down.
+func (m Market) QuoteQuantity(price sdk.Uint, quantity sdk.Uint) (sdk.Uint, error) {
+	return matcheng.NormalizeQuoteQuantityWithDecimals(price, quantity, m.Rules.BaseAssetDecimals())
 }
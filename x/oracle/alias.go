@@ -20,6 +20,7 @@ type (
 	PostedPrice        = types.PostedPrice
 	SortDecs           = types.SortDecs
 	Keeper             = keeper.Keeper
+	AddAssetProposal   = types.AddAssetProposal
 )
 
 const (
@@ -29,6 +30,14 @@ const (
 	QuerierRoute      = types.QuerierRoute
 	DefaultParamspace = types.DefaultParamspace
 	StoreKey          = types.StoreKey
+
+	EventTypePostPrice     = types.EventTypePostPrice
+	EventTypeCurrentPrice  = types.EventTypeCurrentPrice
+	AttributeValueCategory = types.AttributeValueCategory
+	AttributeKeyAssetCode  = types.AttributeKeyAssetCode
+	AttributeKeyOracle     = types.AttributeKeyOracle
+	AttributeKeyPrice      = types.AttributeKeyPrice
+	AttributeKeyExpiry     = types.AttributeKeyExpiry
 )
 
 var (
@@ -49,4 +58,5 @@ var (
 	NewParams           = types.NewParams
 	DefaultParams       = types.DefaultParams
 	NewQuerier          = keeper.NewQuerier
+	NewAddAssetProposal = types.NewAddAssetProposal
 )
@@ -7,6 +7,7 @@ import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/x/mock"
 	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/xar-network/xar-network/x/authority"
 	"github.com/xar-network/xar-network/x/oracle"
 	"github.com/xar-network/xar-network/x/oracle/internal/types"
 
@@ -109,7 +110,6 @@ func TestApp_PostPrice(t *testing.T) {
 			},
 		},
 	}
-	oracleParams.Nominees = []string{addrs[0].String()}
 
 	keeper.SetParams(ctx, oracleParams)
 	_, _ = keeper.SetPrice(
@@ -144,13 +144,15 @@ func setUpMockAppWithoutGenesis() (*mock.App, oracle.Keeper) {
 
 	// Create keepers
 	keyOracle := sdk.NewKVStoreKey(oracle.StoreKey)
+	keyAuthority := sdk.NewKVStoreKey(authority.StoreKey)
 
-	oracleKeeper := oracle.NewKeeper(keyOracle, mapp.Cdc, mapp.ParamsKeeper.Subspace(oracle.DefaultParamspace), oracle.DefaultCodespace)
+	authorityKeeper := authority.NewKeeper(keyAuthority, mapp.Cdc, authority.DefaultCodespace)
+	oracleKeeper := oracle.NewKeeper(keyOracle, mapp.Cdc, mapp.ParamsKeeper.Subspace(oracle.DefaultParamspace), authorityKeeper, oracle.DefaultCodespace)
 
 	// Register routes
 	mapp.Router().AddRoute("oracle", oracle.NewHandler(oracleKeeper))
 	// Mount and load the stores
-	err := mapp.CompleteSetup(keyOracle)
+	err := mapp.CompleteSetup(keyOracle, keyAuthority)
 	if err != nil {
 		panic("mock app setup failed")
 	}
@@ -11,7 +11,10 @@ import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/x/auth"
 	"github.com/cosmos/cosmos-sdk/x/auth/client/utils"
+	govcli "github.com/cosmos/cosmos-sdk/x/gov/client/cli"
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 	tmtime "github.com/tendermint/tendermint/types/time"
 	"github.com/xar-network/xar-network/x/oracle/internal/types"
 )
@@ -194,3 +197,40 @@ func getCmdSetAsset(cdc *codec.Codec) *cobra.Command {
 		},
 	}
 }
+
+// GetCmdSubmitAddAssetProposal is registered as a gov submit-proposal subcommand
+func GetCmdSubmitAddAssetProposal(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "add-oracle-asset [denom] [quote_asset] [oracles]",
+		Example: "xarcli tx gov submit-proposal add-oracle-asset xar quote_asset xar17up20gamd0vh6g9ne0uh67hx8xhyfrv2lyazgu --title=... --description=... --deposit=...",
+		Short:   "Submit a proposal to add an oracle asset",
+		Args:    cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			inBuf := bufio.NewReader(cmd.InOrStdin())
+			txBldr := auth.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
+			cliCtx := context.NewCLIContextWithInput(inBuf).WithCodec(cdc)
+
+			oracles, err := types.ParseOracles(args[2])
+			if err != nil {
+				return err
+			}
+			deposit, err := sdk.ParseCoins(viper.GetString(govcli.FlagDeposit))
+			if err != nil {
+				return err
+			}
+
+			asset := types.NewAsset(args[0], args[0], args[1], oracles, true)
+			content := types.NewAddAssetProposal(viper.GetString(govcli.FlagTitle), viper.GetString(govcli.FlagDescription), asset)
+			msg := govtypes.NewMsgSubmitProposal(content, deposit, cliCtx.GetFromAddress())
+			if err := msg.ValidateBasic(); err != nil {
+				return err
+			}
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+	cmd.Flags().String(govcli.FlagTitle, "", "title of proposal")
+	cmd.Flags().String(govcli.FlagDescription, "", "description of proposal")
+	cmd.Flags().String(govcli.FlagDeposit, "", "deposit of proposal")
+
+	return cmd
+}
@@ -0,0 +1,10 @@
+package client
+
+import (
+	govclient "github.com/cosmos/cosmos-sdk/x/gov/client"
+	"github.com/xar-network/xar-network/x/oracle/client/cli"
+	"github.com/xar-network/xar-network/x/oracle/client/rest"
+)
+
+// AddAssetProposalHandler lets governance add oracle assets
+var AddAssetProposalHandler = govclient.NewProposalHandler(cli.GetCmdSubmitAddAssetProposal, rest.ProposalRESTHandler)
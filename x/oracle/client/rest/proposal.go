@@ -0,0 +1,54 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/cosmos/cosmos-sdk/types/rest"
+	"github.com/xar-network/xar-network/x/oracle/internal/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth/client/utils"
+	govrest "github.com/cosmos/cosmos-sdk/x/gov/client/rest"
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types"
+)
+
+type addAssetProposalReq struct {
+	BaseReq     rest.BaseReq   `json:"base_req"`
+	Title       string         `json:"title"`
+	Description string         `json:"description"`
+	Asset       types.Asset    `json:"asset"`
+	Proposer    sdk.AccAddress `json:"proposer"`
+	Deposit     sdk.Coins      `json:"deposit"`
+}
+
+// ProposalRESTHandler returns the add asset proposal REST handler
+func ProposalRESTHandler(cliCtx context.CLIContext) govrest.ProposalRESTHandler {
+	return govrest.ProposalRESTHandler{
+		SubRoute: "add_oracle_asset",
+		Handler:  postAddAssetProposalHandler(cliCtx),
+	}
+}
+
+func postAddAssetProposalHandler(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req addAssetProposalReq
+		if !rest.ReadRESTReq(w, r, cliCtx.Codec, &req) {
+			return
+		}
+
+		req.BaseReq = req.BaseReq.Sanitize()
+		if !req.BaseReq.ValidateBasic(w) {
+			return
+		}
+
+		content := types.NewAddAssetProposal(req.Title, req.Description, req.Asset)
+		msg := govtypes.NewMsgSubmitProposal(content, req.Deposit, req.Proposer)
+		if err := msg.ValidateBasic(); err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		utils.WriteGenerateStdTxResponse(w, cliCtx, req.BaseReq, []sdk.Msg{msg})
+	}
+}
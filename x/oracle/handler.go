@@ -2,9 +2,12 @@ package oracle
 
 import (
 	"fmt"
+	"time"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types"
 	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/xar-network/xar-network/x/oracle/internal/keeper"
 	"github.com/xar-network/xar-network/x/oracle/internal/types"
 )
 
@@ -29,6 +32,19 @@ func NewHandler(k Keeper) sdk.Handler {
 	}
 }
 
+// NewProposalHandler handles oracle governance proposals
+func NewProposalHandler(k Keeper, sk types.SupplyKeeper) govtypes.Handler {
+	return func(ctx sdk.Context, content govtypes.Content) sdk.Error {
+		switch c := content.(type) {
+		case types.AddAssetProposal:
+			return keeper.HandleAddAssetProposal(ctx, k, sk, c)
+		default:
+			errMsg := fmt.Sprintf("unrecognized oracle proposal content type: %T", c)
+			return sdk.ErrUnknownRequest(errMsg)
+		}
+	}
+}
+
 // price feed questions:
 // do proposers need to post the round in the message? If not, how do we determine the round?
 
@@ -47,7 +63,25 @@ func HandleMsgPostPrice(
 	if er != nil {
 		return types.ErrInvalidOracle(k.Codespace()).Result()
 	}
-	k.SetPrice(ctx, msg.From, msg.AssetCode, msg.Price, msg.Expiry)
+	// expired prices are ignored
+	if posted, err := k.SetPrice(ctx, msg.From, msg.AssetCode, msg.Price, msg.Expiry); err == nil {
+		ctx.EventManager().EmitEvent(
+			sdk.NewEvent(
+				types.EventTypePostPrice,
+				sdk.NewAttribute(types.AttributeKeyAssetCode, posted.AssetCode),
+				sdk.NewAttribute(types.AttributeKeyOracle, posted.OracleAddress.String()),
+				sdk.NewAttribute(types.AttributeKeyPrice, posted.Price.String()),
+				sdk.NewAttribute(types.AttributeKeyExpiry, posted.Expiry.UTC().Format(time.RFC3339)),
+			),
+		)
+	}
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			sdk.EventTypeMessage,
+			sdk.NewAttribute(sdk.AttributeKeyModule, types.AttributeValueCategory),
+			sdk.NewAttribute(sdk.AttributeKeySender, msg.From.String()),
+		),
+	)
 	return sdk.Result{Events: ctx.EventManager().Events()}
 }
 
@@ -65,6 +99,11 @@ func handleMsgAddOracle(
 	if er == nil {
 		return types.ErrInvalidOracle(k.Codespace()).Result()
 	}
+	action := msg
+	action.Nominee = nil
+	if ok, err := k.Approve(ctx, msg.Nominee, action); !ok {
+		return pendingResult(ctx, err)
+	}
 	er = k.AddOracle(ctx, msg.Nominee.String(), msg.Denom, msg.Oracle)
 	if er != nil {
 		return sdk.ErrInternal(er.Error()).Result()
@@ -86,6 +125,11 @@ func handleMsgSetOracles(
 	if !found {
 		return types.ErrInvalidAsset(k.Codespace()).Result()
 	}
+	action := msg
+	action.Nominee = nil
+	if ok, err := k.Approve(ctx, msg.Nominee, action); !ok {
+		return pendingResult(ctx, err)
+	}
 	er := k.SetOracles(ctx, msg.Nominee.String(), msg.Denom, msg.Oracles)
 	if er != nil {
 		return sdk.ErrInternal(er.Error()).Result()
@@ -107,6 +151,11 @@ func handleMsgSetAsset(
 	if !found {
 		return types.ErrInvalidAsset(k.Codespace()).Result()
 	}
+	action := msg
+	action.Nominee = nil
+	if ok, err := k.Approve(ctx, msg.Nominee, action); !ok {
+		return pendingResult(ctx, err)
+	}
 	er := k.SetAsset(ctx, msg.Nominee.String(), msg.Denom, msg.Asset)
 	if er != nil {
 		return sdk.ErrInternal(er.Error()).Result()
@@ -128,6 +177,11 @@ func handleMsgAddAsset(
 	if found {
 		return types.ErrExistingAsset(k.Codespace()).Result()
 	}
+	action := msg
+	action.Nominee = nil
+	if ok, err := k.Approve(ctx, msg.Nominee, action); !ok {
+		return pendingResult(ctx, err)
+	}
 	er := k.AddAsset(ctx, msg.Nominee.String(), msg.Denom, msg.Asset)
 	if er != nil {
 		return sdk.ErrInternal(er.Error()).Result()
@@ -135,6 +189,16 @@ func handleMsgAddAsset(
 	return sdk.Result{Events: ctx.EventManager().Events()}
 }
 
+// pendingResult is the result of a message that was not executed, either
+// because its nominee is not an oracle admin or because it still needs the
+// approval of other oracle admins.
+func pendingResult(ctx sdk.Context, err sdk.Error) sdk.Result {
+	if err != nil {
+		return err.Result()
+	}
+	return sdk.Result{Events: ctx.EventManager().Events()}
+}
+
 // EndBlocker updates the current oracle
 func EndBlocker(ctx sdk.Context, k Keeper) []abci.ValidatorUpdate {
 	// TODO val_state_change.go is relevant if we want to rotate the oracle set
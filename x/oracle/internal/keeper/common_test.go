@@ -9,27 +9,31 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/tendermint/tendermint/crypto"
 
+	"github.com/xar-network/xar-network/x/authority"
 	"github.com/xar-network/xar-network/x/oracle/internal/keeper"
 	"github.com/xar-network/xar-network/x/oracle/internal/types"
 )
 
 type testHelper struct {
-	mApp     *mock.App
-	keeper   keeper.Keeper
-	addrs    []sdk.AccAddress
-	pubKeys  []crypto.PubKey
-	privKeys []crypto.PrivKey
+	mApp      *mock.App
+	keeper    keeper.Keeper
+	authority authority.Keeper
+	addrs     []sdk.AccAddress
+	pubKeys   []crypto.PubKey
+	privKeys  []crypto.PrivKey
 }
 
 func getMockApp(t *testing.T, numGenAccs int, genState types.GenesisState, genAccs []authexported.Account) testHelper {
 	mApp := mock.NewApp()
 	types.RegisterCodec(mApp.Cdc)
 	keyPricefeed := sdk.NewKVStoreKey(types.StoreKey)
+	keyAuthority := sdk.NewKVStoreKey(authority.StoreKey)
 
 	pk := mApp.ParamsKeeper
-	keeper := keeper.NewKeeper(keyPricefeed, mApp.Cdc, pk.Subspace(types.DefaultParamspace), types.DefaultCodespace)
+	authorityKeeper := authority.NewKeeper(keyAuthority, mApp.Cdc, authority.DefaultCodespace)
+	keeper := keeper.NewKeeper(keyPricefeed, mApp.Cdc, pk.Subspace(types.DefaultParamspace), authorityKeeper, types.DefaultCodespace)
 
-	require.NoError(t, mApp.CompleteSetup(keyPricefeed))
+	require.NoError(t, mApp.CompleteSetup(keyPricefeed, keyAuthority))
 
 	valTokens := sdk.TokensFromConsensusPower(42)
 	var (
@@ -44,5 +48,5 @@ func getMockApp(t *testing.T, numGenAccs int, genState types.GenesisState, genAc
 	}
 
 	mock.SetGenesis(mApp, genAccs)
-	return testHelper{mApp, keeper, addrs, pubKeys, privKeys}
+	return testHelper{mApp, keeper, authorityKeeper, addrs, pubKeys, privKeys}
 }
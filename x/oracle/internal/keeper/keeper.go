@@ -7,6 +7,7 @@ import (
 	"github.com/cosmos/cosmos-sdk/codec"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/x/params"
+	"github.com/xar-network/xar-network/x/authority"
 	"github.com/xar-network/xar-network/x/oracle/internal/types"
 )
 
@@ -18,6 +19,8 @@ type Keeper struct {
 	cdc *codec.Codec
 	// The reference to the Paramstore to get and set oracle specific params
 	paramstore params.Subspace
+	// The authority keeper granting the oracle admin role
+	authority types.AuthorityKeeper
 	// Reserved codespace
 	codespace sdk.CodespaceType
 }
@@ -29,12 +32,14 @@ func NewKeeper(
 	storeKey sdk.StoreKey,
 	cdc *codec.Codec,
 	paramstore params.Subspace,
+	authority types.AuthorityKeeper,
 	codespace sdk.CodespaceType,
 ) Keeper {
 	return Keeper{
 		paramstore: paramstore.WithKeyTable(types.ParamKeyTable()),
 		storeKey:   storeKey,
 		cdc:        cdc,
+		authority:  authority,
 		codespace:  codespace,
 	}
 }
@@ -142,6 +147,13 @@ func (k Keeper) SetCurrentPrices(ctx sdk.Context) sdk.Error {
 			store.Set(
 				[]byte(types.CurrentPricePrefix+assetCode), k.cdc.MustMarshalBinaryBare(newPrice),
 			)
+			ctx.EventManager().EmitEvent(
+				sdk.NewEvent(
+					types.EventTypeCurrentPrice,
+					sdk.NewAttribute(types.AttributeKeyAssetCode, assetCode),
+					sdk.NewAttribute(types.AttributeKeyPrice, medianPrice.String()),
+				),
+			)
 		}
 	}
 
@@ -187,13 +199,17 @@ func (k Keeper) Codespace() sdk.CodespaceType {
 	return k.codespace
 }
 
+// IsNominee returns true if nominee is an active oracle admin.
 func (k Keeper) IsNominee(ctx sdk.Context, nominee string) bool {
-	params := k.GetParams(ctx)
-	nominees := params.Nominees
-	for _, v := range nominees {
-		if v == nominee {
-			return true
-		}
+	addr, err := sdk.AccAddressFromBech32(nominee)
+	if err != nil {
+		return false
 	}
-	return false
+	return k.authority.HasRole(ctx, authority.RoleOracleAdmin, addr)
+}
+
+// Approve records the nominee's approval of action and returns true once
+// enough oracle admins approved it for it to go ahead.
+func (k Keeper) Approve(ctx sdk.Context, nominee sdk.AccAddress, action sdk.Msg) (bool, sdk.Error) {
+	return k.authority.Approve(ctx, authority.RoleOracleAdmin, nominee, action)
 }
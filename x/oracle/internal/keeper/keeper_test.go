@@ -9,6 +9,7 @@ import (
 	abci "github.com/tendermint/tendermint/abci/types"
 	tmtime "github.com/tendermint/tendermint/types/time"
 
+	"github.com/xar-network/xar-network/x/authority"
 	"github.com/xar-network/xar-network/x/oracle/internal/types"
 )
 
@@ -63,9 +64,11 @@ func TestKeeper_SetAddAsset(t *testing.T) {
 		Assets: []types.Asset{
 			types.Asset{AssetCode: "tstusd", BaseAsset: "tst", QuoteAsset: "usd", Oracles: types.Oracles{}, Active: true},
 		},
-		Nominees: []string{helper.addrs[0].String()},
 	}
 	helper.keeper.SetParams(ctx, ap)
+	helper.authority.SetRole(ctx, authority.NewRole(authority.RoleOracleAdmin, 1, []authority.Grant{
+		authority.NewGrant(helper.addrs[0], time.Time{}),
+	}))
 	assets := helper.keeper.GetAssetParams(ctx)
 	require.Equal(t, len(assets), 1)
 	require.Equal(t, assets[0].AssetCode, "tstusd")
@@ -10,7 +10,7 @@ import (
 
 // GetParams gets params from the store
 func (k Keeper) GetParams(ctx sdk.Context) types.Params {
-	return types.NewParams(k.GetAssetParams(ctx), k.GetNomineeParams(ctx))
+	return types.NewParams(k.GetAssetParams(ctx))
 }
 
 // SetParams updates params in the store
@@ -25,13 +25,6 @@ func (k Keeper) GetAssetParams(ctx sdk.Context) types.Assets {
 	return assets
 }
 
-// GetNomineeParams get nominee params from store
-func (k Keeper) GetNomineeParams(ctx sdk.Context) []string {
-	var nominees []string
-	k.paramstore.Get(ctx, types.KeyNominees, &nominees)
-	return nominees
-}
-
 // GetOracles returns the oracles in the oracle store
 func (k Keeper) GetOracles(ctx sdk.Context, assetCode string) (types.Oracles, error) {
 
@@ -46,7 +39,7 @@ func (k Keeper) GetOracles(ctx sdk.Context, assetCode string) (types.Oracles, er
 // AddOracle returns the oracle in the oracle store
 func (k Keeper) AddOracle(ctx sdk.Context, nominee string, assetCode string, address sdk.AccAddress) error {
 	if !k.IsNominee(ctx, nominee) {
-		return fmt.Errorf("not a valid nominee %s", nominee)
+		return fmt.Errorf("not an oracle admin %s", nominee)
 	}
 	_, err := k.GetOracle(ctx, assetCode, address)
 	if err == nil {
@@ -76,7 +69,7 @@ func (k Keeper) AddOracle(ctx sdk.Context, nominee string, assetCode string, add
 // AddOracle returns the oracle in the oracle store
 func (k Keeper) SetOracles(ctx sdk.Context, nominee string, assetCode string, addresses types.Oracles) error {
 	if !k.IsNominee(ctx, nominee) {
-		return fmt.Errorf("not a valid nominee %s", nominee)
+		return fmt.Errorf("not an oracle admin %s", nominee)
 	}
 	assets := k.GetAssetParams(ctx)
 	updateAssets := assets[:0]
@@ -100,7 +93,7 @@ func (k Keeper) SetOracles(ctx sdk.Context, nominee string, assetCode string, ad
 // AddOracle returns the oracle in the oracle store
 func (k Keeper) SetAsset(ctx sdk.Context, nominee string, assetCode string, asset types.Asset) error {
 	if !k.IsNominee(ctx, nominee) {
-		return fmt.Errorf("not a valid nominee %s", nominee)
+		return fmt.Errorf("not an oracle admin %s", nominee)
 	}
 	assets := k.GetAssetParams(ctx)
 	updateAssets := assets[:0]
@@ -124,7 +117,7 @@ func (k Keeper) SetAsset(ctx sdk.Context, nominee string, assetCode string, asse
 // AddOracle returns the oracle in the oracle store
 func (k Keeper) AddAsset(ctx sdk.Context, nominee string, assetCode string, asset types.Asset) error {
 	if !k.IsNominee(ctx, nominee) {
-		return fmt.Errorf("not a valid nominee %s", nominee)
+		return fmt.Errorf("not an oracle admin %s", nominee)
 	}
 	_, exists := k.GetAsset(ctx, assetCode)
 	if exists {
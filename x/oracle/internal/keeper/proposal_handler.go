@@ -0,0 +1,25 @@
+package keeper
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/xar-network/xar-network/x/oracle/internal/types"
+)
+
+// HandleAddAssetProposal adds an asset to the oracle. Assets price tokens
+// of the chain, so the base asset has to exist in supply.
+func HandleAddAssetProposal(ctx sdk.Context, k Keeper, sk types.SupplyKeeper, p types.AddAssetProposal) sdk.Error {
+	if _, exists := k.GetAsset(ctx, p.Asset.AssetCode); exists {
+		return sdk.ErrUnknownRequest(fmt.Sprintf("asset already exists: '%s'", p.Asset.AssetCode))
+	}
+	if !sk.GetSupply(ctx).GetTotal().AmountOf(p.Asset.BaseAsset).IsPositive() {
+		return sdk.ErrUnknownRequest(fmt.Sprintf("asset not in supply: '%s'", p.Asset.BaseAsset))
+	}
+
+	params := k.GetParams(ctx)
+	params.Assets = append(params.Assets, p.Asset)
+	k.SetParams(ctx, params)
+	return nil
+}
@@ -0,0 +1,47 @@
+package keeper_test
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/supply"
+	"github.com/cosmos/cosmos-sdk/x/supply/exported"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	tmtime "github.com/tendermint/tendermint/types/time"
+
+	"github.com/xar-network/xar-network/x/oracle/internal/keeper"
+	"github.com/xar-network/xar-network/x/oracle/internal/types"
+)
+
+type fixedSupply sdk.Coins
+
+func (s fixedSupply) GetSupply(sdk.Context) exported.SupplyI {
+	return supply.NewSupply(sdk.Coins(s))
+}
+
+func TestKeeper_AddAssetProposal(t *testing.T) {
+	helper := getMockApp(t, 1, types.GenesisState{}, nil)
+	header := abci.Header{
+		Height: helper.mApp.LastBlockHeight() + 1,
+		Time:   tmtime.Now()}
+	helper.mApp.BeginBlock(abci.RequestBeginBlock{Header: header})
+	ctx := helper.mApp.BaseApp.NewContext(false, header)
+	helper.keeper.SetParams(ctx, types.DefaultParams())
+	sk := fixedSupply(sdk.NewCoins(sdk.NewInt64Coin("tst", 100)))
+
+	asset := types.NewAsset("tst", "tst", "usd", types.Oracles{types.NewOracle(helper.addrs[0])}, true)
+	proposal := types.NewAddAssetProposal("title", "description", asset)
+	require.NoError(t, proposal.ValidateBasic())
+	require.NoError(t, keeper.HandleAddAssetProposal(ctx, helper.keeper, sk, proposal))
+	got, found := helper.keeper.GetAsset(ctx, "tst")
+	require.True(t, found)
+	require.Equal(t, asset, got)
+
+	// assets cannot be added twice
+	require.Error(t, keeper.HandleAddAssetProposal(ctx, helper.keeper, sk, proposal))
+
+	// the base asset has to exist in supply
+	asset = types.NewAsset("tst2", "tst2", "usd", types.Oracles{types.NewOracle(helper.addrs[0])}, true)
+	require.Error(t, keeper.HandleAddAssetProposal(ctx, helper.keeper, sk, types.NewAddAssetProposal("title", "description", asset)))
+}
@@ -11,6 +11,7 @@ func RegisterCodec(cdc *codec.Codec) {
 	cdc.RegisterConcrete(MsgSetOracles{}, "oracle/MsgSetOracles", nil)
 	cdc.RegisterConcrete(MsgAddAsset{}, "oracle/MsgAddAsset", nil)
 	cdc.RegisterConcrete(MsgSetAsset{}, "oracle/MsgSetAsset", nil)
+	cdc.RegisterConcrete(AddAssetProposal{}, "oracle/AddAssetProposal", nil)
 }
 
 // generic sealed codec to be used throughout module
@@ -0,0 +1,14 @@
+package types
+
+// Oracle module event types
+const (
+	EventTypePostPrice    = "post_price"
+	EventTypeCurrentPrice = "current_price"
+
+	AttributeValueCategory = ModuleName
+
+	AttributeKeyAssetCode = "asset_code"
+	AttributeKeyOracle    = "oracle"
+	AttributeKeyPrice     = "price"
+	AttributeKeyExpiry    = "expiry"
+)
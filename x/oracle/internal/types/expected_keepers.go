@@ -0,0 +1,17 @@
+package types
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/supply/exported"
+)
+
+// SupplyKeeper defines the expected supply keeper
+type SupplyKeeper interface {
+	GetSupply(ctx sdk.Context) exported.SupplyI
+}
+
+// AuthorityKeeper defines the expected authority keeper
+type AuthorityKeeper interface {
+	HasRole(ctx sdk.Context, role string, addr sdk.AccAddress) bool
+	Approve(ctx sdk.Context, role string, signer sdk.AccAddress, action sdk.Msg) (bool, sdk.Error)
+}
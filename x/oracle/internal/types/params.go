@@ -10,8 +10,7 @@ import (
 
 var (
 	// KeyAssets store key for assets
-	KeyAssets   = []byte("oracleassets")
-	KeyNominees = []byte("oraclenominees")
+	KeyAssets = []byte("oracleassets")
 )
 
 // ParamKeyTable Key declaration for parameters
@@ -21,8 +20,7 @@ func ParamKeyTable() params.KeyTable {
 
 // Params params for oracle. Can be altered via governance
 type Params struct {
-	Assets   []Asset  `json:"assets" yaml:"assets"` //  Array containing the assets supported by the oracle
-	Nominees []string `json:"nominees" yaml:"nominees"`
+	Assets []Asset `json:"assets" yaml:"assets"` //  Array containing the assets supported by the oracle
 }
 
 // ParamSetPairs implements the ParamSet interface and returns all the key/value pairs
@@ -30,21 +28,19 @@ type Params struct {
 func (p Params) ParamSetPairs() params.ParamSetPairs {
 	return params.ParamSetPairs{
 		{Key: KeyAssets, Value: &p.Assets},
-		{Key: KeyNominees, Value: &p.Nominees},
 	}
 }
 
 // NewParams creates a new AssetParams object
-func NewParams(assets []Asset, nominees []string) Params {
+func NewParams(assets []Asset) Params {
 	return Params{
-		Assets:   assets,
-		Nominees: nominees,
+		Assets: assets,
 	}
 }
 
 // DefaultParams default params for oracle
 func DefaultParams() Params {
-	return NewParams(Assets{}, []string{})
+	return NewParams(Assets{})
 }
 
 // String implements fmt.stringer
@@ -53,9 +49,6 @@ func (p Params) String() string {
 	for _, a := range p.Assets {
 		out += a.String()
 	}
-	for _, a := range p.Nominees {
-		out += a
-	}
 	return strings.TrimSpace(out)
 }
 
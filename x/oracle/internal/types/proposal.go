@@ -0,0 +1,61 @@
+package types
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types"
+)
+
+const (
+	// ProposalTypeAddAsset defines the type for an AddAssetProposal
+	ProposalTypeAddAsset = "AddOracleAsset"
+)
+
+var _ govtypes.Content = AddAssetProposal{}
+
+func init() {
+	govtypes.RegisterProposalType(ProposalTypeAddAsset)
+	govtypes.RegisterProposalTypeCodec(AddAssetProposal{}, "oracle/AddAssetProposal")
+}
+
+// AddAssetProposal adds an asset to the oracle
+type AddAssetProposal struct {
+	Title       string `json:"title" yaml:"title"`
+	Description string `json:"description" yaml:"description"`
+	Asset       Asset  `json:"asset" yaml:"asset"`
+}
+
+// NewAddAssetProposal creates a new add asset proposal
+func NewAddAssetProposal(title, description string, asset Asset) AddAssetProposal {
+	return AddAssetProposal{title, description, asset}
+}
+
+// GetTitle returns the title of an add asset proposal
+func (p AddAssetProposal) GetTitle() string { return p.Title }
+
+// GetDescription returns the description of an add asset proposal
+func (p AddAssetProposal) GetDescription() string { return p.Description }
+
+// ProposalRoute returns the routing key of an add asset proposal
+func (p AddAssetProposal) ProposalRoute() string { return RouterKey }
+
+// ProposalType returns the type of an add asset proposal
+func (p AddAssetProposal) ProposalType() string { return ProposalTypeAddAsset }
+
+// ValidateBasic runs basic stateless validity checks
+func (p AddAssetProposal) ValidateBasic() sdk.Error {
+	if err := govtypes.ValidateAbstract(DefaultCodespace, p); err != nil {
+		return err
+	}
+	return p.Asset.ValidateBasic()
+}
+
+// String implements fmt.Stringer
+func (p AddAssetProposal) String() string {
+	return fmt.Sprintf(`Add Oracle Asset Proposal:
+  Title:       %s
+  Description: %s
+  %s
+`, p.Title, p.Description, p.Asset)
+}
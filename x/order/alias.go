@@ -8,8 +8,16 @@ const (
 	ModuleName        = types.ModuleName
 	StoreKey          = types.StoreKey
 	DefaultParamspace = types.DefaultParamspace
+	DefaultCodespace  = types.DefaultCodespace
 )
 
 var (
-	ModuleCdc = types.ModuleCdc
+	ModuleCdc     = types.ModuleCdc
+	NewParams     = types.NewParams
+	DefaultParams = types.DefaultParams
+)
+
+type (
+	Params      = types.Params
+	OrderParams = types.OrderParams
 )
@@ -0,0 +1,139 @@
+package order
+
+import (
+	"github.com/xar-network/xar-network/pkg/conv"
+	"github.com/xar-network/xar-network/pkg/matcheng"
+	"github.com/xar-network/xar-network/types/store"
+	types3 "github.com/xar-network/xar-network/x/order/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// The book index keeps every resting order under
+//
+//	book/<market id>/<direction>/<price>/<order id>
+//
+// Bid prices are stored bitwise inverted so that both sides of the book
+// iterate best price first, and oldest order first within a price level.
+// Immediate orders are additionally indexed under immediate/<order id> so
+// that whatever is left of them can be cancelled at the end of the block.
+const (
+	bookKey      = "book"
+	immediateKey = "immediate"
+)
+
+// BookIterator iterates over the resting orders on one side of a market,
+// best price first and oldest order first within a price level. Only the
+// orders that are visited are read from the store. The callback must not
+// modify the order store.
+func (k Keeper) BookIterator(ctx sdk.Context, mktID store.EntityID, direction matcheng.Direction, cb IteratorCB) {
+	kv := ctx.KVStore(k.storeKey)
+	iter := sdk.KVStorePrefixIterator(kv, bookSideKey(mktID, direction))
+	k.doIndexIterator(ctx, iter, cb)
+}
+
+// ImmediateIterator iterates over the market, IOC and FOK orders that have
+// not been filled or cancelled yet. The callback must not modify the order
+// store.
+func (k Keeper) ImmediateIterator(ctx sdk.Context, cb IteratorCB) {
+	kv := ctx.KVStore(k.storeKey)
+	iter := sdk.KVStorePrefixIterator(kv, []byte(immediateKey))
+	k.doIndexIterator(ctx, iter, cb)
+}
+
+func (k Keeper) doIndexIterator(ctx sdk.Context, iter sdk.Iterator, cb IteratorCB) {
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		order, err := k.Get(ctx, store.NewEntityIDFromBytes(iter.Value()))
+		if err != nil {
+			// should never happen; implies consensus
+			// or storage bug
+			panic(err)
+		}
+
+		if !cb(order) {
+			break
+		}
+	}
+}
+
+func (k Keeper) indexOrder(ctx sdk.Context, order types3.Order) {
+	kv := ctx.KVStore(k.storeKey)
+	kv.Set(bookOrderKey(order), order.ID.Bytes())
+	if order.Type.IsImmediate() {
+		kv.Set(immediateOrderKey(order.ID), order.ID.Bytes())
+	}
+	k.insertIntoExpiryQueue(ctx, order)
+	k.indexOwnerAndMarket(ctx, order)
+}
+
+func (k Keeper) unindexOrder(ctx sdk.Context, order types3.Order) {
+	kv := ctx.KVStore(k.storeKey)
+	kv.Delete(bookOrderKey(order))
+	if order.Type.IsImmediate() {
+		kv.Delete(immediateOrderKey(order.ID))
+	}
+	k.removeFromExpiryQueue(ctx, order)
+	k.unindexOwnerAndMarket(ctx, order)
+}
+
+func bookSideKey(mktID store.EntityID, direction matcheng.Direction) []byte {
+	return append(store.PrefixKeyString(bookKey, mktID.Bytes(), []byte{byte(direction)}), '/')
+}
+
+func bookOrderKey(order types3.Order) []byte {
+	return store.PrefixKeyString(bookKey, order.MarketID.Bytes(), []byte{byte(order.Direction)}, bookPrice(order.Direction, order.Price), order.ID.Bytes())
+}
+
+func bookPrice(direction matcheng.Direction, price sdk.Uint) []byte {
+	var buf [32]byte
+	b := conv.SDKUint2Big(price).Bytes()
+	copy(buf[32-len(b):], b)
+	if direction == matcheng.Bid {
+		for i := range buf {
+			buf[i] = ^buf[i]
+		}
+	}
+	return buf[:]
+}
+
+func immediateOrderKey(id store.EntityID) []byte {
+	return store.PrefixKeyString(immediateKey, id.Bytes())
+}
+
+// Depth aggregates the visible resting quantity on one side of a market by
+// price level, best price first, and returns at most levels of them. The
+// hidden reserve of iceberg orders is left out. If bucket is
+// positive, bid prices are rounded down and ask prices up to a multiple of
+// it before they are aggregated.
+func (k Keeper) Depth(ctx sdk.Context, mktID store.EntityID, direction matcheng.Direction, levels int, bucket sdk.Uint) []types3.DepthLevel {
+	out := make([]types3.DepthLevel, 0)
+	k.BookIterator(ctx, mktID, direction, func(order types3.Order) bool {
+		price := bucketPrice(order.Price, direction, bucket)
+		if n := len(out); n > 0 && out[n-1].Price.Equal(price) {
+			out[n-1].Quantity = out[n-1].Quantity.Add(order.Visible())
+			out[n-1].Orders++
+			return true
+		}
+		if len(out) == levels {
+			return false
+		}
+		out = append(out, types3.DepthLevel{
+			Price:    price,
+			Quantity: order.Visible(),
+			Orders:   1,
+		})
+		return true
+	})
+	return out
+}
+
+func bucketPrice(price sdk.Uint, direction matcheng.Direction, bucket sdk.Uint) sdk.Uint {
+	if bucket.IsZero() {
+		return price
+	}
+	if direction == matcheng.Ask {
+		price = price.Add(bucket).Sub(sdk.OneUint())
+	}
+	return price.Quo(bucket).Mul(bucket)
+}
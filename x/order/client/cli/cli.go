@@ -14,6 +14,9 @@ func GetQueryCmd(sk string, cdc *codec.Codec) *cobra.Command {
 	}
 	queryCmd.AddCommand(client.GetCommands(
 		GetCmdListOrders(sk, cdc),
+		GetCmdOrderStatus(sk, cdc),
+		GetCmdDepth(sk, cdc),
+		GetCmdParams(sk, cdc),
 	)...)
 	return queryCmd
 }
@@ -26,6 +29,10 @@ func GetTxCmd(cdc *codec.Codec) *cobra.Command {
 	txCmd.AddCommand(client.PostCommands(
 		GetCmdPost(cdc),
 		GetCmdCancel(cdc),
+		GetCmdReplace(cdc),
+		GetCmdCancelAll(cdc),
+		GetCmdPostStop(cdc),
+		GetCmdCancelStop(cdc),
 	)...)
 	return txCmd
 }
@@ -4,20 +4,45 @@ import (
 	"fmt"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 
 	"github.com/cosmos/cosmos-sdk/client/context"
 	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
 
+	"github.com/xar-network/xar-network/types/store"
 	"github.com/xar-network/xar-network/x/order/types"
 )
 
+const (
+	flagOwner    = "owner"
+	flagMinPrice = "min-price"
+	flagMaxPrice = "max-price"
+	flagPage     = "page"
+	flagLimit    = "limit"
+	flagLevels   = "levels"
+	flagBucket   = "bucket"
+)
+
 func GetCmdListOrders(queryRoute string, cdc *codec.Codec) *cobra.Command {
 	out := &cobra.Command{
 		Use:   "list",
 		Short: "lists orders with the specified filters",
+		Long: `Lists open orders, oldest first. Orders can be filtered by --owner, --market,
+--direction and a --min-price and --max-price range. Results are paginated
+with --page and --limit.`,
+		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := context.NewCLIContext().WithCodec(cdc)
-			res, _, err := ctx.QueryWithData(fmt.Sprintf("custom/%s/list", queryRoute), nil)
+			params, err := listQueryParams()
+			if err != nil {
+				return err
+			}
+			bz, err := cdc.MarshalJSON(params)
+			if err != nil {
+				return err
+			}
+			res, _, err := ctx.QueryWithData(fmt.Sprintf("custom/%s/list", queryRoute), bz)
 			if err != nil {
 				return err
 			}
@@ -27,5 +52,124 @@ func GetCmdListOrders(queryRoute string, cdc *codec.Codec) *cobra.Command {
 			return ctx.PrintOutput(out)
 		},
 	}
+	out.Flags().String(flagOwner, "", "only list orders of this address")
+	out.Flags().String(flagMarket, "", "only list orders in this market")
+	out.Flags().String(flagDirection, "", "only list bid or ask orders")
+	out.Flags().String(flagMinPrice, "", "only list orders at or above this price")
+	out.Flags().String(flagMaxPrice, "", "only list orders at or below this price")
+	out.Flags().Int(flagPage, 1, "page of results to show")
+	out.Flags().Int(flagLimit, types.DefaultQueryLimit, "number of orders per page")
+	return out
+}
+
+func listQueryParams() (types.ListQueryParams, error) {
+	params := types.NewListQueryParams(viper.GetInt(flagPage), viper.GetInt(flagLimit))
+	if owner := viper.GetString(flagOwner); owner != "" {
+		addr, err := sdk.AccAddressFromBech32(owner)
+		if err != nil {
+			return params, err
+		}
+		params.Owner = addr
+	}
+	if market := viper.GetString(flagMarket); market != "" {
+		mktID := store.NewEntityIDFromString(market)
+		params.MarketID = &mktID
+	}
+	if arg := viper.GetString(flagDirection); arg != "" {
+		direction, err := parseDirection(arg)
+		if err != nil {
+			return params, err
+		}
+		params.Direction = &direction
+	}
+	if arg := viper.GetString(flagMinPrice); arg != "" {
+		price, err := sdk.ParseUint(arg)
+		if err != nil {
+			return params, err
+		}
+		params.MinPrice = &price
+	}
+	if arg := viper.GetString(flagMaxPrice); arg != "" {
+		price, err := sdk.ParseUint(arg)
+		if err != nil {
+			return params, err
+		}
+		params.MaxPrice = &price
+	}
+	return params, nil
+}
+
+func GetCmdOrderStatus(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "status [order-id]",
+		Short: "shows the status and fills of an open or closed order",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.NewCLIContext().WithCodec(cdc)
+			res, _, err := ctx.QueryWithData(fmt.Sprintf("custom/%s/status/%s", queryRoute, args[0]), nil)
+			if err != nil {
+				return err
+			}
+
+			var out types.OrderRecord
+			cdc.MustUnmarshalJSON(res, &out)
+			return ctx.PrintOutput(out)
+		},
+	}
+}
+
+func GetCmdParams(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "params",
+		Short: "shows the open order limits, posting fee and batch order limit",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.NewCLIContext().WithCodec(cdc)
+			res, _, err := ctx.QueryWithData(fmt.Sprintf("custom/%s/params", queryRoute), nil)
+			if err != nil {
+				return err
+			}
+
+			var out types.Params
+			cdc.MustUnmarshalJSON(res, &out)
+			return ctx.PrintOutput(out)
+		},
+	}
+}
+
+func GetCmdDepth(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	out := &cobra.Command{
+		Use:   "depth [market-id]",
+		Short: "shows the aggregated order book of a market",
+		Long: `Shows the resting quantity on each side of a market aggregated by price
+level, best price first. Use --levels to limit the number of levels per side
+and --bucket to group prices into buckets of that size.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.NewCLIContext().WithCodec(cdc)
+			params := types.NewDepthQueryParams(viper.GetInt(flagLevels))
+			if arg := viper.GetString(flagBucket); arg != "" {
+				bucket, err := sdk.ParseUint(arg)
+				if err != nil {
+					return err
+				}
+				params.BucketSize = &bucket
+			}
+			bz, err := cdc.MarshalJSON(params)
+			if err != nil {
+				return err
+			}
+			res, _, err := ctx.QueryWithData(fmt.Sprintf("custom/%s/depth/%s", queryRoute, args[0]), bz)
+			if err != nil {
+				return err
+			}
+
+			var out types.DepthQueryResult
+			cdc.MustUnmarshalJSON(res, &out)
+			return ctx.PrintOutput(out)
+		},
+	}
+	out.Flags().Int(flagLevels, types.DefaultDepthLevels, "number of price levels per side")
+	out.Flags().String(flagBucket, "", "price bucket size to aggregate levels by")
 	return out
 }
@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 
 	"github.com/cosmos/cosmos-sdk/client"
 	"github.com/xar-network/xar-network/pkg/cliutil"
@@ -22,11 +23,37 @@ import (
 	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
 )
 
+const (
+	flagType         = "type"
+	flagSlippageBps  = "slippage-bps"
+	flagTrigger      = "trigger"
+	flagExpiry       = "expiry"
+	flagGoodTillTime = "good-till-time"
+	flagSTP          = "stp"
+	flagDisplay      = "display"
+	flagMarket       = "market"
+	flagDirection    = "direction"
+)
+
 func GetCmdPost(cdc *codec.Codec) *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "post [market-id] [direction] [price] [quantity] [time-in-force-blocks]",
 		Short: "posts an order",
-		Args:  cobra.ExactArgs(5),
+		Long: `Posts an order. The order type is one of LIMIT, MARKET, IOC, FOK or POST_ONLY.
+Immediate orders (MARKET, IOC and FOK) must use a time in force of 0. For MARKET
+orders the price is the reference price and --slippage-bps the tolerated deviation.
+
+By default orders expire after time-in-force-blocks blocks (GTB). Use --expiry GTT
+with --good-till-time to expire at a unix time instead, or --expiry GTC to keep the
+order until it is filled or cancelled. Both require a time in force of 0.
+
+--stp selects what happens when the order would trade against another order of
+the same owner: CANCEL_NEWEST, CANCEL_OLDEST, CANCEL_BOTH or DECREMENT_AND_CANCEL.
+
+--display posts an iceberg order that only shows that much of its quantity in the
+book. The shown quantity is refilled from the rest after every fill. The full
+quantity is escrowed.`,
+		Args: cobra.ExactArgs(5),
 		RunE: func(cmd *cobra.Command, args []string) error {
 
 			inBuf := bufio.NewReader(cmd.InOrStdin())
@@ -38,14 +65,9 @@ func GetCmdPost(cdc *codec.Codec) *cobra.Command {
 			bldr := authtypes.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
 
 			marketID := store.NewEntityIDFromString(args[0])
-			var direction matcheng.Direction
-			dirArg := strings.ToLower(args[1])
-			if dirArg == "bid" {
-				direction = matcheng.Bid
-			} else if dirArg == "ask" {
-				direction = matcheng.Ask
-			} else {
-				return errors.New("invalid direction")
+			direction, err := parseDirection(args[1])
+			if err != nil {
+				return err
 			}
 
 			price, err := sdk.ParseUint(args[2])
@@ -64,10 +86,46 @@ func GetCmdPost(cdc *codec.Codec) *cobra.Command {
 				return errors.New("time in force too large")
 			}
 
-			msg := types.NewMsgPost(cliCtx.GetFromAddress(), marketID, direction, price, quantity, uint16(tif))
+			orderType, err := matcheng.OrderTypeFromString(strings.ToUpper(viper.GetString(flagType)))
+			if err != nil {
+				return err
+			}
+			slippage := viper.GetUint64(flagSlippageBps)
+			if slippage > math.MaxUint16 {
+				return errors.New("slippage too large")
+			}
+
+			expiryType, err := types.ExpiryTypeFromString(strings.ToUpper(viper.GetString(flagExpiry)))
+			if err != nil {
+				return err
+			}
+
+			stp, err := matcheng.STPModeFromString(strings.ToUpper(viper.GetString(flagSTP)))
+			if err != nil {
+				return err
+			}
+
+			msg := types.NewMsgPost(cliCtx.GetFromAddress(), marketID, direction, price, quantity, uint16(tif), orderType, uint16(slippage))
+			msg.ExpiryType = expiryType
+			msg.GoodTillTime = viper.GetInt64(flagGoodTillTime)
+			msg.STPMode = stp
+			if arg := viper.GetString(flagDisplay); arg != "" {
+				display, err := sdk.ParseUint(arg)
+				if err != nil {
+					return err
+				}
+				msg.DisplayQuantity = &display
+			}
 			return cliutil.ValidateAndBroadcast(cliCtx, bldr, msg)
 		},
 	}
+	cmd.Flags().String(flagType, matcheng.Limit.String(), "order type: LIMIT, MARKET, IOC, FOK or POST_ONLY")
+	cmd.Flags().Uint64(flagSlippageBps, 0, "maximum slippage from the price in basis points for MARKET orders")
+	cmd.Flags().String(flagExpiry, types.GoodTillBlocks.String(), "expiry type: GTB, GTT or GTC")
+	cmd.Flags().Int64(flagGoodTillTime, 0, "unix time at which GTT orders expire")
+	cmd.Flags().String(flagSTP, matcheng.CancelNewest.String(), "self-trade prevention mode")
+	cmd.Flags().String(flagDisplay, "", "only show this much of the quantity in the book at a time")
+	return cmd
 }
 
 func GetCmdCancel(cdc *codec.Codec) *cobra.Command {
@@ -91,3 +149,201 @@ func GetCmdCancel(cdc *codec.Codec) *cobra.Command {
 		},
 	}
 }
+
+func GetCmdReplace(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "replace [order-id] [price] [quantity] [time-in-force-blocks]",
+		Short: "atomically cancels an order and posts a new one in its place",
+		Long: `Cancels an order and posts a new order with the same market, direction and
+order type in one transaction. Only the difference between the funds escrowed
+for the two orders is refunded or taken from the account. The new order gets a
+new ID. Expiry and self-trade prevention flags work as for post.`,
+		Args: cobra.ExactArgs(4),
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			inBuf := bufio.NewReader(cmd.InOrStdin())
+			cliCtx := client.NewCLIContext().WithCodec(cdc)
+			accGetter := authtypes.NewAccountRetriever(cliCtx)
+			if err := accGetter.EnsureExists(cliCtx.GetFromAddress()); err != nil {
+				return err
+			}
+			bldr := authtypes.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
+
+			orderID := store.NewEntityIDFromString(args[0])
+			price, err := sdk.ParseUint(args[1])
+			if err != nil {
+				return err
+			}
+			quantity, err := sdk.ParseUint(args[2])
+			if err != nil {
+				return err
+			}
+			tif, err := strconv.ParseUint(args[3], 10, 64)
+			if err != nil {
+				return err
+			}
+			if tif > math.MaxUint16 {
+				return errors.New("time in force too large")
+			}
+
+			expiryType, err := types.ExpiryTypeFromString(strings.ToUpper(viper.GetString(flagExpiry)))
+			if err != nil {
+				return err
+			}
+			stp, err := matcheng.STPModeFromString(strings.ToUpper(viper.GetString(flagSTP)))
+			if err != nil {
+				return err
+			}
+
+			msg := types.NewMsgReplace(cliCtx.GetFromAddress(), orderID, price, quantity, uint16(tif))
+			msg.ExpiryType = expiryType
+			msg.GoodTillTime = viper.GetInt64(flagGoodTillTime)
+			msg.STPMode = stp
+			return cliutil.ValidateAndBroadcast(cliCtx, bldr, msg)
+		},
+	}
+	cmd.Flags().String(flagExpiry, types.GoodTillBlocks.String(), "expiry type: GTB, GTT or GTC")
+	cmd.Flags().Int64(flagGoodTillTime, 0, "unix time at which GTT orders expire")
+	cmd.Flags().String(flagSTP, matcheng.CancelNewest.String(), "self-trade prevention mode")
+	return cmd
+}
+
+func GetCmdCancelAll(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cancel-all",
+		Short: "cancels all open orders",
+		Long: `Cancels all open orders of the sending account. Use --market to only cancel the
+orders in one market and --direction to only cancel bids or asks.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			inBuf := bufio.NewReader(cmd.InOrStdin())
+			cliCtx := client.NewCLIContext().WithCodec(cdc)
+			accGetter := authtypes.NewAccountRetriever(cliCtx)
+			if err := accGetter.EnsureExists(cliCtx.GetFromAddress()); err != nil {
+				return err
+			}
+			bldr := authtypes.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
+
+			marketID := store.ZeroEntityID
+			if market := viper.GetString(flagMarket); market != "" {
+				marketID = store.NewEntityIDFromString(market)
+			}
+			var direction *matcheng.Direction
+			if arg := viper.GetString(flagDirection); arg != "" {
+				d, err := parseDirection(arg)
+				if err != nil {
+					return err
+				}
+				direction = &d
+			}
+
+			msg := types.NewMsgCancelAll(cliCtx.GetFromAddress(), marketID, direction)
+			return cliutil.ValidateAndBroadcast(cliCtx, bldr, msg)
+		},
+	}
+	cmd.Flags().String(flagMarket, "", "only cancel orders in this market")
+	cmd.Flags().String(flagDirection, "", "only cancel bid or ask orders")
+	return cmd
+}
+
+func GetCmdPostStop(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "post-stop [market-id] [direction] [stop-price] [price] [quantity] [time-in-force-blocks]",
+		Short: "posts a stop or stop-limit order",
+		Long: `Posts an order that is held back until the trigger price crosses the stop
+price. Bids trigger when the price rises to the stop price, asks when it falls
+to it. The order type is LIMIT for stop-limit orders and MARKET for stop orders,
+which must use a time in force of 0. The trigger is CLEARING for the market's
+last execution price or ORACLE for the oracle median price.`,
+		Args: cobra.ExactArgs(6),
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			inBuf := bufio.NewReader(cmd.InOrStdin())
+			cliCtx := client.NewCLIContext().WithCodec(cdc)
+			accGetter := authtypes.NewAccountRetriever(cliCtx)
+			if err := accGetter.EnsureExists(cliCtx.GetFromAddress()); err != nil {
+				return err
+			}
+			bldr := authtypes.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
+
+			marketID := store.NewEntityIDFromString(args[0])
+			direction, err := parseDirection(args[1])
+			if err != nil {
+				return err
+			}
+			stopPrice, err := sdk.ParseUint(args[2])
+			if err != nil {
+				return err
+			}
+			price, err := sdk.ParseUint(args[3])
+			if err != nil {
+				return err
+			}
+			quantity, err := sdk.ParseUint(args[4])
+			if err != nil {
+				return err
+			}
+			tif, err := strconv.ParseUint(args[5], 10, 64)
+			if err != nil {
+				return err
+			}
+			if tif > math.MaxUint16 {
+				return errors.New("time in force too large")
+			}
+
+			orderType, err := matcheng.OrderTypeFromString(strings.ToUpper(viper.GetString(flagType)))
+			if err != nil {
+				return err
+			}
+			slippage := viper.GetUint64(flagSlippageBps)
+			if slippage > math.MaxUint16 {
+				return errors.New("slippage too large")
+			}
+			trigger, err := types.TriggerSourceFromString(strings.ToUpper(viper.GetString(flagTrigger)))
+			if err != nil {
+				return err
+			}
+
+			msg := types.NewMsgPostStop(cliCtx.GetFromAddress(), marketID, direction, stopPrice, price, quantity, uint16(tif), orderType, uint16(slippage), trigger)
+			return cliutil.ValidateAndBroadcast(cliCtx, bldr, msg)
+		},
+	}
+	cmd.Flags().String(flagType, matcheng.Limit.String(), "order type: LIMIT or MARKET")
+	cmd.Flags().Uint64(flagSlippageBps, 0, "maximum slippage from the price in basis points for MARKET orders")
+	cmd.Flags().String(flagTrigger, types.TriggerClearing.String(), "trigger price: CLEARING or ORACLE")
+	return cmd
+}
+
+func GetCmdCancelStop(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "cancel-stop [stop-order-id]",
+		Short: "cancels a stop order that has not triggered yet",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			inBuf := bufio.NewReader(cmd.InOrStdin())
+			cliCtx := client.NewCLIContext().WithCodec(cdc)
+			accGetter := authtypes.NewAccountRetriever(cliCtx)
+			if err := accGetter.EnsureExists(cliCtx.GetFromAddress()); err != nil {
+				return err
+			}
+			bldr := authtypes.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
+
+			orderID := store.NewEntityIDFromString(args[0])
+			msg := types.NewMsgCancelStop(cliCtx.GetFromAddress(), orderID)
+			return cliutil.ValidateAndBroadcast(cliCtx, bldr, msg)
+		},
+	}
+}
+
+func parseDirection(arg string) (matcheng.Direction, error) {
+	switch strings.ToLower(arg) {
+	case "bid":
+		return matcheng.Bid, nil
+	case "ask":
+		return matcheng.Ask, nil
+	default:
+		return matcheng.Bid, errors.New("invalid direction")
+	}
+}
@@ -0,0 +1,162 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/rest"
+
+	"github.com/xar-network/xar-network/pkg/matcheng"
+	"github.com/xar-network/xar-network/types/store"
+	"github.com/xar-network/xar-network/x/order/types"
+)
+
+func listOrdersHandler(cliCtx context.CLIContext, storeName string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		params, err := listQueryParams(r)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		bz, err := cliCtx.Codec.MarshalJSON(params)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		cliCtx, ok := rest.ParseQueryHeightOrReturnBadRequest(w, cliCtx, r)
+		if !ok {
+			return
+		}
+
+		res, height, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/list", storeName), bz)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		cliCtx = cliCtx.WithHeight(height)
+		rest.PostProcessResponse(w, cliCtx, res)
+	}
+}
+
+func depthHandler(cliCtx context.CLIContext, storeName string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		params, err := depthQueryParams(r)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		bz, err := cliCtx.Codec.MarshalJSON(params)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		cliCtx, ok := rest.ParseQueryHeightOrReturnBadRequest(w, cliCtx, r)
+		if !ok {
+			return
+		}
+
+		res, height, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/depth/%s", storeName, mux.Vars(r)["marketID"]), bz)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+			return
+		}
+		cliCtx = cliCtx.WithHeight(height)
+		rest.PostProcessResponse(w, cliCtx, res)
+	}
+}
+
+func paramsHandler(cliCtx context.CLIContext, storeName string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cliCtx, ok := rest.ParseQueryHeightOrReturnBadRequest(w, cliCtx, r)
+		if !ok {
+			return
+		}
+
+		res, height, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/params", storeName), nil)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		cliCtx = cliCtx.WithHeight(height)
+		rest.PostProcessResponse(w, cliCtx, res)
+	}
+}
+
+func depthQueryParams(r *http.Request) (types.DepthQueryParams, error) {
+	query := r.URL.Query()
+	var params types.DepthQueryParams
+	var err error
+	if arg := query.Get("levels"); arg != "" {
+		if params.Levels, err = strconv.Atoi(arg); err != nil {
+			return params, err
+		}
+	}
+	if arg := query.Get("bucket"); arg != "" {
+		bucket, err := sdk.ParseUint(arg)
+		if err != nil {
+			return params, err
+		}
+		params.BucketSize = &bucket
+	}
+	return params, nil
+}
+
+func listQueryParams(r *http.Request) (types.ListQueryParams, error) {
+	query := r.URL.Query()
+	var params types.ListQueryParams
+	var err error
+	if arg := query.Get("page"); arg != "" {
+		if params.Page, err = strconv.Atoi(arg); err != nil {
+			return params, err
+		}
+	}
+	if arg := query.Get("limit"); arg != "" {
+		if params.Limit, err = strconv.Atoi(arg); err != nil {
+			return params, err
+		}
+	}
+	if arg := query.Get("owner"); arg != "" {
+		if params.Owner, err = sdk.AccAddressFromBech32(arg); err != nil {
+			return params, err
+		}
+	}
+	if arg := query.Get("market"); arg != "" {
+		mktID := store.NewEntityIDFromString(arg)
+		params.MarketID = &mktID
+	}
+	if arg := query.Get("direction"); arg != "" {
+		switch strings.ToUpper(arg) {
+		case matcheng.Bid.String():
+			d := matcheng.Bid
+			params.Direction = &d
+		case matcheng.Ask.String():
+			d := matcheng.Ask
+			params.Direction = &d
+		default:
+			return params, errors.New("invalid direction")
+		}
+	}
+	if arg := query.Get("min_price"); arg != "" {
+		price, err := sdk.ParseUint(arg)
+		if err != nil {
+			return params, err
+		}
+		params.MinPrice = &price
+	}
+	if arg := query.Get("max_price"); arg != "" {
+		price, err := sdk.ParseUint(arg)
+		if err != nil {
+			return params, err
+		}
+		params.MaxPrice = &price
+	}
+	return params, nil
+}
@@ -0,0 +1,21 @@
+package rest
+
+import (
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+
+	"github.com/gorilla/mux"
+)
+
+// RegisterRoutes - Central function to define routes that get registered by the main application
+func RegisterRoutes(cliCtx context.CLIContext, r *mux.Router, storeName string) {
+	// Queries
+	r.HandleFunc(fmt.Sprintf("/%s/orders", storeName), listOrdersHandler(cliCtx, storeName)).Methods("GET")
+	r.HandleFunc(fmt.Sprintf("/%s/depth/{marketID}", storeName), depthHandler(cliCtx, storeName)).Methods("GET")
+	r.HandleFunc(fmt.Sprintf("/%s/params", storeName), paramsHandler(cliCtx, storeName)).Methods("GET")
+
+	// Transactions
+	r.HandleFunc(fmt.Sprintf("/%s/orders/replace", storeName), replaceHandler(cliCtx)).Methods("PUT")
+	r.HandleFunc(fmt.Sprintf("/%s/orders/cancel-all", storeName), cancelAllHandler(cliCtx)).Methods("PUT")
+}
@@ -0,0 +1,141 @@
+package rest
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/rest"
+	"github.com/cosmos/cosmos-sdk/x/auth/client/utils"
+
+	"github.com/xar-network/xar-network/pkg/matcheng"
+	"github.com/xar-network/xar-network/types/store"
+	"github.com/xar-network/xar-network/x/order/types"
+)
+
+type replaceReq struct {
+	BaseReq      rest.BaseReq `json:"base_req"`
+	OrderID      string       `json:"order_id"`
+	Price        string       `json:"price"`
+	Quantity     string       `json:"quantity"`
+	TimeInForce  uint16       `json:"time_in_force"`
+	ExpiryType   string       `json:"expiry_type"`
+	GoodTillTime int64        `json:"good_till_time"`
+	STPMode      string       `json:"stp_mode"`
+}
+
+func replaceHandler(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req replaceReq
+		if !rest.ReadRESTReq(w, r, cliCtx.Codec, &req) {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, "failed to parse request")
+			return
+		}
+
+		baseReq := req.BaseReq.Sanitize()
+		if !baseReq.ValidateBasic(w) {
+			return
+		}
+
+		owner, err := sdk.AccAddressFromBech32(baseReq.From)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		price, err := sdk.ParseUint(req.Price)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		quantity, err := sdk.ParseUint(req.Quantity)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		expiryType := types.GoodTillBlocks
+		if req.ExpiryType != "" {
+			expiryType, err = types.ExpiryTypeFromString(strings.ToUpper(req.ExpiryType))
+			if err != nil {
+				rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+		stp := matcheng.CancelNewest
+		if req.STPMode != "" {
+			stp, err = matcheng.STPModeFromString(strings.ToUpper(req.STPMode))
+			if err != nil {
+				rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+
+		// create the message
+		msg := types.NewMsgReplace(owner, store.NewEntityIDFromString(req.OrderID), price, quantity, req.TimeInForce)
+		msg.ExpiryType = expiryType
+		msg.GoodTillTime = req.GoodTillTime
+		msg.STPMode = stp
+		if err := msg.ValidateBasic(); err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		utils.WriteGenerateStdTxResponse(w, cliCtx, baseReq, []sdk.Msg{msg})
+	}
+}
+
+type cancelAllReq struct {
+	BaseReq   rest.BaseReq `json:"base_req"`
+	MarketID  string       `json:"market_id"`
+	Direction string       `json:"direction"`
+}
+
+func cancelAllHandler(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req cancelAllReq
+		if !rest.ReadRESTReq(w, r, cliCtx.Codec, &req) {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, "failed to parse request")
+			return
+		}
+
+		baseReq := req.BaseReq.Sanitize()
+		if !baseReq.ValidateBasic(w) {
+			return
+		}
+
+		owner, err := sdk.AccAddressFromBech32(baseReq.From)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		marketID := store.ZeroEntityID
+		if req.MarketID != "" {
+			marketID = store.NewEntityIDFromString(req.MarketID)
+		}
+		var direction *matcheng.Direction
+		switch strings.ToUpper(req.Direction) {
+		case "":
+		case matcheng.Bid.String():
+			d := matcheng.Bid
+			direction = &d
+		case matcheng.Ask.String():
+			d := matcheng.Ask
+			direction = &d
+		default:
+			rest.WriteErrorResponse(w, http.StatusBadRequest, "invalid direction")
+			return
+		}
+
+		// create the message
+		msg := types.NewMsgCancelAll(owner, marketID, direction)
+		if err := msg.ValidateBasic(); err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		utils.WriteGenerateStdTxResponse(w, cliCtx, baseReq, []sdk.Msg{msg})
+	}
+}
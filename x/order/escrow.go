@@ -0,0 +1,119 @@
+package order
+
+import (
+	"github.com/xar-network/xar-network/pkg/conv"
+	"github.com/xar-network/xar-network/pkg/matcheng"
+	mkttypes "github.com/xar-network/xar-network/x/market/types"
+	types3 "github.com/xar-network/xar-network/x/order/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// Funds posted with an order are held by the order module account until the
+// order is cancelled or filled. Settling a fill releases the part of the
+// order's escrow that is no longer needed into the dust pool, and every
+// payout is drawn from that pool. Whatever is left after a trade has been
+// settled is rounding dust from Market.QuoteQuantity, so the module
+// account always holds exactly the escrow of the open orders plus the dust.
+const dustKey = "dust"
+
+// Escrow returns the funds held for the unfilled part of an order: the
+// quote asset for bids and the base asset for asks. Bids whose remaining
+// quantity is too small to be worth a single unit of the quote asset hold
+// nothing; settlement cancels them as soon as a fill leaves them that small.
+func (k Keeper) Escrow(ctx sdk.Context, order types3.Order) sdk.Coin {
+	mkt, err := k.marketKeeper.Get(ctx, order.MarketID)
+	if err != nil {
+		// should never happen; implies consensus
+		// or storage bug
+		panic(err)
+	}
+	escrow, qErr := escrowFor(mkt, order.Direction, order.Price, order.Quantity)
+	if qErr != nil {
+		return sdk.NewCoin(mkt.QuoteAssetDenom, sdk.ZeroInt())
+	}
+	return escrow
+}
+
+// ReleaseEscrow moves the escrow that an order no longer needs now that only
+// remaining of it is left unfilled into the dust pool, and returns the
+// released coins.
+func (k Keeper) ReleaseEscrow(ctx sdk.Context, order types3.Order, remaining sdk.Uint) sdk.Coin {
+	before := k.Escrow(ctx, order)
+	order.Quantity = remaining
+	released := before.Sub(k.Escrow(ctx, order))
+	k.setDust(ctx, k.GetDust(ctx).Add(sdk.NewCoins(released)))
+	return released
+}
+
+// PayFromDust sends coins drawn from the dust pool to addr. It fails if the
+// pool holds less than coins.
+func (k Keeper) PayFromDust(ctx sdk.Context, addr sdk.AccAddress, coins sdk.Coins) sdk.Error {
+	if err := k.drawDust(ctx, addr.String(), coins); err != nil {
+		return err
+	}
+	if coins.Empty() {
+		return nil
+	}
+	return k.sk.SendCoinsFromModuleToAccount(ctx, ModuleName, addr, coins)
+}
+
+// PayFeeFromDust sends fees drawn from the dust pool to the recipient module
+// account. Like PayFromDust, it fails if the pool holds less than coins.
+func (k Keeper) PayFeeFromDust(ctx sdk.Context, recipientModule string, coins sdk.Coins) sdk.Error {
+	if err := k.drawDust(ctx, recipientModule, coins); err != nil {
+		return err
+	}
+	if coins.Empty() {
+		return nil
+	}
+	return k.sk.SendCoinsFromModuleToModule(ctx, ModuleName, recipientModule, coins)
+}
+
+// drawDust removes coins from the dust pool. Settlement never owes more than
+// the fills it settles released into the pool, so a pool that cannot cover
+// coins is an accounting error rather than rounding, and nothing is drawn.
+func (k Keeper) drawDust(ctx sdk.Context, recipient string, coins sdk.Coins) sdk.Error {
+	dust := k.GetDust(ctx)
+	rest, negative := dust.SafeSub(coins)
+	if negative {
+		return types3.ErrDustShortfall(k.codespace, recipient, coins, dust)
+	}
+	k.setDust(ctx, rest)
+	return nil
+}
+
+// GetDust returns the funds held by the order module account that do not
+// belong to any open order.
+func (k Keeper) GetDust(ctx sdk.Context) sdk.Coins {
+	kv := ctx.KVStore(k.storeKey)
+	bz := kv.Get([]byte(dustKey))
+	if bz == nil {
+		return sdk.NewCoins()
+	}
+	var out sdk.Coins
+	k.cdc.MustUnmarshalBinaryBare(bz, &out)
+	return out
+}
+
+func (k Keeper) setDust(ctx sdk.Context, dust sdk.Coins) {
+	kv := ctx.KVStore(k.storeKey)
+	if dust.Empty() {
+		kv.Delete([]byte(dustKey))
+		return
+	}
+	kv.Set([]byte(dustKey), k.cdc.MustMarshalBinaryBare(dust))
+}
+
+// escrowFor computes the funds escrowed for an order. It fails for bids too
+// small to be worth a single unit of the quote asset.
+func escrowFor(mkt mkttypes.Market, direction matcheng.Direction, price sdk.Uint, quantity sdk.Uint) (sdk.Coin, error) {
+	if direction == matcheng.Bid {
+		amount, err := mkt.QuoteQuantity(price, quantity)
+		if err != nil {
+			return sdk.Coin{}, err
+		}
+		return sdk.NewCoin(mkt.QuoteAssetDenom, sdk.NewIntFromBigInt(conv.SDKUint2Big(amount))), nil
+	}
+	return sdk.NewCoin(mkt.BaseAssetDenom, sdk.NewIntFromBigInt(conv.SDKUint2Big(quantity))), nil
+}
@@ -0,0 +1,85 @@
+package order
+
+import (
+	"github.com/xar-network/xar-network/types/store"
+	types3 "github.com/xar-network/xar-network/x/order/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// Orders that can expire are queued under the height or the unix time at
+// which they stop being good, followed by their ID:
+//
+//	expiry_block/<height>/<order id>
+//	expiry_time/<unix time>/<order id>
+//
+// Good-till-cancel and immediate orders are never queued.
+const (
+	expiryBlockKey = "expiry_block"
+	expiryTimeKey  = "expiry_time"
+)
+
+// ExpiredIterator iterates over the orders that are no longer good at the
+// current block height and time. Only expired orders are read from the
+// store. The callback must not modify the order store.
+func (k Keeper) ExpiredIterator(ctx sdk.Context, cb IteratorCB) {
+	kv := ctx.KVStore(k.storeKey)
+
+	var ids []store.EntityID
+	for _, iter := range []sdk.Iterator{
+		kv.Iterator([]byte(expiryBlockKey), sdk.PrefixEndBytes(expiryBlockQueueKey(ctx.BlockHeight()))),
+		kv.Iterator([]byte(expiryTimeKey), sdk.PrefixEndBytes(expiryTimeQueueKey(ctx.BlockHeader().Time.Unix()))),
+	} {
+		for ; iter.Valid(); iter.Next() {
+			ids = append(ids, store.NewEntityIDFromBytes(iter.Value()))
+		}
+		iter.Close()
+	}
+
+	for _, id := range ids {
+		order, err := k.Get(ctx, id)
+		if err != nil {
+			// should never happen; implies consensus
+			// or storage bug
+			panic(err)
+		}
+		if !cb(order) {
+			break
+		}
+	}
+}
+
+func (k Keeper) insertIntoExpiryQueue(ctx sdk.Context, order types3.Order) {
+	if key := expiryQueueElementKey(order); key != nil {
+		ctx.KVStore(k.storeKey).Set(key, order.ID.Bytes())
+	}
+}
+
+func (k Keeper) removeFromExpiryQueue(ctx sdk.Context, order types3.Order) {
+	if key := expiryQueueElementKey(order); key != nil {
+		ctx.KVStore(k.storeKey).Delete(key)
+	}
+}
+
+func expiryQueueElementKey(order types3.Order) []byte {
+	if order.Type.IsImmediate() {
+		return nil
+	}
+
+	switch order.ExpiryType {
+	case types3.GoodTillBlocks:
+		return store.PrefixKeyBytes(expiryBlockQueueKey(order.ExpiryHeight()), order.ID.Bytes())
+	case types3.GoodTillTime:
+		return store.PrefixKeyBytes(expiryTimeQueueKey(order.GoodTillTime), order.ID.Bytes())
+	default:
+		return nil
+	}
+}
+
+func expiryBlockQueueKey(height int64) []byte {
+	return store.PrefixKeyString(expiryBlockKey, store.Int64Subkey(height))
+}
+
+func expiryTimeQueueKey(time int64) []byte {
+	return store.PrefixKeyString(expiryTimeKey, store.Int64Subkey(time))
+}
@@ -0,0 +1,33 @@
+package order
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/xar-network/xar-network/x/order/types"
+)
+
+// GenesisState holds the order module's parameters. Open orders are not
+// exported; their escrow is part of the module account's balance.
+type GenesisState struct {
+	Params types.Params `json:"params" yaml:"params"`
+}
+
+func NewGenesisState(params types.Params) GenesisState {
+	return GenesisState{Params: params}
+}
+
+func DefaultGenesisState() GenesisState {
+	return NewGenesisState(types.DefaultParams())
+}
+
+func ValidateGenesis(data GenesisState) error {
+	return data.Params.Validate()
+}
+
+func InitGenesis(ctx sdk.Context, k Keeper, data GenesisState) {
+	k.SetParams(ctx, data.Params)
+}
+
+func ExportGenesis(ctx sdk.Context, k Keeper) GenesisState {
+	return NewGenesisState(k.GetParams(ctx))
+}
@@ -19,6 +19,14 @@ func NewHandler(keeper Keeper) sdk.Handler {
 			return handleMsgPost(ctx, keeper, msg)
 		case types.MsgCancel:
 			return handleMsgCancel(ctx, keeper, msg)
+		case types.MsgPostStop:
+			return handleMsgPostStop(ctx, keeper, msg)
+		case types.MsgCancelStop:
+			return handleMsgCancelStop(ctx, keeper, msg)
+		case types.MsgReplace:
+			return handleMsgReplace(ctx, keeper, msg)
+		case types.MsgCancelAll:
+			return handleMsgCancelAll(ctx, keeper, msg)
 		default:
 			return sdk.ErrUnknownRequest(fmt.Sprintf("unknown message type %v", msg.Type())).Result()
 		}
@@ -26,40 +34,146 @@ func NewHandler(keeper Keeper) sdk.Handler {
 }
 
 func handleMsgPost(ctx sdk.Context, keeper Keeper, msg types.MsgPost) sdk.Result {
-	order, err := keeper.Post(
+	order, err := keeper.PostOrder(ctx, msg.Owner, msg.OrderParams())
+
+	if err == nil {
+		logger.Info(
+			"posted order",
+			"id", order.ID.String(),
+			"market_id", order.MarketID.String(),
+			"price", order.Price.String(),
+			"quantity", order.Quantity.String(),
+			"display_quantity", order.DisplayQuantity.String(),
+			"direction", order.Direction.String(),
+			"type", order.Type.String(),
+			"expiry_type", order.ExpiryType.String(),
+			"stp_mode", order.STPMode.String(),
+		)
+		return errs.MessageResult(ctx, types.AttributeValueCategory, msg.Owner, fmt.Sprintf("order_id:%s", order.ID))
+	}
+
+	return err.Result()
+}
+
+func handleMsgCancel(ctx sdk.Context, keeper Keeper, msg types.MsgCancel) sdk.Result {
+	order, err := keeper.Get(ctx, msg.OrderID)
+	if err != nil {
+		return err.Result()
+	}
+	if !order.Owner.Equals(msg.Owner) {
+		return sdk.ErrUnauthorized("cannot cancel unowned order").Result()
+	}
+	if err := keeper.Cancel(ctx, order.ID); err != nil {
+		return err.Result()
+	}
+	return errs.MessageResult(ctx, types.AttributeValueCategory, msg.Owner, "")
+}
+
+func handleMsgPostStop(ctx sdk.Context, keeper Keeper, msg types.MsgPostStop) sdk.Result {
+	order, err := keeper.PostStop(
 		ctx,
 		msg.Owner,
 		msg.MarketID,
 		msg.Direction,
-		msg.Price,
+		msg.OrderType,
+		msg.StopPrice,
+		msg.Post().LimitPrice(),
 		msg.Quantity,
 		msg.TimeInForce,
+		msg.Trigger,
 	)
 
 	if err == nil {
 		logger.Info(
-			"posted order",
+			"posted stop order",
 			"id", order.ID.String(),
 			"market_id", order.MarketID.String(),
+			"stop_price", order.StopPrice.String(),
 			"price", order.Price.String(),
 			"quantity", order.Quantity.String(),
 			"direction", order.Direction.String(),
+			"type", order.Type.String(),
+			"source", order.Source.String(),
 		)
-		return sdk.Result{
-			Log: fmt.Sprintf("order_id:%s", order.ID),
-		}
+		ctx.EventManager().EmitEvent(
+			sdk.NewEvent(
+				types.EventTypePostStopOrder,
+				sdk.NewAttribute(types.AttributeKeyStopOrderID, order.ID.String()),
+				sdk.NewAttribute(types.AttributeKeyMarketID, order.MarketID.String()),
+				sdk.NewAttribute(types.AttributeKeyOwner, order.Owner.String()),
+				sdk.NewAttribute(types.AttributeKeyDirection, order.Direction.String()),
+				sdk.NewAttribute(types.AttributeKeyOrderType, order.Type.String()),
+				sdk.NewAttribute(types.AttributeKeyStopPrice, order.StopPrice.String()),
+				sdk.NewAttribute(types.AttributeKeyPrice, order.Price.String()),
+				sdk.NewAttribute(types.AttributeKeyQuantity, order.Quantity.String()),
+			),
+		)
+		return errs.MessageResult(ctx, types.AttributeValueCategory, msg.Owner, fmt.Sprintf("stop_order_id:%s", order.ID))
 	}
 
 	return err.Result()
 }
 
-func handleMsgCancel(ctx sdk.Context, keeper Keeper, msg types.MsgCancel) sdk.Result {
-	order, err := keeper.Get(ctx, msg.OrderID)
+func handleMsgCancelStop(ctx sdk.Context, keeper Keeper, msg types.MsgCancelStop) sdk.Result {
+	order, err := keeper.GetTrigger(ctx, msg.OrderID)
 	if err != nil {
 		return err.Result()
 	}
 	if !order.Owner.Equals(msg.Owner) {
 		return sdk.ErrUnauthorized("cannot cancel unowned order").Result()
 	}
-	return errs.ErrOrBlankResult(keeper.Del(ctx, order.ID))
+	if err := keeper.DelTrigger(ctx, order.ID); err != nil {
+		return err.Result()
+	}
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeCancelStopOrder,
+			sdk.NewAttribute(types.AttributeKeyStopOrderID, order.ID.String()),
+			sdk.NewAttribute(types.AttributeKeyMarketID, order.MarketID.String()),
+			sdk.NewAttribute(types.AttributeKeyOwner, order.Owner.String()),
+		),
+	)
+	return errs.MessageResult(ctx, types.AttributeValueCategory, msg.Owner, "")
+}
+
+func handleMsgReplace(ctx sdk.Context, keeper Keeper, msg types.MsgReplace) sdk.Result {
+	order, err := keeper.Replace(
+		ctx,
+		msg.Owner,
+		msg.OrderID,
+		msg.Price,
+		msg.Quantity,
+		msg.Expiry(),
+		msg.STPMode,
+	)
+
+	if err == nil {
+		logger.Info(
+			"replaced order",
+			"replaced_id", msg.OrderID.String(),
+			"id", order.ID.String(),
+			"market_id", order.MarketID.String(),
+			"price", order.Price.String(),
+			"quantity", order.Quantity.String(),
+			"direction", order.Direction.String(),
+			"expiry_type", order.ExpiryType.String(),
+		)
+		return errs.MessageResult(ctx, types.AttributeValueCategory, msg.Owner, fmt.Sprintf("order_id:%s", order.ID))
+	}
+
+	return err.Result()
+}
+
+func handleMsgCancelAll(ctx sdk.Context, keeper Keeper, msg types.MsgCancelAll) sdk.Result {
+	ids, err := keeper.CancelAll(ctx, msg.Owner, msg.MarketID, msg.Direction)
+	if err != nil {
+		return err.Result()
+	}
+
+	logger.Info(
+		"cancelled all orders",
+		"owner", msg.Owner.String(),
+		"count", len(ids),
+	)
+	return errs.MessageResult(ctx, types.AttributeValueCategory, msg.Owner, fmt.Sprintf("cancelled:%d", len(ids)))
 }
@@ -0,0 +1,88 @@
+package order
+
+import (
+	"github.com/xar-network/xar-network/types/errs"
+	"github.com/xar-network/xar-network/types/store"
+	types3 "github.com/xar-network/xar-network/x/order/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// Orders that are no longer open are deleted from the book, but a record of
+// their outcome is kept in the order they were closed in:
+//
+//	history_seq                    -> sequence of the last record
+//	history_start                  -> sequence of the oldest kept record
+//	history/<sequence>             -> record
+//	outcome/<order id>             -> sequence of the order's record
+//
+// Only the last MaxOrderRecords records of the params are kept, older ones
+// are evicted as new orders close. Each close evicts at most
+// maxEvictionsPerClose records, so a lowered cap shrinks the history over
+// the following closes instead of in a single block.
+const (
+	historySeqKey   = "history_seq"
+	historyStartKey = "history_start"
+	historyKey      = "history"
+	outcomeKey      = "outcome"
+
+	maxEvictionsPerClose = 2
+)
+
+// Close deletes an order that is no longer open and keeps a record of it
+// with the given terminal status.
+func (k Keeper) Close(ctx sdk.Context, ord types3.Order, status types3.OrderStatus) sdk.Error {
+	ord.Status = status
+	k.archive(ctx, ord.Record(ctx.BlockHeight()))
+	return k.Del(ctx, ord.ID)
+}
+
+// Record returns the outcome of an order: the current state of open orders,
+// or the record kept of closed ones.
+func (k Keeper) Record(ctx sdk.Context, id store.EntityID) (types3.OrderRecord, sdk.Error) {
+	if ord, err := k.Get(ctx, id); err == nil {
+		return ord.Record(0), nil
+	}
+
+	kv := ctx.KVStore(k.storeKey)
+	seq := kv.Get(outcomeIndexKey(id))
+	if seq == nil {
+		return types3.OrderRecord{}, errs.ErrNotFound("order not found")
+	}
+	var out types3.OrderRecord
+	err := store.Get(ctx, k.storeKey, k.cdc, historyRecordKey(store.NewEntityIDFromBytes(seq)), &out)
+	return out, err
+}
+
+func (k Keeper) archive(ctx sdk.Context, rec types3.OrderRecord) {
+	kv := ctx.KVStore(k.storeKey)
+	seq := store.IncrementSeq(ctx, k.storeKey, []byte(historySeqKey))
+	store.Set(ctx, k.storeKey, k.cdc, historyRecordKey(seq), rec)
+	kv.Set(outcomeIndexKey(rec.ID), seq.Bytes())
+
+	max := k.GetParams(ctx).MaxOrderRecords
+	if max == 0 {
+		return
+	}
+	start := store.GetSeq(ctx, k.storeKey, []byte(historyStartKey))
+	if start.IsZero() {
+		start = store.NewEntityID(1)
+	}
+	for i := 0; i < maxEvictionsPerClose && seq.Uint64()-start.Uint64() >= max; i++ {
+		var old types3.OrderRecord
+		if err := store.Get(ctx, k.storeKey, k.cdc, historyRecordKey(start), &old); err == nil {
+			kv.Delete(historyRecordKey(start))
+			kv.Delete(outcomeIndexKey(old.ID))
+		}
+		start = start.Inc()
+	}
+	kv.Set([]byte(historyStartKey), []byte(start.String()))
+}
+
+func historyRecordKey(seq store.EntityID) []byte {
+	return store.PrefixKeyString(historyKey, seq.Bytes())
+}
+
+func outcomeIndexKey(id store.EntityID) []byte {
+	return store.PrefixKeyString(outcomeKey, id.Bytes())
+}
@@ -0,0 +1,148 @@
+package order
+
+import (
+	"encoding/binary"
+
+	"github.com/xar-network/xar-network/types/store"
+	types3 "github.com/xar-network/xar-network/x/order/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// Open orders are indexed by owner and by market, oldest order first:
+//
+//	owner/<owner address>/<order id>
+//	market/<market id>/<order id>
+//
+// The number of open orders of every owner and market is kept alongside so
+// that the order limits can be checked without iterating:
+//
+//	owner_count/<owner address>
+//	market_count/<market id>
+//
+// and so is the number of pending trigger orders, which count towards the
+// same limits:
+//
+//	owner_trigger_count/<owner address>
+//	market_trigger_count/<market id>
+const (
+	ownerIndexKey         = "owner"
+	marketIndexKey        = "market"
+	ownerCountKey         = "owner_count"
+	marketCountKey        = "market_count"
+	ownerTriggerCountKey  = "owner_trigger_count"
+	marketTriggerCountKey = "market_trigger_count"
+)
+
+// OwnerIterator iterates over the open orders of owner, oldest first. The
+// callback must not modify the order store.
+func (k Keeper) OwnerIterator(ctx sdk.Context, owner sdk.AccAddress, cb IteratorCB) {
+	kv := ctx.KVStore(k.storeKey)
+	iter := sdk.KVStorePrefixIterator(kv, ownerPrefixKey(owner))
+	k.doIndexIterator(ctx, iter, cb)
+}
+
+// MarketIterator iterates over the open orders in a market, oldest first.
+// The callback must not modify the order store.
+func (k Keeper) MarketIterator(ctx sdk.Context, mktID store.EntityID, cb IteratorCB) {
+	kv := ctx.KVStore(k.storeKey)
+	iter := sdk.KVStorePrefixIterator(kv, marketPrefixKey(mktID))
+	k.doIndexIterator(ctx, iter, cb)
+}
+
+// OwnerOrderCount returns the number of open orders of owner.
+func (k Keeper) OwnerOrderCount(ctx sdk.Context, owner sdk.AccAddress) uint64 {
+	return k.getCount(ctx, ownerCountKeyFor(owner))
+}
+
+// MarketOrderCount returns the number of open orders in a market.
+func (k Keeper) MarketOrderCount(ctx sdk.Context, mktID store.EntityID) uint64 {
+	return k.getCount(ctx, marketCountKeyFor(mktID))
+}
+
+// OwnerTriggerCount returns the number of pending trigger orders of owner.
+func (k Keeper) OwnerTriggerCount(ctx sdk.Context, owner sdk.AccAddress) uint64 {
+	return k.getCount(ctx, store.PrefixKeyString(ownerTriggerCountKey, owner.Bytes()))
+}
+
+// MarketTriggerCount returns the number of pending trigger orders in a
+// market.
+func (k Keeper) MarketTriggerCount(ctx sdk.Context, mktID store.EntityID) uint64 {
+	return k.getCount(ctx, store.PrefixKeyString(marketTriggerCountKey, mktID.Bytes()))
+}
+
+// countOrder adds or, if opened is false, removes an order from the open
+// order counts of its owner and market.
+func (k Keeper) countOrder(ctx sdk.Context, order types3.Order, opened bool) {
+	k.count(ctx, [][]byte{ownerCountKeyFor(order.Owner), marketCountKeyFor(order.MarketID)}, opened)
+}
+
+// countTrigger adds or, if placed is false, removes a trigger order from the
+// pending trigger order counts of its owner and market.
+func (k Keeper) countTrigger(ctx sdk.Context, order types3.TriggerOrder, placed bool) {
+	k.count(ctx, [][]byte{
+		store.PrefixKeyString(ownerTriggerCountKey, order.Owner.Bytes()),
+		store.PrefixKeyString(marketTriggerCountKey, order.MarketID.Bytes()),
+	}, placed)
+}
+
+func (k Keeper) count(ctx sdk.Context, keys [][]byte, opened bool) {
+	for _, key := range keys {
+		n := k.getCount(ctx, key)
+		if opened {
+			n++
+		} else if n > 0 {
+			n--
+		}
+		kv := ctx.KVStore(k.storeKey)
+		if n == 0 {
+			kv.Delete(key)
+		} else {
+			kv.Set(key, sdk.Uint64ToBigEndian(n))
+		}
+	}
+}
+
+func (k Keeper) getCount(ctx sdk.Context, key []byte) uint64 {
+	bz := ctx.KVStore(k.storeKey).Get(key)
+	if bz == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint64(bz)
+}
+
+func (k Keeper) indexOwnerAndMarket(ctx sdk.Context, order types3.Order) {
+	kv := ctx.KVStore(k.storeKey)
+	kv.Set(ownerOrderKey(order), order.ID.Bytes())
+	kv.Set(marketOrderKey(order), order.ID.Bytes())
+}
+
+func (k Keeper) unindexOwnerAndMarket(ctx sdk.Context, order types3.Order) {
+	kv := ctx.KVStore(k.storeKey)
+	kv.Delete(ownerOrderKey(order))
+	kv.Delete(marketOrderKey(order))
+}
+
+func ownerPrefixKey(owner sdk.AccAddress) []byte {
+	return append(store.PrefixKeyString(ownerIndexKey, owner.Bytes()), '/')
+}
+
+func ownerOrderKey(order types3.Order) []byte {
+	return store.PrefixKeyString(ownerIndexKey, order.Owner.Bytes(), order.ID.Bytes())
+}
+
+func marketPrefixKey(mktID store.EntityID) []byte {
+	return append(store.PrefixKeyString(marketIndexKey, mktID.Bytes()), '/')
+}
+
+func marketOrderKey(order types3.Order) []byte {
+	return store.PrefixKeyString(marketIndexKey, order.MarketID.Bytes(), order.ID.Bytes())
+}
+
+func ownerCountKeyFor(owner sdk.AccAddress) []byte {
+	return store.PrefixKeyString(ownerCountKey, owner.Bytes())
+}
+
+func marketCountKeyFor(mktID store.EntityID) []byte {
+	return store.PrefixKeyString(marketCountKey, mktID.Bytes())
+}
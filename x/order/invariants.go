@@ -0,0 +1,68 @@
+package order
+
+import (
+	"fmt"
+
+	types3 "github.com/xar-network/xar-network/x/order/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// RegisterInvariants registers the order module invariants.
+func RegisterInvariants(ir sdk.InvariantRegistry, k Keeper) {
+	ir.RegisterRoute(ModuleName, "escrow", EscrowInvariant(k))
+	ir.RegisterRoute(ModuleName, "solvency", SolvencyInvariant(k))
+}
+
+// AllInvariants runs all invariants of the order module.
+func AllInvariants(k Keeper) sdk.Invariant {
+	return func(ctx sdk.Context) (string, bool) {
+		if res, stop := SolvencyInvariant(k)(ctx); stop {
+			return res, stop
+		}
+		return EscrowInvariant(k)(ctx)
+	}
+}
+
+// EscrowInvariant checks that the order module account holds exactly the
+// escrow of every open order plus the rounding dust left over from
+// settlement.
+func EscrowInvariant(k Keeper) sdk.Invariant {
+	return func(ctx sdk.Context) (string, bool) {
+		balance, expected, count := escrowBalance(ctx, k)
+		diff, negative := balance.SafeSub(expected)
+		broken := negative || !diff.IsZero()
+
+		return sdk.FormatInvariant(ModuleName, "escrow", fmt.Sprintf(
+			"\torder module balance: %s\n"+
+				"\tescrow of %d open orders plus dust: %s\n", balance, count, expected)), broken
+	}
+}
+
+// SolvencyInvariant checks that the order module account holds at least the
+// escrow of every open order plus the dust. Unlike a surplus, a shortfall
+// means that settlement or refunds would pay out funds the module does not
+// have.
+func SolvencyInvariant(k Keeper) sdk.Invariant {
+	return func(ctx sdk.Context) (string, bool) {
+		balance, expected, count := escrowBalance(ctx, k)
+		_, broken := balance.SafeSub(expected)
+
+		return sdk.FormatInvariant(ModuleName, "solvency", fmt.Sprintf(
+			"\torder module balance: %s\n"+
+				"\tescrow of %d open orders plus dust: %s\n", balance, count, expected)), broken
+	}
+}
+
+// escrowBalance returns the balance of the order module account, the
+// escrow of every open order plus the dust, and the number of open orders.
+func escrowBalance(ctx sdk.Context, k Keeper) (sdk.Coins, sdk.Coins, int) {
+	expected := k.GetDust(ctx)
+	var count int
+	k.Iterator(ctx, func(order types3.Order) bool {
+		expected = expected.Add(sdk.NewCoins(k.Escrow(ctx, order)))
+		count++
+		return true
+	})
+	return k.sk.GetModuleAccount(ctx, ModuleName).GetCoins(), expected, count
+}
@@ -1,107 +1,250 @@
 package order
 
 import (
+	"fmt"
+
 	"github.com/xar-network/xar-network/pkg/matcheng"
 	"github.com/xar-network/xar-network/types"
+	"github.com/xar-network/xar-network/types/errs"
 	"github.com/xar-network/xar-network/types/store"
 	"github.com/xar-network/xar-network/x/market"
+	"github.com/xar-network/xar-network/x/oracle"
 	types3 "github.com/xar-network/xar-network/x/order/types"
 
 	"github.com/cosmos/cosmos-sdk/codec"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/bank"
+	"github.com/cosmos/cosmos-sdk/x/params"
 	"github.com/cosmos/cosmos-sdk/x/supply"
 )
 
 const (
-	seqKey = "seq"
-	valKey = "val"
+	seqKey           = "seq"
+	valKey           = "val"
+	triggerSeqKey    = "trigger_seq"
+	triggerValKey    = "trigger_val"
+	triggerMarketKey = "trigger_market"
+	triggerPriceKey  = "trigger_price"
 )
 
 type IteratorCB func(order types3.Order) bool
 
+type TriggerIteratorCB func(order types3.TriggerOrder) bool
+
 type Keeper struct {
 	sk           supply.Keeper
+	bk           bank.Keeper
 	marketKeeper market.Keeper
+	oracleKeeper oracle.Keeper
 	storeKey     sdk.StoreKey
-	queue        types.Backend
+	paramstore   params.Subspace
+	queue        types.Publisher
 	cdc          *codec.Codec
+	hooks        types3.OrderHooks
+	codespace    sdk.CodespaceType
 }
 
-func NewKeeper(sk supply.Keeper, mk market.Keeper, storeKey sdk.StoreKey, queue types.Backend, cdc *codec.Codec) Keeper {
+func NewKeeper(sk supply.Keeper, bk bank.Keeper, mk market.Keeper, ok oracle.Keeper, storeKey sdk.StoreKey, paramstore params.Subspace, queue types.Publisher, cdc *codec.Codec, codespace sdk.CodespaceType) Keeper {
 	return Keeper{
 		sk:           sk,
+		bk:           bk,
 		marketKeeper: mk,
+		oracleKeeper: ok,
 		storeKey:     storeKey,
+		paramstore:   paramstore.WithKeyTable(types3.ParamKeyTable()),
 		queue:        queue,
 		cdc:          cdc,
+		codespace:    codespace,
+	}
+}
+
+// SetHooks sets the order hooks. It panics if hooks have already been set.
+func (k *Keeper) SetHooks(oh types3.OrderHooks) *Keeper {
+	if k.hooks != nil {
+		panic("cannot set order hooks twice")
 	}
+	k.hooks = oh
+	return k
 }
 
-func (k Keeper) Post(ctx sdk.Context, owner sdk.AccAddress, mktID store.EntityID, direction matcheng.Direction, price sdk.Uint, quantity sdk.Uint, tif uint16) (types3.Order, sdk.Error) {
+// PostOrder escrows the funds for and creates an order. It fails if the
+// owner or the market already has the maximum number of open orders, and
+// charges the posting fee. Iceberg orders escrow their full quantity.
+func (k Keeper) PostOrder(ctx sdk.Context, owner sdk.AccAddress, p types3.OrderParams) (types3.Order, sdk.Error) {
+	return k.post(ctx, owner, p, true)
+}
+
+// post is PostOrder, charging the posting fee only if chargeFee is set.
+// Triggered stop orders paid it when they were placed.
+func (k Keeper) post(ctx sdk.Context, owner sdk.AccAddress, p types3.OrderParams, chargeFee bool) (types3.Order, sdk.Error) {
 	var err sdk.Error
-	mkt, err := k.marketKeeper.Get(ctx, mktID)
+	mkt, err := k.marketKeeper.Get(ctx, p.MarketID)
 	if err != nil {
 		return types3.Order{}, err
 	}
 
-	// validateSufficientQuantity
-	// price - assumed to be the 8 decimal value integer
-
-	var postedAsset string
-	var postedAmt sdk.Uint
-	if direction == matcheng.Bid {
-
-		postedAsset = mkt.QuoteAssetDenom
-		p, err := matcheng.NormalizeQuoteQuantity(price, quantity)
-		if err != nil {
-			return types3.Order{}, sdk.ErrInvalidCoins(err.Error())
+	escrow, err := k.checkOrder(ctx, mkt, p)
+	if err != nil {
+		return types3.Order{}, err
+	}
+	params := k.GetParams(ctx)
+	if err := k.checkOrderLimits(ctx, params, owner, p.MarketID); err != nil {
+		return types3.Order{}, err
+	}
+	if chargeFee {
+		if err := k.chargePostFee(ctx, params, owner); err != nil {
+			return types3.Order{}, err
 		}
-		postedAmt = p
-	} else {
-		postedAsset = mkt.BaseAssetDenom
-		postedAmt = quantity
 	}
+	err = k.sk.SendCoinsFromAccountToModule(ctx, owner, ModuleName, sdk.NewCoins(escrow))
 	if err != nil {
-		// should never happen; implies consensus
-		// or storage bug
-		panic(err)
+		return types3.Order{}, err
 	}
 
-	amount, ok := sdk.NewIntFromString(postedAmt.String())
-	if !ok {
+	return k.create(ctx, owner, p)
+}
+
+// Replace cancels an open order of owner and posts a new order with the same
+// market, direction and type in its place. The old order's escrow is reused
+// for the new one, so only the difference is refunded or taken from the
+// owner. The posting fee is charged for the new order. Iceberg orders keep
+// their display quantity unless the new quantity is no larger than it, in
+// which case the new order shows all of its quantity.
+func (k Keeper) Replace(ctx sdk.Context, owner sdk.AccAddress, id store.EntityID, price sdk.Uint, quantity sdk.Uint, expiry types3.Expiry, stp matcheng.STPMode) (types3.Order, sdk.Error) {
+	var err sdk.Error
+	old, err := k.Get(ctx, id)
+	if err != nil {
+		return types3.Order{}, err
+	}
+	if !old.Owner.Equals(owner) {
+		return types3.Order{}, sdk.ErrUnauthorized("cannot replace unowned order")
+	}
+	if old.Type.IsImmediate() {
+		return types3.Order{}, errs.ErrInvalidArgument("immediate orders cannot be replaced")
+	}
+	mkt, err := k.marketKeeper.Get(ctx, old.MarketID)
+	if err != nil {
 		return types3.Order{}, err
 	}
 
-	err = k.sk.SendCoinsFromAccountToModule(ctx, owner, ModuleName, sdk.NewCoins(sdk.NewCoin(postedAsset, amount)))
+	p := types3.OrderParams{
+		MarketID:        old.MarketID,
+		Direction:       old.Direction,
+		Type:            old.Type,
+		Price:           price,
+		Quantity:        quantity,
+		DisplayQuantity: old.DisplayQuantity,
+		Expiry:          expiry,
+		STPMode:         stp,
+	}
+	if p.DisplayQuantity.GTE(quantity) {
+		p.DisplayQuantity = sdk.ZeroUint()
+	}
+	escrow, err := k.checkOrder(ctx, mkt, p)
 	if err != nil {
 		return types3.Order{}, err
 	}
+	// the new order takes the old one's place, so the limits are not
+	// checked again
+	if err := k.chargePostFee(ctx, k.GetParams(ctx), owner); err != nil {
+		return types3.Order{}, err
+	}
+	oldEscrow := k.Escrow(ctx, old)
+	refund := sdk.NewCoin(oldEscrow.Denom, sdk.ZeroInt())
+	if escrow.IsLT(oldEscrow) {
+		refund = oldEscrow.Sub(escrow)
+	} else if oldEscrow.IsLT(escrow) {
+		err = k.sk.SendCoinsFromAccountToModule(ctx, owner, ModuleName, sdk.NewCoins(escrow.Sub(oldEscrow)))
+		if err != nil {
+			return types3.Order{}, err
+		}
+	}
+	if err := k.cancel(ctx, old, refund, types3.CancelledReplaced); err != nil {
+		return types3.Order{}, err
+	}
 
-	return k.Create(
-		ctx,
-		owner,
-		mktID,
-		direction,
-		price,
-		quantity,
-		tif,
-	)
+	return k.create(ctx, owner, p)
+}
+
+// checkOrder validates a new order against the state of its market and
+// returns the escrow it requires.
+func (k Keeper) checkOrder(ctx sdk.Context, mkt market.Market, p types3.OrderParams) (sdk.Coin, sdk.Error) {
+	if !mkt.Status.AcceptsOrders() {
+		return sdk.Coin{}, errs.ErrInvalidArgument(fmt.Sprintf("market is %s", mkt.Status))
+	}
+	if breaker := k.marketKeeper.GetCircuitBreaker(ctx, mkt.ID); breaker.IsHalted(ctx.BlockHeight()) {
+		return sdk.Coin{}, errs.ErrInvalidArgument(fmt.Sprintf("market is halted by its price band until height %d", breaker.HaltedUntil))
+	}
+
+	if p.Expiry.Type == types3.GoodTillTime && p.Expiry.Time <= ctx.BlockHeader().Time.Unix() {
+		return sdk.Coin{}, errs.ErrInvalidArgument("good till time has already passed")
+	}
+
+	if p.Type == matcheng.PostOnly && k.crossesBook(ctx, mkt.ID, p.Direction, p.Price) {
+		return sdk.Coin{}, errs.ErrInvalidArgument("post-only order would cross the book")
+	}
+
+	if err := checkTradingRules(mkt, p.Type, p.Price, p.Quantity); err != nil {
+		return sdk.Coin{}, err
+	}
+
+	if err := checkDisplay(mkt, p.Type, p.Quantity, p.Display()); err != nil {
+		return sdk.Coin{}, err
+	}
+
+	escrow, err := escrowFor(mkt, p.Direction, p.Price, p.Quantity)
+	if err != nil {
+		return sdk.Coin{}, sdk.ErrInvalidCoins(err.Error())
+	}
+	if !escrow.IsPositive() {
+		return sdk.Coin{}, sdk.ErrInvalidCoins("quantity too small to represent")
+	}
+	return escrow, nil
+}
+
+// create creates an escrowed order and runs the order hooks on it.
+func (k Keeper) create(ctx sdk.Context, owner sdk.AccAddress, p types3.OrderParams) (types3.Order, sdk.Error) {
+	order, err := k.Create(ctx, owner, p)
+	if err != nil {
+		return order, err
+	}
+
+	if k.hooks != nil {
+		if err := k.hooks.AfterOrderPosted(ctx, order); err != nil {
+			return order, err
+		}
+	}
+	return order, nil
 }
 
-func (k Keeper) Create(ctx sdk.Context, owner sdk.AccAddress, marketID store.EntityID, direction matcheng.Direction, price sdk.Uint, quantity sdk.Uint, tif uint16) (types3.Order, sdk.Error) {
+func (k Keeper) Create(ctx sdk.Context, owner sdk.AccAddress, p types3.OrderParams) (types3.Order, sdk.Error) {
 	id := k.incrementSeq(ctx)
 	order := types3.Order{
 		ID:                id,
 		Owner:             owner,
-		MarketID:          marketID,
-		Direction:         direction,
-		Price:             price,
-		Quantity:          quantity,
-		TimeInForceBlocks: tif,
+		MarketID:          p.MarketID,
+		Direction:         p.Direction,
+		Price:             p.Price,
+		Quantity:          p.Quantity,
+		TimeInForceBlocks: p.Expiry.Blocks,
 		CreatedBlock:      ctx.BlockHeight(),
+		Type:              p.Type,
+		ExpiryType:        p.Expiry.Type,
+		GoodTillTime:      p.Expiry.Time,
+		STPMode:           p.STPMode,
+		Status:            types3.Open,
+		InitialQuantity:   p.Quantity,
+		FilledQuantity:    sdk.ZeroUint(),
+		FilledValue:       sdk.ZeroUint(),
+		DisplayQuantity:   p.Display(),
 	}
 	err := store.SetNotExists(ctx, k.storeKey, k.cdc, orderKey(id), order)
-	_ = k.queue.Publish(types.OrderCreated{
+	if err != nil {
+		return order, err
+	}
+	k.indexOrder(ctx, order)
+	k.countOrder(ctx, order, true)
+	ev := types.OrderCreated{
 		ID:                order.ID,
 		Owner:             order.Owner,
 		MarketID:          order.MarketID,
@@ -110,59 +253,239 @@ func (k Keeper) Create(ctx sdk.Context, owner sdk.AccAddress, marketID store.Ent
 		Quantity:          order.Quantity,
 		TimeInForceBlocks: order.TimeInForceBlocks,
 		CreatedBlock:      order.CreatedBlock,
-	})
+		Type:              order.Type,
+		ExpiryType:        order.ExpiryType,
+		GoodTillTime:      order.GoodTillTime,
+		STPMode:           order.STPMode,
+	}
+	if order.IsIceberg() {
+		ev.DisplayQuantity = &order.DisplayQuantity
+	}
+	k.queue.Publish(ctx, ev)
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types3.EventTypePostOrder,
+			sdk.NewAttribute(types3.AttributeKeyOrderID, order.ID.String()),
+			sdk.NewAttribute(types3.AttributeKeyMarketID, order.MarketID.String()),
+			sdk.NewAttribute(types3.AttributeKeyOwner, order.Owner.String()),
+			sdk.NewAttribute(types3.AttributeKeyDirection, order.Direction.String()),
+			sdk.NewAttribute(types3.AttributeKeyOrderType, order.Type.String()),
+			sdk.NewAttribute(types3.AttributeKeyPrice, order.Price.String()),
+			sdk.NewAttribute(types3.AttributeKeyQuantity, order.Quantity.String()),
+		),
+	)
 
-	return order, err
+	return order, nil
 }
 
+// Cancel refunds the escrow of and deletes an order cancelled by its owner.
+// Owners can only cancel orders in markets that accept cancellations.
 func (k Keeper) Cancel(ctx sdk.Context, id store.EntityID) sdk.Error {
-	var err sdk.Error
 	ord, err := k.Get(ctx, id)
 	if err != nil {
 		return err
 	}
 	mkt, err := k.marketKeeper.Get(ctx, ord.MarketID)
 	if err != nil {
-		// should never happen; implies consensus
-		// or storage bug
-		panic(err)
+		return err
+	}
+	if !mkt.Status.AcceptsCancels() {
+		return errs.ErrInvalidArgument(fmt.Sprintf("market is %s", mkt.Status))
 	}
+	return k.cancel(ctx, ord, k.Escrow(ctx, ord), types3.CancelledByOwner)
+}
+
+// CancelWithReason refunds the escrow of and deletes an order, recording
+// why it was cancelled.
+func (k Keeper) CancelWithReason(ctx sdk.Context, id store.EntityID, reason types3.CancelReason) sdk.Error {
+	ord, err := k.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	return k.cancel(ctx, ord, k.Escrow(ctx, ord), reason)
+}
 
-	var postedAsset string
-	var postedAmt sdk.Uint
-	if ord.Direction == matcheng.Bid {
-		postedAsset = mkt.QuoteAssetDenom
-		p, err := matcheng.NormalizeQuoteQuantity(ord.Price, ord.Quantity)
+// CancelAll cancels the open orders of owner. Only orders in mktID are
+// cancelled if it is defined, and only orders in direction if it is not
+// nil. Orders in markets that do not accept cancellations are skipped. It
+// returns the IDs of the cancelled orders.
+func (k Keeper) CancelAll(ctx sdk.Context, owner sdk.AccAddress, mktID store.EntityID, direction *matcheng.Direction) ([]store.EntityID, sdk.Error) {
+	var ids []store.EntityID
+	cancellable := make(map[string]bool)
+	k.OwnerIterator(ctx, owner, func(ord types3.Order) bool {
+		if mktID.IsDefined() && !ord.MarketID.Equals(mktID) {
+			return true
+		}
+		if direction != nil && ord.Direction != *direction {
+			return true
+		}
+		ok, seen := cancellable[ord.MarketID.String()]
+		if !seen {
+			mkt, err := k.marketKeeper.Get(ctx, ord.MarketID)
+			ok = err == nil && mkt.Status.AcceptsCancels()
+			cancellable[ord.MarketID.String()] = ok
+		}
+		if !ok {
+			return true
+		}
+		ids = append(ids, ord.ID)
+		return true
+	})
+
+	for _, id := range ids {
+		if err := k.Cancel(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+	return ids, nil
+}
+
+// CancelMarket refunds every open order in a market and drops its pending
+// trigger orders. It returns the IDs of the cancelled orders.
+func (k Keeper) CancelMarket(ctx sdk.Context, mktID store.EntityID) ([]store.EntityID, sdk.Error) {
+	var ids []store.EntityID
+	k.MarketIterator(ctx, mktID, func(ord types3.Order) bool {
+		ids = append(ids, ord.ID)
+		return true
+	})
+	for _, id := range ids {
+		if err := k.CancelWithReason(ctx, id, types3.CancelledDelisted); err != nil {
+			return nil, err
+		}
+	}
+
+	var triggers []store.EntityID
+	k.MarketTriggerIterator(ctx, mktID, func(trig types3.TriggerOrder) bool {
+		triggers = append(triggers, trig.ID)
+		return true
+	})
+	for _, id := range triggers {
+		if err := k.DelTrigger(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+	return ids, nil
+}
+
+// cancel closes an order and refunds refund out of its escrow to the
+// owner.
+func (k Keeper) cancel(ctx sdk.Context, ord types3.Order, refund sdk.Coin, reason types3.CancelReason) sdk.Error {
+	if refund.IsPositive() {
+		err := k.sk.SendCoinsFromModuleToAccount(ctx, ModuleName, ord.Owner, sdk.NewCoins(refund))
 		if err != nil {
-			return sdk.ErrInvalidCoins(err.Error())
+			// should never happen, implies consensus
+			// or storage bug
+			panic(err)
 		}
-		postedAmt = p
-	} else {
-		postedAsset = mkt.BaseAssetDenom
-		postedAmt = ord.Quantity
 	}
-	if err != nil {
-		// should never happen; implies consensus
-		// or storage bug
-		panic(err)
+	k.queue.Publish(ctx, types.OrderCancelled{
+		OrderID: ord.ID,
+		Reason:  reason,
+	})
+	eventType := types3.EventTypeCancelOrder
+	if reason == types3.CancelledExpired {
+		eventType = types3.EventTypeExpireOrder
 	}
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			eventType,
+			sdk.NewAttribute(types3.AttributeKeyOrderID, ord.ID.String()),
+			sdk.NewAttribute(types3.AttributeKeyMarketID, ord.MarketID.String()),
+			sdk.NewAttribute(types3.AttributeKeyOwner, ord.Owner.String()),
+			sdk.NewAttribute(types3.AttributeKeyQuantity, ord.Quantity.String()),
+			sdk.NewAttribute(types3.AttributeKeyReason, reason.String()),
+		),
+	)
+
+	return k.Close(ctx, ord, reason.Status())
+}
 
-	amount, ok := sdk.NewIntFromString(postedAmt.String())
-	if !ok {
+// Reduce cancels quantity of an order without filling it and refunds the
+// escrow no longer needed. Orders reduced to nothing are cancelled, and so
+// are bids reduced to a remainder too small to hold any escrow.
+func (k Keeper) Reduce(ctx sdk.Context, id store.EntityID, quantity sdk.Uint, reason types3.CancelReason) sdk.Error {
+	var err sdk.Error
+	ord, err := k.Get(ctx, id)
+	if err != nil {
 		return err
 	}
+	if !quantity.LT(ord.Quantity) {
+		return k.CancelWithReason(ctx, id, reason)
+	}
 
-	err = k.sk.SendCoinsFromModuleToAccount(ctx, ModuleName, ord.Owner, sdk.NewCoins(sdk.NewCoin(postedAsset, amount)))
+	remaining := ord.Quantity.Sub(quantity)
+	mkt, err := k.marketKeeper.Get(ctx, ord.MarketID)
 	if err != nil {
-		// should never happen, implies consensus
-		// or storage bug
-		panic(err)
+		return err
+	}
+	if _, qErr := escrowFor(mkt, ord.Direction, ord.Price, remaining); qErr != nil {
+		return k.CancelWithReason(ctx, id, reason)
+	}
+	refund := k.ReleaseEscrow(ctx, ord, remaining)
+	ord.Quantity = remaining
+	if refund.IsPositive() {
+		if err := k.PayFromDust(ctx, ord.Owner, sdk.NewCoins(refund)); err != nil {
+			// should never happen, implies consensus
+			// or storage bug
+			panic(err)
+		}
 	}
-	_ = k.queue.Publish(types.OrderCancelled{
-		OrderID: id,
+	k.queue.Publish(ctx, types.OrderReduced{
+		OrderID:  id,
+		Quantity: quantity,
+		Reason:   reason,
 	})
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types3.EventTypeReduceOrder,
+			sdk.NewAttribute(types3.AttributeKeyOrderID, ord.ID.String()),
+			sdk.NewAttribute(types3.AttributeKeyMarketID, ord.MarketID.String()),
+			sdk.NewAttribute(types3.AttributeKeyOwner, ord.Owner.String()),
+			sdk.NewAttribute(types3.AttributeKeyQuantity, quantity.String()),
+			sdk.NewAttribute(types3.AttributeKeyReason, reason.String()),
+		),
+	)
+
+	return k.Set(ctx, ord)
+}
+
+// checkTradingRules enforces the market's tick size, lot size and minimum
+// notional. Market orders are not held to the tick size since their price
+// only bounds slippage.
+func checkTradingRules(mkt market.Market, orderType matcheng.OrderType, price sdk.Uint, quantity sdk.Uint) sdk.Error {
+	rules := mkt.Rules
+	if orderType != matcheng.Market {
+		if err := rules.CheckPrice(price); err != nil {
+			return errs.ErrInvalidArgument(err.Error())
+		}
+	}
+	if err := rules.CheckQuantity(quantity); err != nil {
+		return errs.ErrInvalidArgument(err.Error())
+	}
+	notional, _ := mkt.QuoteQuantity(price, quantity)
+	if err := rules.CheckNotional(notional); err != nil {
+		return errs.ErrInvalidArgument(err.Error())
+	}
+	return nil
+}
 
-	return k.Del(ctx, ord.ID)
+// checkDisplay validates the display quantity of an iceberg order. Orders
+// with a zero display quantity show all of their quantity. Immediate orders
+// never rest in the book, so they cannot hide any of it.
+func checkDisplay(mkt market.Market, orderType matcheng.OrderType, quantity sdk.Uint, display sdk.Uint) sdk.Error {
+	if display.IsZero() {
+		return nil
+	}
+	if orderType.IsImmediate() {
+		return errs.ErrInvalidArgument("immediate orders cannot be iceberg orders")
+	}
+	if !display.LT(quantity) {
+		return errs.ErrInvalidArgument("display quantity must be less than the quantity")
+	}
+	if err := mkt.Rules.CheckQuantity(display); err != nil {
+		return errs.ErrInvalidArgument(fmt.Sprintf("display quantity: %s", err))
+	}
+	return nil
 }
 
 func (k Keeper) Get(ctx sdk.Context, id store.EntityID) (types3.Order, sdk.Error) {
@@ -172,6 +495,12 @@ func (k Keeper) Get(ctx sdk.Context, id store.EntityID) (types3.Order, sdk.Error
 }
 
 func (k Keeper) Set(ctx sdk.Context, order types3.Order) sdk.Error {
+	old, err := k.Get(ctx, order.ID)
+	if err != nil {
+		return err
+	}
+	k.unindexOrder(ctx, old)
+	k.indexOrder(ctx, order)
 	return store.SetExists(ctx, k.storeKey, k.cdc, orderKey(order.ID), order)
 }
 
@@ -180,6 +509,12 @@ func (k Keeper) Has(ctx sdk.Context, id store.EntityID) bool {
 }
 
 func (k Keeper) Del(ctx sdk.Context, id store.EntityID) sdk.Error {
+	order, err := k.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	k.unindexOrder(ctx, order)
+	k.countOrder(ctx, order, false)
 	return store.Del(ctx, k.storeKey, orderKey(id))
 }
 
@@ -212,6 +547,21 @@ func (k Keeper) doIterator(iter sdk.Iterator, cb IteratorCB) {
 	}
 }
 
+// crossesBook returns true if an order at the given price would match a
+// resting order on the opposite side of the market.
+func (k Keeper) crossesBook(ctx sdk.Context, mktID store.EntityID, direction matcheng.Direction, price sdk.Uint) bool {
+	var crosses bool
+	k.BookIterator(ctx, mktID, direction.Opposite(), func(best types3.Order) bool {
+		if direction == matcheng.Bid {
+			crosses = !best.Price.GT(price)
+		} else {
+			crosses = best.Price.GTE(price)
+		}
+		return false
+	})
+	return crosses
+}
+
 func orderKey(id store.EntityID) []byte {
 	return store.PrefixKeyString(valKey, id.Bytes())
 }
@@ -2,6 +2,7 @@ package order_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -12,11 +13,15 @@ import (
 	"github.com/xar-network/xar-network/testutil/testflags"
 	"github.com/xar-network/xar-network/types/errs"
 	"github.com/xar-network/xar-network/types/store"
+	"github.com/xar-network/xar-network/x/authority"
 	"github.com/xar-network/xar-network/x/denominations"
 	types2 "github.com/xar-network/xar-network/x/market/types"
+	"github.com/xar-network/xar-network/x/oracle"
+	"github.com/xar-network/xar-network/x/order"
 	types4 "github.com/xar-network/xar-network/x/order/types"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
 	"github.com/cosmos/cosmos-sdk/x/supply"
 )
 
@@ -24,6 +29,7 @@ type testCtx struct {
 	ctx      sdk.Context
 	marketID store.EntityID
 	owner    sdk.AccAddress
+	nominee  sdk.AccAddress
 	buyer    sdk.AccAddress
 	seller   sdk.AccAddress
 	app      *mockapp.MockApp
@@ -36,31 +42,61 @@ func TestKeeper_Post(t *testing.T) {
 	testflags.UnitTest(t)
 	t.Run("returns an error for a nonexistent market", func(t *testing.T) {
 		ctx := setupTest(t)
-		_, err := ctx.app.OrderKeeper.Post(ctx.ctx, ctx.buyer, ctx.marketID.Inc(), matcheng.Bid, testutil.ToBaseUnits(1), testutil.ToBaseUnits(10), 599)
+		_, err := ctx.app.OrderKeeper.PostOrder(ctx.ctx, ctx.buyer, types4.OrderParams{
+			MarketID:  ctx.marketID.Inc(),
+			Direction: matcheng.Bid,
+			Price:     testutil.ToBaseUnits(1),
+			Quantity:  testutil.ToBaseUnits(10),
+			Expiry:    types4.NewBlockExpiry(599),
+		})
 		assert.Error(t, err)
 		assert.Equal(t, err.Code(), errs.CodeNotFound)
 	})
 	t.Run("returns an error if buying more than owned coins", func(t *testing.T) {
 		ctx := setupTest(t)
-		_, err := ctx.app.OrderKeeper.Post(ctx.ctx, ctx.buyer, ctx.marketID, matcheng.Bid, testutil.ToBaseUnits(2), testutil.ToBaseUnits(5001), 599)
+		_, err := ctx.app.OrderKeeper.PostOrder(ctx.ctx, ctx.buyer, types4.OrderParams{
+			MarketID:  ctx.marketID,
+			Direction: matcheng.Bid,
+			Price:     testutil.ToBaseUnits(2),
+			Quantity:  testutil.ToBaseUnits(5001),
+			Expiry:    types4.NewBlockExpiry(599),
+		})
 		assert.Error(t, err)
 		assert.Equal(t, err.Code(), sdk.CodeInsufficientCoins)
 	})
 	t.Run("returns an error if selling more than owned coins", func(t *testing.T) {
 		ctx := setupTest(t)
-		_, err := ctx.app.OrderKeeper.Post(ctx.ctx, ctx.seller, ctx.marketID, matcheng.Ask, testutil.ToBaseUnits(2), testutil.ToBaseUnits(10001), 599)
+		_, err := ctx.app.OrderKeeper.PostOrder(ctx.ctx, ctx.seller, types4.OrderParams{
+			MarketID:  ctx.marketID,
+			Direction: matcheng.Ask,
+			Price:     testutil.ToBaseUnits(2),
+			Quantity:  testutil.ToBaseUnits(10001),
+			Expiry:    types4.NewBlockExpiry(599),
+		})
 		assert.Error(t, err)
 		assert.Equal(t, err.Code(), sdk.CodeInsufficientCoins)
 	})
 	t.Run("returns an error if trying to post a non-representable order", func(t *testing.T) {
 		ctx := setupTest(t)
-		_, err := ctx.app.OrderKeeper.Post(ctx.ctx, ctx.seller, ctx.marketID, matcheng.Bid, sdk.NewUint(2), sdk.NewUint(2), 599)
+		_, err := ctx.app.OrderKeeper.PostOrder(ctx.ctx, ctx.seller, types4.OrderParams{
+			MarketID:  ctx.marketID,
+			Direction: matcheng.Bid,
+			Price:     sdk.NewUint(2),
+			Quantity:  sdk.NewUint(2),
+			Expiry:    types4.NewBlockExpiry(599),
+		})
 		assert.Error(t, err)
 		assert.Equal(t, err.Code(), sdk.CodeInvalidCoins)
 	})
 	t.Run("creates the order", func(t *testing.T) {
 		ctx := setupTest(t)
-		created, err := ctx.app.OrderKeeper.Post(ctx.ctx, ctx.buyer, ctx.marketID, matcheng.Bid, testutil.ToBaseUnits(1), testutil.ToBaseUnits(10), 599)
+		created, err := ctx.app.OrderKeeper.PostOrder(ctx.ctx, ctx.buyer, types4.OrderParams{
+			MarketID:  ctx.marketID,
+			Direction: matcheng.Bid,
+			Price:     testutil.ToBaseUnits(1),
+			Quantity:  testutil.ToBaseUnits(10),
+			Expiry:    types4.NewBlockExpiry(599),
+		})
 		require.NoError(t, err)
 		retrieved, err := ctx.app.OrderKeeper.Get(ctx.ctx, created.ID)
 		require.NoError(t, err)
@@ -68,39 +104,714 @@ func TestKeeper_Post(t *testing.T) {
 	})
 	t.Run("debits the correct coins", func(t *testing.T) {
 		ctx := setupTest(t)
-		_, err := ctx.app.OrderKeeper.Post(ctx.ctx, ctx.buyer, ctx.marketID, matcheng.Bid, testutil.ToBaseUnits(2), testutil.ToBaseUnits(10), 599)
+		_, err := ctx.app.OrderKeeper.PostOrder(ctx.ctx, ctx.buyer, types4.OrderParams{
+			MarketID:  ctx.marketID,
+			Direction: matcheng.Bid,
+			Price:     testutil.ToBaseUnits(2),
+			Quantity:  testutil.ToBaseUnits(10),
+			Expiry:    types4.NewBlockExpiry(599),
+		})
 		require.NoError(t, err)
-		_, err = ctx.app.OrderKeeper.Post(ctx.ctx, ctx.seller, ctx.marketID, matcheng.Ask, testutil.ToBaseUnits(2), testutil.ToBaseUnits(10), 599)
+		_, err = ctx.app.OrderKeeper.PostOrder(ctx.ctx, ctx.seller, types4.OrderParams{
+			MarketID:  ctx.marketID,
+			Direction: matcheng.Ask,
+			Price:     testutil.ToBaseUnits(2),
+			Quantity:  testutil.ToBaseUnits(10),
+			Expiry:    types4.NewBlockExpiry(599),
+		})
 		require.NoError(t, err)
 	})
 }
 
+func TestKeeper_PostOrderTypes(t *testing.T) {
+	testflags.UnitTest(t)
+	t.Run("rejects post-only orders that would cross the book", func(t *testing.T) {
+		ctx := setupTest(t)
+		_, err := ctx.app.OrderKeeper.PostOrder(ctx.ctx, ctx.seller, types4.OrderParams{
+			MarketID:  ctx.marketID,
+			Direction: matcheng.Ask,
+			Price:     testutil.ToBaseUnits(2),
+			Quantity:  testutil.ToBaseUnits(10),
+			Expiry:    types4.NewBlockExpiry(599),
+		})
+		require.NoError(t, err)
+		_, err = ctx.app.OrderKeeper.PostOrder(ctx.ctx, ctx.buyer, types4.OrderParams{
+			MarketID:  ctx.marketID,
+			Direction: matcheng.Bid,
+			Type:      matcheng.PostOnly,
+			Price:     testutil.ToBaseUnits(2),
+			Quantity:  testutil.ToBaseUnits(10),
+			Expiry:    types4.NewBlockExpiry(599),
+		})
+		assert.Error(t, err)
+		assert.Equal(t, err.Code(), errs.CodeInvalidArgument)
+	})
+	t.Run("accepts post-only orders that rest in the book", func(t *testing.T) {
+		ctx := setupTest(t)
+		_, err := ctx.app.OrderKeeper.PostOrder(ctx.ctx, ctx.seller, types4.OrderParams{
+			MarketID:  ctx.marketID,
+			Direction: matcheng.Ask,
+			Price:     testutil.ToBaseUnits(2),
+			Quantity:  testutil.ToBaseUnits(10),
+			Expiry:    types4.NewBlockExpiry(599),
+		})
+		require.NoError(t, err)
+		created, err := ctx.app.OrderKeeper.PostOrder(ctx.ctx, ctx.buyer, types4.OrderParams{
+			MarketID:  ctx.marketID,
+			Direction: matcheng.Bid,
+			Type:      matcheng.PostOnly,
+			Price:     testutil.ToBaseUnits(1),
+			Quantity:  testutil.ToBaseUnits(10),
+			Expiry:    types4.NewBlockExpiry(599),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, matcheng.PostOnly, created.Type)
+	})
+}
+
+func TestKeeper_TradingRules(t *testing.T) {
+	testflags.UnitTest(t)
+	ctx := setupTest(t)
+	nominee := ctx.nominee.String()
+	rules := types2.NewTradingRules(1000000, 1000000, testutil.ToBaseUnits(1).Uint64(), 6, 0)
+	mkt, err := ctx.app.MarketKeeper.CreateMarket(ctx.ctx, nominee, types2.MarketParams{
+		BaseAsset:    "tst1",
+		QuoteAsset:   "tst2",
+		MatchingMode: types2.MatchingModeBatch,
+		Rules:        rules,
+	})
+	require.NoError(t, err)
+
+	post := func(orderType matcheng.OrderType, price, quantity uint64) sdk.Error {
+		_, err := ctx.app.OrderKeeper.PostOrder(ctx.ctx, ctx.buyer, types4.OrderParams{
+			MarketID:  mkt.ID,
+			Direction: matcheng.Bid,
+			Type:      orderType,
+			Price:     sdk.NewUint(price),
+			Quantity:  sdk.NewUint(quantity),
+			Expiry:    types4.NewBlockExpiry(599),
+		})
+		return err
+	}
+	t.Run("rejects prices off the tick size", func(t *testing.T) {
+		err := post(matcheng.Limit, 150000001, 2000000)
+		require.Error(t, err)
+		assert.Equal(t, errs.CodeInvalidArgument, err.Code())
+	})
+	t.Run("does not hold market orders to the tick size", func(t *testing.T) {
+		require.NoError(t, post(matcheng.Market, 150000001, 2000000))
+	})
+	t.Run("rejects quantities off the lot size", func(t *testing.T) {
+		err := post(matcheng.Limit, 150000000, 2500000)
+		require.Error(t, err)
+		assert.Equal(t, errs.CodeInvalidArgument, err.Code())
+	})
+	t.Run("rejects orders below the minimum notional", func(t *testing.T) {
+		err := post(matcheng.Limit, 50000000, 1000000)
+		require.Error(t, err)
+		assert.Equal(t, errs.CodeInvalidArgument, err.Code())
+	})
+	t.Run("escrows using the base asset decimals", func(t *testing.T) {
+		before := ctx.app.BankKeeper.GetCoins(ctx.ctx, ctx.buyer).AmountOf("tst2")
+		require.NoError(t, post(matcheng.Limit, 150000000, 2000000))
+		after := ctx.app.BankKeeper.GetCoins(ctx.ctx, ctx.buyer).AmountOf("tst2")
+		assert.Equal(t, sdk.NewInt(300000000), before.Sub(after))
+	})
+}
+
+func marketTriggers(ctx *testCtx) []store.EntityID {
+	var ids []store.EntityID
+	ctx.app.OrderKeeper.MarketTriggerIterator(ctx.ctx, ctx.marketID, func(trig types4.TriggerOrder) bool {
+		ids = append(ids, trig.ID)
+		return true
+	})
+	return ids
+}
+
+func TestKeeper_ProcessTriggers(t *testing.T) {
+	testflags.UnitTest(t)
+	t.Run("keeps stop orders dormant until the clearing price is reached", func(t *testing.T) {
+		ctx := setupTest(t)
+		trig, err := ctx.app.OrderKeeper.PostStop(ctx.ctx, ctx.buyer, ctx.marketID, matcheng.Bid, matcheng.Limit, testutil.ToBaseUnits(2), testutil.ToBaseUnits(3), testutil.ToBaseUnits(10), 599, types4.TriggerClearing)
+		require.NoError(t, err)
+		before := ctx.app.BankKeeper.GetCoins(ctx.ctx, ctx.buyer)
+
+		ctx.app.OrderKeeper.ProcessTriggers(ctx.ctx)
+		assert.True(t, ctx.app.OrderKeeper.HasTrigger(ctx.ctx, trig.ID))
+
+		ctx.app.MarketKeeper.SetLastPrice(ctx.ctx, ctx.marketID, testutil.ToBaseUnits(1))
+		ctx.app.OrderKeeper.ProcessTriggers(ctx.ctx)
+		assert.True(t, ctx.app.OrderKeeper.HasTrigger(ctx.ctx, trig.ID))
+		assert.Equal(t, before, ctx.app.BankKeeper.GetCoins(ctx.ctx, ctx.buyer))
+
+		assert.Equal(t, []store.EntityID{trig.ID}, marketTriggers(ctx))
+
+		ctx.app.MarketKeeper.SetLastPrice(ctx.ctx, ctx.marketID, testutil.ToBaseUnits(2))
+		ctx.app.OrderKeeper.ProcessTriggers(ctx.ctx)
+		assert.False(t, ctx.app.OrderKeeper.HasTrigger(ctx.ctx, trig.ID))
+		assert.Empty(t, marketTriggers(ctx))
+
+		ord, err := ctx.app.OrderKeeper.Get(ctx.ctx, store.NewEntityID(1))
+		require.NoError(t, err)
+		assert.Equal(t, ctx.buyer, ord.Owner)
+		testutil.AssertEqualUints(t, testutil.ToBaseUnits(3), ord.Price)
+		testutil.AssertEqualUints(t, testutil.ToBaseUnits(10), ord.Quantity)
+		escrowed := before.AmountOf("tst2").Sub(ctx.app.BankKeeper.GetCoins(ctx.ctx, ctx.buyer).AmountOf("tst2"))
+		assert.Equal(t, testutil.ToBaseUnits(30).String(), escrowed.String())
+	})
+	t.Run("triggers sell stops when the price falls", func(t *testing.T) {
+		ctx := setupTest(t)
+		trig, err := ctx.app.OrderKeeper.PostStop(ctx.ctx, ctx.seller, ctx.marketID, matcheng.Ask, matcheng.Limit, testutil.ToBaseUnits(2), testutil.ToBaseUnits(1), testutil.ToBaseUnits(10), 599, types4.TriggerClearing)
+		require.NoError(t, err)
+
+		ctx.app.MarketKeeper.SetLastPrice(ctx.ctx, ctx.marketID, testutil.ToBaseUnits(3))
+		ctx.app.OrderKeeper.ProcessTriggers(ctx.ctx)
+		assert.True(t, ctx.app.OrderKeeper.HasTrigger(ctx.ctx, trig.ID))
+
+		ctx.app.MarketKeeper.SetLastPrice(ctx.ctx, ctx.marketID, testutil.ToBaseUnits(2))
+		ctx.app.OrderKeeper.ProcessTriggers(ctx.ctx)
+		assert.False(t, ctx.app.OrderKeeper.HasTrigger(ctx.ctx, trig.ID))
+		assert.True(t, ctx.app.OrderKeeper.Has(ctx.ctx, store.NewEntityID(1)))
+	})
+	t.Run("rejects stop orders the owner cannot cover", func(t *testing.T) {
+		ctx := setupTest(t)
+		_, err := ctx.app.OrderKeeper.PostStop(ctx.ctx, ctx.seller, ctx.marketID, matcheng.Ask, matcheng.Limit, testutil.ToBaseUnits(2), testutil.ToBaseUnits(1), testutil.ToBaseUnits(10001), 599, types4.TriggerClearing)
+		require.Error(t, err)
+		assert.Equal(t, sdk.CodeInsufficientCoins, err.Code())
+		assert.Empty(t, marketTriggers(ctx))
+	})
+	t.Run("drops triggered orders that cannot be escrowed", func(t *testing.T) {
+		ctx := setupTest(t)
+		trig, err := ctx.app.OrderKeeper.PostStop(ctx.ctx, ctx.seller, ctx.marketID, matcheng.Ask, matcheng.Limit, testutil.ToBaseUnits(2), testutil.ToBaseUnits(1), testutil.ToBaseUnits(10), 599, types4.TriggerClearing)
+		require.NoError(t, err)
+		// the seller spends the funds before the order triggers
+		require.NoError(t, ctx.app.BankKeeper.SendCoins(ctx.ctx, ctx.seller, testutil.RandAddr(), ctx.app.BankKeeper.GetCoins(ctx.ctx, ctx.seller)))
+
+		ctx.ctx = ctx.ctx.WithEventManager(sdk.NewEventManager())
+		ctx.app.MarketKeeper.SetLastPrice(ctx.ctx, ctx.marketID, testutil.ToBaseUnits(1))
+		ctx.app.OrderKeeper.ProcessTriggers(ctx.ctx)
+		assert.False(t, ctx.app.OrderKeeper.HasTrigger(ctx.ctx, trig.ID))
+		assert.False(t, ctx.app.OrderKeeper.Has(ctx.ctx, store.NewEntityID(1)))
+		events := ctx.ctx.EventManager().Events()
+		require.Len(t, events, 1)
+		assert.Equal(t, types4.EventTypeDropStopOrder, events[0].Type)
+		assert.Contains(t, events[0].Attributes, sdk.NewAttribute(types4.AttributeKeyStopOrderID, trig.ID.String()).ToKVPair())
+		assert.Contains(t, events[0].Attributes, sdk.NewAttribute(types4.AttributeKeyOwner, ctx.seller.String()).ToKVPair())
+	})
+	t.Run("triggers only the crossed stop prices", func(t *testing.T) {
+		ctx := setupTest(t)
+		stop := func(owner sdk.AccAddress, direction matcheng.Direction, stopPrice uint64) types4.TriggerOrder {
+			trig, err := ctx.app.OrderKeeper.PostStop(ctx.ctx, owner, ctx.marketID, direction, matcheng.Limit, testutil.ToBaseUnits(stopPrice), testutil.ToBaseUnits(stopPrice), testutil.ToBaseUnits(1), 599, types4.TriggerClearing)
+			require.NoError(t, err)
+			return trig
+		}
+		buy4, buy2, buy3 := stop(ctx.buyer, matcheng.Bid, 4), stop(ctx.buyer, matcheng.Bid, 2), stop(ctx.buyer, matcheng.Bid, 3)
+		sell1, sell5 := stop(ctx.seller, matcheng.Ask, 1), stop(ctx.seller, matcheng.Ask, 5)
+
+		ctx.app.MarketKeeper.SetLastPrice(ctx.ctx, ctx.marketID, testutil.ToBaseUnits(3))
+		ctx.app.OrderKeeper.ProcessTriggers(ctx.ctx)
+		assert.Equal(t, []store.EntityID{buy4.ID, sell1.ID}, marketTriggers(ctx))
+
+		// the crossed stops are posted in the order the price crossed them
+		first, err := ctx.app.OrderKeeper.Get(ctx.ctx, store.NewEntityID(1))
+		require.NoError(t, err)
+		testutil.AssertEqualUints(t, testutil.ToBaseUnits(2), first.Price)
+		second, err := ctx.app.OrderKeeper.Get(ctx.ctx, store.NewEntityID(2))
+		require.NoError(t, err)
+		testutil.AssertEqualUints(t, testutil.ToBaseUnits(3), second.Price)
+		third, err := ctx.app.OrderKeeper.Get(ctx.ctx, store.NewEntityID(3))
+		require.NoError(t, err)
+		testutil.AssertEqualUints(t, testutil.ToBaseUnits(5), third.Price)
+		assert.False(t, ctx.app.OrderKeeper.HasTrigger(ctx.ctx, buy2.ID))
+		assert.False(t, ctx.app.OrderKeeper.HasTrigger(ctx.ctx, buy3.ID))
+		assert.False(t, ctx.app.OrderKeeper.HasTrigger(ctx.ctx, sell5.ID))
+	})
+	t.Run("rejects oracle stops for markets without an oracle asset", func(t *testing.T) {
+		ctx := setupTest(t)
+		_, err := ctx.app.OrderKeeper.PostStop(ctx.ctx, ctx.buyer, ctx.marketID, matcheng.Bid, matcheng.Limit, testutil.ToBaseUnits(2), testutil.ToBaseUnits(3), testutil.ToBaseUnits(10), 599, types4.TriggerOracle)
+		assert.Error(t, err)
+		assert.Equal(t, err.Code(), errs.CodeInvalidArgument)
+	})
+	t.Run("triggers on the oracle price", func(t *testing.T) {
+		ctx := setupTest(t)
+		oracleAddr := testutil.RandAddr()
+		ctx.app.OracleKeeper.SetParams(ctx.ctx, oracle.NewParams(
+			oracle.Assets{{AssetCode: "tst1:tst2", BaseAsset: "tst1", QuoteAsset: "tst2", Oracles: oracle.Oracles{{Address: oracleAddr}}, Active: true}},
+		))
+		trig, err := ctx.app.OrderKeeper.PostStop(ctx.ctx, ctx.buyer, ctx.marketID, matcheng.Bid, matcheng.Limit, testutil.ToBaseUnits(2), testutil.ToBaseUnits(3), testutil.ToBaseUnits(10), 599, types4.TriggerOracle)
+		require.NoError(t, err)
+
+		// the clearing price is ignored by oracle stops
+		ctx.app.MarketKeeper.SetLastPrice(ctx.ctx, ctx.marketID, testutil.ToBaseUnits(5))
+		_, err = ctx.app.OracleKeeper.SetPrice(ctx.ctx, oracleAddr, "tst1:tst2", sdk.NewDecWithPrec(15, 1), ctx.ctx.BlockTime().Add(time.Hour))
+		require.NoError(t, err)
+		require.NoError(t, ctx.app.OracleKeeper.SetCurrentPrices(ctx.ctx))
+		ctx.app.OrderKeeper.ProcessTriggers(ctx.ctx)
+		assert.True(t, ctx.app.OrderKeeper.HasTrigger(ctx.ctx, trig.ID))
+
+		_, err = ctx.app.OracleKeeper.SetPrice(ctx.ctx, oracleAddr, "tst1:tst2", sdk.NewDecWithPrec(25, 1), ctx.ctx.BlockTime().Add(time.Hour))
+		require.NoError(t, err)
+		require.NoError(t, ctx.app.OracleKeeper.SetCurrentPrices(ctx.ctx))
+		ctx.app.OrderKeeper.ProcessTriggers(ctx.ctx)
+		assert.False(t, ctx.app.OrderKeeper.HasTrigger(ctx.ctx, trig.ID))
+		assert.True(t, ctx.app.OrderKeeper.Has(ctx.ctx, store.NewEntityID(1)))
+	})
+}
+
 func TestKeeper_Cancel(t *testing.T) {
 	testflags.UnitTest(t)
 	t.Run("returns an error for a nonexistent order", func(t *testing.T) {
 		ctx := setupTest(t)
-		_, err := ctx.app.OrderKeeper.Post(ctx.ctx, ctx.buyer, store.NewEntityID(0), matcheng.Bid, testutil.ToBaseUnits(2), testutil.ToBaseUnits(10), 599)
+		_, err := ctx.app.OrderKeeper.PostOrder(ctx.ctx, ctx.buyer, types4.OrderParams{
+			MarketID:  store.NewEntityID(0),
+			Direction: matcheng.Bid,
+			Price:     testutil.ToBaseUnits(2),
+			Quantity:  testutil.ToBaseUnits(10),
+			Expiry:    types4.NewBlockExpiry(599),
+		})
 		assert.Error(t, err)
 		assert.Equal(t, err.Code(), errs.CodeNotFound)
 	})
 	t.Run("deletes the order and returns coins after cancellation", func(t *testing.T) {
 		ctx := setupTest(t)
-		bid, err := ctx.app.OrderKeeper.Post(ctx.ctx, ctx.buyer, ctx.marketID, matcheng.Bid, testutil.ToBaseUnits(2), testutil.ToBaseUnits(10), 599)
+		bid, err := ctx.app.OrderKeeper.PostOrder(ctx.ctx, ctx.buyer, types4.OrderParams{
+			MarketID:  ctx.marketID,
+			Direction: matcheng.Bid,
+			Price:     testutil.ToBaseUnits(2),
+			Quantity:  testutil.ToBaseUnits(10),
+			Expiry:    types4.NewBlockExpiry(599),
+		})
 		require.NoError(t, err)
 		err = ctx.app.OrderKeeper.Cancel(ctx.ctx, bid.ID)
 		require.NoError(t, err)
 		assert.False(t, ctx.app.OrderKeeper.Has(ctx.ctx, bid.ID))
 	})
+	t.Run("keeps a record of the cancelled order", func(t *testing.T) {
+		ctx := setupTest(t)
+		bid, err := ctx.app.OrderKeeper.PostOrder(ctx.ctx, ctx.buyer, types4.OrderParams{
+			MarketID:  ctx.marketID,
+			Direction: matcheng.Bid,
+			Price:     testutil.ToBaseUnits(2),
+			Quantity:  testutil.ToBaseUnits(10),
+			Expiry:    types4.NewBlockExpiry(599),
+		})
+		require.NoError(t, err)
+		rec, err := ctx.app.OrderKeeper.Record(ctx.ctx, bid.ID)
+		require.NoError(t, err)
+		assert.Equal(t, types4.Open, rec.Status)
+
+		require.NoError(t, ctx.app.OrderKeeper.CancelWithReason(ctx.ctx, bid.ID, types4.CancelledExpired))
+		rec, err = ctx.app.OrderKeeper.Record(ctx.ctx, bid.ID)
+		require.NoError(t, err)
+		assert.Equal(t, types4.Expired, rec.Status)
+		testutil.AssertEqualUints(t, testutil.ToBaseUnits(10), rec.Quantity)
+		testutil.AssertEqualUints(t, sdk.ZeroUint(), rec.QuantityFilled)
+		testutil.AssertEqualUints(t, sdk.ZeroUint(), rec.AvgPrice)
+
+		_, err = ctx.app.OrderKeeper.Record(ctx.ctx, store.NewEntityID(100))
+		assert.Error(t, err)
+	})
+	t.Run("evicts the oldest records beyond the retention cap", func(t *testing.T) {
+		ctx := setupTest(t)
+		ctx.app.OrderKeeper.SetParams(ctx.ctx, types4.NewParams(0, 0, sdk.NewCoins(), 0, 2))
+		var ids []store.EntityID
+		for i := 0; i < 3; i++ {
+			bid, err := ctx.app.OrderKeeper.PostOrder(ctx.ctx, ctx.buyer, types4.OrderParams{
+				MarketID:  ctx.marketID,
+				Direction: matcheng.Bid,
+				Price:     testutil.ToBaseUnits(2),
+				Quantity:  testutil.ToBaseUnits(1),
+				Expiry:    types4.NewBlockExpiry(599),
+			})
+			require.NoError(t, err)
+			require.NoError(t, ctx.app.OrderKeeper.Cancel(ctx.ctx, bid.ID))
+			ids = append(ids, bid.ID)
+		}
+		_, err := ctx.app.OrderKeeper.Record(ctx.ctx, ids[0])
+		assert.Error(t, err)
+		for _, id := range ids[1:] {
+			_, err := ctx.app.OrderKeeper.Record(ctx.ctx, id)
+			assert.NoError(t, err)
+		}
+	})
+}
+
+func TestKeeper_Events(t *testing.T) {
+	testflags.UnitTest(t)
+	ctx := setupTest(t)
+	sdkCtx := ctx.ctx.WithEventManager(sdk.NewEventManager())
+	bid, err := ctx.app.OrderKeeper.PostOrder(sdkCtx, ctx.buyer, types4.OrderParams{
+		MarketID:  ctx.marketID,
+		Direction: matcheng.Bid,
+		Price:     testutil.ToBaseUnits(2),
+		Quantity:  testutil.ToBaseUnits(10),
+		Expiry:    types4.NewBlockExpiry(599),
+	})
+	require.NoError(t, err)
+	ask, err := ctx.app.OrderKeeper.PostOrder(sdkCtx, ctx.seller, types4.OrderParams{
+		MarketID:  ctx.marketID,
+		Direction: matcheng.Ask,
+		Price:     testutil.ToBaseUnits(3),
+		Quantity:  testutil.ToBaseUnits(10),
+		Expiry:    types4.NewBlockExpiry(599),
+	})
+	require.NoError(t, err)
+	require.NoError(t, ctx.app.OrderKeeper.Cancel(sdkCtx, bid.ID))
+	require.NoError(t, ctx.app.OrderKeeper.CancelWithReason(sdkCtx, ask.ID, types4.CancelledExpired))
+
+	events := make(map[string][]sdk.Attribute)
+	for _, ev := range sdk.StringifyEvents(sdkCtx.EventManager().ABCIEvents()) {
+		events[ev.Type] = ev.Attributes
+	}
+	posted := events[types4.EventTypePostOrder]
+	require.Len(t, posted, 14)
+	assert.Equal(t, sdk.Attribute{Key: types4.AttributeKeyOrderID, Value: bid.ID.String()}, posted[0])
+	assert.Equal(t, sdk.Attribute{Key: types4.AttributeKeyOrderID, Value: ask.ID.String()}, posted[7])
+	assert.Equal(t, sdk.Attribute{Key: types4.AttributeKeyDirection, Value: "ASK"}, posted[10])
+	assert.Equal(t, []sdk.Attribute{
+		{Key: types4.AttributeKeyOrderID, Value: bid.ID.String()},
+		{Key: types4.AttributeKeyMarketID, Value: ctx.marketID.String()},
+		{Key: types4.AttributeKeyOwner, Value: ctx.buyer.String()},
+		{Key: types4.AttributeKeyQuantity, Value: testutil.ToBaseUnits(10).String()},
+		{Key: types4.AttributeKeyReason, Value: "OWNER"},
+	}, events[types4.EventTypeCancelOrder])
+	expired := events[types4.EventTypeExpireOrder]
+	require.Len(t, expired, 5)
+	assert.Equal(t, sdk.Attribute{Key: types4.AttributeKeyOrderID, Value: ask.ID.String()}, expired[0])
+	assert.Equal(t, sdk.Attribute{Key: types4.AttributeKeyReason, Value: "EXPIRED"}, expired[4])
+}
+
+func TestKeeper_PayFromDust(t *testing.T) {
+	testflags.UnitTest(t)
+	ctx := setupTest(t)
+	_, err := ctx.app.OrderKeeper.PostOrder(ctx.ctx, ctx.seller, types4.OrderParams{
+		MarketID:  ctx.marketID,
+		Direction: matcheng.Ask,
+		Price:     testutil.ToBaseUnits(2),
+		Quantity:  testutil.ToBaseUnits(10),
+		Expiry:    types4.NewBlockExpiry(599),
+	})
+	require.NoError(t, err)
+
+	// the escrow of open orders is not dust
+	owed := sdk.NewCoins(sdk.NewCoin("tst1", sdk.NewInt(1)))
+	err = ctx.app.OrderKeeper.PayFromDust(ctx.ctx, ctx.buyer, owed)
+	require.Error(t, err)
+	assert.Equal(t, types4.CodeDustShortfall, err.Code())
+	assert.True(t, ctx.app.OrderKeeper.GetDust(ctx.ctx).Empty())
+
+	msg, broken := order.AllInvariants(ctx.app.OrderKeeper)(ctx.ctx)
+	assert.False(t, broken, msg)
+}
+
+func TestKeeper_Replace(t *testing.T) {
+	testflags.UnitTest(t)
+	balance := func(ctx *testCtx) sdk.Int {
+		return ctx.app.BankKeeper.GetCoins(ctx.ctx, ctx.buyer).AmountOf("tst2")
+	}
+	t.Run("only moves the escrow difference", func(t *testing.T) {
+		ctx := setupTest(t)
+		bid, err := ctx.app.OrderKeeper.PostOrder(ctx.ctx, ctx.buyer, types4.OrderParams{
+			MarketID:  ctx.marketID,
+			Direction: matcheng.Bid,
+			Price:     testutil.ToBaseUnits(2),
+			Quantity:  testutil.ToBaseUnits(10),
+			Expiry:    types4.NewBlockExpiry(599),
+		})
+		require.NoError(t, err)
+		before := balance(ctx)
+
+		replaced, err := ctx.app.OrderKeeper.Replace(ctx.ctx, ctx.buyer, bid.ID, testutil.ToBaseUnits(3), testutil.ToBaseUnits(10), types4.NewNoExpiry(), matcheng.CancelNewest)
+		require.NoError(t, err)
+		assert.False(t, ctx.app.OrderKeeper.Has(ctx.ctx, bid.ID))
+		assert.Equal(t, types4.GoodTillCancel, replaced.ExpiryType)
+		assert.Equal(t, bid.Direction, replaced.Direction)
+		assert.Equal(t, testutil.ToBaseUnits(10).String(), before.Sub(balance(ctx)).String())
+
+		_, err = ctx.app.OrderKeeper.Replace(ctx.ctx, ctx.buyer, replaced.ID, testutil.ToBaseUnits(1), testutil.ToBaseUnits(5), types4.NewBlockExpiry(10), matcheng.CancelNewest)
+		require.NoError(t, err)
+		assert.Equal(t, testutil.ToBaseUnits(15).String(), balance(ctx).Sub(before).String())
+	})
+	t.Run("rejects unowned orders", func(t *testing.T) {
+		ctx := setupTest(t)
+		bid, err := ctx.app.OrderKeeper.PostOrder(ctx.ctx, ctx.buyer, types4.OrderParams{
+			MarketID:  ctx.marketID,
+			Direction: matcheng.Bid,
+			Price:     testutil.ToBaseUnits(2),
+			Quantity:  testutil.ToBaseUnits(10),
+			Expiry:    types4.NewBlockExpiry(599),
+		})
+		require.NoError(t, err)
+		_, err = ctx.app.OrderKeeper.Replace(ctx.ctx, ctx.seller, bid.ID, testutil.ToBaseUnits(3), testutil.ToBaseUnits(10), types4.NewBlockExpiry(10), matcheng.CancelNewest)
+		require.Error(t, err)
+		assert.Equal(t, sdk.CodeUnauthorized, err.Code())
+		assert.True(t, ctx.app.OrderKeeper.Has(ctx.ctx, bid.ID))
+	})
+	t.Run("rejects increases the owner cannot afford", func(t *testing.T) {
+		ctx := setupTest(t)
+		bid, err := ctx.app.OrderKeeper.PostOrder(ctx.ctx, ctx.buyer, types4.OrderParams{
+			MarketID:  ctx.marketID,
+			Direction: matcheng.Bid,
+			Price:     testutil.ToBaseUnits(2),
+			Quantity:  testutil.ToBaseUnits(10),
+			Expiry:    types4.NewBlockExpiry(599),
+		})
+		require.NoError(t, err)
+		_, err = ctx.app.OrderKeeper.Replace(ctx.ctx, ctx.buyer, bid.ID, testutil.ToBaseUnits(2), testutil.ToBaseUnits(5001), types4.NewBlockExpiry(10), matcheng.CancelNewest)
+		require.Error(t, err)
+		assert.Equal(t, sdk.CodeInsufficientCoins, err.Code())
+	})
+}
+
+func TestKeeper_CancelAll(t *testing.T) {
+	testflags.UnitTest(t)
+	ctx := setupTest(t)
+	nominee := ctx.nominee.String()
+	other, err := ctx.app.MarketKeeper.CreateMarket(ctx.ctx, nominee, types2.MarketParams{
+		BaseAsset:    "tst2",
+		QuoteAsset:   "tst1",
+		MatchingMode: types2.MatchingModeBatch,
+	})
+	require.NoError(t, err)
+	before := ctx.app.BankKeeper.GetCoins(ctx.ctx, ctx.buyer)
+
+	post := func(mktID store.EntityID, direction matcheng.Direction) store.EntityID {
+		ord, err := ctx.app.OrderKeeper.PostOrder(ctx.ctx, ctx.buyer, types4.OrderParams{
+			MarketID:  mktID,
+			Direction: direction,
+			Price:     testutil.ToBaseUnits(1),
+			Quantity:  testutil.ToBaseUnits(1),
+			Expiry:    types4.NewBlockExpiry(599),
+		})
+		require.NoError(t, err)
+		return ord.ID
+	}
+	bid := post(ctx.marketID, matcheng.Bid)
+	ask := post(ctx.marketID, matcheng.Ask)
+	otherBid := post(other.ID, matcheng.Bid)
+	sellerAsk, err := ctx.app.OrderKeeper.PostOrder(ctx.ctx, ctx.seller, types4.OrderParams{
+		MarketID:  ctx.marketID,
+		Direction: matcheng.Ask,
+		Price:     testutil.ToBaseUnits(3),
+		Quantity:  testutil.ToBaseUnits(1),
+		Expiry:    types4.NewBlockExpiry(599),
+	})
+	require.NoError(t, err)
+
+	direction := matcheng.Ask
+	ids, err := ctx.app.OrderKeeper.CancelAll(ctx.ctx, ctx.buyer, ctx.marketID, &direction)
+	require.NoError(t, err)
+	assert.EqualValues(t, []store.EntityID{ask}, ids)
+
+	ids, err = ctx.app.OrderKeeper.CancelAll(ctx.ctx, ctx.buyer, store.ZeroEntityID, nil)
+	require.NoError(t, err)
+	assert.EqualValues(t, []store.EntityID{bid, otherBid}, ids)
+	assert.True(t, ctx.app.OrderKeeper.Has(ctx.ctx, sellerAsk.ID))
+	assert.Equal(t, before, ctx.app.BankKeeper.GetCoins(ctx.ctx, ctx.buyer))
+}
+
+func TestKeeper_OrderLimits(t *testing.T) {
+	testflags.UnitTest(t)
+	post := func(ctx *testCtx, owner sdk.AccAddress) (types4.Order, sdk.Error) {
+		return ctx.app.OrderKeeper.PostOrder(ctx.ctx, owner, types4.OrderParams{
+			MarketID:  ctx.marketID,
+			Direction: matcheng.Bid,
+			Price:     testutil.ToBaseUnits(1),
+			Quantity:  testutil.ToBaseUnits(1),
+			Expiry:    types4.NewBlockExpiry(599),
+		})
+	}
+	t.Run("caps the open orders of an account", func(t *testing.T) {
+		ctx := setupTest(t)
+		ctx.app.OrderKeeper.SetParams(ctx.ctx, types4.NewParams(2, 0, sdk.NewCoins(), 0, 0))
+		first, err := post(ctx, ctx.buyer)
+		require.NoError(t, err)
+		_, err = post(ctx, ctx.buyer)
+		require.NoError(t, err)
+		assert.EqualValues(t, 2, ctx.app.OrderKeeper.OwnerOrderCount(ctx.ctx, ctx.buyer))
+
+		_, err = post(ctx, ctx.buyer)
+		require.Error(t, err)
+		assert.Equal(t, types4.CodeAccountOrderLimit, err.Code())
+		// other accounts are not affected
+		_, err = post(ctx, ctx.seller)
+		require.NoError(t, err)
+
+		// replacing an order does not count as another one
+		replaced, err := ctx.app.OrderKeeper.Replace(ctx.ctx, ctx.buyer, first.ID, testutil.ToBaseUnits(2), testutil.ToBaseUnits(1), types4.NewNoExpiry(), matcheng.CancelNewest)
+		require.NoError(t, err)
+
+		require.NoError(t, ctx.app.OrderKeeper.Cancel(ctx.ctx, replaced.ID))
+		assert.EqualValues(t, 1, ctx.app.OrderKeeper.OwnerOrderCount(ctx.ctx, ctx.buyer))
+		_, err = post(ctx, ctx.buyer)
+		require.NoError(t, err)
+	})
+	t.Run("counts pending stop orders", func(t *testing.T) {
+		ctx := setupTest(t)
+		ctx.app.OrderKeeper.SetParams(ctx.ctx, types4.NewParams(2, 0, sdk.NewCoins(), 0, 0))
+		stop := func() (types4.TriggerOrder, sdk.Error) {
+			return ctx.app.OrderKeeper.PostStop(ctx.ctx, ctx.buyer, ctx.marketID, matcheng.Bid, matcheng.Limit, testutil.ToBaseUnits(2), testutil.ToBaseUnits(3), testutil.ToBaseUnits(1), 599, types4.TriggerClearing)
+		}
+		trig, err := stop()
+		require.NoError(t, err)
+		_, err = post(ctx, ctx.buyer)
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, ctx.app.OrderKeeper.OwnerTriggerCount(ctx.ctx, ctx.buyer))
+		assert.EqualValues(t, 1, ctx.app.OrderKeeper.MarketTriggerCount(ctx.ctx, ctx.marketID))
+
+		_, err = stop()
+		require.Error(t, err)
+		assert.Equal(t, types4.CodeAccountOrderLimit, err.Code())
+		_, err = post(ctx, ctx.buyer)
+		require.Error(t, err)
+
+		// a triggered stop order takes its own place
+		ctx.app.MarketKeeper.SetLastPrice(ctx.ctx, ctx.marketID, testutil.ToBaseUnits(2))
+		ctx.app.OrderKeeper.ProcessTriggers(ctx.ctx)
+		assert.False(t, ctx.app.OrderKeeper.HasTrigger(ctx.ctx, trig.ID))
+		assert.Zero(t, ctx.app.OrderKeeper.OwnerTriggerCount(ctx.ctx, ctx.buyer))
+		assert.EqualValues(t, 2, ctx.app.OrderKeeper.OwnerOrderCount(ctx.ctx, ctx.buyer))
+	})
+	t.Run("caps the open orders of a market", func(t *testing.T) {
+		ctx := setupTest(t)
+		ctx.app.OrderKeeper.SetParams(ctx.ctx, types4.NewParams(0, 2, sdk.NewCoins(), 0, 0))
+		_, err := post(ctx, ctx.buyer)
+		require.NoError(t, err)
+		_, err = post(ctx, ctx.seller)
+		require.NoError(t, err)
+		assert.EqualValues(t, 2, ctx.app.OrderKeeper.MarketOrderCount(ctx.ctx, ctx.marketID))
+
+		_, err = post(ctx, ctx.seller)
+		require.Error(t, err)
+		assert.Equal(t, types4.CodeMarketOrderLimit, err.Code())
+	})
+}
+
+func TestKeeper_PostFee(t *testing.T) {
+	testflags.UnitTest(t)
+	ctx := setupTest(t)
+	fee := sdk.NewCoins(sdk.NewInt64Coin("tst1", 1000))
+	ctx.app.OrderKeeper.SetParams(ctx.ctx, types4.NewParams(0, 0, fee, 0, 0))
+	collected := func() sdk.Coins {
+		return ctx.app.SupplyKeeper.GetModuleAccount(ctx.ctx, auth.FeeCollectorName).GetCoins()
+	}
+	before := ctx.app.BankKeeper.GetCoins(ctx.ctx, ctx.buyer)
+
+	bid, err := ctx.app.OrderKeeper.PostOrder(ctx.ctx, ctx.buyer, types4.OrderParams{
+		MarketID:  ctx.marketID,
+		Direction: matcheng.Bid,
+		Price:     testutil.ToBaseUnits(1),
+		Quantity:  testutil.ToBaseUnits(1),
+		Expiry:    types4.NewBlockExpiry(599),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, fee, collected())
+
+	// replacing pays the fee again, cancelling does not refund it
+	replaced, err := ctx.app.OrderKeeper.Replace(ctx.ctx, ctx.buyer, bid.ID, testutil.ToBaseUnits(2), testutil.ToBaseUnits(1), types4.NewNoExpiry(), matcheng.CancelNewest)
+	require.NoError(t, err)
+	require.NoError(t, ctx.app.OrderKeeper.Cancel(ctx.ctx, replaced.ID))
+	assert.Equal(t, fee.Add(fee), collected())
+	assert.Equal(t, before.Sub(fee.Add(fee)), ctx.app.BankKeeper.GetCoins(ctx.ctx, ctx.buyer))
+
+	t.Run("charges stop orders when they are placed", func(t *testing.T) {
+		paid := collected()
+		_, err := ctx.app.OrderKeeper.PostStop(ctx.ctx, ctx.buyer, ctx.marketID, matcheng.Bid, matcheng.Limit, testutil.ToBaseUnits(2), testutil.ToBaseUnits(3), testutil.ToBaseUnits(1), 599, types4.TriggerClearing)
+		require.NoError(t, err)
+		assert.Equal(t, paid.Add(fee), collected())
+
+		// but not again when they trigger
+		ctx.app.MarketKeeper.SetLastPrice(ctx.ctx, ctx.marketID, testutil.ToBaseUnits(2))
+		ctx.app.OrderKeeper.ProcessTriggers(ctx.ctx)
+		assert.EqualValues(t, 1, ctx.app.OrderKeeper.OwnerOrderCount(ctx.ctx, ctx.buyer))
+		assert.Equal(t, paid.Add(fee), collected())
+	})
+	t.Run("rejects orders of owners that cannot pay", func(t *testing.T) {
+		poor := testutil.RandAddr()
+		require.NoError(t, ctx.app.SupplyKeeper.SendCoinsFromModuleToAccount(ctx.ctx, denominations.ModuleName, poor, sdk.NewCoins(sdk.NewInt64Coin("tst2", 10000000000))))
+		_, err := ctx.app.OrderKeeper.PostOrder(ctx.ctx, poor, types4.OrderParams{
+			MarketID:  ctx.marketID,
+			Direction: matcheng.Bid,
+			Price:     testutil.ToBaseUnits(1),
+			Quantity:  testutil.ToBaseUnits(1),
+			Expiry:    types4.NewBlockExpiry(599),
+		})
+		require.Error(t, err)
+		assert.Equal(t, sdk.CodeInsufficientFee, err.Code())
+		assert.Zero(t, ctx.app.OrderKeeper.OwnerOrderCount(ctx.ctx, poor))
+	})
+}
+
+func TestKeeper_Iceberg(t *testing.T) {
+	testflags.UnitTest(t)
+	ctx := setupTest(t)
+	post := func(orderType matcheng.OrderType, quantity, display sdk.Uint, tif uint16) (types4.Order, sdk.Error) {
+		return ctx.app.OrderKeeper.PostOrder(ctx.ctx, ctx.seller, types4.OrderParams{
+			MarketID:        ctx.marketID,
+			Direction:       matcheng.Ask,
+			Type:            orderType,
+			Price:           testutil.ToBaseUnits(2),
+			Quantity:        quantity,
+			DisplayQuantity: display,
+			Expiry:          types4.NewBlockExpiry(tif),
+		})
+	}
+	before := ctx.app.BankKeeper.GetCoins(ctx.ctx, ctx.seller).AmountOf("tst1")
+
+	ask, err := post(matcheng.Limit, testutil.ToBaseUnits(10), testutil.ToBaseUnits(3), 599)
+	require.NoError(t, err)
+	assert.True(t, ask.IsIceberg())
+	testutil.AssertEqualUints(t, testutil.ToBaseUnits(3), ask.Visible())
+	// the hidden reserve is escrowed too
+	assert.Equal(t, testutil.ToBaseUnits(10).String(), before.Sub(ctx.app.BankKeeper.GetCoins(ctx.ctx, ctx.seller).AmountOf("tst1")).String())
+
+	depth := ctx.app.OrderKeeper.Depth(ctx.ctx, ctx.marketID, matcheng.Ask, 10, sdk.ZeroUint())
+	require.Len(t, depth, 1)
+	testutil.AssertEqualUints(t, testutil.ToBaseUnits(3), depth[0].Quantity)
+
+	_, err = post(matcheng.Limit, testutil.ToBaseUnits(10), testutil.ToBaseUnits(10), 599)
+	require.Error(t, err)
+	_, err = post(matcheng.ImmediateOrCancel, testutil.ToBaseUnits(10), testutil.ToBaseUnits(3), 0)
+	require.Error(t, err)
+
+	t.Run("replacing keeps the display quantity", func(t *testing.T) {
+		replaced, err := ctx.app.OrderKeeper.Replace(ctx.ctx, ctx.seller, ask.ID, testutil.ToBaseUnits(2), testutil.ToBaseUnits(8), types4.NewNoExpiry(), matcheng.CancelNewest)
+		require.NoError(t, err)
+		testutil.AssertEqualUints(t, testutil.ToBaseUnits(3), replaced.DisplayQuantity)
+
+		replaced, err = ctx.app.OrderKeeper.Replace(ctx.ctx, ctx.seller, replaced.ID, testutil.ToBaseUnits(2), testutil.ToBaseUnits(2), types4.NewNoExpiry(), matcheng.CancelNewest)
+		require.NoError(t, err)
+		assert.False(t, replaced.IsIceberg())
+		testutil.AssertEqualUints(t, testutil.ToBaseUnits(2), replaced.Visible())
+	})
 }
 
 func TestKeeper_Iteration(t *testing.T) {
 	testflags.UnitTest(t)
 	ctx := setupTest(t)
-	first, err := ctx.app.OrderKeeper.Post(ctx.ctx, ctx.buyer, ctx.marketID, matcheng.Bid, testutil.ToBaseUnits(2), testutil.ToBaseUnits(10), 599)
+	first, err := ctx.app.OrderKeeper.PostOrder(ctx.ctx, ctx.buyer, types4.OrderParams{
+		MarketID:  ctx.marketID,
+		Direction: matcheng.Bid,
+		Price:     testutil.ToBaseUnits(2),
+		Quantity:  testutil.ToBaseUnits(10),
+		Expiry:    types4.NewBlockExpiry(599),
+	})
 	require.NoError(t, err)
-	_, err = ctx.app.OrderKeeper.Post(ctx.ctx, ctx.buyer, ctx.marketID, matcheng.Bid, testutil.ToBaseUnits(2), testutil.ToBaseUnits(10), 599)
+	_, err = ctx.app.OrderKeeper.PostOrder(ctx.ctx, ctx.buyer, types4.OrderParams{
+		MarketID:  ctx.marketID,
+		Direction: matcheng.Bid,
+		Price:     testutil.ToBaseUnits(2),
+		Quantity:  testutil.ToBaseUnits(10),
+		Expiry:    types4.NewBlockExpiry(599),
+	})
 	require.NoError(t, err)
-	last, err := ctx.app.OrderKeeper.Post(ctx.ctx, ctx.buyer, ctx.marketID, matcheng.Bid, testutil.ToBaseUnits(2), testutil.ToBaseUnits(10), 599)
+	last, err := ctx.app.OrderKeeper.PostOrder(ctx.ctx, ctx.buyer, types4.OrderParams{
+		MarketID:  ctx.marketID,
+		Direction: matcheng.Bid,
+		Price:     testutil.ToBaseUnits(2),
+		Quantity:  testutil.ToBaseUnits(10),
+		Expiry:    types4.NewBlockExpiry(599),
+	})
 	require.NoError(t, err)
 
 	var coll []store.EntityID
@@ -124,6 +835,59 @@ func TestKeeper_Iteration(t *testing.T) {
 	assert.EqualValues(t, []store.EntityID{store.NewEntityID(3), store.NewEntityID(2)}, coll)
 }
 
+func TestKeeper_BookIterator(t *testing.T) {
+	testflags.UnitTest(t)
+	ctx := setupTest(t)
+	post := func(direction matcheng.Direction, price uint64) store.EntityID {
+		owner := ctx.buyer
+		if direction == matcheng.Ask {
+			owner = ctx.seller
+		}
+		ord, err := ctx.app.OrderKeeper.PostOrder(ctx.ctx, owner, types4.OrderParams{
+			MarketID:  ctx.marketID,
+			Direction: direction,
+			Price:     testutil.ToBaseUnits(price),
+			Quantity:  testutil.ToBaseUnits(1),
+			Expiry:    types4.NewBlockExpiry(599),
+		})
+		require.NoError(t, err)
+		return ord.ID
+	}
+	collect := func(direction matcheng.Direction) []store.EntityID {
+		var coll []store.EntityID
+		ctx.app.OrderKeeper.BookIterator(ctx.ctx, ctx.marketID, direction, func(order types4.Order) bool {
+			coll = append(coll, order.ID)
+			return true
+		})
+		return coll
+	}
+
+	bid1 := post(matcheng.Bid, 2)
+	bid2 := post(matcheng.Bid, 3)
+	bid3 := post(matcheng.Bid, 2)
+	ask1 := post(matcheng.Ask, 5)
+	ask2 := post(matcheng.Ask, 4)
+	ask3 := post(matcheng.Ask, 5)
+
+	assert.EqualValues(t, []store.EntityID{bid2, bid1, bid3}, collect(matcheng.Bid))
+	assert.EqualValues(t, []store.EntityID{ask2, ask1, ask3}, collect(matcheng.Ask))
+
+	require.NoError(t, ctx.app.OrderKeeper.Cancel(ctx.ctx, bid2))
+	ord, err := ctx.app.OrderKeeper.Get(ctx.ctx, ask1)
+	require.NoError(t, err)
+	ord.Price = testutil.ToBaseUnits(6)
+	require.NoError(t, ctx.app.OrderKeeper.Set(ctx.ctx, ord))
+	assert.EqualValues(t, []store.EntityID{bid1, bid3}, collect(matcheng.Bid))
+	assert.EqualValues(t, []store.EntityID{ask2, ask3, ask1}, collect(matcheng.Ask))
+
+	var other []store.EntityID
+	ctx.app.OrderKeeper.BookIterator(ctx.ctx, ctx.marketID.Inc(), matcheng.Bid, func(order types4.Order) bool {
+		other = append(other, order.ID)
+		return true
+	})
+	assert.Empty(t, other)
+}
+
 func setupTest(t *testing.T) *testCtx {
 	app := mockapp.New(t)
 	nominee := testutil.RandAddr()
@@ -131,9 +895,7 @@ func setupTest(t *testing.T) *testCtx {
 	seller := testutil.RandAddr()
 
 	app.SupplyKeeper.SetSupply(app.Ctx, supply.NewSupply(sdk.Coins{}))
-	marketParams := app.MarketKeeper.GetParams(app.Ctx)
-	marketParams.Nominees = []string{nominee.String()}
-	app.MarketKeeper.SetParams(app.Ctx, marketParams)
+	app.GrantRole(authority.RoleMarketAdmin, nominee)
 
 	err := app.SupplyKeeper.MintCoins(app.Ctx, denominations.ModuleName, sdk.NewCoins(sdk.NewCoin("tst1", sdk.NewInt(1000000000000)), sdk.NewCoin("tst2", sdk.NewInt(1000000000000))))
 	require.NoError(t, err)
@@ -141,15 +903,14 @@ func setupTest(t *testing.T) *testCtx {
 	require.NoError(t, app.SupplyKeeper.SendCoinsFromModuleToAccount(app.Ctx, denominations.ModuleName, buyer, sdk.NewCoins(sdk.NewCoin("tst2", sdk.NewInt(10000000000)))))
 	require.NoError(t, app.SupplyKeeper.SendCoinsFromModuleToAccount(app.Ctx, denominations.ModuleName, seller, sdk.NewCoins(sdk.NewCoin("tst1", sdk.NewInt(10000000000)))))
 	require.NoError(t, app.SupplyKeeper.SendCoinsFromModuleToAccount(app.Ctx, denominations.ModuleName, seller, sdk.NewCoins(sdk.NewCoin("tst2", sdk.NewInt(10000000000)))))
-	market := types2.NewMsgCreateMarket(nominee, "tst1", "tst2")
-	mkt, err := app.MarketKeeper.CreateMarket(app.Ctx, market.Nominee.String(), market.BaseAsset, market.QuoteAsset)
+	market := types2.NewMsgCreateMarket(nominee, "tst1", "tst2", types2.MatchingModeBatch)
+	mkt, err := app.MarketKeeper.CreateMarket(app.Ctx, market.Nominee.String(), market.MarketParams())
 	require.NoError(t, err)
 
-	marketParams = app.MarketKeeper.GetParams(app.Ctx)
-
 	return &testCtx{
 		ctx:      app.Ctx,
 		marketID: mkt.ID,
+		nominee:  nominee,
 		buyer:    buyer,
 		seller:   seller,
 		app:      app,
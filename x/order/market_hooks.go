@@ -0,0 +1,27 @@
+package order
+
+import (
+	"github.com/xar-network/xar-network/types/store"
+	"github.com/xar-network/xar-network/x/market"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// MarketHooks wrapper struct for the order keeper
+type MarketHooks struct {
+	k Keeper
+}
+
+var _ market.MarketHooks = MarketHooks{}
+
+// MarketHooks returns the market hooks used to refund the orders of
+// delisted markets
+func (k Keeper) MarketHooks() MarketHooks {
+	return MarketHooks{k}
+}
+
+// AfterMarketDelisted refunds every open order in a delisted market.
+func (h MarketHooks) AfterMarketDelisted(ctx sdk.Context, mktID store.EntityID) sdk.Error {
+	_, err := h.k.CancelMarket(ctx, mktID)
+	return err
+}
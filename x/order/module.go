@@ -7,6 +7,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/xar-network/xar-network/x/order/client/cli"
+	"github.com/xar-network/xar-network/x/order/client/rest"
 	types3 "github.com/xar-network/xar-network/x/order/types"
 
 	abci "github.com/tendermint/tendermint/abci/types"
@@ -33,14 +34,20 @@ func (a AppModuleBasic) RegisterCodec(cdc *codec.Codec) {
 }
 
 func (a AppModuleBasic) DefaultGenesis() json.RawMessage {
-	return []byte("{}")
+	return types3.ModuleCdc.MustMarshalJSON(DefaultGenesisState())
 }
 
-func (a AppModuleBasic) ValidateGenesis(json.RawMessage) error {
-	return nil
+func (a AppModuleBasic) ValidateGenesis(b json.RawMessage) error {
+	var data GenesisState
+	err := types3.ModuleCdc.UnmarshalJSON(b, &data)
+	if err != nil {
+		return err
+	}
+	return ValidateGenesis(data)
 }
 
-func (a AppModuleBasic) RegisterRESTRoutes(context.CLIContext, *mux.Router) {
+func (a AppModuleBasic) RegisterRESTRoutes(ctx context.CLIContext, rtr *mux.Router) {
+	rest.RegisterRoutes(ctx, rtr, types3.StoreKey)
 }
 
 func (a AppModuleBasic) GetTxCmd(cdc *codec.Codec) *cobra.Command {
@@ -63,15 +70,20 @@ func NewAppModule(keeper Keeper) AppModule {
 	}
 }
 
-func (a AppModule) InitGenesis(types.Context, json.RawMessage) []abci.ValidatorUpdate {
+func (a AppModule) InitGenesis(ctx types.Context, data json.RawMessage) []abci.ValidatorUpdate {
+	var genesisState GenesisState
+	types3.ModuleCdc.MustUnmarshalJSON(data, &genesisState)
+	InitGenesis(ctx, a.keeper, genesisState)
 	return []abci.ValidatorUpdate{}
 }
 
-func (a AppModule) ExportGenesis(types.Context) json.RawMessage {
-	return []byte("{}")
+func (a AppModule) ExportGenesis(ctx types.Context) json.RawMessage {
+	gs := ExportGenesis(ctx, a.keeper)
+	return types3.ModuleCdc.MustMarshalJSON(gs)
 }
 
-func (a AppModule) RegisterInvariants(types.InvariantRegistry) {
+func (a AppModule) RegisterInvariants(ir types.InvariantRegistry) {
+	RegisterInvariants(ir, a.keeper)
 }
 
 func (a AppModule) Route() string {
@@ -93,6 +105,9 @@ func (a AppModule) NewQuerierHandler() types.Querier {
 func (a AppModule) BeginBlock(types.Context, abci.RequestBeginBlock) {
 }
 
-func (a AppModule) EndBlock(types.Context, abci.RequestEndBlock) []abci.ValidatorUpdate {
+// EndBlock posts the stop orders triggered by this block's prices. It must
+// run after the oracle has updated its current prices.
+func (a AppModule) EndBlock(ctx types.Context, _ abci.RequestEndBlock) []abci.ValidatorUpdate {
+	a.keeper.ProcessTriggers(ctx)
 	return []abci.ValidatorUpdate{}
 }
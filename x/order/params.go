@@ -0,0 +1,48 @@
+package order
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+
+	"github.com/xar-network/xar-network/types/store"
+	types3 "github.com/xar-network/xar-network/x/order/types"
+)
+
+// GetParams returns the order limits and posting fee.
+func (k Keeper) GetParams(ctx sdk.Context) types3.Params {
+	var params types3.Params
+	k.paramstore.GetParamSet(ctx, &params)
+	return params
+}
+
+// SetParams replaces the order limits and posting fee.
+func (k Keeper) SetParams(ctx sdk.Context, params types3.Params) {
+	k.paramstore.SetParamSet(ctx, &params)
+}
+
+// checkOrderLimits returns an error if one more open order would exceed the
+// open order limit of owner or of the market. Pending trigger orders count
+// as open orders.
+func (k Keeper) checkOrderLimits(ctx sdk.Context, params types3.Params, owner sdk.AccAddress, mktID store.EntityID) sdk.Error {
+	if max := params.MaxOpenOrdersPerAccount; max > 0 && k.OwnerOrderCount(ctx, owner)+k.OwnerTriggerCount(ctx, owner) >= max {
+		return types3.ErrAccountOrderLimit(k.codespace, owner, max)
+	}
+	if max := params.MaxOpenOrdersPerMarket; max > 0 && k.MarketOrderCount(ctx, mktID)+k.MarketTriggerCount(ctx, mktID) >= max {
+		return types3.ErrMarketOrderLimit(k.codespace, mktID, max)
+	}
+	return nil
+}
+
+// chargePostFee sends the posting fee from owner to the fee collector.
+func (k Keeper) chargePostFee(ctx sdk.Context, params types3.Params, owner sdk.AccAddress) sdk.Error {
+	if params.PostFee.IsZero() {
+		return nil
+	}
+	err := k.sk.SendCoinsFromAccountToModule(ctx, owner, auth.FeeCollectorName, params.PostFee)
+	if err != nil && err.Code() == sdk.CodeInsufficientCoins {
+		return sdk.ErrInsufficientFee(fmt.Sprintf("cannot pay order posting fee of %s", params.PostFee))
+	}
+	return err
+}
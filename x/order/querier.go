@@ -1,38 +1,81 @@
 package order
 
 import (
+	"fmt"
+
 	abci "github.com/tendermint/tendermint/abci/types"
 
 	"github.com/cosmos/cosmos-sdk/codec"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 
+	"github.com/xar-network/xar-network/pkg/matcheng"
+	"github.com/xar-network/xar-network/types/store"
 	"github.com/xar-network/xar-network/x/order/types"
 )
 
 const (
-	QueryList = "list"
+	QueryList   = "list"
+	QueryStatus = "status"
+	QueryDepth  = "depth"
+	QueryParams = "params"
 )
 
 func NewQuerier(keeper Keeper) sdk.Querier {
 	return func(ctx sdk.Context, path []string, req abci.RequestQuery) (res []byte, err sdk.Error) {
 		switch path[0] {
 		case QueryList:
-			return queryList(ctx, keeper)
+			return queryList(ctx, req, keeper)
+		case QueryDepth:
+			return queryDepth(ctx, path[1:], req, keeper)
+		case QueryStatus:
+			return queryStatus(ctx, path[1:], keeper)
+		case QueryParams:
+			return queryParams(ctx, keeper)
 		default:
 			return nil, sdk.ErrUnknownRequest("unknown order query endpoint")
 		}
 	}
 }
 
-func queryList(ctx sdk.Context, keeper Keeper) ([]byte, sdk.Error) {
+func queryList(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) ([]byte, sdk.Error) {
+	var params types.ListQueryParams
+	if len(req.Data) > 0 {
+		if err := keeper.cdc.UnmarshalJSON(req.Data, &params); err != nil {
+			return nil, sdk.ErrUnknownRequest(fmt.Sprintf("failed to parse params: %s", err))
+		}
+	}
+	skip, limit := params.Bounds()
 	res := types.ListQueryResult{
 		Orders: make([]types.Order, 0),
+		Page:   skip/limit + 1,
+		Limit:  limit,
 	}
 
-	keeper.Iterator(ctx, func(order types.Order) bool {
+	cb := func(order types.Order) bool {
+		if !params.Matches(order) {
+			return true
+		}
+		if skip > 0 {
+			skip--
+			return true
+		}
+		if len(res.Orders) == limit {
+			res.More = true
+			return false
+		}
 		res.Orders = append(res.Orders, order)
 		return true
-	})
+	}
+	// the narrowest index available is iterated, the other filters are
+	// applied to the orders it yields.
+	switch {
+	case !params.Owner.Empty():
+		keeper.OwnerIterator(ctx, params.Owner, cb)
+	case params.MarketID != nil:
+		keeper.MarketIterator(ctx, *params.MarketID, cb)
+	default:
+		keeper.Iterator(ctx, cb)
+	}
 
 	b, err := codec.MarshalJSONIndent(keeper.cdc, res)
 	if err != nil {
@@ -40,3 +83,61 @@ func queryList(ctx sdk.Context, keeper Keeper) ([]byte, sdk.Error) {
 	}
 	return b, nil
 }
+
+func queryStatus(ctx sdk.Context, path []string, keeper Keeper) ([]byte, sdk.Error) {
+	if len(path) != 1 {
+		return nil, sdk.ErrUnknownRequest("order status query requires an order ID")
+	}
+	rec, err := keeper.Record(ctx, store.NewEntityIDFromString(path[0]))
+	if err != nil {
+		return nil, err
+	}
+
+	b, mErr := codec.MarshalJSONIndent(keeper.cdc, rec)
+	if mErr != nil {
+		panic("could not marshal result")
+	}
+	return b, nil
+}
+
+func queryDepth(ctx sdk.Context, path []string, req abci.RequestQuery, keeper Keeper) ([]byte, sdk.Error) {
+	if len(path) != 1 {
+		return nil, sdk.ErrUnknownRequest("depth query requires a market ID")
+	}
+	var params types.DepthQueryParams
+	if len(req.Data) > 0 {
+		if err := keeper.cdc.UnmarshalJSON(req.Data, &params); err != nil {
+			return nil, sdk.ErrUnknownRequest(fmt.Sprintf("failed to parse params: %s", err))
+		}
+	}
+	mkt, err := keeper.marketKeeper.Get(ctx, store.NewEntityIDFromString(path[0]))
+	if err != nil {
+		return nil, err
+	}
+	bucket := sdk.ZeroUint()
+	if params.BucketSize != nil {
+		bucket = *params.BucketSize
+	}
+
+	levels := params.MaxLevels()
+	res := types.DepthQueryResult{
+		MarketID:    mkt.ID,
+		BlockNumber: ctx.BlockHeight(),
+		Bids:        keeper.Depth(ctx, mkt.ID, matcheng.Bid, levels, bucket),
+		Asks:        keeper.Depth(ctx, mkt.ID, matcheng.Ask, levels, bucket),
+	}
+
+	b, mErr := codec.MarshalJSONIndent(keeper.cdc, res)
+	if mErr != nil {
+		panic("could not marshal result")
+	}
+	return b, nil
+}
+
+func queryParams(ctx sdk.Context, keeper Keeper) ([]byte, sdk.Error) {
+	b, err := codec.MarshalJSONIndent(keeper.cdc, keeper.GetParams(ctx))
+	if err != nil {
+		panic("could not marshal result")
+	}
+	return b, nil
+}
@@ -0,0 +1,186 @@
+package order_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	"github.com/xar-network/xar-network/pkg/matcheng"
+	"github.com/xar-network/xar-network/testutil"
+	"github.com/xar-network/xar-network/testutil/testflags"
+	"github.com/xar-network/xar-network/types/store"
+	types2 "github.com/xar-network/xar-network/x/market/types"
+	"github.com/xar-network/xar-network/x/order"
+	types4 "github.com/xar-network/xar-network/x/order/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+func TestQuerier_List(t *testing.T) {
+	testflags.UnitTest(t)
+	ctx := setupTest(t)
+	nominee := ctx.nominee.String()
+	other, err := ctx.app.MarketKeeper.CreateMarket(ctx.ctx, nominee, types2.MarketParams{
+		BaseAsset:    "tst2",
+		QuoteAsset:   "tst1",
+		MatchingMode: ctx.market.MatchingMode,
+	})
+	require.NoError(t, err)
+
+	var buyerBids []store.EntityID
+	for price := uint64(1); price <= 5; price++ {
+		ord, err := ctx.app.OrderKeeper.PostOrder(ctx.ctx, ctx.buyer, types4.OrderParams{
+			MarketID:  ctx.marketID,
+			Direction: matcheng.Bid,
+			Price:     testutil.ToBaseUnits(price),
+			Quantity:  testutil.ToBaseUnits(1),
+			Expiry:    types4.NewBlockExpiry(599),
+		})
+		require.NoError(t, err)
+		buyerBids = append(buyerBids, ord.ID)
+	}
+	sellerAsk, err := ctx.app.OrderKeeper.PostOrder(ctx.ctx, ctx.seller, types4.OrderParams{
+		MarketID:  ctx.marketID,
+		Direction: matcheng.Ask,
+		Price:     testutil.ToBaseUnits(10),
+		Quantity:  testutil.ToBaseUnits(1),
+		Expiry:    types4.NewBlockExpiry(599),
+	})
+	require.NoError(t, err)
+	otherAsk, err := ctx.app.OrderKeeper.PostOrder(ctx.ctx, ctx.buyer, types4.OrderParams{
+		MarketID:  other.ID,
+		Direction: matcheng.Ask,
+		Price:     testutil.ToBaseUnits(1),
+		Quantity:  testutil.ToBaseUnits(1),
+		Expiry:    types4.NewBlockExpiry(599),
+	})
+	require.NoError(t, err)
+	require.NoError(t, ctx.app.OrderKeeper.Cancel(ctx.ctx, buyerBids[0]))
+	buyerBids = buyerBids[1:]
+
+	querier := order.NewQuerier(ctx.app.OrderKeeper)
+	list := func(params *types4.ListQueryParams) types4.ListQueryResult {
+		var req abci.RequestQuery
+		if params != nil {
+			req.Data = ctx.app.Cdc.MustMarshalJSON(params)
+		}
+		res, err := querier(ctx.ctx, []string{order.QueryList}, req)
+		require.NoError(t, err)
+		var out types4.ListQueryResult
+		ctx.app.Cdc.MustUnmarshalJSON(res, &out)
+		return out
+	}
+	ids := func(res types4.ListQueryResult) []store.EntityID {
+		var out []store.EntityID
+		for _, ord := range res.Orders {
+			out = append(out, ord.ID)
+		}
+		return out
+	}
+
+	t.Run("lists every order without params", func(t *testing.T) {
+		res := list(nil)
+		assert.Len(t, res.Orders, 6)
+		assert.False(t, res.More)
+	})
+	t.Run("filters by owner", func(t *testing.T) {
+		params := types4.NewListQueryParams(1, 0)
+		params.Owner = ctx.seller
+		assert.EqualValues(t, []store.EntityID{sellerAsk.ID}, ids(list(&params)))
+	})
+	t.Run("filters by owner and market", func(t *testing.T) {
+		params := types4.NewListQueryParams(1, 0)
+		params.Owner = ctx.buyer
+		params.MarketID = &other.ID
+		assert.EqualValues(t, []store.EntityID{otherAsk.ID}, ids(list(&params)))
+	})
+	t.Run("filters by market, direction and price", func(t *testing.T) {
+		params := types4.NewListQueryParams(1, 0)
+		params.MarketID = &ctx.marketID
+		direction := matcheng.Bid
+		params.Direction = &direction
+		min, max := testutil.ToBaseUnits(3), testutil.ToBaseUnits(4)
+		params.MinPrice, params.MaxPrice = &min, &max
+		assert.EqualValues(t, buyerBids[1:3], ids(list(&params)))
+	})
+	t.Run("paginates", func(t *testing.T) {
+		params := types4.NewListQueryParams(1, 3)
+		params.Owner = ctx.buyer
+		res := list(&params)
+		assert.EqualValues(t, buyerBids[:3], ids(res))
+		assert.True(t, res.More)
+
+		params.Page = 2
+		res = list(&params)
+		assert.EqualValues(t, []store.EntityID{buyerBids[3], otherAsk.ID}, ids(res))
+		assert.False(t, res.More)
+	})
+}
+
+func TestQuerier_Depth(t *testing.T) {
+	testflags.UnitTest(t)
+	ctx := setupTest(t)
+	post := func(direction matcheng.Direction, price string, qty uint64) {
+		owner := ctx.buyer
+		if direction == matcheng.Ask {
+			owner = ctx.seller
+		}
+		_, err := ctx.app.OrderKeeper.PostOrder(ctx.ctx, owner, types4.OrderParams{
+			MarketID:  ctx.marketID,
+			Direction: direction,
+			Price:     sdk.NewUintFromString(price),
+			Quantity:  testutil.ToBaseUnits(qty),
+			Expiry:    types4.NewBlockExpiry(599),
+		})
+		require.NoError(t, err)
+	}
+	post(matcheng.Bid, "200000000", 1)
+	post(matcheng.Bid, "200000000", 2)
+	post(matcheng.Bid, "250000000", 4)
+	post(matcheng.Bid, "100000000", 8)
+	post(matcheng.Ask, "300000000", 1)
+	post(matcheng.Ask, "350000000", 2)
+
+	querier := order.NewQuerier(ctx.app.OrderKeeper)
+	depth := func(params types4.DepthQueryParams) types4.DepthQueryResult {
+		req := abci.RequestQuery{Data: ctx.app.Cdc.MustMarshalJSON(params)}
+		res, err := querier(ctx.ctx, []string{order.QueryDepth, ctx.marketID.String()}, req)
+		require.NoError(t, err)
+		var out types4.DepthQueryResult
+		ctx.app.Cdc.MustUnmarshalJSON(res, &out)
+		return out
+	}
+	assertLevel := func(t *testing.T, level types4.DepthLevel, price string, qty uint64, orders int) {
+		testutil.AssertEqualUints(t, sdk.NewUintFromString(price), level.Price)
+		testutil.AssertEqualUints(t, testutil.ToBaseUnits(qty), level.Quantity)
+		assert.Equal(t, orders, level.Orders)
+	}
+
+	t.Run("aggregates price levels best first", func(t *testing.T) {
+		res := depth(types4.NewDepthQueryParams(2))
+		require.Len(t, res.Bids, 2)
+		assertLevel(t, res.Bids[0], "250000000", 4, 1)
+		assertLevel(t, res.Bids[1], "200000000", 3, 2)
+		require.Len(t, res.Asks, 2)
+		assertLevel(t, res.Asks[0], "300000000", 1, 1)
+		assertLevel(t, res.Asks[1], "350000000", 2, 1)
+	})
+	t.Run("groups prices into buckets", func(t *testing.T) {
+		params := types4.NewDepthQueryParams(0)
+		bucket := testutil.ToBaseUnits(1)
+		params.BucketSize = &bucket
+		res := depth(params)
+		require.Len(t, res.Bids, 2)
+		assertLevel(t, res.Bids[0], "200000000", 7, 3)
+		assertLevel(t, res.Bids[1], "100000000", 8, 1)
+		require.Len(t, res.Asks, 2)
+		assertLevel(t, res.Asks[0], "300000000", 1, 1)
+		assertLevel(t, res.Asks[1], "400000000", 2, 1)
+	})
+	t.Run("rejects unknown markets", func(t *testing.T) {
+		_, err := querier(ctx.ctx, []string{order.QueryDepth, "100"}, abci.RequestQuery{})
+		assert.Error(t, err)
+	})
+}
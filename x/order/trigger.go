@@ -0,0 +1,305 @@
+package order
+
+import (
+	"fmt"
+
+	"github.com/xar-network/xar-network/pkg/matcheng"
+	"github.com/xar-network/xar-network/types/errs"
+	"github.com/xar-network/xar-network/types/store"
+	mkttypes "github.com/xar-network/xar-network/x/market/types"
+	types3 "github.com/xar-network/xar-network/x/order/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// PostStop stores a stop or stop-limit order and charges the posting fee.
+// Pending stop orders count towards the open order limits. No funds are
+// escrowed until the order triggers, but the owner must hold the escrow of
+// the order when it is placed.
+func (k Keeper) PostStop(ctx sdk.Context, owner sdk.AccAddress, mktID store.EntityID, direction matcheng.Direction, orderType matcheng.OrderType, stopPrice sdk.Uint, price sdk.Uint, quantity sdk.Uint, tif uint16, source types3.TriggerSource) (types3.TriggerOrder, sdk.Error) {
+	mkt, err := k.marketKeeper.Get(ctx, mktID)
+	if err != nil {
+		return types3.TriggerOrder{}, err
+	}
+	if !mkt.Status.AcceptsOrders() {
+		return types3.TriggerOrder{}, errs.ErrInvalidArgument(fmt.Sprintf("market is %s", mkt.Status))
+	}
+	if err := checkTradingRules(mkt, orderType, price, quantity); err != nil {
+		return types3.TriggerOrder{}, err
+	}
+	if source == types3.TriggerOracle {
+		if _, ok := k.oracleAssetCode(ctx, mkt); !ok {
+			return types3.TriggerOrder{}, errs.ErrInvalidArgument("market has no oracle price")
+		}
+	}
+	escrow, qErr := escrowFor(mkt, direction, price, quantity)
+	if qErr != nil {
+		return types3.TriggerOrder{}, sdk.ErrInvalidCoins(qErr.Error())
+	}
+	if !escrow.IsPositive() {
+		return types3.TriggerOrder{}, sdk.ErrInvalidCoins("quantity too small to represent")
+	}
+	params := k.GetParams(ctx)
+	if err := k.checkOrderLimits(ctx, params, owner, mktID); err != nil {
+		return types3.TriggerOrder{}, err
+	}
+	if needed := sdk.NewCoins(escrow).Add(params.PostFee); !k.bk.HasCoins(ctx, owner, needed) {
+		return types3.TriggerOrder{}, sdk.ErrInsufficientCoins(fmt.Sprintf("cannot cover the escrow and posting fee of %s", needed))
+	}
+	if err := k.chargePostFee(ctx, params, owner); err != nil {
+		return types3.TriggerOrder{}, err
+	}
+
+	order := types3.TriggerOrder{
+		ID:                store.IncrementSeq(ctx, k.storeKey, []byte(triggerSeqKey)),
+		Owner:             owner,
+		MarketID:          mktID,
+		Direction:         direction,
+		Type:              orderType,
+		StopPrice:         stopPrice,
+		Price:             price,
+		Quantity:          quantity,
+		TimeInForceBlocks: tif,
+		Source:            source,
+		CreatedBlock:      ctx.BlockHeight(),
+	}
+	if err := store.SetNotExists(ctx, k.storeKey, k.cdc, triggerKey(order.ID), order); err != nil {
+		return types3.TriggerOrder{}, err
+	}
+	kv := ctx.KVStore(k.storeKey)
+	kv.Set(marketTriggerKey(order), order.ID.Bytes())
+	kv.Set(priceTriggerKey(order), order.ID.Bytes())
+	k.countTrigger(ctx, order, true)
+	return order, nil
+}
+
+func (k Keeper) GetTrigger(ctx sdk.Context, id store.EntityID) (types3.TriggerOrder, sdk.Error) {
+	var out types3.TriggerOrder
+	err := store.Get(ctx, k.storeKey, k.cdc, triggerKey(id), &out)
+	return out, err
+}
+
+func (k Keeper) HasTrigger(ctx sdk.Context, id store.EntityID) bool {
+	return store.Has(ctx, k.storeKey, triggerKey(id))
+}
+
+func (k Keeper) DelTrigger(ctx sdk.Context, id store.EntityID) sdk.Error {
+	order, err := k.GetTrigger(ctx, id)
+	if err != nil {
+		return err
+	}
+	kv := ctx.KVStore(k.storeKey)
+	kv.Delete(marketTriggerKey(order))
+	kv.Delete(priceTriggerKey(order))
+	k.countTrigger(ctx, order, false)
+	return store.Del(ctx, k.storeKey, triggerKey(id))
+}
+
+func (k Keeper) TriggerIterator(ctx sdk.Context, cb TriggerIteratorCB) {
+	kv := ctx.KVStore(k.storeKey)
+	iter := sdk.KVStorePrefixIterator(kv, []byte(triggerValKey))
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		var order types3.TriggerOrder
+		k.cdc.MustUnmarshalBinaryBare(iter.Value(), &order)
+
+		if !cb(order) {
+			break
+		}
+	}
+}
+
+// MarketTriggerIterator iterates over the trigger orders of a market, oldest
+// first. The callback must not modify the trigger store.
+func (k Keeper) MarketTriggerIterator(ctx sdk.Context, mktID store.EntityID, cb TriggerIteratorCB) {
+	kv := ctx.KVStore(k.storeKey)
+	iter := sdk.KVStorePrefixIterator(kv, marketTriggerPrefixKey(mktID))
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		order, err := k.GetTrigger(ctx, store.NewEntityIDFromBytes(iter.Value()))
+		if err != nil {
+			// should never happen; implies consensus
+			// or storage bug
+			panic(err)
+		}
+
+		if !cb(order) {
+			break
+		}
+	}
+}
+
+// crossedTriggerIterator iterates over the trigger orders on one side of a
+// market that are triggered at the reference price ref of source, in the
+// order their stop prices were crossed and oldest first within a stop
+// price. It stops at the first order that is not triggered, so only the
+// crossed stop prices are read. The callback must not modify the trigger
+// store.
+func (k Keeper) crossedTriggerIterator(ctx sdk.Context, mktID store.EntityID, source types3.TriggerSource, direction matcheng.Direction, ref sdk.Uint, cb TriggerIteratorCB) {
+	kv := ctx.KVStore(k.storeKey)
+	iter := sdk.KVStorePrefixIterator(kv, priceTriggerSideKey(mktID, source, direction))
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		order, err := k.GetTrigger(ctx, store.NewEntityIDFromBytes(iter.Value()))
+		if err != nil {
+			// should never happen; implies consensus
+			// or storage bug
+			panic(err)
+		}
+
+		if !order.Triggered(ref) || !cb(order) {
+			break
+		}
+	}
+}
+
+// ProcessTriggers posts every trigger order whose stop price has been
+// reached, without charging the posting fee again. Only markets with
+// pending trigger orders are visited, and only the stop prices crossed by
+// their reference prices are read. A triggered order that cannot be posted,
+// e.g. because its owner no longer has the funds to cover it, is dropped
+// and a drop_stop_order event tells its owner why. Markets halted by their
+// price band accept no orders, so their trigger orders wait for the halt to
+// end.
+func (k Keeper) ProcessTriggers(ctx sdk.Context) {
+	var triggered []types3.TriggerOrder
+	collect := func(order types3.TriggerOrder) bool {
+		triggered = append(triggered, order)
+		return true
+	}
+	for _, mktID := range k.triggerMarkets(ctx) {
+		if k.marketKeeper.IsHalted(ctx, mktID) {
+			continue
+		}
+		for _, source := range []types3.TriggerSource{types3.TriggerClearing, types3.TriggerOracle} {
+			ref := k.referencePrice(ctx, mktID, source)
+			if ref.IsZero() {
+				continue
+			}
+			k.crossedTriggerIterator(ctx, mktID, source, matcheng.Bid, ref, collect)
+			k.crossedTriggerIterator(ctx, mktID, source, matcheng.Ask, ref, collect)
+		}
+	}
+
+	for _, trig := range triggered {
+		if err := k.DelTrigger(ctx, trig.ID); err != nil {
+			// should never happen; implies consensus
+			// or storage bug
+			panic(err)
+		}
+
+		postCtx, write := ctx.CacheContext()
+		order, err := k.post(postCtx, trig.Owner, types3.OrderParams{
+			MarketID:  trig.MarketID,
+			Direction: trig.Direction,
+			Type:      trig.Type,
+			Price:     trig.Price,
+			Quantity:  trig.Quantity,
+			Expiry:    types3.NewBlockExpiry(trig.TimeInForceBlocks),
+		}, false)
+		if err != nil {
+			logger.Info(
+				"dropped triggered order",
+				"trigger_id", trig.ID.String(),
+				"reason", err.Error(),
+			)
+			ctx.EventManager().EmitEvent(
+				sdk.NewEvent(
+					types3.EventTypeDropStopOrder,
+					sdk.NewAttribute(types3.AttributeKeyStopOrderID, trig.ID.String()),
+					sdk.NewAttribute(types3.AttributeKeyMarketID, trig.MarketID.String()),
+					sdk.NewAttribute(types3.AttributeKeyOwner, trig.Owner.String()),
+					sdk.NewAttribute(types3.AttributeKeyReason, err.Error()),
+				),
+			)
+			continue
+		}
+		write()
+		ctx.EventManager().EmitEvents(postCtx.EventManager().Events())
+
+		logger.Info(
+			"triggered order",
+			"trigger_id", trig.ID.String(),
+			"id", order.ID.String(),
+			"source", trig.Source.String(),
+		)
+	}
+}
+
+// triggerMarkets returns the IDs of the markets with pending trigger
+// orders, read from their trigger order counts.
+func (k Keeper) triggerMarkets(ctx sdk.Context) []store.EntityID {
+	prefix := append([]byte(marketTriggerCountKey), '/')
+	iter := sdk.KVStorePrefixIterator(ctx.KVStore(k.storeKey), prefix)
+	defer iter.Close()
+	var ids []store.EntityID
+	for ; iter.Valid(); iter.Next() {
+		ids = append(ids, store.NewEntityIDFromBytes(iter.Key()[len(prefix):]))
+	}
+	return ids
+}
+
+// referencePrice returns the price trigger orders of the given source are
+// compared against, or zero if there is none yet.
+func (k Keeper) referencePrice(ctx sdk.Context, mktID store.EntityID, source types3.TriggerSource) sdk.Uint {
+	if source == types3.TriggerClearing {
+		price, _ := k.marketKeeper.LastPrice(ctx, mktID)
+		return price
+	}
+
+	return k.OraclePrice(ctx, mktID)
+}
+
+// OraclePrice returns the oracle median price of a market's pair in the
+// market's price units, or zero if no active oracle asset quotes the pair.
+func (k Keeper) OraclePrice(ctx sdk.Context, mktID store.EntityID) sdk.Uint {
+	mkt, err := k.marketKeeper.Get(ctx, mktID)
+	if err != nil {
+		return sdk.ZeroUint()
+	}
+	assetCode, ok := k.oracleAssetCode(ctx, mkt)
+	if !ok {
+		return sdk.ZeroUint()
+	}
+	return matcheng.NormalizePriceWithDecimals(k.oracleKeeper.GetCurrentPrice(ctx, assetCode).Price, mkt.Rules.QuoteAssetDecimals())
+}
+
+// oracleAssetCode finds the active oracle asset quoting the market's pair.
+func (k Keeper) oracleAssetCode(ctx sdk.Context, mkt mkttypes.Market) (string, bool) {
+	for _, asset := range k.oracleKeeper.GetAssetParams(ctx) {
+		if asset.Active && asset.BaseAsset == mkt.BaseAssetDenom && asset.QuoteAsset == mkt.QuoteAssetDenom {
+			return asset.AssetCode, true
+		}
+	}
+	return "", false
+}
+
+func triggerKey(id store.EntityID) []byte {
+	return store.PrefixKeyString(triggerValKey, id.Bytes())
+}
+
+// Trigger orders are indexed by market, oldest order first:
+//
+//	trigger_market/<market id>/<trigger id>
+func marketTriggerPrefixKey(mktID store.EntityID) []byte {
+	return append(store.PrefixKeyString(triggerMarketKey, mktID.Bytes()), '/')
+}
+
+func marketTriggerKey(order types3.TriggerOrder) []byte {
+	return store.PrefixKeyString(triggerMarketKey, order.MarketID.Bytes(), order.ID.Bytes())
+}
+
+// Trigger orders are also indexed by the price they trigger on:
+//
+//	trigger_price/<market id>/<source>/<direction>/<stop price>/<trigger id>
+//
+// Ask stop prices are stored bitwise inverted, so that both sides iterate in
+// the order a moving reference price crosses them: buy stops from the
+// lowest stop price up, sell stops from the highest down.
+func priceTriggerSideKey(mktID store.EntityID, source types3.TriggerSource, direction matcheng.Direction) []byte {
+	return append(store.PrefixKeyString(triggerPriceKey, mktID.Bytes(), []byte{byte(source)}, []byte{byte(direction)}), '/')
+}
+
+func priceTriggerKey(order types3.TriggerOrder) []byte {
+	return store.PrefixKeyString(triggerPriceKey, order.MarketID.Bytes(), []byte{byte(order.Source)}, []byte{byte(order.Direction)}, bookPrice(order.Direction.Opposite(), order.StopPrice), order.ID.Bytes())
+}
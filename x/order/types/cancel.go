@@ -0,0 +1,101 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+const (
+	// CancelledByOwner orders were cancelled by their owner.
+	CancelledByOwner CancelReason = iota
+	// CancelledExpired orders outlived their expiry.
+	CancelledExpired
+	// CancelledUnfilled orders were immediate orders whose remainder could
+	// not be filled in the block they were posted in.
+	CancelledUnfilled
+	// CancelledSelfTrade orders would have traded against an order of the
+	// same owner.
+	CancelledSelfTrade
+	// CancelledReplaced orders were replaced by a new order of their owner.
+	CancelledReplaced
+	// CancelledKilled orders were fill-or-kill orders that could not be
+	// filled completely.
+	CancelledKilled
+	// CancelledDelisted orders were refunded because their market was
+	// delisted.
+	CancelledDelisted
+	// CancelledDust orders were bids whose remainder became too small to be
+	// worth a single unit of the quote asset.
+	CancelledDust
+	// CancelledBand orders were continuous orders whose remainder would have
+	// traded outside their market's price band.
+	CancelledBand
+)
+
+// CancelReason records why an order was cancelled or reduced.
+type CancelReason uint8
+
+var cancelReasonNames = map[CancelReason]string{
+	CancelledByOwner:   "OWNER",
+	CancelledExpired:   "EXPIRED",
+	CancelledUnfilled:  "UNFILLED",
+	CancelledSelfTrade: "SELF_TRADE",
+	CancelledReplaced:  "REPLACED",
+	CancelledKilled:    "KILLED",
+	CancelledDelisted:  "DELISTED",
+	CancelledDust:      "DUST",
+	CancelledBand:      "PRICE_BAND",
+}
+
+func (r CancelReason) String() string {
+	name, ok := cancelReasonNames[r]
+	if !ok {
+		return "UNKNOWN"
+	}
+	return name
+}
+
+func (r CancelReason) IsValid() bool {
+	_, ok := cancelReasonNames[r]
+	return ok
+}
+
+// Status returns the terminal status of orders cancelled for this reason.
+func (r CancelReason) Status() OrderStatus {
+	switch r {
+	case CancelledExpired:
+		return Expired
+	case CancelledKilled:
+		return Rejected
+	default:
+		return Cancelled
+	}
+}
+
+func CancelReasonFromString(str string) (CancelReason, error) {
+	for r, name := range cancelReasonNames {
+		if name == str {
+			return r, nil
+		}
+	}
+	return CancelledByOwner, errors.New("invalid cancel reason")
+}
+
+func (r *CancelReason) UnmarshalJSON(data []byte) error {
+	var str string
+	err := json.Unmarshal(data, &str)
+	if err != nil {
+		return err
+	}
+
+	out, err := CancelReasonFromString(str)
+	if err != nil {
+		return err
+	}
+	*r = out
+	return nil
+}
+
+func (r CancelReason) MarshalJSON() ([]byte, error) {
+	return []byte("\"" + r.String() + "\""), nil
+}
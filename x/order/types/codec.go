@@ -7,4 +7,8 @@ var ModuleCdc = codec.New()
 func RegisterCodec(cdc *codec.Codec) {
 	cdc.RegisterConcrete(MsgPost{}, "order/Post", nil)
 	cdc.RegisterConcrete(MsgCancel{}, "order/Cancel", nil)
+	cdc.RegisterConcrete(MsgPostStop{}, "order/PostStop", nil)
+	cdc.RegisterConcrete(MsgCancelStop{}, "order/CancelStop", nil)
+	cdc.RegisterConcrete(MsgReplace{}, "order/Replace", nil)
+	cdc.RegisterConcrete(MsgCancelAll{}, "order/CancelAll", nil)
 }
@@ -0,0 +1,37 @@
+package types
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/xar-network/xar-network/types/store"
+)
+
+const (
+	// DefaultCodespace codespace for the module
+	DefaultCodespace sdk.CodespaceType = ModuleName
+
+	CodeAccountOrderLimit sdk.CodeType = 1
+	CodeMarketOrderLimit  sdk.CodeType = 2
+	CodeDustShortfall     sdk.CodeType = 3
+)
+
+// ErrAccountOrderLimit is returned when an account already has the maximum
+// number of open orders.
+func ErrAccountOrderLimit(codespace sdk.CodespaceType, owner sdk.AccAddress, max uint64) sdk.Error {
+	return sdk.NewError(codespace, CodeAccountOrderLimit, fmt.Sprintf("%s already has the maximum of %d open orders", owner, max))
+}
+
+// ErrMarketOrderLimit is returned when a market already holds the maximum
+// number of open orders.
+func ErrMarketOrderLimit(codespace sdk.CodespaceType, mktID store.EntityID, max uint64) sdk.Error {
+	return sdk.NewError(codespace, CodeMarketOrderLimit, fmt.Sprintf("market %s already holds the maximum of %d open orders", mktID, max))
+}
+
+// ErrDustShortfall is returned when settlement owes more than the dust pool
+// holds. Fills never owe more than they release into the pool, so this means
+// that escrowed funds have been lost.
+func ErrDustShortfall(codespace sdk.CodespaceType, recipient string, owed sdk.Coins, dust sdk.Coins) sdk.Error {
+	return sdk.NewError(codespace, CodeDustShortfall, fmt.Sprintf("dust pool of %s cannot pay %s to %s", dust, owed, recipient))
+}
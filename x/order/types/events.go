@@ -0,0 +1,33 @@
+package types
+
+// Order module event types. Fills and batches are emitted by the execution
+// keeper, which matches the orders of this module.
+const (
+	EventTypePostOrder       = "post_order"
+	EventTypeCancelOrder     = "cancel_order"
+	EventTypeExpireOrder     = "expire_order"
+	EventTypeReduceOrder     = "reduce_order"
+	EventTypeFillOrder       = "fill_order"
+	EventTypeClearBatch      = "clear_batch"
+	EventTypePostStopOrder   = "post_stop_order"
+	EventTypeCancelStopOrder = "cancel_stop_order"
+	EventTypeDropStopOrder   = "drop_stop_order"
+
+	AttributeValueCategory = ModuleName
+
+	AttributeKeyOrderID          = "order_id"
+	AttributeKeyStopOrderID      = "stop_order_id"
+	AttributeKeyMarketID         = "market_id"
+	AttributeKeyOwner            = "owner"
+	AttributeKeyDirection        = "direction"
+	AttributeKeyOrderType        = "order_type"
+	AttributeKeyPrice            = "price"
+	AttributeKeyStopPrice        = "stop_price"
+	AttributeKeyQuantity         = "quantity"
+	AttributeKeyReason           = "reason"
+	AttributeKeyQuantityFilled   = "quantity_filled"
+	AttributeKeyQuantityUnfilled = "quantity_unfilled"
+	AttributeKeyMaker            = "maker"
+	AttributeKeyFee              = "fee"
+	AttributeKeyClearingPrice    = "clearing_price"
+)
@@ -0,0 +1,96 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+const (
+	// GoodTillBlocks orders are cancelled once TimeInForceBlocks blocks
+	// have passed since they were created.
+	GoodTillBlocks ExpiryType = iota
+	// GoodTillTime orders are cancelled in the first block whose time is
+	// at or after GoodTillTime.
+	GoodTillTime
+	// GoodTillCancel orders rest in the book until they are filled or
+	// cancelled by their owner.
+	GoodTillCancel
+)
+
+type ExpiryType uint8
+
+var expiryTypeNames = map[ExpiryType]string{
+	GoodTillBlocks: "GTB",
+	GoodTillTime:   "GTT",
+	GoodTillCancel: "GTC",
+}
+
+func (t ExpiryType) String() string {
+	name, ok := expiryTypeNames[t]
+	if !ok {
+		return "UNKNOWN"
+	}
+	return name
+}
+
+func (t ExpiryType) IsValid() bool {
+	_, ok := expiryTypeNames[t]
+	return ok
+}
+
+func ExpiryTypeFromString(str string) (ExpiryType, error) {
+	for t, name := range expiryTypeNames {
+		if name == str {
+			return t, nil
+		}
+	}
+	return GoodTillBlocks, errors.New("invalid expiry type")
+}
+
+func (t *ExpiryType) UnmarshalJSON(data []byte) error {
+	var str string
+	err := json.Unmarshal(data, &str)
+	if err != nil {
+		return err
+	}
+
+	out, err := ExpiryTypeFromString(str)
+	if err != nil {
+		return err
+	}
+	*t = out
+	return nil
+}
+
+func (t ExpiryType) MarshalJSON() ([]byte, error) {
+	return []byte("\"" + t.String() + "\""), nil
+}
+
+// Expiry describes how long an order may rest in the book. Blocks is only
+// used by GoodTillBlocks orders and Time, in unix seconds, only by
+// GoodTillTime orders.
+type Expiry struct {
+	Type   ExpiryType
+	Blocks uint16
+	Time   int64
+}
+
+func NewBlockExpiry(blocks uint16) Expiry {
+	return Expiry{
+		Type:   GoodTillBlocks,
+		Blocks: blocks,
+	}
+}
+
+func NewTimeExpiry(time int64) Expiry {
+	return Expiry{
+		Type: GoodTillTime,
+		Time: time,
+	}
+}
+
+func NewNoExpiry() Expiry {
+	return Expiry{
+		Type: GoodTillCancel,
+	}
+}
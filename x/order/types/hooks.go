@@ -0,0 +1,10 @@
+package types
+
+import sdk "github.com/cosmos/cosmos-sdk/types"
+
+// OrderHooks event hooks for order objects
+type OrderHooks interface {
+	// AfterOrderPosted is called once a posted order has been escrowed and
+	// persisted. Returning an error aborts the post.
+	AfterOrderPosted(ctx sdk.Context, order Order) sdk.Error
+}
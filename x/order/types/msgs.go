@@ -8,16 +8,32 @@ import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 )
 
+// MaxSlippageBps caps the slippage of market orders at 100%.
+const MaxSlippageBps = 10000
+
+// MsgPost posts an order. For market orders Price is the reference price
+// and SlippageBps the tolerated deviation from it, see LimitPrice.
+// TimeInForce is only used by good-till-blocks orders and GoodTillTime,
+// in unix seconds, only by good-till-time orders. STPMode selects how the
+// order is resolved against crossing orders of the same owner.
 type MsgPost struct {
-	Owner       sdk.AccAddress     `json:"owner" yaml:"owner"`
-	MarketID    store.EntityID     `json:"market_id" yaml:"market_id"`
-	Direction   matcheng.Direction `json:"direction" yaml:"direction"`
-	Price       sdk.Uint           `json:"price" yaml:"price"`
-	Quantity    sdk.Uint           `json:"quantity" yaml:"quantity"`
-	TimeInForce uint16             `json:"time_in_force" yaml:"time_in_force"`
+	Owner        sdk.AccAddress     `json:"owner" yaml:"owner"`
+	MarketID     store.EntityID     `json:"market_id" yaml:"market_id"`
+	Direction    matcheng.Direction `json:"direction" yaml:"direction"`
+	Price        sdk.Uint           `json:"price" yaml:"price"`
+	Quantity     sdk.Uint           `json:"quantity" yaml:"quantity"`
+	TimeInForce  uint16             `json:"time_in_force" yaml:"time_in_force"`
+	OrderType    matcheng.OrderType `json:"order_type,omitempty" yaml:"order_type"`
+	SlippageBps  uint16             `json:"slippage_bps,omitempty" yaml:"slippage_bps"`
+	ExpiryType   ExpiryType         `json:"expiry_type,omitempty" yaml:"expiry_type"`
+	GoodTillTime int64              `json:"good_till_time,omitempty" yaml:"good_till_time"`
+	STPMode      matcheng.STPMode   `json:"stp_mode,omitempty" yaml:"stp_mode"`
+	// DisplayQuantity makes the order an iceberg order that only shows
+	// this much of its quantity in the book at a time.
+	DisplayQuantity *sdk.Uint `json:"display_quantity,omitempty" yaml:"display_quantity"`
 }
 
-func NewMsgPost(owner sdk.AccAddress, marketID store.EntityID, direction matcheng.Direction, price sdk.Uint, quantity sdk.Uint, tif uint16) MsgPost {
+func NewMsgPost(owner sdk.AccAddress, marketID store.EntityID, direction matcheng.Direction, price sdk.Uint, quantity sdk.Uint, tif uint16, orderType matcheng.OrderType, slippageBps uint16) MsgPost {
 	return MsgPost{
 		Owner:       owner,
 		MarketID:    marketID,
@@ -25,6 +41,8 @@ func NewMsgPost(owner sdk.AccAddress, marketID store.EntityID, direction matchen
 		Price:       price,
 		Quantity:    quantity,
 		TimeInForce: tif,
+		OrderType:   orderType,
+		SlippageBps: slippageBps,
 	}
 }
 
@@ -46,15 +64,122 @@ func (msg MsgPost) ValidateBasic() sdk.Error {
 	if msg.Quantity.IsZero() {
 		return sdk.ErrInvalidCoins("quantity cannot be zero")
 	}
-	if msg.TimeInForce == 0 {
-		return sdk.ErrInternal("time in force cannot be zero")
+	if !msg.OrderType.IsValid() {
+		return sdk.ErrUnknownRequest("invalid order type")
+	}
+	if err := validateExpiry(msg.OrderType, msg.Expiry()); err != nil {
+		return err
+	}
+	if !msg.STPMode.IsValid() {
+		return sdk.ErrUnknownRequest("invalid self-trade prevention mode")
+	}
+	if msg.OrderType != matcheng.Market && msg.SlippageBps != 0 {
+		return sdk.ErrUnknownRequest("slippage is only supported for market orders")
+	}
+	if msg.SlippageBps > MaxSlippageBps {
+		return sdk.ErrUnknownRequest("slippage cannot be larger than 10000 bps")
 	}
-	if msg.TimeInForce > MaxTimeInForce {
-		return sdk.ErrInternal("time in force cannot be larger than 600")
+	if msg.LimitPrice().IsZero() {
+		return sdk.ErrInvalidCoins("limit price cannot be zero")
+	}
+	if display := msg.Display(); !display.IsZero() {
+		if msg.OrderType.IsImmediate() {
+			return sdk.ErrUnknownRequest("immediate orders cannot be iceberg orders")
+		}
+		if !display.LT(msg.Quantity) {
+			return sdk.ErrInvalidCoins("display quantity must be less than the quantity")
+		}
+	}
+	return nil
+}
+
+func validateExpiry(orderType matcheng.OrderType, expiry Expiry) sdk.Error {
+	if !expiry.Type.IsValid() {
+		return sdk.ErrUnknownRequest("invalid expiry type")
+	}
+	if orderType.IsImmediate() {
+		if expiry.Type != GoodTillBlocks {
+			return sdk.ErrUnknownRequest("immediate orders cannot rest in the book")
+		}
+		if expiry.Blocks != 0 {
+			return sdk.ErrInternal("time in force must be zero for immediate orders")
+		}
+		return nil
+	}
+
+	switch expiry.Type {
+	case GoodTillTime:
+		if expiry.Blocks != 0 {
+			return sdk.ErrInternal("time in force must be zero for good-till-time orders")
+		}
+		if expiry.Time <= 0 {
+			return sdk.ErrInternal("good till time must be positive")
+		}
+	case GoodTillCancel:
+		if expiry.Blocks != 0 || expiry.Time != 0 {
+			return sdk.ErrInternal("good-till-cancel orders cannot expire")
+		}
+	default:
+		if expiry.Blocks == 0 {
+			return sdk.ErrInternal("time in force cannot be zero")
+		}
+		if expiry.Blocks > MaxTimeInForce {
+			return sdk.ErrInternal("time in force cannot be larger than 600")
+		}
+	}
+	if expiry.Type != GoodTillTime && expiry.Time != 0 {
+		return sdk.ErrInternal("good till time is only supported for good-till-time orders")
 	}
 	return nil
 }
 
+// Expiry returns how long the order may rest in the book.
+func (msg MsgPost) Expiry() Expiry {
+	return Expiry{
+		Type:   msg.ExpiryType,
+		Blocks: msg.TimeInForce,
+		Time:   msg.GoodTillTime,
+	}
+}
+
+// OrderParams returns the order the message posts.
+func (msg MsgPost) OrderParams() OrderParams {
+	return OrderParams{
+		MarketID:        msg.MarketID,
+		Direction:       msg.Direction,
+		Type:            msg.OrderType,
+		Price:           msg.LimitPrice(),
+		Quantity:        msg.Quantity,
+		DisplayQuantity: msg.Display(),
+		Expiry:          msg.Expiry(),
+		STPMode:         msg.STPMode,
+	}
+}
+
+// Display returns the display quantity of an iceberg order, or zero if the
+// order shows all of its quantity.
+func (msg MsgPost) Display() sdk.Uint {
+	if msg.DisplayQuantity == nil {
+		return sdk.ZeroUint()
+	}
+	return *msg.DisplayQuantity
+}
+
+// LimitPrice returns the worst price the order may execute at. For market
+// orders this is the reference price moved by the slippage tolerance, for
+// all other order types it is the order price.
+func (msg MsgPost) LimitPrice() sdk.Uint {
+	if msg.OrderType != matcheng.Market {
+		return msg.Price
+	}
+
+	slippage := msg.Price.MulUint64(uint64(msg.SlippageBps)).QuoUint64(MaxSlippageBps)
+	if msg.Direction == matcheng.Bid {
+		return msg.Price.Add(slippage)
+	}
+	return msg.Price.Sub(slippage)
+}
+
 func (msg MsgPost) GetSignBytes() []byte {
 	return serde.MustMarshalSortedJSON(msg)
 }
@@ -100,3 +225,222 @@ func (msg MsgCancel) GetSignBytes() []byte {
 func (msg MsgCancel) GetSigners() []sdk.AccAddress {
 	return []sdk.AccAddress{msg.Owner}
 }
+
+// MsgReplace atomically cancels an order and posts a new one with the same
+// market, direction and order type in its place. Only the difference
+// between the escrow of the two orders is transferred. The new order gets a
+// new ID and loses the old order's time priority.
+type MsgReplace struct {
+	Owner        sdk.AccAddress   `json:"owner" yaml:"owner"`
+	OrderID      store.EntityID   `json:"order_id" yaml:"order_id"`
+	Price        sdk.Uint         `json:"price" yaml:"price"`
+	Quantity     sdk.Uint         `json:"quantity" yaml:"quantity"`
+	TimeInForce  uint16           `json:"time_in_force" yaml:"time_in_force"`
+	ExpiryType   ExpiryType       `json:"expiry_type,omitempty" yaml:"expiry_type"`
+	GoodTillTime int64            `json:"good_till_time,omitempty" yaml:"good_till_time"`
+	STPMode      matcheng.STPMode `json:"stp_mode,omitempty" yaml:"stp_mode"`
+}
+
+func NewMsgReplace(owner sdk.AccAddress, orderID store.EntityID, price sdk.Uint, quantity sdk.Uint, tif uint16) MsgReplace {
+	return MsgReplace{
+		Owner:       owner,
+		OrderID:     orderID,
+		Price:       price,
+		Quantity:    quantity,
+		TimeInForce: tif,
+	}
+}
+
+func (msg MsgReplace) Route() string {
+	return "order"
+}
+
+func (msg MsgReplace) Type() string {
+	return "replace"
+}
+
+func (msg MsgReplace) ValidateBasic() sdk.Error {
+	if msg.Owner.Empty() {
+		return sdk.ErrUnauthorized("owner cannot be empty")
+	}
+	if !msg.OrderID.IsDefined() {
+		return sdk.ErrInternal("invalid order ID")
+	}
+	if msg.Price.IsZero() {
+		return sdk.ErrInvalidCoins("price cannot be zero")
+	}
+	if msg.Quantity.IsZero() {
+		return sdk.ErrInvalidCoins("quantity cannot be zero")
+	}
+	// only orders resting in the book can be replaced
+	if err := validateExpiry(matcheng.Limit, msg.Expiry()); err != nil {
+		return err
+	}
+	if !msg.STPMode.IsValid() {
+		return sdk.ErrUnknownRequest("invalid self-trade prevention mode")
+	}
+	return nil
+}
+
+// Expiry returns how long the new order may rest in the book.
+func (msg MsgReplace) Expiry() Expiry {
+	return Expiry{
+		Type:   msg.ExpiryType,
+		Blocks: msg.TimeInForce,
+		Time:   msg.GoodTillTime,
+	}
+}
+
+func (msg MsgReplace) GetSignBytes() []byte {
+	return serde.MustMarshalSortedJSON(msg)
+}
+
+func (msg MsgReplace) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Owner}
+}
+
+// MsgCancelAll cancels all open orders of Owner. If MarketID is defined
+// only orders in that market are cancelled, and if Direction is set only
+// orders on that side of the book.
+type MsgCancelAll struct {
+	Owner     sdk.AccAddress      `json:"owner" yaml:"owner"`
+	MarketID  store.EntityID      `json:"market_id" yaml:"market_id"`
+	Direction *matcheng.Direction `json:"direction,omitempty" yaml:"direction"`
+}
+
+func NewMsgCancelAll(owner sdk.AccAddress, marketID store.EntityID, direction *matcheng.Direction) MsgCancelAll {
+	return MsgCancelAll{
+		Owner:     owner,
+		MarketID:  marketID,
+		Direction: direction,
+	}
+}
+
+func (msg MsgCancelAll) Route() string {
+	return "order"
+}
+
+func (msg MsgCancelAll) Type() string {
+	return "cancel_all"
+}
+
+func (msg MsgCancelAll) ValidateBasic() sdk.Error {
+	if msg.Owner.Empty() {
+		return sdk.ErrUnauthorized("owner cannot be empty")
+	}
+	if msg.Direction != nil && *msg.Direction != matcheng.Bid && *msg.Direction != matcheng.Ask {
+		return sdk.ErrUnknownRequest("invalid direction")
+	}
+	return nil
+}
+
+func (msg MsgCancelAll) GetSignBytes() []byte {
+	return serde.MustMarshalSortedJSON(msg)
+}
+
+func (msg MsgCancelAll) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Owner}
+}
+
+// MsgPostStop posts a stop (OrderType Market) or stop-limit (OrderType
+// Limit) order. It is held back until the price selected by Trigger
+// crosses StopPrice and is then posted like the MsgPost returned by Post.
+type MsgPostStop struct {
+	Owner       sdk.AccAddress     `json:"owner" yaml:"owner"`
+	MarketID    store.EntityID     `json:"market_id" yaml:"market_id"`
+	Direction   matcheng.Direction `json:"direction" yaml:"direction"`
+	StopPrice   sdk.Uint           `json:"stop_price" yaml:"stop_price"`
+	Price       sdk.Uint           `json:"price" yaml:"price"`
+	Quantity    sdk.Uint           `json:"quantity" yaml:"quantity"`
+	TimeInForce uint16             `json:"time_in_force" yaml:"time_in_force"`
+	OrderType   matcheng.OrderType `json:"order_type" yaml:"order_type"`
+	SlippageBps uint16             `json:"slippage_bps,omitempty" yaml:"slippage_bps"`
+	Trigger     TriggerSource      `json:"trigger" yaml:"trigger"`
+}
+
+func NewMsgPostStop(owner sdk.AccAddress, marketID store.EntityID, direction matcheng.Direction, stopPrice sdk.Uint, price sdk.Uint, quantity sdk.Uint, tif uint16, orderType matcheng.OrderType, slippageBps uint16, trigger TriggerSource) MsgPostStop {
+	return MsgPostStop{
+		Owner:       owner,
+		MarketID:    marketID,
+		Direction:   direction,
+		StopPrice:   stopPrice,
+		Price:       price,
+		Quantity:    quantity,
+		TimeInForce: tif,
+		OrderType:   orderType,
+		SlippageBps: slippageBps,
+		Trigger:     trigger,
+	}
+}
+
+func (msg MsgPostStop) Route() string {
+	return "order"
+}
+
+func (msg MsgPostStop) Type() string {
+	return "post_stop"
+}
+
+func (msg MsgPostStop) ValidateBasic() sdk.Error {
+	if msg.OrderType != matcheng.Limit && msg.OrderType != matcheng.Market {
+		return sdk.ErrUnknownRequest("stop orders must be limit or market orders")
+	}
+	if msg.StopPrice.IsZero() {
+		return sdk.ErrInvalidCoins("stop price cannot be zero")
+	}
+	if !msg.Trigger.IsValid() {
+		return sdk.ErrUnknownRequest("invalid trigger source")
+	}
+	return msg.Post().ValidateBasic()
+}
+
+// Post returns the order that is posted once the stop price is reached.
+func (msg MsgPostStop) Post() MsgPost {
+	return NewMsgPost(msg.Owner, msg.MarketID, msg.Direction, msg.Price, msg.Quantity, msg.TimeInForce, msg.OrderType, msg.SlippageBps)
+}
+
+func (msg MsgPostStop) GetSignBytes() []byte {
+	return serde.MustMarshalSortedJSON(msg)
+}
+
+func (msg MsgPostStop) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Owner}
+}
+
+type MsgCancelStop struct {
+	Owner   sdk.AccAddress `json:"owner" yaml:"owner"`
+	OrderID store.EntityID `json:"order_id" yaml:"order_id"`
+}
+
+func NewMsgCancelStop(owner sdk.AccAddress, orderID store.EntityID) MsgCancelStop {
+	return MsgCancelStop{
+		Owner:   owner,
+		OrderID: orderID,
+	}
+}
+
+func (msg MsgCancelStop) Route() string {
+	return "order"
+}
+
+func (msg MsgCancelStop) Type() string {
+	return "cancel_stop"
+}
+
+func (msg MsgCancelStop) ValidateBasic() sdk.Error {
+	if msg.Owner.Empty() {
+		return sdk.ErrUnauthorized("owner cannot be empty")
+	}
+	if !msg.OrderID.IsDefined() {
+		return sdk.ErrInternal("invalid order ID")
+	}
+	return nil
+}
+
+func (msg MsgCancelStop) GetSignBytes() []byte {
+	return serde.MustMarshalSortedJSON(msg)
+}
+
+func (msg MsgCancelStop) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Owner}
+}
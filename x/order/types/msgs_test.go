@@ -18,7 +18,7 @@ func TestMsgSort(t *testing.T) {
 	quantity := sdk.NewUintFromString("10")
 	marketID := store.NewEntityID(1)
 
-	msg := types.NewMsgPost(addr, marketID, matcheng.Bid, price, quantity, 600)
+	msg := types.NewMsgPost(addr, marketID, matcheng.Bid, price, quantity, 600, matcheng.Limit, 0)
 
 	require.Equal(t, `{"direction":"BID","market_id":"1","owner":"cosmos1wdhk6e2wv9kk2j88d92","price":"3005","quantity":"10","time_in_force":600}`, string(msg.GetSignBytes()))
 	signed := auth.StdSignBytes(
@@ -26,3 +26,158 @@ func TestMsgSort(t *testing.T) {
 	)
 	require.Equal(t, `{"account_number":"4","chain_id":"xar-chain-zafx","fee":{"amount":[],"gas":"200000"},"memo":"","msgs":[{"direction":"BID","market_id":"1","owner":"cosmos1wdhk6e2wv9kk2j88d92","price":"3005","quantity":"10","time_in_force":600}],"sequence":"1"}`, string(signed))
 }
+
+func TestMsgPost_ValidateBasic(t *testing.T) {
+	addr := sdk.AccAddress([]byte("someName"))
+	price := sdk.NewUint(10000)
+	quantity := sdk.NewUint(10)
+	marketID := store.NewEntityID(1)
+
+	tests := []struct {
+		name  string
+		msg   types.MsgPost
+		valid bool
+	}{
+		{"limit", types.NewMsgPost(addr, marketID, matcheng.Bid, price, quantity, 600, matcheng.Limit, 0), true},
+		{"limit without time in force", types.NewMsgPost(addr, marketID, matcheng.Bid, price, quantity, 0, matcheng.Limit, 0), false},
+		{"post only", types.NewMsgPost(addr, marketID, matcheng.Ask, price, quantity, 10, matcheng.PostOnly, 0), true},
+		{"ioc", types.NewMsgPost(addr, marketID, matcheng.Bid, price, quantity, 0, matcheng.ImmediateOrCancel, 0), true},
+		{"ioc with time in force", types.NewMsgPost(addr, marketID, matcheng.Bid, price, quantity, 10, matcheng.ImmediateOrCancel, 0), false},
+		{"fok", types.NewMsgPost(addr, marketID, matcheng.Bid, price, quantity, 0, matcheng.FillOrKill, 0), true},
+		{"market", types.NewMsgPost(addr, marketID, matcheng.Bid, price, quantity, 0, matcheng.Market, 50), true},
+		{"market with full slippage ask", types.NewMsgPost(addr, marketID, matcheng.Ask, price, quantity, 0, matcheng.Market, 10000), false},
+		{"market with too much slippage", types.NewMsgPost(addr, marketID, matcheng.Bid, price, quantity, 0, matcheng.Market, 10001), false},
+		{"slippage on a limit order", types.NewMsgPost(addr, marketID, matcheng.Bid, price, quantity, 600, matcheng.Limit, 50), false},
+		{"unknown type", types.NewMsgPost(addr, marketID, matcheng.Bid, price, quantity, 600, matcheng.OrderType(99), 0), false},
+		{"good till time", withExpiry(types.NewMsgPost(addr, marketID, matcheng.Bid, price, quantity, 0, matcheng.Limit, 0), types.GoodTillTime, 1558332092), true},
+		{"good till time without time", withExpiry(types.NewMsgPost(addr, marketID, matcheng.Bid, price, quantity, 0, matcheng.Limit, 0), types.GoodTillTime, 0), false},
+		{"good till time with time in force", withExpiry(types.NewMsgPost(addr, marketID, matcheng.Bid, price, quantity, 10, matcheng.Limit, 0), types.GoodTillTime, 1558332092), false},
+		{"good till cancel", withExpiry(types.NewMsgPost(addr, marketID, matcheng.Ask, price, quantity, 0, matcheng.PostOnly, 0), types.GoodTillCancel, 0), true},
+		{"good till cancel with time", withExpiry(types.NewMsgPost(addr, marketID, matcheng.Ask, price, quantity, 0, matcheng.Limit, 0), types.GoodTillCancel, 1558332092), false},
+		{"good till blocks with time", withExpiry(types.NewMsgPost(addr, marketID, matcheng.Ask, price, quantity, 10, matcheng.Limit, 0), types.GoodTillBlocks, 1558332092), false},
+		{"good till cancel ioc", withExpiry(types.NewMsgPost(addr, marketID, matcheng.Bid, price, quantity, 0, matcheng.ImmediateOrCancel, 0), types.GoodTillCancel, 0), false},
+		{"unknown expiry", withExpiry(types.NewMsgPost(addr, marketID, matcheng.Bid, price, quantity, 10, matcheng.Limit, 0), types.ExpiryType(9), 0), false},
+		{"cancel both", withSTP(types.NewMsgPost(addr, marketID, matcheng.Bid, price, quantity, 10, matcheng.Limit, 0), matcheng.CancelBoth), true},
+		{"unknown self-trade prevention", withSTP(types.NewMsgPost(addr, marketID, matcheng.Bid, price, quantity, 10, matcheng.Limit, 0), matcheng.STPMode(9)), false},
+		{"iceberg", withDisplay(types.NewMsgPost(addr, marketID, matcheng.Bid, price, quantity, 10, matcheng.Limit, 0), 2), true},
+		{"iceberg showing everything", withDisplay(types.NewMsgPost(addr, marketID, matcheng.Bid, price, quantity, 10, matcheng.Limit, 0), 10), false},
+		{"immediate iceberg", withDisplay(types.NewMsgPost(addr, marketID, matcheng.Bid, price, quantity, 0, matcheng.ImmediateOrCancel, 0), 2), false},
+		{"zero display quantity", withDisplay(types.NewMsgPost(addr, marketID, matcheng.Bid, price, quantity, 10, matcheng.Limit, 0), 0), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.msg.ValidateBasic()
+			if tt.valid {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
+
+func withExpiry(msg types.MsgPost, expiryType types.ExpiryType, goodTillTime int64) types.MsgPost {
+	msg.ExpiryType = expiryType
+	msg.GoodTillTime = goodTillTime
+	return msg
+}
+
+func TestMsgReplace_ValidateBasic(t *testing.T) {
+	addr := sdk.AccAddress([]byte("someName"))
+	price := sdk.NewUint(10000)
+	quantity := sdk.NewUint(10)
+	orderID := store.NewEntityID(1)
+	gtc := types.NewMsgReplace(addr, orderID, price, quantity, 0)
+	gtc.ExpiryType = types.GoodTillCancel
+
+	tests := []struct {
+		name  string
+		msg   types.MsgReplace
+		valid bool
+	}{
+		{"replace", types.NewMsgReplace(addr, orderID, price, quantity, 10), true},
+		{"good till cancel", gtc, true},
+		{"without owner", types.NewMsgReplace(sdk.AccAddress{}, orderID, price, quantity, 10), false},
+		{"without order", types.NewMsgReplace(addr, store.ZeroEntityID, price, quantity, 10), false},
+		{"without quantity", types.NewMsgReplace(addr, orderID, price, sdk.ZeroUint(), 10), false},
+		{"without time in force", types.NewMsgReplace(addr, orderID, price, quantity, 0), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.msg.ValidateBasic()
+			if tt.valid {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestMsgCancelAll_ValidateBasic(t *testing.T) {
+	addr := sdk.AccAddress([]byte("someName"))
+	bid := matcheng.Bid
+	invalid := matcheng.Direction(9)
+
+	require.NoError(t, types.NewMsgCancelAll(addr, store.ZeroEntityID, nil).ValidateBasic())
+	require.NoError(t, types.NewMsgCancelAll(addr, store.NewEntityID(1), &bid).ValidateBasic())
+	require.Error(t, types.NewMsgCancelAll(addr, store.NewEntityID(1), &invalid).ValidateBasic())
+	require.Error(t, types.NewMsgCancelAll(sdk.AccAddress{}, store.ZeroEntityID, nil).ValidateBasic())
+	require.Equal(t, `{"market_id":"0","owner":"cosmos1wdhk6e2wv9kk2j88d92"}`, string(types.NewMsgCancelAll(addr, store.ZeroEntityID, nil).GetSignBytes()))
+}
+
+func withSTP(msg types.MsgPost, mode matcheng.STPMode) types.MsgPost {
+	msg.STPMode = mode
+	return msg
+}
+
+func withDisplay(msg types.MsgPost, display uint64) types.MsgPost {
+	quantity := sdk.NewUint(display)
+	msg.DisplayQuantity = &quantity
+	return msg
+}
+
+func TestMsgPost_LimitPrice(t *testing.T) {
+	addr := sdk.AccAddress([]byte("someName"))
+	price := sdk.NewUint(10000)
+	quantity := sdk.NewUint(10)
+	marketID := store.NewEntityID(1)
+
+	bid := types.NewMsgPost(addr, marketID, matcheng.Bid, price, quantity, 0, matcheng.Market, 150)
+	require.Equal(t, "10150", bid.LimitPrice().String())
+	ask := types.NewMsgPost(addr, marketID, matcheng.Ask, price, quantity, 0, matcheng.Market, 150)
+	require.Equal(t, "9850", ask.LimitPrice().String())
+	limit := types.NewMsgPost(addr, marketID, matcheng.Ask, price, quantity, 10, matcheng.Limit, 0)
+	require.Equal(t, "10000", limit.LimitPrice().String())
+}
+
+func TestMsgPostStop_ValidateBasic(t *testing.T) {
+	addr := sdk.AccAddress([]byte("someName"))
+	stop := sdk.NewUint(9000)
+	price := sdk.NewUint(10000)
+	quantity := sdk.NewUint(10)
+	marketID := store.NewEntityID(1)
+
+	tests := []struct {
+		name  string
+		msg   types.MsgPostStop
+		valid bool
+	}{
+		{"stop limit", types.NewMsgPostStop(addr, marketID, matcheng.Bid, stop, price, quantity, 600, matcheng.Limit, 0, types.TriggerClearing), true},
+		{"stop", types.NewMsgPostStop(addr, marketID, matcheng.Ask, stop, price, quantity, 0, matcheng.Market, 50, types.TriggerOracle), true},
+		{"stop with time in force", types.NewMsgPostStop(addr, marketID, matcheng.Ask, stop, price, quantity, 10, matcheng.Market, 50, types.TriggerOracle), false},
+		{"zero stop price", types.NewMsgPostStop(addr, marketID, matcheng.Bid, sdk.ZeroUint(), price, quantity, 600, matcheng.Limit, 0, types.TriggerClearing), false},
+		{"ioc", types.NewMsgPostStop(addr, marketID, matcheng.Bid, stop, price, quantity, 0, matcheng.ImmediateOrCancel, 0, types.TriggerClearing), false},
+		{"unknown trigger", types.NewMsgPostStop(addr, marketID, matcheng.Bid, stop, price, quantity, 600, matcheng.Limit, 0, types.TriggerSource(9)), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.msg.ValidateBasic()
+			if tt.valid {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package types
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/params"
+)
+
+var (
+	KeyMaxOpenOrdersPerAccount = []byte("MaxOpenOrdersPerAccount")
+	KeyMaxOpenOrdersPerMarket  = []byte("MaxOpenOrdersPerMarket")
+	KeyPostFee                 = []byte("PostFee")
+	KeyMaxBatchOrders          = []byte("MaxBatchOrders")
+	KeyMaxOrderRecords         = []byte("MaxOrderRecords")
+)
+
+const (
+	DefaultMaxOpenOrdersPerAccount uint64 = 200
+	DefaultMaxOpenOrdersPerMarket  uint64 = 10000
+	DefaultMaxBatchOrders          uint64 = 1000
+	DefaultMaxOrderRecords         uint64 = 100000
+)
+
+// ParamKeyTable returns the key table of the order module's parameters.
+func ParamKeyTable() params.KeyTable {
+	return params.NewKeyTable().RegisterParamSet(&Params{})
+}
+
+// Params limit the load a single account or market can put on the order
+// book. A limit of zero is disabled.
+type Params struct {
+	// MaxOpenOrdersPerAccount caps the open orders of an account across
+	// all markets.
+	MaxOpenOrdersPerAccount uint64 `json:"max_open_orders_per_account" yaml:"max_open_orders_per_account"`
+	// MaxOpenOrdersPerMarket caps the open orders resting in a market.
+	MaxOpenOrdersPerMarket uint64 `json:"max_open_orders_per_market" yaml:"max_open_orders_per_market"`
+	// PostFee is charged for every posted or replaced order and sent to the
+	// fee collector. It is not refunded when the order is cancelled.
+	PostFee sdk.Coins `json:"post_fee" yaml:"post_fee"`
+	// MaxBatchOrders caps the orders a batch auction of a market matches.
+	// Crossing orders beyond the cap keep their priority and are matched
+	// in the following batches.
+	MaxBatchOrders uint64 `json:"max_batch_orders" yaml:"max_batch_orders"`
+	// MaxOrderRecords caps the records kept of closed orders. The oldest
+	// records are evicted as new orders close.
+	MaxOrderRecords uint64 `json:"max_order_records" yaml:"max_order_records"`
+}
+
+// ParamSetPairs implements the ParamSet interface.
+func (p *Params) ParamSetPairs() params.ParamSetPairs {
+	return params.ParamSetPairs{
+		{Key: KeyMaxOpenOrdersPerAccount, Value: &p.MaxOpenOrdersPerAccount},
+		{Key: KeyMaxOpenOrdersPerMarket, Value: &p.MaxOpenOrdersPerMarket},
+		{Key: KeyPostFee, Value: &p.PostFee},
+		{Key: KeyMaxBatchOrders, Value: &p.MaxBatchOrders},
+		{Key: KeyMaxOrderRecords, Value: &p.MaxOrderRecords},
+	}
+}
+
+func NewParams(maxPerAccount, maxPerMarket uint64, postFee sdk.Coins, maxBatchOrders, maxOrderRecords uint64) Params {
+	return Params{
+		MaxOpenOrdersPerAccount: maxPerAccount,
+		MaxOpenOrdersPerMarket:  maxPerMarket,
+		PostFee:                 postFee,
+		MaxBatchOrders:          maxBatchOrders,
+		MaxOrderRecords:         maxOrderRecords,
+	}
+}
+
+func DefaultParams() Params {
+	return NewParams(DefaultMaxOpenOrdersPerAccount, DefaultMaxOpenOrdersPerMarket, sdk.NewCoins(), DefaultMaxBatchOrders, DefaultMaxOrderRecords)
+}
+
+// Validate returns an error if the posting fee is not a valid set of coins
+// or a batch could not hold both a bid and an ask.
+func (p Params) Validate() error {
+	if !p.PostFee.IsValid() {
+		return fmt.Errorf("invalid post fee: %s", p.PostFee)
+	}
+	if p.MaxBatchOrders == 1 {
+		return fmt.Errorf("max batch orders must be zero or at least 2")
+	}
+	return nil
+}
+
+func (p Params) String() string {
+	return fmt.Sprintf(`Params:
+  Max Open Orders Per Account: %d
+  Max Open Orders Per Market:  %d
+  Post Fee:                    %s
+  Max Batch Orders:            %d
+  Max Order Records:           %d`,
+		p.MaxOpenOrdersPerAccount, p.MaxOpenOrdersPerMarket, p.PostFee, p.MaxBatchOrders, p.MaxOrderRecords)
+}
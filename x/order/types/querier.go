@@ -5,10 +5,80 @@ import (
 	"strconv"
 
 	"github.com/olekukonko/tablewriter"
+
+	"github.com/xar-network/xar-network/pkg/matcheng"
+	"github.com/xar-network/xar-network/types/store"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	DefaultQueryLimit = 100
+	MaxQueryLimit     = 1000
 )
 
+// ListQueryParams selects a page of the open orders, oldest first. Filters
+// that are not set match every order. Prices are inclusive bounds.
+type ListQueryParams struct {
+	Owner     sdk.AccAddress      `json:"owner,omitempty"`
+	MarketID  *store.EntityID     `json:"market_id,omitempty"`
+	Direction *matcheng.Direction `json:"direction,omitempty"`
+	MinPrice  *sdk.Uint           `json:"min_price,omitempty"`
+	MaxPrice  *sdk.Uint           `json:"max_price,omitempty"`
+	Page      int                 `json:"page"`
+	Limit     int                 `json:"limit"`
+}
+
+func NewListQueryParams(page, limit int) ListQueryParams {
+	return ListQueryParams{
+		Page:  page,
+		Limit: limit,
+	}
+}
+
+// Matches returns true if the order passes all filters.
+func (p ListQueryParams) Matches(order Order) bool {
+	if !p.Owner.Empty() && !order.Owner.Equals(p.Owner) {
+		return false
+	}
+	if p.MarketID != nil && !order.MarketID.Equals(*p.MarketID) {
+		return false
+	}
+	if p.Direction != nil && order.Direction != *p.Direction {
+		return false
+	}
+	if p.MinPrice != nil && order.Price.LT(*p.MinPrice) {
+		return false
+	}
+	if p.MaxPrice != nil && order.Price.GT(*p.MaxPrice) {
+		return false
+	}
+	return true
+}
+
+// Bounds returns how many matching orders to skip and how many to return,
+// applying the default limit and capping it at MaxQueryLimit.
+func (p ListQueryParams) Bounds() (skip int, limit int) {
+	limit = p.Limit
+	if limit <= 0 {
+		limit = DefaultQueryLimit
+	}
+	if limit > MaxQueryLimit {
+		limit = MaxQueryLimit
+	}
+	page := p.Page
+	if page < 1 {
+		page = 1
+	}
+	return (page - 1) * limit, limit
+}
+
 type ListQueryResult struct {
 	Orders []Order `json:"orders"`
+	Page   int     `json:"page"`
+	Limit  int     `json:"limit"`
+	// More is true if there are matching orders beyond this page.
+	More bool `json:"more"`
 }
 
 func (l ListQueryResult) String() string {
@@ -19,6 +89,7 @@ func (l ListQueryResult) String() string {
 		"Owner",
 		"MarketID",
 		"Direction",
+		"Type",
 		"Price",
 		"Quantity",
 		"Time In Force",
@@ -31,12 +102,99 @@ func (l ListQueryResult) String() string {
 			o.Owner.String(),
 			o.MarketID.String(),
 			o.Direction.String(),
+			o.Type.String(),
 			o.Price.String(),
 			o.Quantity.String(),
-			strconv.FormatUint(uint64(o.TimeInForceBlocks), 10),
+			o.timeInForce(),
 			strconv.Itoa(int(o.CreatedBlock)),
 		})
 	}
 	t.Render()
 	return string(buf.Bytes())
 }
+
+func (o Order) timeInForce() string {
+	switch o.ExpiryType {
+	case GoodTillTime:
+		return "GTT " + strconv.FormatInt(o.GoodTillTime, 10)
+	case GoodTillCancel:
+		return "GTC"
+	default:
+		return strconv.FormatUint(uint64(o.TimeInForceBlocks), 10)
+	}
+}
+
+const (
+	DefaultDepthLevels = 20
+	MaxDepthLevels     = 500
+)
+
+// DepthQueryParams limits a depth query to the best Levels price levels on
+// each side. Prices are grouped into buckets of BucketSize if it is set:
+// bids are rounded down and asks up to a multiple of it.
+type DepthQueryParams struct {
+	Levels     int       `json:"levels"`
+	BucketSize *sdk.Uint `json:"bucket_size,omitempty"`
+}
+
+func NewDepthQueryParams(levels int) DepthQueryParams {
+	return DepthQueryParams{
+		Levels: levels,
+	}
+}
+
+// MaxLevels returns the number of levels to return per side, applying the
+// default and capping it at MaxDepthLevels.
+func (p DepthQueryParams) MaxLevels() int {
+	if p.Levels <= 0 {
+		return DefaultDepthLevels
+	}
+	if p.Levels > MaxDepthLevels {
+		return MaxDepthLevels
+	}
+	return p.Levels
+}
+
+// DepthLevel is the total quantity resting at a price level.
+type DepthLevel struct {
+	Price    sdk.Uint `json:"price"`
+	Quantity sdk.Uint `json:"quantity"`
+	Orders   int      `json:"orders"`
+}
+
+type DepthQueryResult struct {
+	MarketID    store.EntityID `json:"market_id"`
+	BlockNumber int64          `json:"block_number"`
+	Bids        []DepthLevel   `json:"bids"`
+	Asks        []DepthLevel   `json:"asks"`
+}
+
+func (d DepthQueryResult) String() string {
+	var buf bytes.Buffer
+	t := tablewriter.NewWriter(&buf)
+	t.SetHeader([]string{
+		"Bid Orders",
+		"Bid Quantity",
+		"Bid Price",
+		"Ask Price",
+		"Ask Quantity",
+		"Ask Orders",
+	})
+
+	for i := 0; i < len(d.Bids) || i < len(d.Asks); i++ {
+		row := make([]string, 6)
+		if i < len(d.Bids) {
+			row[0] = strconv.Itoa(d.Bids[i].Orders)
+			row[1] = d.Bids[i].Quantity.String()
+			row[2] = d.Bids[i].Price.String()
+		}
+		if i < len(d.Asks) {
+			row[3] = d.Asks[i].Price.String()
+			row[4] = d.Asks[i].Quantity.String()
+			row[5] = strconv.Itoa(d.Asks[i].Orders)
+		}
+		t.Append(row)
+	}
+	t.Render()
+	return string(buf.Bytes())
+}
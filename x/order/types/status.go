@@ -0,0 +1,82 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+const (
+	// Open orders rest in the book and have not been filled yet.
+	Open OrderStatus = iota
+	// PartiallyFilled orders rest in the book with part of their quantity
+	// filled.
+	PartiallyFilled
+	// Filled orders were filled completely.
+	Filled
+	// Cancelled orders were cancelled before being filled completely.
+	Cancelled
+	// Expired orders outlived their expiry.
+	Expired
+	// Rejected orders were killed because they could not be filled as
+	// their order type demands.
+	Rejected
+)
+
+// OrderStatus is the stage of an order's lifecycle. Open and PartiallyFilled
+// orders are kept in the book, all other statuses are terminal.
+type OrderStatus uint8
+
+var orderStatusNames = map[OrderStatus]string{
+	Open:            "OPEN",
+	PartiallyFilled: "PARTIALLY_FILLED",
+	Filled:          "FILLED",
+	Cancelled:       "CANCELLED",
+	Expired:         "EXPIRED",
+	Rejected:        "REJECTED",
+}
+
+func (s OrderStatus) String() string {
+	name, ok := orderStatusNames[s]
+	if !ok {
+		return "UNKNOWN"
+	}
+	return name
+}
+
+func (s OrderStatus) IsValid() bool {
+	_, ok := orderStatusNames[s]
+	return ok
+}
+
+// IsTerminal returns true if orders with this status are no longer open.
+func (s OrderStatus) IsTerminal() bool {
+	return s != Open && s != PartiallyFilled
+}
+
+func OrderStatusFromString(str string) (OrderStatus, error) {
+	for s, name := range orderStatusNames {
+		if name == str {
+			return s, nil
+		}
+	}
+	return Open, errors.New("invalid order status")
+}
+
+func (s *OrderStatus) UnmarshalJSON(data []byte) error {
+	var str string
+	err := json.Unmarshal(data, &str)
+	if err != nil {
+		return err
+	}
+
+	out, err := OrderStatusFromString(str)
+	if err != nil {
+		return err
+	}
+	*s = out
+	return nil
+}
+
+func (s OrderStatus) MarshalJSON() ([]byte, error) {
+	return []byte("\"" + s.String() + "\""), nil
+}
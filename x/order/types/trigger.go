@@ -0,0 +1,99 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/xar-network/xar-network/pkg/matcheng"
+	"github.com/xar-network/xar-network/types/store"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	// TriggerClearing triggers on the last clearing price of the market.
+	TriggerClearing TriggerSource = iota
+	// TriggerOracle triggers on the oracle median price of the market's
+	// base and quote assets.
+	TriggerOracle
+)
+
+// TriggerSource selects the reference price a trigger order is checked
+// against.
+type TriggerSource uint8
+
+var triggerSourceNames = map[TriggerSource]string{
+	TriggerClearing: "CLEARING",
+	TriggerOracle:   "ORACLE",
+}
+
+func (s TriggerSource) String() string {
+	name, ok := triggerSourceNames[s]
+	if !ok {
+		return "UNKNOWN"
+	}
+	return name
+}
+
+func (s TriggerSource) IsValid() bool {
+	_, ok := triggerSourceNames[s]
+	return ok
+}
+
+func TriggerSourceFromString(str string) (TriggerSource, error) {
+	for s, name := range triggerSourceNames {
+		if name == str {
+			return s, nil
+		}
+	}
+	return TriggerClearing, errors.New("invalid trigger source")
+}
+
+func (s *TriggerSource) UnmarshalJSON(data []byte) error {
+	var str string
+	err := json.Unmarshal(data, &str)
+	if err != nil {
+		return err
+	}
+
+	out, err := TriggerSourceFromString(str)
+	if err != nil {
+		return err
+	}
+	*s = out
+	return nil
+}
+
+func (s TriggerSource) MarshalJSON() ([]byte, error) {
+	return []byte("\"" + s.String() + "\""), nil
+}
+
+// TriggerOrder is a stop or stop-limit order that stays dormant until the
+// reference price crosses StopPrice. Nothing is escrowed until it triggers,
+// at which point it is posted as a regular order at Price.
+type TriggerOrder struct {
+	ID                store.EntityID     `json:"id"`
+	Owner             sdk.AccAddress     `json:"owner"`
+	MarketID          store.EntityID     `json:"market"`
+	Direction         matcheng.Direction `json:"direction"`
+	Type              matcheng.OrderType `json:"type"`
+	StopPrice         sdk.Uint           `json:"stop_price"`
+	Price             sdk.Uint           `json:"price"`
+	Quantity          sdk.Uint           `json:"quantity"`
+	TimeInForceBlocks uint16             `json:"time_in_force_blocks"`
+	Source            TriggerSource      `json:"source"`
+	CreatedBlock      int64              `json:"created_block"`
+}
+
+// Triggered returns true if the order should be posted at the given
+// reference price. Buy stops trigger when the price rises to the stop
+// price, sell stops when it falls to it.
+func (t TriggerOrder) Triggered(ref sdk.Uint) bool {
+	if ref.IsZero() {
+		return false
+	}
+	if t.Direction == matcheng.Bid {
+		return ref.GTE(t.StopPrice)
+	}
+	return !ref.GT(t.StopPrice)
+}
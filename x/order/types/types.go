@@ -18,9 +18,21 @@ type Order struct {
 	Quantity          sdk.Uint           `json:"quantity"`
 	TimeInForceBlocks uint16             `json:"time_in_force_blocks"`
 	CreatedBlock      int64              `json:"created_block"`
+	Type              matcheng.OrderType `json:"type"`
+	ExpiryType        ExpiryType         `json:"expiry_type"`
+	GoodTillTime      int64              `json:"good_till_time"`
+	STPMode           matcheng.STPMode   `json:"stp_mode"`
+	Status            OrderStatus        `json:"status"`
+	InitialQuantity   sdk.Uint           `json:"initial_quantity"`
+	FilledQuantity    sdk.Uint           `json:"filled_quantity"`
+	FilledValue       sdk.Uint           `json:"filled_value"`
+	// DisplayQuantity is the most of an iceberg order's quantity that is
+	// shown in the book at a time. It is zero for orders that show all of
+	// their quantity.
+	DisplayQuantity sdk.Uint `json:"display_quantity"`
 }
 
-func New(owner sdk.AccAddress, marketID store.EntityID, direction matcheng.Direction, price sdk.Uint, quantity sdk.Uint, tif uint16, created int64) Order {
+func New(owner sdk.AccAddress, marketID store.EntityID, direction matcheng.Direction, orderType matcheng.OrderType, price sdk.Uint, quantity sdk.Uint, tif uint16, created int64) Order {
 	return Order{
 		Owner:             owner,
 		MarketID:          marketID,
@@ -29,5 +41,117 @@ func New(owner sdk.AccAddress, marketID store.EntityID, direction matcheng.Direc
 		Quantity:          quantity,
 		TimeInForceBlocks: tif,
 		CreatedBlock:      created,
+		Type:              orderType,
+		InitialQuantity:   quantity,
+		FilledQuantity:    sdk.ZeroUint(),
+		FilledValue:       sdk.ZeroUint(),
+		DisplayQuantity:   sdk.ZeroUint(),
 	}
 }
+
+// OrderParams describe an order to post. The zero values of the optional
+// fields post a limit order that shows all of its quantity and cancels the
+// newest order on a self-trade. Price is the worst price the order may
+// execute at.
+type OrderParams struct {
+	MarketID  store.EntityID
+	Direction matcheng.Direction
+	Type      matcheng.OrderType
+	Price     sdk.Uint
+	Quantity  sdk.Uint
+	// DisplayQuantity makes the order an iceberg order that only shows this
+	// much of its quantity in the book at a time.
+	DisplayQuantity sdk.Uint
+	Expiry          Expiry
+	STPMode         matcheng.STPMode
+}
+
+// Display returns the display quantity of the order, or zero if it shows
+// all of its quantity.
+func (p OrderParams) Display() sdk.Uint {
+	if p.DisplayQuantity == (sdk.Uint{}) {
+		return sdk.ZeroUint()
+	}
+	return p.DisplayQuantity
+}
+
+// IsIceberg returns true if the order hides part of its quantity.
+func (o Order) IsIceberg() bool {
+	return !o.DisplayQuantity.IsZero()
+}
+
+// Visible returns the quantity of the order that is shown in the book and
+// matched. The visible slice of an iceberg order is refilled from its
+// hidden reserve after every fill until the reserve runs out.
+func (o Order) Visible() sdk.Uint {
+	if o.IsIceberg() && o.DisplayQuantity.LT(o.Quantity) {
+		return o.DisplayQuantity
+	}
+	return o.Quantity
+}
+
+// Fill records that quantity of the order was filled at price.
+func (o *Order) Fill(price sdk.Uint, quantity sdk.Uint) {
+	o.Quantity = o.Quantity.Sub(quantity)
+	o.FilledQuantity = o.FilledQuantity.Add(quantity)
+	o.FilledValue = o.FilledValue.Add(price.Mul(quantity))
+	o.Status = PartiallyFilled
+}
+
+// AvgPrice returns the average price the order was filled at, or zero if
+// nothing was filled.
+func (o Order) AvgPrice() sdk.Uint {
+	if o.FilledQuantity.IsZero() {
+		return sdk.ZeroUint()
+	}
+	return o.FilledValue.Quo(o.FilledQuantity)
+}
+
+// Record summarizes the outcome of the order as of closedBlock.
+func (o Order) Record(closedBlock int64) OrderRecord {
+	return OrderRecord{
+		ID:             o.ID,
+		Owner:          o.Owner,
+		MarketID:       o.MarketID,
+		Direction:      o.Direction,
+		Type:           o.Type,
+		Price:          o.Price,
+		Quantity:       o.InitialQuantity,
+		QuantityFilled: o.FilledQuantity,
+		AvgPrice:       o.AvgPrice(),
+		Status:         o.Status,
+		CreatedBlock:   o.CreatedBlock,
+		ClosedBlock:    closedBlock,
+	}
+}
+
+// OrderRecord is the outcome of an order. Records of orders that are no
+// longer open are kept in state after the order itself is deleted.
+type OrderRecord struct {
+	ID             store.EntityID     `json:"id"`
+	Owner          sdk.AccAddress     `json:"owner"`
+	MarketID       store.EntityID     `json:"market"`
+	Direction      matcheng.Direction `json:"direction"`
+	Type           matcheng.OrderType `json:"type"`
+	Price          sdk.Uint           `json:"price"`
+	Quantity       sdk.Uint           `json:"quantity"`
+	QuantityFilled sdk.Uint           `json:"quantity_filled"`
+	AvgPrice       sdk.Uint           `json:"avg_price"`
+	Status         OrderStatus        `json:"status"`
+	CreatedBlock   int64              `json:"created_block"`
+	ClosedBlock    int64              `json:"closed_block"`
+}
+
+func (o Order) Expiry() Expiry {
+	return Expiry{
+		Type:   o.ExpiryType,
+		Blocks: o.TimeInForceBlocks,
+		Time:   o.GoodTillTime,
+	}
+}
+
+// ExpiryHeight returns the first height at which a GoodTillBlocks order is
+// no longer good.
+func (o Order) ExpiryHeight() int64 {
+	return o.CreatedBlock + int64(o.TimeInForceBlocks) + 1
+}